@@ -11,7 +11,7 @@ mkdir -p bin
 
 # Build gateway
 echo "Building MCP Gateway..."
-go build -o bin/gateway main.go
+go build -o bin/gateway .
 
 # Build test servers
 echo "Building Test Server 1..."
@@ -0,0 +1,214 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerAdminRoutes mounts the admin HTTP endpoints on mux
+func (g *Gateway) registerAdminRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/tools.json", g.handleToolsExport)
+	mux.HandleFunc("POST /admin/replay", g.handleReplay)
+	mux.HandleFunc("POST /admin/backends/{name}/refresh", g.handleRefreshBackend)
+	mux.HandleFunc("GET /admin/backends/{name}/pipeline", g.handleBackendPipeline)
+	mux.HandleFunc("DELETE /admin/backends/{name}", g.handleRemoveBackend)
+}
+
+// AdminHandler returns the handler for the separate admin listener (admin.listen).
+// It serves the admin endpoints when admin is enabled and the pprof profiling
+// endpoints under /debug/pprof/ when admin.pprof is set; otherwise those routes don't exist.
+func (g *Gateway) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	if g.config.Admin.Enabled {
+		g.registerAdminRoutes(mux)
+	}
+	if g.config.Admin.Pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	return g.loggingMiddleware(mux)
+}
+
+// toolFilters returns the filters applied to tools/list, in order
+func (g *Gateway) toolFilters() []server.ToolFilterFunc {
+	return []server.ToolFilterFunc{
+		g.discoveryToolFilter,
+		g.readOnlyToolFilter,
+		g.toolPriorityFilter,
+		g.protocolCompatibilityFilter,
+	}
+}
+
+// listedTools returns the tools a client sees in tools/list, sorted by name
+func (g *Gateway) listedTools(ctx context.Context) []mcp.Tool {
+	tools := append([]mcp.Tool{}, g.builtinTools...)
+
+	g.toolsLock.RLock()
+	tools = append(tools, g.aggregatedTools...)
+	g.toolsLock.RUnlock()
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	for _, filter := range g.toolFilters() {
+		tools = filter(ctx, tools)
+	}
+	return tools
+}
+
+// exportedTool renders a tool as it appears in tools/list plus its origin
+func (g *Gateway) exportedTool(tool mcp.Tool) (map[string]any, error) {
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return nil, err
+	}
+	var exported map[string]any
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, err
+	}
+
+	origin := map[string]any{"backend": "gateway", "tool": tool.Name}
+	g.toolsLock.RLock()
+	if route, ok := g.toolRoutes[tool.Name]; ok {
+		origin = map[string]any{"backend": route.Backend, "tool": route.ToolName}
+	} else if discoverable := g.discoverableTools[tool.Name]; len(discoverable) > 0 {
+		// A discovery tool stands in for its backend's tools
+		if route, ok := g.toolRoutes[discoverable[0].Name]; ok {
+			origin = map[string]any{"backend": route.Backend, "tool": discoveryToolName}
+		}
+	}
+	g.toolsLock.RUnlock()
+	exported["origin"] = origin
+
+	return exported, nil
+}
+
+// handleToolsExport serves the aggregated tool registry as JSON, or as an
+// OpenAPI document with ?format=openapi
+func (g *Gateway) handleToolsExport(w http.ResponseWriter, r *http.Request) {
+	exported := make([]map[string]any, 0)
+	for _, tool := range g.listedTools(r.Context()) {
+		entry, err := g.exportedTool(tool)
+		if err != nil {
+			log.Printf("❌ Failed to export tool %s: %v", tool.Name, err)
+			http.Error(w, "failed to export tools", http.StatusInternalServerError)
+			return
+		}
+		exported = append(exported, entry)
+	}
+
+	var document any = map[string]any{"tools": exported}
+	if r.URL.Query().Get("format") == "openapi" {
+		document = openAPIDocument(exported)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		log.Printf("❌ Failed to write tool export: %v", err)
+	}
+}
+
+// openAPIDocument describes each tool as a POST operation taking its input schema
+func openAPIDocument(tools []map[string]any) map[string]any {
+	paths := make(map[string]any, len(tools))
+	for _, tool := range tools {
+		name, _ := tool["name"].(string)
+		description, _ := tool["description"].(string)
+		paths["/tools/"+name] = map[string]any{
+			"post": map[string]any{
+				"operationId": name,
+				"summary":     description,
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": tool["inputSchema"]},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{"description": "MCP tool call result"},
+				},
+				"x-mcp-origin": tool["origin"],
+			},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "MCP Gateway Tools",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+	}
+}
+
+// Client session ID used for replayed calls; replays share its backend connections
+const replaySessionID = "admin-replay"
+
+// adminBypassKey is the context key marking calls made by the admin API, which bypass
+// the backend's throttle and circuit breaker
+type adminBypassKey struct{}
+
+// isAdminBypass reports whether a call was made by the admin API
+func isAdminBypass(ctx context.Context) bool {
+	bypass, _ := ctx.Value(adminBypassKey{}).(bool)
+	return bypass
+}
+
+// replayRequest identifies a tool call to replay against a backend
+type replayRequest struct {
+	Backend   string         `json:"backend"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// handleReplay replays a tool call against its backend through the normal proxy path
+// and returns the result, for reproducing reported failures without a client. Replays
+// reach the backend even while it is throttled or its circuit is open, and their
+// outcome doesn't count towards the circuit.
+func (g *Gateway) handleReplay(w http.ResponseWriter, r *http.Request) {
+	var replay replayRequest
+	if err := json.NewDecoder(r.Body).Decode(&replay); err != nil {
+		http.Error(w, fmt.Sprintf("invalid replay request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if replay.Backend == "" || replay.Tool == "" {
+		http.Error(w, "backend and tool are required", http.StatusBadRequest)
+		return
+	}
+	backend, ok := g.findBackend(replay.Backend)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown backend %s", replay.Backend), http.StatusNotFound)
+		return
+	}
+
+	toolName := g.gatewayToolName(backend, replay.Tool)
+	log.Printf("🔧 Replaying %s on %s", replay.Tool, replay.Backend)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = toolName
+	req.Params.Arguments = replay.Arguments
+
+	ctx := context.WithValue(r.Context(), adminBypassKey{}, true)
+	result, err := g.proxyToolCall(ctx, replaySessionID, toolName, toolRoute{Backend: replay.Backend, ToolName: replay.Tool}, req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("replay failed: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("❌ Failed to write replay result: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fetchJSON fetches and decodes a JSON document from the gateway
+func fetchJSON(t *testing.T, url string, out any) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s returned %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("Failed to decode %s: %v", url, err)
+	}
+}
+
+// TestAdminToolsExport verifies the tool export matches tools/list plus backend origins
+func TestAdminToolsExport(t *testing.T) {
+	backend := newStubBackend(t, "Stub",
+		textTool(mcp.NewTool("search",
+			mcp.WithDescription("Search things"),
+			mcp.WithString("query", mcp.Required()),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), "found"),
+		textTool(mcp.NewTool("delete", mcp.WithDescription("Delete things")), "deleted"),
+	)
+	tracker := newStubBackend(t, "Tracker",
+		textTool(mcp.NewTool("open_issue", mcp.WithReadOnlyHintAnnotation(true)), "opened"),
+		textTool(mcp.NewTool("list_issues", mcp.WithReadOnlyHintAnnotation(true)), "issues"),
+	)
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	config.ReadOnly = ReadOnlyConfig{Enabled: true, HideMutatingTools: true}
+	config.Backends = []BackendConfig{
+		{Name: "server1", URL: backend.URL},
+		{Name: "server2", URL: tracker.URL, Discovery: DiscoveryConfig{ToolThreshold: 1}},
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	listed, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	var export struct {
+		Tools []map[string]any `json:"tools"`
+	}
+	fetchJSON(t, gatewayURL+"/admin/tools.json", &export)
+
+	if len(export.Tools) != len(listed.Tools) {
+		t.Fatalf("Export has %d tools, tools/list has %d", len(export.Tools), len(listed.Tools))
+	}
+	for i, tool := range listed.Tools {
+		exported := export.Tools[i]
+		origin, _ := exported["origin"].(map[string]any)
+		delete(exported, "origin")
+
+		var want map[string]any
+		data, _ := json.Marshal(tool)
+		json.Unmarshal(data, &want)
+		if !reflect.DeepEqual(exported, want) {
+			t.Errorf("Exported tool %d = %v, want %v", i, exported, want)
+		}
+
+		switch tool.Name {
+		case "server1-search":
+			if origin["backend"] != "server1" || origin["tool"] != "search" {
+				t.Errorf("Unexpected origin for %s: %v", tool.Name, origin)
+			}
+		case "server2-discover_tools":
+			if origin["backend"] != "server2" || origin["tool"] != discoveryToolName {
+				t.Errorf("Unexpected origin for %s: %v", tool.Name, origin)
+			}
+		case "gateway_info", "gateway_describe_backend", "gateway_refresh_backend", "gateway_recent_errors", "gateway_tool_stats":
+			if origin["backend"] != "gateway" {
+				t.Errorf("Unexpected origin for %s: %v", tool.Name, origin)
+			}
+		default:
+			t.Errorf("Unexpected tool %s in export", tool.Name)
+		}
+	}
+
+	var openAPI struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	fetchJSON(t, gatewayURL+"/admin/tools.json?format=openapi", &openAPI)
+	if openAPI.OpenAPI == "" || openAPI.Paths["/tools/server1-search"]["post"] == nil {
+		t.Errorf("Expected an OpenAPI operation for server1-search, got %+v", openAPI)
+	}
+	if _, ok := openAPI.Paths["/tools/server1-delete"]; ok {
+		t.Error("Expected hidden tool server1-delete to be absent from the OpenAPI document")
+	}
+	if _, ok := openAPI.Paths["/tools/server2-open_issue"]; ok {
+		t.Error("Expected server2-open_issue, listed behind its discovery tool, to be absent from the OpenAPI document")
+	}
+}
+
+// TestAdminReplay verifies a replayed call reaches the backend and returns its result
+func TestAdminReplay(t *testing.T) {
+	var received atomic.Value
+	backend := newStubBackend(t, "Stub", stubTool{
+		tool: mcp.NewTool("lookup", mcp.WithString("id")),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			received.Store(req.GetString("id", ""))
+			return mcp.NewToolResultText("found " + req.GetString("id", "")), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	body := `{"backend":"server1","tool":"lookup","arguments":{"id":"42"}}`
+	resp, err := http.Post(gatewayURL+"/admin/replay", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Replay request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Replay returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Content []mcp.TextContent `json:"content"`
+		IsError bool              `json:"isError"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode replay result: %v", err)
+	}
+	if result.IsError || len(result.Content) == 0 || result.Content[0].Text != "found 42" {
+		t.Errorf("Unexpected replay result: %+v", result)
+	}
+	if received.Load() != "42" {
+		t.Errorf("Expected the backend to receive id 42, got %v", received.Load())
+	}
+
+	resp, err = http.Post(gatewayURL+"/admin/replay", "application/json", strings.NewReader(`{"backend":"nope","tool":"lookup"}`))
+	if err != nil {
+		t.Fatalf("Replay request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown backend, got %d", resp.StatusCode)
+	}
+}
+
+// TestAdminReplayBypass verifies replays reach a backend whose circuit is open and that
+// is throttled, without affecting its circuit
+func TestAdminReplayBypass(t *testing.T) {
+	backend := newStubBackend(t, "Stub", textTool(mcp.NewTool("lookup"), "found"))
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	config.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	replay := func() string {
+		resp, err := http.Post(gatewayURL+"/admin/replay", "application/json", strings.NewReader(`{"backend":"server1","tool":"lookup"}`))
+		if err != nil {
+			t.Fatalf("Replay request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var result struct {
+			Content []mcp.TextContent `json:"content"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Content) == 0 {
+			t.Fatalf("Failed to decode replay result: %v", err)
+		}
+		return result.Content[0].Text
+	}
+
+	gateway.breakers["server1"].recordFailure()
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-lookup", nil)); !strings.Contains(text, "circuit open") {
+		t.Fatalf("Expected client calls to be rejected while the circuit is open, got %q", text)
+	}
+	if text := replay(); text != "found" {
+		t.Errorf("Expected the replay to bypass the open circuit, got %q", text)
+	}
+	if !gateway.breakers["server1"].isOpen() {
+		t.Error("Expected a successful replay to leave the circuit open")
+	}
+
+	gateway.breakers["server1"].recordSuccess()
+	gateway.throttles["server1"].throttled(time.Minute)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-lookup", nil)); text == "found" {
+		t.Fatal("Expected client calls to be rejected while the backend is throttled")
+	}
+	if text := replay(); text != "found" {
+		t.Errorf("Expected the replay to bypass the throttle, got %q", text)
+	}
+}
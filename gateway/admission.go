@@ -0,0 +1,199 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default delay suggested to clients rejected by admission control
+const defaultAdmissionRetryAfter = 5 * time.Second
+
+// JSON-RPC error code for sessions rejected by admission control (implementation-defined server error range)
+const overloadedErrorCode = -32001
+
+// AdmissionConfig sheds load by rejecting new sessions past configured thresholds.
+// Existing sessions are never rejected.
+type AdmissionConfig struct {
+	// MaxActiveCalls rejects new sessions while this many tool calls are in flight; 0 is unlimited
+	MaxActiveCalls int `yaml:"maxActiveCalls"`
+	// MaxQueueDepth rejects new sessions while this many backend operations wait for a
+	// fan-out worker; 0 is unlimited
+	MaxQueueDepth int `yaml:"maxQueueDepth"`
+	// RetryAfter is the delay suggested to rejected clients (default 5s)
+	RetryAfter time.Duration `yaml:"retryAfter"`
+	// InitializeRate limits how fast each client may create sessions
+	InitializeRate InitializeRateConfig `yaml:"initializeRate"`
+}
+
+// InitializeRateConfig limits session creation (initialize) per client key
+type InitializeRateConfig struct {
+	// PerSecond is the sustained initialize rate allowed per key; 0 is unlimited
+	PerSecond float64 `yaml:"perSecond"`
+	// Burst is how many initializes a key may make at once (default 1)
+	Burst int `yaml:"burst"`
+	// KeyHeader names a request header identifying the client (e.g. X-Api-Key);
+	// the client IP is used when unset or when the header is missing
+	KeyHeader string `yaml:"keyHeader"`
+}
+
+// Number of initialize requests between sweeps of idle rate limit buckets
+const initializeRateSweepInterval = 256
+
+// initializeLimiter keeps an initialize token bucket per client key
+type initializeLimiter struct {
+	config  InitializeRateConfig
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+	calls   int
+}
+
+// newInitializeLimiter creates a limiter, or returns nil when the rate is unlimited
+func newInitializeLimiter(config InitializeRateConfig) *initializeLimiter {
+	if config.PerSecond <= 0 {
+		return nil
+	}
+	return &initializeLimiter{config: config, buckets: make(map[string]*tokenBucket)}
+}
+
+// key identifies the client making a request
+func (l *initializeLimiter) key(r *http.Request) string {
+	if l.config.KeyHeader != "" {
+		if value := r.Header.Get(l.config.KeyHeader); value != "" {
+			return value
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allow takes an initialize token for key, returning how long to wait when none is left
+func (l *initializeLimiter) allow(key string) (bool, time.Duration) {
+	l.lock.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(l.config.PerSecond, l.config.Burst)
+		l.buckets[key] = bucket
+	}
+
+	// Buckets idle long enough to have refilled are the same as new ones
+	l.calls++
+	if l.calls%initializeRateSweepInterval == 0 {
+		refill := time.Duration(float64(max(l.config.Burst, 1)) / l.config.PerSecond * float64(time.Second))
+		for other, b := range l.buckets {
+			if other != key && time.Since(b.idleSince()) > refill {
+				delete(l.buckets, other)
+			}
+		}
+	}
+	l.lock.Unlock()
+
+	if bucket.take() {
+		return true, 0
+	}
+	return false, bucket.untilNext()
+}
+
+// retryAfter returns the suggested retry delay
+func (c AdmissionConfig) retryAfter() time.Duration {
+	if c.RetryAfter > 0 {
+		return c.RetryAfter
+	}
+	return defaultAdmissionRetryAfter
+}
+
+// admissionStats tracks load and the sessions turned away because of it
+type admissionStats struct {
+	activeCalls atomic.Int64
+	rejected    atomic.Int64
+	// throttled counts initializes rejected by the initialize rate limit
+	throttled atomic.Int64
+}
+
+// overloaded returns why the gateway is over its admission thresholds, or "" if it is not
+func (g *Gateway) overloaded() string {
+	config := g.config.Admission
+	if active := g.admission.activeCalls.Load(); config.MaxActiveCalls > 0 && active >= int64(config.MaxActiveCalls) {
+		return fmt.Sprintf("%d active calls", active)
+	}
+	if depth := g.fanOut.queueDepth(); config.MaxQueueDepth > 0 && depth >= int64(config.MaxQueueDepth) {
+		return fmt.Sprintf("%d queued backend operations", depth)
+	}
+	return ""
+}
+
+// admissionMiddleware rejects initialize requests with 503 and Retry-After while the
+// gateway is overloaded, and with 429 when the client creates sessions faster than the
+// initialize rate. It must run inside initializeMetaMiddleware.
+func (g *Gateway) admissionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := initializeRequestFromContext(r.Context())
+		if request == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if g.initializeLimiter != nil {
+			key := g.initializeLimiter.key(r)
+			if ok, wait := g.initializeLimiter.allow(key); !ok {
+				g.admission.throttled.Add(1)
+				log.Printf("⚠️ Rejecting new session: %s is initializing sessions too fast", key)
+				rejectInitialize(w, request, http.StatusTooManyRequests, wait, "Too many sessions created: retry later")
+				return
+			}
+		}
+
+		if g.config.Sessions.Overflow == sessionOverflowReject && g.sessions.full() {
+			g.admission.rejected.Add(1)
+			log.Printf("⚠️ Rejecting new session: limit of %d sessions reached", g.config.Sessions.MaxSessions)
+			rejectInitialize(w, request, http.StatusServiceUnavailable, g.config.Admission.retryAfter(), "Session limit reached: retry later")
+			return
+		}
+
+		reason := g.overloaded()
+		if reason == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		g.admission.rejected.Add(1)
+		log.Printf("⚠️ Rejecting new session: gateway overloaded (%s)", reason)
+		rejectInitialize(w, request, http.StatusServiceUnavailable, g.config.Admission.retryAfter(), "Gateway overloaded: retry later")
+	})
+}
+
+// rejectInitialize responds to an initialize request with a JSON-RPC error, the given
+// HTTP status and a Retry-After header
+func rejectInitialize(w http.ResponseWriter, request *initializeRequest, status int, wait time.Duration, message string) {
+	retryAfter := max(int(math.Ceil(wait.Seconds())), 1)
+	w.Header().Set("Retry-After", fmt.Sprint(retryAfter))
+	writeInitializeError(w, request, status, overloadedErrorCode, message, map[string]any{"retryAfterSeconds": retryAfter})
+}
+
+// writeInitializeError responds to an initialize request with a JSON-RPC error
+func writeInitializeError(w http.ResponseWriter, request *initializeRequest, status, code int, message string, data any) {
+	id := request.ID
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"data":    data,
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestAdmissionControl verifies new sessions are rejected with 503 past the active call
+// threshold while existing sessions keep working
+func TestAdmissionControl(t *testing.T) {
+	backend := newStubBackend(t, "Stub",
+		slowTool("slow", 500*time.Millisecond),
+		textTool(mcp.NewTool("echo"), "ok"),
+	)
+
+	config := DefaultConfig()
+	config.Admission = AdmissionConfig{MaxActiveCalls: 1, RetryAfter: 7 * time.Second}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	existing := newTestClient(t, gatewayURL)
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		callTool(t, existing, "server1-slow", nil)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for gateway.admission.activeCalls.Load() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Slow call never became active")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// A new session is shed with 503 and Retry-After
+	resp, err := http.Post(gatewayURL, "application/json", strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"late","version":"1.0.0"}}}`))
+	if err != nil {
+		t.Fatalf("Initialize request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for a new session while overloaded, got %d", resp.StatusCode)
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "7" {
+		t.Errorf("Expected Retry-After 7, got %q", retryAfter)
+	}
+	if rejected := gateway.admission.rejected.Load(); rejected != 1 {
+		t.Errorf("Expected 1 rejected session, got %d", rejected)
+	}
+
+	// The existing session continues
+	if text := extractTextFromResult(callTool(t, existing, "server1-echo", nil)); text != "ok" {
+		t.Errorf("Expected the existing session to keep working, got %q", text)
+	}
+
+	// Once load drops, new sessions are admitted again
+	<-slowDone
+	newTestClient(t, gatewayURL)
+}
+
+// TestInitializeRateLimit verifies a client flooding initialize is throttled with 429
+// while other clients can still create sessions
+func TestInitializeRateLimit(t *testing.T) {
+	backend := newStubBackend(t, "Stub", textTool(mcp.NewTool("echo"), "ok"))
+
+	config := DefaultConfig()
+	config.Admission.InitializeRate = InitializeRateConfig{PerSecond: 0.5, Burst: 2, KeyHeader: "X-Client-Key"}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	initialize := func(key string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, gatewayURL, strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"loop","version":"1.0.0"}}}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set("X-Client-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Initialize request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	var statuses []int
+	for i := 0; i < 5; i++ {
+		resp := initialize("flooder")
+		statuses = append(statuses, resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Errorf("Expected Retry-After on a throttled initialize")
+		}
+	}
+	expected := []int{200, 200, 429, 429, 429}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Fatalf("Expected statuses %v for the flooding client, got %v", expected, statuses)
+		}
+	}
+
+	if resp := initialize("well-behaved"); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected another client to be unaffected, got %d", resp.StatusCode)
+	}
+	if throttled := gateway.admission.throttled.Load(); throttled != 3 {
+		t.Errorf("Expected 3 throttled initializes, got %d", throttled)
+	}
+}
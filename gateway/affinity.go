@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// A connection to a replicated backend stays on the replica picked when it was made.
+// With the backend's affinityTTL set, the shared connection of a stateless backend is
+// replaced, once older than the TTL, on its next call by one to the replica the balancer
+// picks then, so traffic rebalances as replicas are added or removed. The old connection
+// is closed once the requests in flight on it finish. A client's session on a stateful
+// backend keeps its replica for the session's lifetime, since its state lives there.
+
+// affinityReplica returns the replica transport of a backend connection whose affinity
+// TTL has expired
+func affinityReplica(backend BackendConfig, backendClient BackendTransport) (*replicaTransport, bool) {
+	if backend.AffinityTTL <= 0 {
+		return nil, false
+	}
+	var replica *replicaTransport
+	findTransport(backendClient, func(t transport.Interface) bool {
+		replica, _ = t.(*replicaTransport)
+		return replica != nil
+	})
+	if replica == nil || time.Since(replica.connected) < backend.AffinityTTL {
+		return nil, false
+	}
+	return replica, true
+}
+
+// rebalanceSharedClient replaces the shared connection of a stateless backend whose
+// affinity TTL has expired, keeping it when no replica can be connected
+func (g *Gateway) rebalanceSharedClient(ctx context.Context, backend BackendConfig, expired BackendTransport, replica *replicaTransport) BackendTransport {
+	g.rebalanceLock.Lock()
+	defer g.rebalanceLock.Unlock()
+
+	// Another call may have rebalanced it already
+	if current, ok := g.sharedClient(backend.Name); ok && current != expired {
+		return current
+	}
+
+	log.Printf("🔧 %s shared connection to replica %s is older than its affinity TTL %s, rebalancing",
+		backend.Name, replica.replica.name(), backend.AffinityTTL)
+	backendClient, _, err := g.connectBackend(ctx, backend, "MCP Gateway (Shared)", nil)
+	if err != nil {
+		log.Printf("⚠️ Keeping %s shared connection to replica %s: %v", backend.Name, replica.replica.name(), err)
+		return expired
+	}
+
+	g.toolsLock.Lock()
+	shared := make(map[string]BackendTransport, len(g.sharedClients))
+	for name, sharedClient := range g.sharedClients {
+		shared[name] = sharedClient
+	}
+	shared[backend.Name] = backendClient
+	g.sharedClients = shared
+	// The startup connection stays open for refreshing the backend's tools
+	startup := g.startupClients[backend.Name] == expired
+	g.toolsLock.Unlock()
+
+	if !startup {
+		replica.retire()
+	}
+	return backendClient
+}
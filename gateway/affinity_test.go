@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newAffinityReplica starts a stateless stub replica whose whoami tool answers with its
+// name, and whose slow tool answers once release is closed
+func newAffinityReplica(t *testing.T, name string, release <-chan struct{}) *httptest.Server {
+	mcpServer := server.NewMCPServer(name, "1.0.0", server.WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(name), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("slow"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return mcp.NewToolResultText(name + " done"), nil
+	})
+	replica := httptest.NewServer(server.NewStreamableHTTPServer(mcpServer, server.WithStateLess(true)))
+	t.Cleanup(replica.Close)
+	return replica
+}
+
+// TestAffinityTTL verifies a stateless backend's shared connection stays on its replica
+// within the affinity TTL, and re-picks one on its first call after it without
+// interrupting calls in flight
+func TestAffinityTTL(t *testing.T) {
+	const ttl = 500 * time.Millisecond
+	release := make(chan struct{})
+	local := newAffinityReplica(t, "local", release)
+	remote := newAffinityReplica(t, "remote", release)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:        "server1",
+		AffinityTTL: ttl,
+		Replicas: []ReplicaConfig{
+			// The gateway's startup connection, which stays open, fills the local replica
+			{URL: local.URL, Tier: 0, MaxSessions: 1},
+			{URL: remote.URL, Tier: 1},
+		},
+	}}
+	gateway, gatewayURL := startTestGateway(t, config)
+	if _, ok := gateway.sharedClient("server1"); !ok {
+		t.Fatal("Expected the stateless backend to be shared")
+	}
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-whoami", nil)); text != "local" {
+		t.Errorf("Expected the shared connection to stay on the local replica within the affinity TTL, got %q", text)
+	}
+	time.Sleep(ttl)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-whoami", nil)); text != "remote" {
+		t.Fatalf("Expected the shared connection to re-pick a replica after the affinity TTL, got %q", text)
+	}
+
+	// The replaced connection is retired once the call in flight on it finishes
+	slow := make(chan string, 1)
+	go func() {
+		result, err := mcpClient.CallTool(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "server1-slow"}})
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		slow <- extractTextFromResult(result)
+	}()
+	time.Sleep(ttl)
+	callTool(t, mcpClient, "server1-whoami", nil)
+
+	remoteReplica := gateway.replicas["server1"][1]
+	remoteSessions := func() int {
+		remoteReplica.lock.Lock()
+		defer remoteReplica.lock.Unlock()
+		return remoteReplica.sessions
+	}
+	if sessions := remoteSessions(); sessions != 2 {
+		t.Errorf("Expected the old connection to stay open for its call in flight, got %d sessions", sessions)
+	}
+	close(release)
+	if text := <-slow; text != "remote done" {
+		t.Errorf("Expected the call in flight to finish on its connection, got %q", text)
+	}
+	if sessions := remoteSessions(); sessions != 1 {
+		t.Errorf("Expected the old connection to be closed after its call, got %d sessions", sessions)
+	}
+}
+
+// TestAffinityTTLStateful verifies sessions on a stateful backend keep their replica,
+// and its state, past the affinity TTL
+func TestAffinityTTLStateful(t *testing.T) {
+	const ttl = 200 * time.Millisecond
+	replica := newStubBackend(t, "Replica", counterTool())
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:        "server1",
+		AffinityTTL: ttl,
+		Replicas:    []ReplicaConfig{{URL: replica.URL}},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-count", nil)); text != "1" {
+		t.Fatalf("Expected a first count of 1, got %q", text)
+	}
+	time.Sleep(ttl)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-count", nil)); text != "2" {
+		t.Errorf("Expected the backend session to be kept past the affinity TTL (count 2), got %q", text)
+	}
+}
+
+// TestAffinityTTLValidation verifies affinityTTL is rejected without replicas
+func TestAffinityTTLValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends[0].AffinityTTL = time.Minute
+	if err := config.Validate(); err == nil {
+		t.Error("Expected affinityTTL without replicas to be rejected")
+	}
+}
@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Name of the _meta field confirming a call to a destructive tool
+const confirmDestructiveMeta = "confirmDestructive"
+
+// ToolAnnotationsConfig sets MCP annotation hints of a tool. Unset hints keep the
+// backend's value. mcp-go fills in defaults for hints a backend omits, so a missing
+// hint can't be told apart from the default and configured hints always apply.
+type ToolAnnotationsConfig struct {
+	ReadOnlyHint    *bool `yaml:"readOnlyHint"`
+	DestructiveHint *bool `yaml:"destructiveHint"`
+	IdempotentHint  *bool `yaml:"idempotentHint"`
+	OpenWorldHint   *bool `yaml:"openWorldHint"`
+}
+
+// DefaultAnnotationsRule supplies annotation hints for matching tools whose backend
+// doesn't declare them. Hints the backend declares always win.
+type DefaultAnnotationsRule struct {
+	// Tool is a gateway tool name; glob patterns are supported (e.g. *-get_*)
+	Tool                  string `yaml:"tool"`
+	ToolAnnotationsConfig `yaml:",inline"`
+}
+
+// validateDefaultAnnotations checks the rules' tool patterns
+func validateDefaultAnnotations(rules []DefaultAnnotationsRule) error {
+	for i, rule := range rules {
+		if rule.Tool == "" {
+			return fmt.Errorf("defaultAnnotations[%d]: tool is required", i)
+		}
+		if err := validatePatterns([]string{rule.Tool}); err != nil {
+			return fmt.Errorf("defaultAnnotations[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// DestructiveToolsConfig enforces policy for calls of destructive tools
+type DestructiveToolsConfig struct {
+	// RequireConfirmation rejects calls without params._meta.confirmDestructive set to true
+	RequireConfirmation bool `yaml:"requireConfirmation"`
+	// Audit logs every call with its session and arguments
+	Audit bool `yaml:"audit"`
+}
+
+// applyAnnotations sets the configured annotation hints on an aggregated tool: default
+// hints fill in those the backend didn't declare, then the tool's own configured hints
+// override any
+func (g *Gateway) applyAnnotations(tool mcp.Tool) mcp.Tool {
+	tool = g.applyDefaultAnnotations(tool)
+	config, ok := g.config.Annotations[tool.Name]
+	if !ok {
+		return tool
+	}
+
+	if config.ReadOnlyHint != nil {
+		tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(*config.ReadOnlyHint)
+	}
+	if config.DestructiveHint != nil {
+		tool.Annotations.DestructiveHint = mcp.ToBoolPtr(*config.DestructiveHint)
+	}
+	if config.IdempotentHint != nil {
+		tool.Annotations.IdempotentHint = mcp.ToBoolPtr(*config.IdempotentHint)
+	}
+	if config.OpenWorldHint != nil {
+		tool.Annotations.OpenWorldHint = mcp.ToBoolPtr(*config.OpenWorldHint)
+	}
+	return tool
+}
+
+// applyDefaultAnnotations fills in hints the backend didn't declare from the first
+// matching default annotations rule that sets them
+func (g *Gateway) applyDefaultAnnotations(tool mcp.Tool) mcp.Tool {
+	fill := func(hint **bool, value *bool) {
+		if *hint == nil && value != nil {
+			*hint = mcp.ToBoolPtr(*value)
+		}
+	}
+	for _, rule := range g.config.DefaultAnnotations {
+		if !matchesAny([]string{rule.Tool}, tool.Name) {
+			continue
+		}
+		fill(&tool.Annotations.ReadOnlyHint, rule.ReadOnlyHint)
+		fill(&tool.Annotations.DestructiveHint, rule.DestructiveHint)
+		fill(&tool.Annotations.IdempotentHint, rule.IdempotentHint)
+		fill(&tool.Annotations.OpenWorldHint, rule.OpenWorldHint)
+	}
+	return tool
+}
+
+// isDestructiveTool reports whether a tool may perform destructive updates. As in the
+// MCP spec, destructiveHint only applies to tools that aren't read-only and defaults to true.
+func (g *Gateway) isDestructiveTool(tool mcp.Tool) bool {
+	if g.isReadOnlyTool(tool) {
+		return false
+	}
+	return tool.Annotations.DestructiveHint == nil || *tool.Annotations.DestructiveHint
+}
+
+// destructiveMiddleware applies the destructive tools policy to calls of aggregated tools
+func (g *Gateway) destructiveMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		policy := g.config.DestructiveTools
+		if !policy.RequireConfirmation && !policy.Audit {
+			return next(ctx, req)
+		}
+
+		tool, ok := g.lookupTool(req.Params.Name)
+		if !ok || !g.isDestructiveTool(tool) {
+			return next(ctx, req)
+		}
+
+		confirmed := false
+		if req.Params.Meta != nil {
+			confirmed, _ = req.Params.Meta.AdditionalFields[confirmDestructiveMeta].(bool)
+		}
+
+		if policy.Audit {
+			sessionID := ""
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				sessionID = session.SessionID()
+			}
+			arguments, _ := json.Marshal(req.Params.Arguments)
+			log.Printf("⚠️ Audit: destructive call %s (client: %s, confirmed: %t) arguments: %s",
+				req.Params.Name, sessionID, confirmed, arguments)
+		}
+
+		if policy.RequireConfirmation && !confirmed {
+			log.Printf("❌ Rejected unconfirmed call to destructive tool %s", req.Params.Name)
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"Tool %s is destructive: repeat the call with _meta.%s set to true to confirm", req.Params.Name, confirmDestructiveMeta)), nil
+		}
+
+		return next(ctx, req)
+	}
+}
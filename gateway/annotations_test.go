@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestDestructiveConfirmation verifies destructive tools need the confirmation flag
+// while the policy is on, and configured annotations override the backend's
+func TestDestructiveConfirmation(t *testing.T) {
+	var deletes atomic.Int64
+	backend := newStubBackend(t, "Files",
+		stubTool{
+			tool: mcp.NewTool("delete_all"),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				deletes.Add(1)
+				return mcp.NewToolResultText("deleted"), nil
+			},
+		},
+		textTool(mcp.NewTool("list", mcp.WithReadOnlyHintAnnotation(true)), "a, b"),
+		textTool(mcp.NewTool("archive"), "archived"),
+	)
+
+	config := DefaultConfig()
+	config.DestructiveTools = DestructiveToolsConfig{RequireConfirmation: true, Audit: true}
+	config.Annotations = map[string]ToolAnnotationsConfig{
+		"server1-archive": {DestructiveHint: mcp.ToBoolPtr(false)},
+	}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Unconfirmed destructive calls never reach the backend
+	result := callTool(t, mcpClient, "server1-delete_all", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), confirmDestructiveMeta) {
+		t.Fatalf("Expected an unconfirmed destructive call to be rejected, got %q", extractTextFromResult(result))
+	}
+	if deletes.Load() != 0 {
+		t.Fatalf("Expected the backend not to be called, got %d calls", deletes.Load())
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "server1-delete_all"
+	req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{confirmDestructiveMeta: true}}
+	result, err := mcpClient.CallTool(context.Background(), req)
+	if err != nil || extractTextFromResult(result) != "deleted" {
+		t.Fatalf("Expected a confirmed call to succeed, got %+v, %v", result, err)
+	}
+
+	// Read-only tools and tools configured as non-destructive need no confirmation
+	for tool, expected := range map[string]string{"server1-list": "a, b", "server1-archive": "archived"} {
+		if text := extractTextFromResult(callTool(t, mcpClient, tool, nil)); text != expected {
+			t.Errorf("Expected %s to run without confirmation, got %q", tool, text)
+		}
+	}
+
+	archive, _ := gateway.lookupTool("server1-archive")
+	if hint := archive.Annotations.DestructiveHint; hint == nil || *hint {
+		t.Errorf("Expected the configured destructiveHint false in tools/list, got %v", hint)
+	}
+}
+
+// TestDefaultAnnotations verifies default hints fill in those a backend doesn't declare,
+// and never override hints it does declare
+func TestDefaultAnnotations(t *testing.T) {
+	backend := newStubBackend(t, "Legacy",
+		textTool(mcp.Tool{Name: "get_user", InputSchema: mcp.ToolInputSchema{Type: "object"}}, "alice"),
+		textTool(mcp.Tool{Name: "get_and_reset", InputSchema: mcp.ToolInputSchema{Type: "object"},
+			Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(false)}}, "reset"),
+		textTool(mcp.Tool{Name: "update_user", InputSchema: mcp.ToolInputSchema{Type: "object"}}, "updated"),
+	)
+
+	config := DefaultConfig()
+	config.DefaultAnnotations = []DefaultAnnotationsRule{
+		{Tool: "server1-get_*", ToolAnnotationsConfig: ToolAnnotationsConfig{ReadOnlyHint: mcp.ToBoolPtr(true)}},
+		{Tool: "*", ToolAnnotationsConfig: ToolAnnotationsConfig{ReadOnlyHint: mcp.ToBoolPtr(false), OpenWorldHint: mcp.ToBoolPtr(false)}},
+	}
+	gateway, _ := startTestGateway(t, config, backend)
+
+	hint := func(value *bool) string {
+		if value == nil {
+			return "unset"
+		}
+		return fmt.Sprint(*value)
+	}
+	for name, expected := range map[string][2]string{
+		"server1-get_user":      {"true", "false"},
+		"server1-get_and_reset": {"false", "false"},
+		"server1-update_user":   {"false", "false"},
+	} {
+		tool, ok := gateway.lookupTool(name)
+		if !ok {
+			t.Fatalf("Expected %s to be listed", name)
+		}
+		if got := [2]string{hint(tool.Annotations.ReadOnlyHint), hint(tool.Annotations.OpenWorldHint)}; got != expected {
+			t.Errorf("Expected %s readOnlyHint/openWorldHint %v, got %v", name, expected, got)
+		}
+		if tool.Annotations.DestructiveHint != nil {
+			t.Errorf("Expected %s destructiveHint to stay unset, got %v", name, *tool.Annotations.DestructiveHint)
+		}
+	}
+}
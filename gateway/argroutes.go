@@ -0,0 +1,154 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ArgumentRoute exposes a tool that several backends serve for different partitions of
+// data, routing each call to a backend by the value of one of its arguments
+type ArgumentRoute struct {
+	// Tool is the gateway tool name clients call
+	Tool string `yaml:"tool"`
+	// BackendTool is the tool's name on the backends (defaults to Tool)
+	BackendTool string `yaml:"backendTool"`
+	// Argument is the argument whose value picks the backend
+	Argument string `yaml:"argument"`
+	// Rules are tried in order; the first matching rule's backend serves the call
+	Rules []ArgumentRule `yaml:"rules"`
+	// Default serves calls no rule matches; the tool is listed with its definition
+	Default string `yaml:"default"`
+}
+
+// ArgumentRule routes calls whose argument equals one of Values, or matches the
+// path.Match glob Match, to Backend
+type ArgumentRule struct {
+	Values  []string `yaml:"values"`
+	Match   string   `yaml:"match"`
+	Backend string   `yaml:"backend"`
+}
+
+// backendTool returns the tool's name on the backends
+func (r ArgumentRoute) backendTool() string {
+	if r.BackendTool == "" {
+		return r.Tool
+	}
+	return r.BackendTool
+}
+
+// matches reports whether an argument value satisfies the rule
+func (r ArgumentRule) matches(value string) bool {
+	for _, v := range r.Values {
+		if v == value {
+			return true
+		}
+	}
+	if r.Match != "" {
+		ok, _ := path.Match(r.Match, value)
+		return ok
+	}
+	return false
+}
+
+// validateArgumentRoutes checks each route names its tool and argument and only known backends
+func validateArgumentRoutes(routes []ArgumentRoute, backends []BackendConfig) error {
+	known := make(map[string]bool, len(backends))
+	for _, backend := range backends {
+		known[backend.Name] = true
+	}
+	seen := make(map[string]bool, len(routes))
+	for i, route := range routes {
+		if route.Tool == "" || route.Argument == "" || route.Default == "" {
+			return fmt.Errorf("argumentRoutes[%d]: tool, argument and default are required", i)
+		}
+		if seen[route.Tool] {
+			return fmt.Errorf("argumentRoutes[%d]: duplicate tool %s", i, route.Tool)
+		}
+		seen[route.Tool] = true
+		if !known[route.Default] {
+			return fmt.Errorf("argumentRoutes[%d]: default is unknown backend %s", i, route.Default)
+		}
+		for j, rule := range route.Rules {
+			if len(rule.Values) == 0 && rule.Match == "" {
+				return fmt.Errorf("argumentRoutes[%d].rules[%d]: values or match is required", i, j)
+			}
+			if _, err := path.Match(rule.Match, ""); err != nil {
+				return fmt.Errorf("argumentRoutes[%d].rules[%d].match: %w", i, j, err)
+			}
+			if !known[rule.Backend] {
+				return fmt.Errorf("argumentRoutes[%d].rules[%d]: unknown backend %q", i, j, rule.Backend)
+			}
+		}
+	}
+	return nil
+}
+
+// argumentRoutedTools lists the tools of argument routes, defined by their default
+// backend's tool, skipping names already taken
+func (g *Gateway) argumentRoutedTools(lists map[string][]mcp.Tool, routes map[string]toolRoute) []mcp.Tool {
+	var tools []mcp.Tool
+	for _, argumentRoute := range g.config.ArgumentRoutes {
+		if g.drains.isRemoved(argumentRoute.Default) {
+			continue
+		}
+		var tool mcp.Tool
+		found := false
+		for _, candidate := range lists[argumentRoute.Default] {
+			if candidate.Name == argumentRoute.backendTool() {
+				tool, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			log.Printf("⚠️ Skipping argument-routed tool %s: default backend %s has no tool %s",
+				argumentRoute.Tool, argumentRoute.Default, argumentRoute.backendTool())
+			continue
+		}
+		if g.isBuiltinTool(argumentRoute.Tool) {
+			log.Printf("⚠️ Skipping argument-routed tool %s: the name is reserved for a built-in tool", argumentRoute.Tool)
+			continue
+		}
+		if existing, ok := routes[argumentRoute.Tool]; ok {
+			log.Printf("⚠️ Skipping argument-routed tool %s: already provided by %s tool %s", argumentRoute.Tool, existing.Backend, existing.ToolName)
+			continue
+		}
+
+		tool.Name = argumentRoute.Tool
+		tool = g.applyDefaultSchema(tool)
+		tool = g.applyAnnotations(tool)
+		tools = append(tools, tool)
+		routes[tool.Name] = toolRoute{Backend: argumentRoute.Default, ToolName: argumentRoute.backendTool()}
+	}
+	return tools
+}
+
+// routeByArgument picks the backend of an argument-routed tool call from its argument;
+// other calls keep their route
+func (g *Gateway) routeByArgument(toolName string, route toolRoute, arguments any) toolRoute {
+	for _, argumentRoute := range g.config.ArgumentRoutes {
+		if argumentRoute.Tool != toolName {
+			continue
+		}
+		args, _ := arguments.(map[string]any)
+		value, ok := args[argumentRoute.Argument]
+		if !ok {
+			return route
+		}
+		text, isString := value.(string)
+		if !isString {
+			text = fmt.Sprint(value)
+		}
+		for _, rule := range argumentRoute.Rules {
+			if rule.matches(text) {
+				log.Printf("🔗 Routing %s by %s=%s to %s", toolName, argumentRoute.Argument, text, rule.Backend)
+				route.Backend = rule.Backend
+				return route
+			}
+		}
+		return route
+	}
+	return route
+}
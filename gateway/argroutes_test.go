@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestArgumentRouting verifies calls of an argument-routed tool reach the backend whose
+// rule matches the argument, and the default backend when none does
+func TestArgumentRouting(t *testing.T) {
+	us := newStubBackend(t, "US", textTool(mcp.NewTool("query", mcp.WithString("region")), "us data"))
+	eu := newStubBackend(t, "EU", textTool(mcp.NewTool("query", mcp.WithString("region")), "eu data"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "us", URL: us.URL}, {Name: "eu", URL: eu.URL}}
+	config.ArgumentRoutes = []ArgumentRoute{{
+		Tool:     "query",
+		Argument: "region",
+		Rules: []ArgumentRule{
+			{Values: []string{"us"}, Backend: "us"},
+			{Match: "eu-*", Backend: "eu"},
+		},
+		Default: "us",
+	}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid config, got %v", err)
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if !containsString(listToolNames(t, mcpClient), "query") {
+		t.Fatalf("Expected the routed query tool to be listed")
+	}
+	for region, want := range map[string]string{
+		"us":      "us data",
+		"eu-west": "eu data",
+		"ap":      "us data",
+	} {
+		if text := extractTextFromResult(callTool(t, mcpClient, "query", map[string]any{"region": region})); text != want {
+			t.Errorf("Expected region %s to be answered with %q, got %q", region, want, text)
+		}
+	}
+}
+
+// TestArgumentRouteValidation verifies rules must name known backends
+func TestArgumentRouteValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.ArgumentRoutes = []ArgumentRoute{{
+		Tool:     "query",
+		Argument: "region",
+		Rules:    []ArgumentRule{{Values: []string{"eu"}, Backend: "missing"}},
+		Default:  "server1",
+	}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `argumentRoutes[0].rules[0]: unknown backend "missing"`) {
+		t.Errorf("Expected an unknown backend error, got %v", err)
+	}
+}
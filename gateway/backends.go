@@ -0,0 +1,119 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BackendInitError describes a failure to initialize a connection to a backend.
+// RequestedProtocolVersion is the version the gateway asked for; the failed handshake
+// negotiated none.
+type BackendInitError struct {
+	Backend                  string
+	URL                      string
+	RequestedProtocolVersion string
+	Err                      error
+}
+
+func (e *BackendInitError) Error() string {
+	return fmt.Sprintf("backend %s (%s, requested protocol %s): %v", e.Backend, e.URL, e.RequestedProtocolVersion, e.Err)
+}
+
+func (e *BackendInitError) Unwrap() error {
+	return e.Err
+}
+
+// clientSessionKey is the context key for the client session a backend connection is made for
+type clientSessionKey struct{}
+
+// connectBackend creates and initializes a client connection to a backend, choosing
+// a replica when the backend has several.
+// wrap, if set, is applied to the mcp-go transport before the client is created.
+// The backend's warmup requests are sent before the connection is returned.
+func (g *Gateway) connectBackend(ctx context.Context, backend BackendConfig, clientName string, wrap func(transport.Interface) transport.Interface) (BackendTransport, *mcp.InitializeResult, error) {
+	backendClient, serverInfo, err := g.dialBackend(ctx, backend, clientName, wrap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g.warmUp(ctx, backend, backendClient)
+	return backendClient, serverInfo, nil
+}
+
+// dialBackend is connectBackend without the warmup requests
+func (g *Gateway) dialBackend(ctx context.Context, backend BackendConfig, clientName string, wrap func(transport.Interface) transport.Interface) (BackendTransport, *mcp.InitializeResult, error) {
+	wrap = g.wrapOutputSchemaValidation(backend, wrap)
+	if len(g.replicas[backend.Name]) > 0 {
+		return g.connectReplica(ctx, backend, clientName, wrap)
+	}
+	return g.connectBackendTransport(ctx, backend, clientName, wrap)
+}
+
+// dialBackendTransport makes a single attempt at connectBackendTransport
+func (g *Gateway) dialBackendTransport(ctx context.Context, backend BackendConfig, clientName string, wrap func(transport.Interface) transport.Interface) (BackendTransport, *mcp.InitializeResult, error) {
+	protocolVersion := mcp.LATEST_PROTOCOL_VERSION
+	initErr := func(err error) error {
+		return &BackendInitError{
+			Backend:                  backend.Name,
+			URL:                      backend.URL,
+			RequestedProtocolVersion: protocolVersion,
+			Err:                      err,
+		}
+	}
+
+	factory, err := lookupTransport(backend.transportName())
+	if err != nil {
+		return nil, nil, initErr(err)
+	}
+
+	clientSessionID, _ := ctx.Value(clientSessionKey{}).(string)
+	httpClient, err := g.newBackendHTTPClient(backend, clientSessionID)
+	if err != nil {
+		return nil, nil, initErr(fmt.Errorf("failed to create HTTP client: %w", err))
+	}
+
+	backendClient, err := factory(ctx, backend, TransportOptions{HTTPClient: httpClient, Wrap: wrap, SessionID: clientSessionID})
+	if err != nil {
+		return nil, nil, initErr(err)
+	}
+
+	// Initialize with timeout
+	initCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = protocolVersion
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    clientName,
+		Version: "1.0.0",
+	}
+	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
+
+	serverInfo, err := backendClient.Initialize(initCtx, initRequest)
+	if err != nil {
+		backendClient.Close()
+		return nil, nil, initErr(fmt.Errorf("initialize failed: %w", err))
+	}
+
+	return backendClient, serverInfo, nil
+}
+
+// BackendURLs returns the URLs of all configured backends, including every replica
+func (g *Gateway) BackendURLs() []string {
+	urls := make([]string, 0, len(g.config.Backends))
+	for _, backend := range g.config.Backends {
+		if backend.Stdio != nil {
+			urls = append(urls, stdioTransport+":"+backend.Stdio.Command)
+		} else if len(backend.Replicas) == 0 {
+			urls = append(urls, backend.URL)
+		}
+		for _, replica := range backend.Replicas {
+			urls = append(urls, replica.URL)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestBackendInitErrorsAggregated verifies that every failing backend is reported at startup
+func TestBackendInitErrorsAggregated(t *testing.T) {
+	healthy := newStubBackend(t, "Healthy Server", textTool(mcp.NewTool("echo"), "ok"))
+
+	// A backend that rejects every request as unauthorized
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	t.Cleanup(rejecting.Close)
+
+	// A backend that isn't listening at all
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := unreachable.URL
+	unreachable.Close()
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "healthy", URL: healthy.URL},
+		{Name: "rejecting", URL: rejecting.URL},
+		{Name: "unreachable", URL: unreachableURL},
+	}
+
+	gateway := newGateway(config)
+	err := gateway.initializeBackends()
+	if err == nil {
+		t.Fatal("Expected initialization to fail")
+	}
+
+	message := err.Error()
+	for _, expected := range []string{"rejecting", rejecting.URL, "unreachable", unreachableURL, "requested protocol " + mcp.LATEST_PROTOCOL_VERSION} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected error to mention %q, got: %s", expected, message)
+		}
+	}
+	if strings.Contains(message, "backend healthy") {
+		t.Errorf("Healthy backend should not be reported, got: %s", message)
+	}
+
+	var initErr *BackendInitError
+	if !errors.As(err, &initErr) {
+		t.Fatalf("Expected a BackendInitError in the chain, got %T", err)
+	}
+}
@@ -0,0 +1,235 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BatchingConfig coalesces rapid calls of a backend's batchable tools into one
+// JSON-RPC batch request. Only enable it for backends that accept JSON-RPC batches.
+type BatchingConfig struct {
+	// Tools lists backend (unprefixed) tool names that may be batched; path.Match
+	// globs are supported. Batching is off when empty.
+	Tools []string `yaml:"tools"`
+	// Window is how long the first call of a batch waits for others (default 5ms)
+	Window time.Duration `yaml:"window"`
+	// MaxSize sends a batch as soon as it holds this many calls (default 20)
+	MaxSize int `yaml:"maxSize"`
+}
+
+// enabled reports whether any tools are batchable
+func (c BatchingConfig) enabled() bool {
+	return len(c.Tools) > 0
+}
+
+// window returns how long calls are collected before a batch is sent
+func (c BatchingConfig) window() time.Duration {
+	if c.Window == 0 {
+		return 5 * time.Millisecond
+	}
+	return c.Window
+}
+
+// maxSize returns the largest number of calls sent in one batch
+func (c BatchingConfig) maxSize() int {
+	if c.MaxSize == 0 {
+		return 20
+	}
+	return c.MaxSize
+}
+
+// Validate checks the batching settings for a backend
+func (c BatchingConfig) Validate(backend BackendConfig) error {
+	if !c.enabled() {
+		return nil
+	}
+	if backend.transportName() != defaultTransport || len(backend.Replicas) > 0 {
+		return fmt.Errorf("batching requires the %s transport and a single url", defaultTransport)
+	}
+	if c.Window < 0 || c.MaxSize < 0 {
+		return fmt.Errorf("batching settings must not be negative")
+	}
+	return validatePatterns(c.Tools)
+}
+
+// batchedCall is a tools/call request waiting for its batch to be sent
+type batchedCall struct {
+	request transport.JSONRPCRequest
+	done    chan batchedResult
+}
+
+// batchedResult is a batched call's response, demultiplexed from the batch
+type batchedResult struct {
+	response *transport.JSONRPCResponse
+	err      error
+}
+
+// batchingTransport collects tools/call requests for batchable tools over a short
+// window and sends them to the backend as a single JSON-RPC batch. Other requests,
+// and batches of one, go through the wrapped transport unchanged.
+type batchingTransport struct {
+	transport.Interface
+	backend    BackendConfig
+	httpClient *http.Client
+
+	lock    sync.Mutex
+	pending []*batchedCall
+	timer   *time.Timer
+}
+
+// newBatchingTransport wraps a backend's streamable HTTP transport with call batching
+func newBatchingTransport(next transport.Interface, backend BackendConfig, httpClient *http.Client) *batchingTransport {
+	return &batchingTransport{
+		Interface:  next,
+		backend:    backend,
+		httpClient: httpClient,
+	}
+}
+
+// SendRequest queues calls of batchable tools and waits for their batch's response
+func (t *batchingTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if request.Method != string(mcp.MethodToolsCall) || !t.batchable(request) {
+		return t.Interface.SendRequest(ctx, request)
+	}
+
+	call := &batchedCall{request: request, done: make(chan batchedResult, 1)}
+	t.enqueue(call)
+
+	select {
+	case result := <-call.done:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// batchable reports whether the request calls one of the backend's batchable tools
+func (t *batchingTransport) batchable(request transport.JSONRPCRequest) bool {
+	data, err := json.Marshal(request.Params)
+	if err != nil {
+		return false
+	}
+	var params struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return false
+	}
+	return matchesAny(t.backend.Batching.Tools, params.Name)
+}
+
+// enqueue adds a call to the pending batch, sending it when full or when the window ends
+func (t *batchingTransport) enqueue(call *batchedCall) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.pending = append(t.pending, call)
+	if len(t.pending) >= t.backend.Batching.maxSize() {
+		if t.timer != nil {
+			t.timer.Stop()
+		}
+		go t.send(t.takeLocked())
+		return
+	}
+	if len(t.pending) == 1 {
+		t.timer = time.AfterFunc(t.backend.Batching.window(), t.flush)
+	}
+}
+
+// flush sends the pending batch when its window ends
+func (t *batchingTransport) flush() {
+	t.lock.Lock()
+	calls := t.takeLocked()
+	t.lock.Unlock()
+
+	if len(calls) > 0 {
+		t.send(calls)
+	}
+}
+
+// takeLocked removes and returns the pending calls; t.lock must be held
+func (t *batchingTransport) takeLocked() []*batchedCall {
+	calls := t.pending
+	t.pending = nil
+	return calls
+}
+
+// send sends the calls, as a batch when there are several, and delivers each response
+func (t *batchingTransport) send(calls []*batchedCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.backend.Timeouts.request())
+	defer cancel()
+
+	if len(calls) == 1 {
+		response, err := t.Interface.SendRequest(ctx, calls[0].request)
+		calls[0].done <- batchedResult{response: response, err: err}
+		return
+	}
+
+	log.Printf("🔧 Sending %d batched tool calls to %s", len(calls), t.backend.Name)
+	responses, err := t.sendBatch(ctx, calls)
+	for _, call := range calls {
+		if err != nil {
+			call.done <- batchedResult{err: err}
+			continue
+		}
+		response, ok := responses[call.request.ID.String()]
+		if !ok {
+			call.done <- batchedResult{err: fmt.Errorf("batch response from %s has no response to request %s", t.backend.Name, call.request.ID.String())}
+			continue
+		}
+		call.done <- batchedResult{response: response}
+	}
+}
+
+// sendBatch posts the calls as one JSON-RPC batch and returns the responses by request ID
+func (t *batchingTransport) sendBatch(ctx context.Context, calls []*batchedCall) (map[string]*transport.JSONRPCResponse, error) {
+	requests := make([]transport.JSONRPCRequest, len(calls))
+	for i, call := range calls {
+		requests[i] = call.request
+	}
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.backend.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if tracker, ok := t.Interface.(sessionTracker); ok && tracker.GetSessionId() != "" {
+		req.Header.Set("Mcp-Session-Id", tracker.GetSessionId())
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("batch request to %s failed with HTTP %d", t.backend.Name, resp.StatusCode)
+	}
+
+	var batch []transport.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("invalid batch response from %s: %w", t.backend.Name, err)
+	}
+	responses := make(map[string]*transport.JSONRPCResponse, len(batch))
+	for i := range batch {
+		responses[batch[i].ID.String()] = &batch[i]
+	}
+	return responses, nil
+}
@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newBatchingBackend serves an echo tool and accepts JSON-RPC batches, which mcp-go's
+// server doesn't, by handling each batched message separately. It counts batch requests
+// and unbatched tools/call requests.
+func newBatchingBackend(t *testing.T) (*httptest.Server, *atomic.Int64, *atomic.Int64) {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Batching", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo", mcp.WithString("message")),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(fmt.Sprint(req.GetArguments()["message"])), nil
+		})
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	var batches, singles atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var messages []json.RawMessage
+		if json.Unmarshal(body, &messages) != nil {
+			var message struct {
+				Method string `json:"method"`
+			}
+			if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodToolsCall) {
+				singles.Add(1)
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			streamable.ServeHTTP(w, r)
+			return
+		}
+
+		batches.Add(1)
+		responses := make([]json.RawMessage, 0, len(messages))
+		for _, message := range messages {
+			single := r.Clone(r.Context())
+			single.Body = io.NopCloser(bytes.NewReader(message))
+			recorder := httptest.NewRecorder()
+			streamable.ServeHTTP(recorder, single)
+			responses = append(responses, bytes.TrimSpace(recorder.Body.Bytes()))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	}))
+	t.Cleanup(backend.Close)
+	return backend, &batches, &singles
+}
+
+// TestBatchingCoalescesRapidCalls verifies calls of a batchable tool made within the
+// window reach the backend as one batch and each client gets its own result
+func TestBatchingCoalescesRapidCalls(t *testing.T) {
+	backend, batches, singles := newBatchingBackend(t)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:     "server1",
+		URL:      backend.URL,
+		Batching: BatchingConfig{Tools: []string{"echo"}, Window: 100 * time.Millisecond},
+	}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid config, got %v", err)
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Connect the session to the backend before the rapid calls
+	callTool(t, mcpClient, "server1-echo", map[string]interface{}{"message": "warm"})
+	singles.Store(0)
+
+	const calls = 5
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			message := fmt.Sprintf("call %d", i)
+			result := callTool(t, mcpClient, "server1-echo", map[string]interface{}{"message": message})
+			if text := extractTextFromResult(result); text != message {
+				t.Errorf("Expected %q, got %q", message, text)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if batches.Load() != 1 || singles.Load() != 0 {
+		t.Errorf("Expected %d calls in 1 batch, got %d batches and %d unbatched calls", calls, batches.Load(), singles.Load())
+	}
+}
+
+// TestBatchingValidation verifies batching is rejected for backends that can't accept batches
+func TestBatchingValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:     "server1",
+		Replicas: []ReplicaConfig{{URL: "http://localhost:1"}},
+		Batching: BatchingConfig{Tools: []string{"echo"}},
+	}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected batching with replicas to be rejected")
+	}
+}
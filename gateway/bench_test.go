@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callsPerSession is the number of tool calls each session makes per benchmark iteration
+const callsPerSession = 10
+
+// BenchmarkGatewayToolCalls drives concurrent sessions through the gateway against in-process
+// stub backends, reporting throughput and p50/p95 call latency.
+//
+//	go test -run '^$' -bench BenchmarkGatewayToolCalls
+func BenchmarkGatewayToolCalls(b *testing.B) {
+	for _, sessions := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("sessions=%d", sessions), func(b *testing.B) {
+			benchmarkToolCalls(b, sessions)
+		})
+	}
+}
+
+func benchmarkToolCalls(b *testing.B, sessions int) {
+	// The gateway logs every request; silence it so logging doesn't dominate the numbers
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	backend1 := newStubBackend(b, "Bench Server 1", textTool(mcp.NewTool("echo"), "one"))
+	backend2 := newStubBackend(b, "Bench Server 2", textTool(mcp.NewTool("echo"), "two"))
+
+	_, gatewayURL := startTestGateway(b, DefaultConfig(), backend1, backend2)
+
+	// Create sessions and warm their backend connections before timing
+	clients := make([]*client.Client, sessions)
+	for i := range clients {
+		clients[i] = newTestClient(b, gatewayURL)
+		callTool(b, clients[i], "server1-echo", nil)
+		callTool(b, clients[i], "server2-echo", nil)
+	}
+
+	latencies := make([]time.Duration, 0, b.N*sessions*callsPerSession)
+	var latenciesLock sync.Mutex
+	var failures atomic.Int64
+
+	b.ResetTimer()
+	started := time.Now()
+	for i := 0; i < b.N; i++ {
+		var wg sync.WaitGroup
+		for _, sessionClient := range clients {
+			wg.Add(1)
+			go func(c *client.Client) {
+				defer wg.Done()
+				local := make([]time.Duration, 0, callsPerSession)
+				for call := 0; call < callsPerSession; call++ {
+					toolName := "server1-echo"
+					if call%2 == 1 {
+						toolName = "server2-echo"
+					}
+					req := mcp.CallToolRequest{}
+					req.Params.Name = toolName
+					callStarted := time.Now()
+					if _, err := c.CallTool(context.Background(), req); err != nil {
+						failures.Add(1)
+					}
+					local = append(local, time.Since(callStarted))
+				}
+				latenciesLock.Lock()
+				latencies = append(latencies, local...)
+				latenciesLock.Unlock()
+			}(sessionClient)
+		}
+		wg.Wait()
+	}
+	elapsed := time.Since(started)
+	b.StopTimer()
+
+	if n := failures.Load(); n > 0 {
+		b.Fatalf("%d tool calls failed during benchmark", n)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b.ReportMetric(float64(len(latencies))/elapsed.Seconds(), "calls/s")
+	b.ReportMetric(percentile(latencies, 0.50).Seconds()*1000, "p50-ms")
+	b.ReportMetric(percentile(latencies, 0.95).Seconds()*1000, "p95-ms")
+}
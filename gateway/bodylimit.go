@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+)
+
+// Default limit on client request bodies
+const defaultMaxRequestBodyBytes = 4 << 20
+
+// maxRequestBodyBytes returns the client request body limit
+func (c *Config) maxRequestBodyBytes() int64 {
+	if c.MaxRequestBodyBytes > 0 {
+		return c.MaxRequestBodyBytes
+	}
+	return defaultMaxRequestBodyBytes
+}
+
+// bodyLimitMiddleware rejects client requests whose body exceeds the limit with 413.
+// A declared Content-Length over the limit is rejected without reading the body;
+// otherwise at most limit+1 bytes are read, so chunked bodies are bounded too.
+func (g *Gateway) bodyLimitMiddleware(next http.Handler) http.Handler {
+	limit := g.config.maxRequestBodyBytes()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength > limit {
+			g.rejectLargeBody(w, r.ContentLength, limit)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) > limit {
+			g.rejectLargeBody(w, -1, limit)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rejectLargeBody responds 413; size is -1 when the body had no declared length
+func (g *Gateway) rejectLargeBody(w http.ResponseWriter, size, limit int64) {
+	if size >= 0 {
+		log.Printf("❌ Rejected %d byte request body (limit %d)", size, limit)
+	} else {
+		log.Printf("❌ Rejected request body over %d bytes", limit)
+	}
+	// The rest of the body is never read, so don't reuse the connection
+	w.Header().Set("Connection", "close")
+	http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+}
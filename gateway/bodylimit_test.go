@@ -0,0 +1,45 @@
+package gateway
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestRequestBodyLimit verifies oversized request bodies get 413, with or without a Content-Length
+func TestRequestBodyLimit(t *testing.T) {
+	backend := newStubBackend(t, "Echo", textTool(mcp.NewTool("echo"), "ok"))
+
+	config := DefaultConfig()
+	config.MaxRequestBodyBytes = 1024
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	// Requests under the limit work as usual
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); text != "ok" {
+		t.Fatalf("Expected a normal call to succeed, got %q", text)
+	}
+
+	oversized := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"padding":"` + strings.Repeat("x", 2048) + `"}}`
+	for name, body := range map[string]io.Reader{
+		"declared length": strings.NewReader(oversized),
+		// Hiding the type makes the client send the body chunked, without a length
+		"chunked": io.MultiReader(strings.NewReader(oversized)),
+	} {
+		req, _ := http.NewRequest(http.MethodPost, gatewayURL, body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s: expected 413, got %d", name, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ChaosConfig injects synthetic failures into backend tool calls to test client
+// resilience and the gateway's circuit breaker and retries. It is for testing only.
+type ChaosConfig struct {
+	// Enabled must be set explicitly (the gateway's -chaos flag); it can't be
+	// turned on from a config file, so a stray chaos section is inert
+	Enabled bool `yaml:"-"`
+	// Backends configures fault injection by backend name
+	Backends map[string]BackendChaosConfig `yaml:"backends"`
+}
+
+// BackendChaosConfig selects the faults injected into calls to a backend
+type BackendChaosConfig struct {
+	// ErrorFraction of calls fail without reaching the backend, between 0 and 1
+	ErrorFraction float64 `yaml:"errorFraction"`
+	// DelayFraction of calls are held for Delay before being forwarded, between 0 and 1
+	DelayFraction float64 `yaml:"delayFraction"`
+	// Delay added to delayed calls; it counts towards the request timeout
+	Delay time.Duration `yaml:"delay"`
+}
+
+// Validate checks the fractions and that every configured backend exists
+func (c ChaosConfig) Validate(backends []BackendConfig) error {
+	for name, chaos := range c.Backends {
+		found := false
+		for _, backend := range backends {
+			found = found || backend.Name == name
+		}
+		if !found {
+			return fmt.Errorf("chaos.backends.%s: unknown backend", name)
+		}
+		if chaos.ErrorFraction < 0 || chaos.ErrorFraction > 1 || chaos.DelayFraction < 0 || chaos.DelayFraction > 1 {
+			return fmt.Errorf("chaos.backends.%s: fractions must be between 0 and 1", name)
+		}
+		if chaos.DelayFraction > 0 && chaos.Delay <= 0 {
+			return fmt.Errorf("chaos.backends.%s: delay must be positive", name)
+		}
+	}
+	return nil
+}
+
+// ChaosError is a failure injected by chaos testing
+type ChaosError struct {
+	Backend string
+}
+
+func (e *ChaosError) Error() string {
+	return fmt.Sprintf("chaos: injected failure calling %s", e.Backend)
+}
+
+// chaosInjector injects the configured faults into backend tool calls
+type chaosInjector struct {
+	backends map[string]BackendChaosConfig
+
+	// Injected fault counts
+	errors atomic.Int64
+	delays atomic.Int64
+}
+
+// newChaosInjector returns nil unless chaos testing is enabled
+func newChaosInjector(config ChaosConfig) *chaosInjector {
+	if !config.Enabled {
+		return nil
+	}
+	for name, chaos := range config.Backends {
+		log.Printf("⚠️ CHAOS TESTING: failing %.0f%% and delaying %.0f%% (by %s) of calls to %s",
+			chaos.ErrorFraction*100, chaos.DelayFraction*100, chaos.Delay, name)
+	}
+	return &chaosInjector{backends: config.Backends}
+}
+
+// callTool calls a backend tool, first injecting the backend's configured faults
+func (c *chaosInjector) callTool(ctx context.Context, backend string, backendClient BackendTransport, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if c == nil {
+		return backendClient.CallTool(ctx, req)
+	}
+	chaos, ok := c.backends[backend]
+	if !ok {
+		return backendClient.CallTool(ctx, req)
+	}
+
+	if rand.Float64() < chaos.DelayFraction {
+		c.delays.Add(1)
+		select {
+		case <-time.After(chaos.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if rand.Float64() < chaos.ErrorFraction {
+		c.errors.Add(1)
+		return nil, &ChaosError{Backend: backend}
+	}
+	return backendClient.CallTool(ctx, req)
+}
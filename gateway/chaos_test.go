@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// countingTransport is a backend transport that counts tool calls
+type countingTransport struct {
+	calls int
+}
+
+func (c *countingTransport) Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error) {
+	return &mcp.InitializeResult{}, nil
+}
+
+func (c *countingTransport) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{}, nil
+}
+
+func (c *countingTransport) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	c.calls++
+	return mcp.NewToolResultText("ok"), nil
+}
+
+func (c *countingTransport) Close() error { return nil }
+
+// TestChaosFractions verifies the configured error and delay fractions are approximately honored
+func TestChaosFractions(t *testing.T) {
+	const calls = 20000
+	chaos := newChaosInjector(ChaosConfig{
+		Enabled: true,
+		Backends: map[string]BackendChaosConfig{
+			"server1": {ErrorFraction: 0.25, DelayFraction: 0.1, Delay: time.Nanosecond},
+		},
+	})
+
+	backendClient := &countingTransport{}
+	failed := 0
+	for range calls {
+		_, err := chaos.callTool(context.Background(), "server1", backendClient, mcp.CallToolRequest{})
+		var chaosErr *ChaosError
+		if errors.As(err, &chaosErr) {
+			failed++
+		} else if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	approx := func(name string, got int64, fraction float64) {
+		if rate := float64(got) / calls; math.Abs(rate-fraction) > 0.02 {
+			t.Errorf("Expected about %.0f%% %s, got %.1f%%", fraction*100, name, rate*100)
+		}
+	}
+	approx("errors", int64(failed), 0.25)
+	approx("delays", chaos.delays.Load(), 0.1)
+	if chaos.errors.Load() != int64(failed) || backendClient.calls != calls-failed {
+		t.Errorf("Expected %d forwarded calls, got %d", calls-failed, backendClient.calls)
+	}
+
+	// Other backends are untouched
+	backendClient.calls = 0
+	for range 100 {
+		if _, err := chaos.callTool(context.Background(), "server2", backendClient, mcp.CallToolRequest{}); err != nil {
+			t.Fatalf("Unexpected error for a backend without chaos: %v", err)
+		}
+	}
+	if backendClient.calls != 100 {
+		t.Errorf("Expected all 100 calls forwarded, got %d", backendClient.calls)
+	}
+}
+
+// TestChaosOpensCircuit verifies injected failures count against the circuit breaker,
+// and that a chaos config without the explicit flag is inert
+func TestChaosOpensCircuit(t *testing.T) {
+	backend := newStubBackend(t, "Stable", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.Chaos = ChaosConfig{Backends: map[string]BackendChaosConfig{"server1": {ErrorFraction: 1}}}
+	config.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	if text := extractTextFromResult(callTool(t, newTestClient(t, gatewayURL), "server1-echo", nil)); text != "hello" {
+		t.Fatalf("Expected chaos to be off without the flag, got %q", text)
+	}
+
+	config = DefaultConfig()
+	config.Chaos = ChaosConfig{Enabled: true, Backends: map[string]BackendChaosConfig{"server1": {ErrorFraction: 1}}}
+	config.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute}
+	_, gatewayURL = startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	for range 2 {
+		if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); !strings.Contains(text, "chaos: injected failure") {
+			t.Fatalf("Expected an injected failure, got %q", text)
+		}
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); !strings.Contains(text, "circuit open") {
+		t.Errorf("Expected the circuit to open after injected failures, got %q", text)
+	}
+}
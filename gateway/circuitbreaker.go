@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Default time a circuit stays open before allowing a trial call
+const defaultCircuitOpenDuration = 30 * time.Second
+
+// CircuitBreakerConfig stops routing calls to a backend after repeated failures
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit; 0 disables
+	FailureThreshold int `yaml:"failureThreshold"`
+	// OpenDuration is how long the circuit stays open before a trial call (default 30s)
+	OpenDuration time.Duration `yaml:"openDuration"`
+	// ProbeWithHealthCheck makes the half-open trial the backend's health check rather
+	// than the next client call, which is only sent once the probe succeeds
+	ProbeWithHealthCheck bool `yaml:"probeWithHealthCheck"`
+}
+
+// circuitBreaker tracks consecutive call failures for a single backend
+type circuitBreaker struct {
+	backend      string
+	threshold    int
+	openDuration time.Duration
+
+	lock          sync.Mutex
+	failures      int
+	openedAt      time.Time
+	trialInFlight bool
+}
+
+// newCircuitBreaker creates a circuit breaker for a backend
+func newCircuitBreaker(backend string, config CircuitBreakerConfig) *circuitBreaker {
+	openDuration := config.OpenDuration
+	if openDuration == 0 {
+		openDuration = defaultCircuitOpenDuration
+	}
+	return &circuitBreaker{
+		backend:      backend,
+		threshold:    config.FailureThreshold,
+		openDuration: openDuration,
+	}
+}
+
+// allow reports whether a call may be sent. Once the open duration has passed a
+// single trial call is let through (half-open) to probe the backend.
+func (b *circuitBreaker) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.threshold == 0 || b.failures < b.threshold {
+		return true
+	}
+	if time.Since(b.openedAt) >= b.openDuration && !b.trialInFlight {
+		b.trialInFlight = true
+		return true
+	}
+	return false
+}
+
+// allowProbed is allow with a health probe as the half-open trial: once the open duration
+// has passed, probe runs in place of the call and the call is let through only if the
+// probe succeeds. Calls arriving while the probe runs are rejected.
+func (b *circuitBreaker) allowProbed(ctx context.Context, probe func(context.Context) error) bool {
+	b.lock.Lock()
+	if b.threshold == 0 || b.failures < b.threshold {
+		b.lock.Unlock()
+		return true
+	}
+	if time.Since(b.openedAt) < b.openDuration || b.trialInFlight {
+		b.lock.Unlock()
+		return false
+	}
+	b.trialInFlight = true
+	b.lock.Unlock()
+
+	log.Printf("🔧 Circuit for %s half-open, probing its health", b.backend)
+	if err := probe(ctx); err != nil {
+		log.Printf("❌ Half-open probe of %s failed, circuit stays open: %v", b.backend, err)
+		b.recordFailure()
+		return false
+	}
+	b.recordSuccess()
+	return true
+}
+
+// recordSuccess closes the circuit
+func (b *circuitBreaker) recordSuccess() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.threshold > 0 && b.failures >= b.threshold {
+		log.Printf("✅ Circuit for %s closed", b.backend)
+	}
+	b.failures = 0
+	b.trialInFlight = false
+}
+
+// recordFailure counts a failed call, opening the circuit at the threshold
+func (b *circuitBreaker) recordFailure() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			log.Printf("⚠️ Circuit for %s opened after %d consecutive failures", b.backend, b.failures)
+		}
+		b.openedAt = time.Now()
+	}
+}
+
+// release gives up a call let through by allow without recording an outcome, such as
+// one its client abandoned, so another call can be the half-open trial
+func (b *circuitBreaker) release() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.trialInFlight = false
+}
+
+// failureCount returns the number of consecutive failures
+func (b *circuitBreaker) failureCount() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.failures
+}
+
+// isOpen reports whether the circuit is open, without claiming a trial call
+func (b *circuitBreaker) isOpen() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.threshold > 0 && b.failures >= b.threshold
+}
+
+// Circuit states reported by state
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// state reports whether the circuit is closed, open, or half-open (open, but its open
+// duration has passed so a trial call would be let through)
+func (b *circuitBreaker) state() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch {
+	case b.threshold == 0 || b.failures < b.threshold:
+		return circuitClosed
+	case time.Since(b.openedAt) >= b.openDuration:
+		return circuitHalfOpen
+	default:
+		return circuitOpen
+	}
+}
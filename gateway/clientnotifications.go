@@ -0,0 +1,184 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Client notifications handled by the gateway
+const (
+	methodNotificationInitialized      = "notifications/initialized"
+	methodNotificationCancelled        = "notifications/cancelled"
+	methodNotificationRootsListChanged = "notifications/roots/list_changed"
+)
+
+// errCancelledByClient is the cause of calls the client cancelled
+var errCancelledByClient = errors.New("request cancelled by client")
+
+// notificationTarget is implemented by backend clients whose transport can send
+// notifications to the backend
+type notificationTarget interface {
+	GetTransport() transport.Interface
+}
+
+// clientRequestKey carries the JSON-RPC id of the client request being handled
+type clientRequestKey struct{}
+
+// clientCalls tracks in-flight tool calls by client session and request id, so a
+// client's cancellation can stop them
+type clientCalls struct {
+	cancels map[string]context.CancelCauseFunc
+	lock    sync.Mutex
+}
+
+// track makes a call cancellable by its client request id; release must be called when
+// the call ends. Calls without a known request id are returned unchanged.
+func (c *clientCalls) track(ctx context.Context, sessionID string) (context.Context, func()) {
+	id, ok := ctx.Value(clientRequestKey{}).(string)
+	if !ok {
+		return ctx, func() {}
+	}
+	key := sessionID + " " + id
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	c.lock.Lock()
+	if c.cancels == nil {
+		c.cancels = make(map[string]context.CancelCauseFunc)
+	}
+	c.cancels[key] = cancel
+	c.lock.Unlock()
+
+	return ctx, func() {
+		c.lock.Lock()
+		delete(c.cancels, key)
+		c.lock.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancel stops a session's in-flight call, reporting whether there was one
+func (c *clientCalls) cancel(sessionID, id string) bool {
+	c.lock.Lock()
+	cancel, ok := c.cancels[sessionID+" "+id]
+	c.lock.Unlock()
+	if ok {
+		cancel(errCancelledByClient)
+	}
+	return ok
+}
+
+// normalizeRequestID returns a JSON-RPC id in a canonical JSON form, so ids from requests
+// and from cancellations compare equal
+func normalizeRequestID(raw any) (string, bool) {
+	if data, ok := raw.(json.RawMessage); ok {
+		if len(data) == 0 || json.Unmarshal(data, &raw) != nil {
+			return "", false
+		}
+	}
+	if raw == nil {
+		return "", false
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+// clientRequestMiddleware records the id of a session's tools/call request in its
+// context, for cancellation
+func (g *Gateway) clientRequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Mcp-Session-Id") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message jsonRPCMessage
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodToolsCall) {
+			r = r.WithContext(withClientArguments(r.Context(), message.Params))
+			if id, ok := normalizeRequestID(message.ID); ok {
+				r = r.WithContext(context.WithValue(r.Context(), clientRequestKey{}, id))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setupClientNotifications registers the handlers of client notifications. Notifications
+// get no response: the MCP server answers their POST with 202 and an empty body.
+func (g *Gateway) setupClientNotifications() {
+	g.mcpServer.AddNotificationHandler(methodNotificationInitialized, g.handleClientInitialized)
+	g.mcpServer.AddNotificationHandler(methodNotificationCancelled, g.handleClientCancelled)
+	g.mcpServer.AddNotificationHandler(methodNotificationRootsListChanged, g.forwardClientNotification)
+}
+
+// handleClientInitialized notes the client is ready. It isn't forwarded: each backend
+// session is the gateway's own and gets the gateway's initialized notification when
+// its connection is made.
+func (g *Gateway) handleClientInitialized(ctx context.Context, notification mcp.JSONRPCNotification) {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		log.Printf("✅ Client %s initialized", session.SessionID())
+	}
+}
+
+// handleClientCancelled stops the client's in-flight call with the cancelled request id
+func (g *Gateway) handleClientCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	session := server.ClientSessionFromContext(ctx)
+	id, ok := normalizeRequestID(notification.Params.AdditionalFields["requestId"])
+	if session == nil || !ok {
+		return
+	}
+	if g.clientCalls.cancel(session.SessionID(), id) {
+		log.Printf("🔧 Client %s cancelled request %s: %v", session.SessionID(), id, notification.Params.AdditionalFields["reason"])
+	}
+}
+
+// forwardClientNotification sends a client notification to each backend the client's
+// session is connected to
+func (g *Gateway) forwardClientNotification(ctx context.Context, notification mcp.JSONRPCNotification) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	g.connectionsLock.RLock()
+	connections, ok := g.clientConnections[session.SessionID()]
+	g.connectionsLock.RUnlock()
+	if !ok {
+		return
+	}
+
+	connections.lock.Lock()
+	clients := make(map[string]BackendTransport, len(connections.Clients))
+	for name, backendClient := range connections.Clients {
+		clients[name] = backendClient
+	}
+	connections.lock.Unlock()
+
+	for name, backendClient := range clients {
+		target, ok := backendClient.(notificationTarget)
+		if !ok {
+			continue
+		}
+		if err := target.GetTransport().SendNotification(ctx, notification); err != nil {
+			log.Printf("❌ Failed to forward %s to %s for client %s: %v", notification.Method, name, session.SessionID(), err)
+		}
+	}
+}
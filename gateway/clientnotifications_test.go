@@ -0,0 +1,119 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// notificationLog records the notifications a backend receives
+type notificationLog struct {
+	methods []string
+	lock    sync.Mutex
+}
+
+func (l *notificationLog) received() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.methods...)
+}
+
+// newNotifiedBackend serves an echo tool and a slow tool, recording the client
+// notifications it receives
+func newNotifiedBackend(t *testing.T, notified *notificationLog) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Notified", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	slow := slowTool("slow", 5*time.Second)
+	mcpServer.AddTool(slow.tool, slow.handler)
+	for _, method := range []string{methodNotificationInitialized, methodNotificationRootsListChanged} {
+		mcpServer.AddNotificationHandler(method, func(ctx context.Context, notification mcp.JSONRPCNotification) {
+			notified.lock.Lock()
+			notified.methods = append(notified.methods, notification.Method)
+			notified.lock.Unlock()
+		})
+	}
+	backend := httptest.NewServer(server.NewStreamableHTTPServer(mcpServer))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// postNotification sends a client notification and checks it gets 202 with no body
+func postNotification(t *testing.T, url, sessionID, body string) {
+	t.Helper()
+
+	resp := postMessage(t, url, sessionID, body)
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusAccepted || len(content) != 0 {
+		t.Errorf("Expected 202 with no response to %s, got HTTP %d %q", body, resp.StatusCode, content)
+	}
+}
+
+// TestClientNotifications verifies client notifications get no response, initialized is
+// answered by the gateway's own backend handshake and roots/list_changed reaches backends
+func TestClientNotifications(t *testing.T) {
+	var notified notificationLog
+	backend := newNotifiedBackend(t, &notified)
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{"roots":{"listChanged":true}}`)
+	postNotification(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	// The session's backend connection is made on its first call, initialized included
+	resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-echo"}}`)
+	resp.Body.Close()
+	if received := notified.received(); !containsString(received, methodNotificationInitialized) {
+		t.Errorf("Expected the backend to be sent notifications/initialized, got %v", received)
+	}
+
+	postNotification(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`)
+	if received := notified.received(); !containsString(received, methodNotificationRootsListChanged) {
+		t.Errorf("Expected roots/list_changed to be forwarded to the backend, got %v", received)
+	}
+
+	// Notifications the gateway doesn't handle get no response either
+	postNotification(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","method":"notifications/custom"}`)
+}
+
+// TestClientCancellation verifies notifications/cancelled stops the client's in-flight call
+func TestClientCancellation(t *testing.T) {
+	var notified notificationLog
+	backend := newNotifiedBackend(t, &notified)
+	gateway, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+	started := time.Now()
+	done := make(chan string, 1)
+	go func() {
+		resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":"call-7","method":"tools/call","params":{"name":"server1-slow"}}`)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		done <- string(body)
+	}()
+	waitForInFlight(t, gateway, "server1", 1)
+
+	postNotification(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-7","reason":"user aborted"}}`)
+	select {
+	case body := <-done:
+		if !strings.Contains(body, "Request cancelled by client") {
+			t.Errorf("Expected the call to be cancelled, got %s", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the cancelled call to end")
+	}
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Errorf("Expected the call to end well before the tool finished, took %s", elapsed)
+	}
+}
@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ClientVersionRule requires clients whose clientInfo.name matches Name to report a
+// clientInfo.version within [MinVersion, MaxVersion] at initialize
+type ClientVersionRule struct {
+	// Name is a clientInfo.name pattern; path.Match globs are supported
+	Name string `yaml:"name"`
+	// MinVersion is the oldest accepted version, inclusive (optional)
+	MinVersion string `yaml:"minVersion"`
+	// MaxVersion is the newest accepted version, inclusive (optional)
+	MaxVersion string `yaml:"maxVersion"`
+	// Message replaces the default upgrade message returned to rejected clients
+	Message string `yaml:"message"`
+}
+
+// validateClientVersions checks the client version rules
+func validateClientVersions(rules []ClientVersionRule) error {
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("clientVersions[%d]: name is required", i)
+		}
+		if _, err := path.Match(rule.Name, ""); err != nil {
+			return fmt.Errorf("clientVersions[%d]: invalid name pattern %q: %w", i, rule.Name, err)
+		}
+		if rule.MinVersion == "" && rule.MaxVersion == "" {
+			return fmt.Errorf("clientVersions[%d]: minVersion or maxVersion is required", i)
+		}
+		for _, version := range []string{rule.MinVersion, rule.MaxVersion} {
+			if _, ok := parseVersion(version); version != "" && !ok {
+				return fmt.Errorf("clientVersions[%d]: invalid version %q", i, version)
+			}
+		}
+	}
+	return nil
+}
+
+// parseVersion parses a dotted numeric version such as 1.2.3 or v1.2.3-beta.1,
+// ignoring any pre-release or build suffix
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// compareVersions returns -1, 0 or 1 as a is older than, equal to or newer than b;
+// missing components count as zero
+func compareVersions(a, b []int) int {
+	for i := range max(len(a), len(b)) {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// allows reports whether the rule accepts a client version; versions that can't be
+// parsed are rejected
+func (r ClientVersionRule) allows(version string) bool {
+	parsed, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	if lowest, ok := parseVersion(r.MinVersion); ok && compareVersions(parsed, lowest) < 0 {
+		return false
+	}
+	if highest, ok := parseVersion(r.MaxVersion); ok && compareVersions(parsed, highest) > 0 {
+		return false
+	}
+	return true
+}
+
+// upgradeMessage explains why a client was rejected
+func (r ClientVersionRule) upgradeMessage(client mcp.Implementation) string {
+	if r.Message != "" {
+		return r.Message
+	}
+	var required []string
+	if r.MinVersion != "" {
+		required = append(required, ">= "+r.MinVersion)
+	}
+	if r.MaxVersion != "" {
+		required = append(required, "<= "+r.MaxVersion)
+	}
+	return fmt.Sprintf("Client %s %s is not supported by this gateway: use a version %s",
+		client.Name, client.Version, strings.Join(required, " and "))
+}
+
+// clientVersionMiddleware rejects initialize requests from clients failing the first
+// client version rule matching their name
+func (g *Gateway) clientVersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := initializeRequestFromContext(r.Context())
+		if request == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client := request.ClientInfo
+		for _, rule := range g.config.ClientVersions {
+			if ok, _ := path.Match(rule.Name, client.Name); !ok {
+				continue
+			}
+			if !rule.allows(client.Version) {
+				log.Printf("❌ Rejecting client %s %s: version not allowed by rule %q", client.Name, client.Version, rule.Name)
+				writeInitializeError(w, request, http.StatusOK, mcp.INVALID_REQUEST, rule.upgradeMessage(client), map[string]any{
+					"minVersion": rule.MinVersion,
+					"maxVersion": rule.MaxVersion,
+				})
+				return
+			}
+			break
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeAs initializes a new client reporting the given clientInfo
+func initializeAs(t *testing.T, url, name, version string) error {
+	t.Helper()
+
+	httpTransport, err := transport.NewStreamableHTTP(url)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+	mcpClient := client.NewClient(httpTransport)
+	t.Cleanup(func() { mcpClient.Close() })
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: name, Version: version}
+	_, err = mcpClient.Initialize(context.Background(), initRequest)
+	return err
+}
+
+// TestClientVersionGate verifies clients older than a matching rule's minimum are
+// rejected with an upgrade message while current and unmatched clients are accepted
+func TestClientVersionGate(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.ClientVersions = []ClientVersionRule{
+		{Name: "acme-sdk*", MinVersion: "2.1.0"},
+		{Name: "legacy-cli", MaxVersion: "0.9"},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid config, got %v", err)
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	err := initializeAs(t, gatewayURL, "acme-sdk-python", "2.0.9")
+	if err == nil || !strings.Contains(err.Error(), "use a version >= 2.1.0") {
+		t.Errorf("Expected the old client to be told to upgrade, got %v", err)
+	}
+	if err := initializeAs(t, gatewayURL, "legacy-cli", "1.0.0"); err == nil {
+		t.Error("Expected a client above the maximum version to be rejected")
+	}
+
+	for _, client := range []struct{ name, version string }{
+		{"acme-sdk-python", "2.1.0"},
+		{"acme-sdk-go", "v2.10.0-beta.1"},
+		{"other-client", "0.0.1"},
+	} {
+		if err := initializeAs(t, gatewayURL, client.name, client.version); err != nil {
+			t.Errorf("Expected %s %s to be accepted, got %v", client.name, client.version, err)
+		}
+	}
+}
@@ -0,0 +1,663 @@
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the gateway configuration loaded from a YAML file
+type Config struct {
+	// Backends are the MCP servers whose tools are aggregated
+	Backends []BackendConfig `yaml:"backends"`
+
+	// PolicyGroups are named backend settings that backends reference by group
+	PolicyGroups map[string]PolicyGroup `yaml:"policyGroups"`
+
+	// ReadOnly restricts which tools may be called
+	ReadOnly ReadOnlyConfig `yaml:"readOnly"`
+
+	// ResultMeta adds backend origin details to tool results
+	ResultMeta ResultMetaConfig `yaml:"resultMeta"`
+
+	// Truncation caps the size of text content in tool results
+	Truncation TruncationConfig `yaml:"truncation"`
+
+	// Flatten turns the non-text result content of individual gateway tools into text
+	Flatten map[string]FlattenConfig `yaml:"flatten"`
+
+	// InputLimits caps the size of tool call arguments, per tool
+	InputLimits InputLimitsConfig `yaml:"inputLimits"`
+
+	// GatewayInfo adds fields from external sources to the gateway_info result
+	GatewayInfo GatewayInfoConfig `yaml:"gatewayInfo"`
+
+	// ArgumentRoutes expose tools whose calls are routed to a backend by an argument's value
+	ArgumentRoutes []ArgumentRoute `yaml:"argumentRoutes"`
+
+	// Admin enables debugging tools that reveal backend details
+	Admin AdminConfig `yaml:"admin"`
+
+	// Sessions controls client session lifecycle
+	Sessions SessionsConfig `yaml:"sessions"`
+
+	// Rewrites modify JSON fields of tool call arguments and results
+	Rewrites []RewriteRule `yaml:"rewrites"`
+
+	// CircuitBreaker stops calls to failing backends
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker"`
+
+	// Cache serves repeated tool calls from a result cache
+	Cache CacheConfig `yaml:"cache"`
+
+	// ToolPriority lists tool name patterns floated to the top of tools/list, in order
+	ToolPriority []string `yaml:"toolPriority"`
+
+	// Shadow mirrors calls of individual gateway tools to canary backends
+	Shadow map[string]ShadowConfig `yaml:"shadow"`
+
+	// FanOut bounds how many backends are contacted at once during startup and refreshes
+	FanOut FanOutConfig `yaml:"fanOut"`
+
+	// Notifications forwards backend notifications to clients with per-session limits
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// TLS serves the gateway over HTTPS
+	TLS TLSConfig `yaml:"tls"`
+
+	// Tenants are logical gateways with their own backends, selected by TLS server name (SNI)
+	Tenants map[string]TenantConfig `yaml:"tenants"`
+
+	// Gateways run several named gateway instances, each with its own backends and
+	// listen address, from one process; when set the top-level backends aren't served
+	Gateways []InstanceConfig `yaml:"gateways"`
+
+	// DefaultSchemas fills in input schemas for tools whose backend omits one
+	DefaultSchemas DefaultSchemasConfig `yaml:"defaultSchemas"`
+
+	// Admission rejects new sessions while the gateway is overloaded
+	Admission AdmissionConfig `yaml:"admission"`
+
+	// ClientVersions rejects initialize from clients whose clientInfo version is not
+	// allowed by the first rule matching their name
+	ClientVersions []ClientVersionRule `yaml:"clientVersions"`
+
+	// PartialToolLists starts and lists tools while some backends can't be queried,
+	// naming degraded backends in the tools/list _meta
+	PartialToolLists bool `yaml:"partialToolLists"`
+
+	// Startup serves clients before every backend has connected, or waits for them
+	Startup StartupConfig `yaml:"startup"`
+
+	// ProtocolCompatibility handles clients on protocol versions the gateway can't
+	// translate a backend's protocol version to
+	ProtocolCompatibility ProtocolCompatibilityConfig `yaml:"protocolCompatibility"`
+
+	// MaxRequestBodyBytes rejects larger client request bodies with 413 (default 4 MiB)
+	MaxRequestBodyBytes int64 `yaml:"maxRequestBodyBytes"`
+
+	// Server sets the client-facing server's timeouts against slow clients
+	Server ServerConfig `yaml:"server"`
+
+	// Store selects the key-value store holding gateway state such as idempotency records
+	Store StoreConfig `yaml:"store"`
+
+	// ToolErrors returns failed calls of backend tools as error results ("result",
+	// the default) or as JSON-RPC errors ("protocol")
+	ToolErrors string `yaml:"toolErrors"`
+
+	// DuplicateTools handles a backend listing the same tool name more than once:
+	// "keep-first" (default), "keep-last" or "error" (the backend's list fails)
+	DuplicateTools string `yaml:"duplicateTools"`
+
+	// AllowReplicaOverride lets a client pin a call to a replica of a replicated backend
+	// with _meta.replica, bypassing replica balancing; off by default
+	AllowReplicaOverride bool `yaml:"allowReplicaOverride"`
+
+	// NotFoundFallback retries a call once on the tool's current backend when the routed
+	// backend reports the tool not found, e.g. while backends are reconfigured; off by default
+	NotFoundFallback bool `yaml:"notFoundFallback"`
+
+	// Quotas cap how many times a session may call tools, by the session's tier
+	Quotas QuotasConfig `yaml:"quotas"`
+
+	// Annotations set MCP annotation hints of individual gateway tool names
+	Annotations map[string]ToolAnnotationsConfig `yaml:"annotations"`
+
+	// DefaultAnnotations supply annotation hints, by tool name glob, for tools whose
+	// backend doesn't declare them
+	DefaultAnnotations []DefaultAnnotationsRule `yaml:"defaultAnnotations"`
+
+	// DestructiveTools requires confirmation for, or audits, calls of destructive tools
+	DestructiveTools DestructiveToolsConfig `yaml:"destructiveTools"`
+
+	// Deprecation hides, warns about or passes through tools deprecated by their backend
+	Deprecation DeprecationConfig `yaml:"deprecation"`
+
+	// Health probes backends in the background for /readyz
+	Health HealthConfig `yaml:"health"`
+
+	// ToolNames controls how backend tools are named
+	ToolNames ToolNamesConfig `yaml:"toolNames"`
+
+	// Retries repeats tool calls that failed with a transient error
+	Retries RetriesConfig `yaml:"retries"`
+
+	// Chaos injects synthetic failures into backend calls; testing only
+	Chaos ChaosConfig `yaml:"chaos"`
+
+	// Identity sets the User-Agent and identifying headers sent to every backend
+	Identity IdentityConfig `yaml:"identity"`
+
+	// Metrics sends session and backend metrics to Prometheus, StatsD or custom sinks
+	Metrics MetricsConfig `yaml:"metrics"`
+
+	// Idempotency deduplicates retried tool calls that carry an idempotency key
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+
+	// Connections caps open backend sockets, gateway-wide and per backend
+	Connections ConnectionsConfig `yaml:"connections"`
+
+	// OutputSchemas validates tool results against the output schema their tool declares
+	OutputSchemas OutputSchemasConfig `yaml:"outputSchemas"`
+
+	// RemapRequestIDs rewrites outbound request IDs so they are unique across all
+	// client sessions (default true, or the REMAP_REQUEST_IDS environment variable)
+	RemapRequestIDs *bool `yaml:"remapRequestIDs"`
+
+	// dialControl, if set, runs on each backend socket before it connects (used by tests)
+	dialControl func(network, address string, c syscall.RawConn) error
+}
+
+// SessionsConfig configures client session resumption
+type SessionsConfig struct {
+	// ResumeGracePeriod keeps a disconnected client's backend sessions alive so it can
+	// resume by presenting its previous session ID; 0 ends sessions on disconnect
+	ResumeGracePeriod time.Duration `yaml:"resumeGracePeriod"`
+	// MaxSessions caps concurrent client sessions, including disconnected ones
+	// awaiting resumption; 0 is unlimited
+	MaxSessions int `yaml:"maxSessions"`
+	// Overflow is what a new session does at the cap: "evict" (default) ends the
+	// least recently used session, "reject" refuses the new session
+	Overflow string `yaml:"overflow"`
+	// IDs selects how session IDs are generated
+	IDs SessionIDsConfig `yaml:"ids"`
+}
+
+// AdminConfig gates administrative and debugging features
+type AdminConfig struct {
+	// Enabled exposes admin tools such as gateway_describe_backend
+	Enabled bool `yaml:"enabled"`
+	// Listen serves the admin endpoints on a separate address (e.g. 127.0.0.1:9090)
+	// instead of the main port
+	Listen string `yaml:"listen"`
+	// Pprof mounts the Go profiling endpoints under /debug/pprof/ on the admin listener
+	Pprof bool `yaml:"pprof"`
+	// DrainTimeout is how long a removed backend's in-flight calls may take to finish
+	// before they are cancelled (default 30s); a removal request can override it
+	DrainTimeout time.Duration `yaml:"drainTimeout"`
+	// RecentErrors is how many failed tool calls gateway_recent_errors remembers
+	// (default 100)
+	RecentErrors int `yaml:"recentErrors"`
+	// ToolStats is how many tools gateway_tool_stats tracks; calls to further tools
+	// aren't tracked (default 1000)
+	ToolStats int `yaml:"toolStats"`
+}
+
+// ReadOnlyConfig configures the global read-only mode
+type ReadOnlyConfig struct {
+	// Enabled rejects calls to any tool that is not read-only
+	Enabled bool `yaml:"enabled"`
+	// AllowedTools lists gateway tool names treated as read-only when the backend doesn't annotate them
+	AllowedTools []string `yaml:"allowedTools"`
+	// HideMutatingTools removes non-read-only tools from tools/list while enabled
+	HideMutatingTools bool `yaml:"hideMutatingTools"`
+}
+
+// ResultMetaConfig controls the origin annotation added to tool result _meta
+type ResultMetaConfig struct {
+	// Enabled annotates every tool result unless overridden per tool
+	Enabled bool `yaml:"enabled"`
+	// Tools overrides Enabled for individual gateway tool names
+	Tools map[string]bool `yaml:"tools"`
+}
+
+// TruncationConfig limits text content length in tool results (opt-in)
+type TruncationConfig struct {
+	// MaxTextBytes caps each text content item; 0 disables truncation
+	MaxTextBytes int `yaml:"maxTextBytes"`
+	// Tools overrides MaxTextBytes for individual gateway tool names (0 disables)
+	Tools map[string]int `yaml:"tools"`
+	// AddMeta records original lengths of truncated content in the result _meta
+	AddMeta bool `yaml:"addMeta"`
+}
+
+// BackendConfig describes a single backend MCP server
+type BackendConfig struct {
+	// Name is used as the tool prefix (e.g. server1-echo)
+	Name string `yaml:"name"`
+	// URL is the backend's streamable HTTP endpoint, or the address understood by its transport
+	URL string `yaml:"url"`
+	// Transport names a registered backend transport (default streamable-http)
+	Transport string `yaml:"transport"`
+	// Stdio runs the backend as a subprocess per connection (transport stdio)
+	Stdio *StdioConfig `yaml:"stdio"`
+	// Replicas replace URL with several endpoints tried in tier order
+	Replicas []ReplicaConfig `yaml:"replicas"`
+	// AffinityTTL limits how long a stateless backend's shared connection stays on the
+	// replica it was made to; the next call after it re-picks a replica. Sessions on
+	// stateful backends keep their replica. 0 (default) keeps the replica for the
+	// connection's lifetime.
+	AffinityTTL time.Duration `yaml:"affinityTTL"`
+	// Tools controls which of the backend's tools are exposed
+	Tools ToolPolicy `yaml:"tools"`
+	// Signing adds an HMAC signature to every outbound request
+	Signing *SigningConfig `yaml:"signing"`
+	// Timeouts configures dial, request and session idle timeouts
+	Timeouts BackendTimeouts `yaml:"timeouts"`
+	// Retries replaces the gateway-wide retries for the backend's tool calls and connections
+	Retries *RetriesConfig `yaml:"retries"`
+	// Group names a policy group supplying defaults for the settings above and for
+	// responseHeaders and identity
+	Group string `yaml:"group"`
+	// ResponseHeaders lists backend HTTP response headers copied to the client response
+	ResponseHeaders []string `yaml:"responseHeaders"`
+	// ContentType replaces the Content-Type of requests to the backend (default
+	// application/json), for backends expecting e.g. a charset parameter
+	ContentType string `yaml:"contentType"`
+	// Warmup requests are sent right after each connection to the backend is initialized
+	Warmup WarmupConfig `yaml:"warmup"`
+	// Namespace prefixes the backend's gateway tool names (default: the backend name).
+	// With toolNames.separator "/" it may have several segments, e.g. team/server1.
+	Namespace string `yaml:"namespace"`
+	// HealthCheck selects the probe used by the health prober and /readyz
+	HealthCheck HealthCheckConfig `yaml:"healthCheck"`
+	// Batching coalesces rapid calls of batchable tools into JSON-RPC batch requests
+	Batching BatchingConfig `yaml:"batching"`
+	// Identity overrides the gateway-wide identifying headers for this backend
+	Identity IdentityConfig `yaml:"identity"`
+	// DependsOn names backends that must be up before this one: they are initialized
+	// first, and this backend is unavailable while one of them is unavailable or unhealthy
+	DependsOn []string `yaml:"dependsOn"`
+	// MaxConnections caps open sockets to this backend across all client sessions;
+	// 0 is unlimited. connections.overflow applies.
+	MaxConnections int `yaml:"maxConnections"`
+	// Discovery lists the backend's tools as a single searchable discovery tool once
+	// there are more than discovery.toolThreshold of them
+	Discovery DiscoveryConfig `yaml:"discovery"`
+	// MaxMessageBytes caps each message read from the backend: a stdio line, an SSE
+	// event or a JSON response. Longer messages are discarded as they are read and fail
+	// their call; 0 (default) is unlimited.
+	MaxMessageBytes int `yaml:"maxMessageBytes"`
+}
+
+// remapRequestIDs reports whether outbound request IDs are rewritten
+func (c *Config) remapRequestIDs() bool {
+	return c.RemapRequestIDs == nil || *c.RemapRequestIDs
+}
+
+// DefaultConfig returns the configuration used when no config file is given
+func DefaultConfig() *Config {
+	remapRequestIDs := getEnv("REMAP_REQUEST_IDS", "true") == "true"
+	return &Config{
+		Backends: []BackendConfig{
+			{Name: "server1", URL: getEnv("SERVER1_URL", "http://localhost:8081")},
+			{Name: "server2", URL: getEnv("SERVER2_URL", "http://localhost:8082")},
+		},
+		RemapRequestIDs: &remapRequestIDs,
+	}
+}
+
+// LoadConfig reads a YAML config file, falling back to defaults when path is empty
+func LoadConfig(path string) (*Config, error) {
+	config := DefaultConfig()
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	// Decode strictly: unknown fields are reported with their line rather than ignored
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := checkKnownFields(&root); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if root.Kind != 0 {
+		if err := root.Decode(config); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	if err := config.ResolveGroups(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, locateConfigError(&root, err))
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, locateConfigError(&root, err))
+	}
+
+	return config, nil
+}
+
+// Validate checks the configuration for missing or conflicting values
+func (c *Config) Validate() error {
+	if len(c.Backends) == 0 {
+		return fmt.Errorf("at least one backend is required")
+	}
+
+	if c.Truncation.MaxTextBytes < 0 {
+		return fmt.Errorf("truncation.maxTextBytes must not be negative")
+	}
+	for tool, limit := range c.Truncation.Tools {
+		if limit < 0 {
+			return fmt.Errorf("truncation.tools.%s must not be negative", tool)
+		}
+	}
+
+	if err := c.InputLimits.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := compileRewriteRules(c.Rewrites); err != nil {
+		return err
+	}
+
+	if _, err := compileFlattenTemplates(c.Flatten); err != nil {
+		return err
+	}
+
+	if c.CircuitBreaker.FailureThreshold < 0 || c.CircuitBreaker.OpenDuration < 0 {
+		return fmt.Errorf("circuitBreaker settings must not be negative")
+	}
+
+	if c.Admin.Pprof && c.Admin.Listen == "" {
+		return fmt.Errorf("admin.pprof requires admin.listen")
+	}
+	if c.Admin.DrainTimeout < 0 {
+		return fmt.Errorf("admin.drainTimeout must not be negative")
+	}
+	if c.Admin.RecentErrors < 0 {
+		return fmt.Errorf("admin.recentErrors must not be negative")
+	}
+	if c.Admin.ToolStats < 0 {
+		return fmt.Errorf("admin.toolStats must not be negative")
+	}
+
+	for tool, shadow := range c.Shadow {
+		if shadow.CanaryURL == "" {
+			return fmt.Errorf("shadow.%s.canaryURL is required", tool)
+		}
+		if shadow.Fraction < 0 || shadow.Fraction > 1 {
+			return fmt.Errorf("shadow.%s.fraction must be between 0 and 1", tool)
+		}
+		if _, err := newResultComparator(shadow.Compare); err != nil {
+			return fmt.Errorf("shadow.%s.compare: %w", tool, err)
+		}
+	}
+
+	if err := validateDefaultAnnotations(c.DefaultAnnotations); err != nil {
+		return err
+	}
+	if err := c.DefaultSchemas.Validate(); err != nil {
+		return err
+	}
+	if err := c.Retries.Validate(); err != nil {
+		return err
+	}
+	if err := c.Chaos.Validate(c.Backends); err != nil {
+		return err
+	}
+	if err := c.Metrics.Validate(); err != nil {
+		return err
+	}
+	if err := c.GatewayInfo.Validate(); err != nil {
+		return err
+	}
+	if err := c.OutputSchemas.Validate(); err != nil {
+		return err
+	}
+	if err := c.Connections.Validate(); err != nil {
+		return err
+	}
+	if err := validateClientVersions(c.ClientVersions); err != nil {
+		return err
+	}
+	if err := c.validateInstances(); err != nil {
+		return err
+	}
+
+	if err := validatePatterns(c.ToolPriority); err != nil {
+		return fmt.Errorf("toolPriority: %w", err)
+	}
+
+	if err := c.Cache.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := lookupStore(c.Store.typeName()); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	for tool, idempotency := range c.Idempotency.Tools {
+		if idempotency.TTL <= 0 {
+			return fmt.Errorf("idempotency.tools.%s.ttl must be positive", tool)
+		}
+	}
+
+	switch c.ToolErrors {
+	case "", toolErrorsResult, toolErrorsProtocol:
+	default:
+		return fmt.Errorf("toolErrors must be %s or %s", toolErrorsResult, toolErrorsProtocol)
+	}
+
+	switch c.DuplicateTools {
+	case "", duplicateToolsKeepFirst, duplicateToolsKeepLast, duplicateToolsError:
+	default:
+		return fmt.Errorf("duplicateTools must be %s, %s or %s", duplicateToolsKeepFirst, duplicateToolsKeepLast, duplicateToolsError)
+	}
+
+	if err := c.Quotas.Validate(); err != nil {
+		return err
+	}
+
+	switch c.Deprecation.Mode {
+	case "", deprecationPassthrough, deprecationWarn, deprecationHide:
+	default:
+		return fmt.Errorf("deprecation.mode must be %s, %s or %s", deprecationPassthrough, deprecationWarn, deprecationHide)
+	}
+
+	if c.Health.Interval < 0 {
+		return fmt.Errorf("health.interval must not be negative")
+	}
+	if err := c.Health.Scoring.Validate(); err != nil {
+		return err
+	}
+
+	if c.Admission.MaxActiveCalls < 0 || c.Admission.MaxQueueDepth < 0 || c.Admission.RetryAfter < 0 {
+		return fmt.Errorf("admission settings must not be negative")
+	}
+	if c.Admission.InitializeRate.PerSecond < 0 || c.Admission.InitializeRate.Burst < 0 {
+		return fmt.Errorf("admission.initializeRate settings must not be negative")
+	}
+
+	if c.MaxRequestBodyBytes < 0 {
+		return fmt.Errorf("maxRequestBodyBytes must not be negative")
+	}
+	if err := c.Server.Validate(); err != nil {
+		return err
+	}
+
+	if c.FanOut.Concurrency < 0 {
+		return fmt.Errorf("fanOut.concurrency must not be negative")
+	}
+
+	if c.Notifications.RatePerSecond < 0 || c.Notifications.Burst < 0 || c.Notifications.BufferSize < 0 || c.Notifications.MaxAge < 0 {
+		return fmt.Errorf("notifications settings must not be negative")
+	}
+	if c.Notifications.LongPoll.Enabled && !c.Notifications.Forward {
+		return fmt.Errorf("notifications.longPoll requires notifications.forward")
+	}
+	if c.Notifications.LongPoll.MaxWait < 0 || c.Notifications.LongPoll.BufferSize < 0 {
+		return fmt.Errorf("notifications.longPoll settings must not be negative")
+	}
+	switch c.Notifications.Overflow {
+	case "", overflowDropOldest, overflowDropNewest:
+	default:
+		return fmt.Errorf("notifications.overflow must be %s or %s", overflowDropOldest, overflowDropNewest)
+	}
+
+	if c.Sessions.ResumeGracePeriod < 0 {
+		return fmt.Errorf("sessions.resumeGracePeriod must not be negative")
+	}
+	if c.Sessions.MaxSessions < 0 {
+		return fmt.Errorf("sessions.maxSessions must not be negative")
+	}
+	switch c.Sessions.Overflow {
+	case "", sessionOverflowEvict, sessionOverflowReject:
+	default:
+		return fmt.Errorf("sessions.overflow must be %s or %s", sessionOverflowEvict, sessionOverflowReject)
+	}
+	if err := c.Sessions.IDs.Validate(); err != nil {
+		return err
+	}
+	if err := c.Startup.Validate(); err != nil {
+		return err
+	}
+	if err := c.ProtocolCompatibility.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateBackends(c.Backends); err != nil {
+		return err
+	}
+	if err := validateToolNames(c.ToolNames, c.Backends); err != nil {
+		return err
+	}
+	if err := validateArgumentRoutes(c.ArgumentRoutes, c.Backends); err != nil {
+		return err
+	}
+
+	if len(c.Tenants) > 0 && (c.TLS.CertFile == "" || c.TLS.KeyFile == "") {
+		return fmt.Errorf("tenants require tls.certFile and tls.keyFile")
+	}
+	for host, tenant := range c.Tenants {
+		if len(tenant.Backends) == 0 {
+			return fmt.Errorf("tenants.%s: at least one backend is required", host)
+		}
+		if err := validateBackends(tenant.Backends); err != nil {
+			return fmt.Errorf("tenants.%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// validateBackends checks each backend's settings and that names are unique
+func validateBackends(backends []BackendConfig) error {
+	seen := make(map[string]bool)
+	for i, backend := range backends {
+		if backend.Name == "" {
+			return fmt.Errorf("backends[%d]: name is required", i)
+		}
+		if backend.transportName() == stdioTransport {
+			if backend.Stdio == nil || backend.Stdio.Command == "" {
+				return fmt.Errorf("backend %s: stdio.command is required", backend.Name)
+			}
+			if _, err := backend.Stdio.parseEnv(); err != nil {
+				return fmt.Errorf("backend %s: %w", backend.Name, err)
+			}
+		} else if backend.URL == "" && len(backend.Replicas) == 0 {
+			return fmt.Errorf("backend %s: url is required", backend.Name)
+		}
+		if backend.URL != "" && len(backend.Replicas) > 0 {
+			return fmt.Errorf("backend %s: url and replicas are mutually exclusive", backend.Name)
+		}
+		replicaNames := make(map[string]bool)
+		for j, replica := range backend.Replicas {
+			if replica.URL == "" {
+				return fmt.Errorf("backend %s: replicas[%d].url is required", backend.Name, j)
+			}
+			if replica.Name != "" {
+				if replicaNames[replica.Name] {
+					return fmt.Errorf("backend %s: duplicate replica name %s", backend.Name, replica.Name)
+				}
+				replicaNames[replica.Name] = true
+			}
+			if replica.MaxSessions < 0 {
+				return fmt.Errorf("backend %s: replicas[%d].maxSessions must not be negative", backend.Name, j)
+			}
+		}
+		if backend.AffinityTTL < 0 {
+			return fmt.Errorf("backend %s: affinityTTL must not be negative", backend.Name)
+		}
+		if backend.AffinityTTL > 0 && len(backend.Replicas) == 0 {
+			return fmt.Errorf("backend %s: affinityTTL requires replicas", backend.Name)
+		}
+		if backend.MaxConnections < 0 {
+			return fmt.Errorf("backend %s: maxConnections must not be negative", backend.Name)
+		}
+		if seen[backend.Name] {
+			return fmt.Errorf("backend %s: duplicate name", backend.Name)
+		}
+		if _, err := lookupTransport(backend.transportName()); err != nil {
+			return fmt.Errorf("backend %s: %w", backend.Name, err)
+		}
+		if err := backend.Tools.Validate(); err != nil {
+			return fmt.Errorf("backend %s: %w", backend.Name, err)
+		}
+		if backend.Timeouts.Dial < 0 || backend.Timeouts.Request < 0 || backend.Timeouts.Idle < 0 {
+			return fmt.Errorf("backend %s: timeouts must not be negative", backend.Name)
+		}
+		if backend.Signing != nil {
+			if err := backend.Signing.Validate(); err != nil {
+				return fmt.Errorf("backend %s: %w", backend.Name, err)
+			}
+		}
+		if backend.Retries != nil {
+			if err := backend.Retries.Validate(); err != nil {
+				return fmt.Errorf("backend %s: %w", backend.Name, err)
+			}
+		}
+		if err := validateResponseHeaders(backend.ResponseHeaders); err != nil {
+			return fmt.Errorf("backend %s: %w", backend.Name, err)
+		}
+		if backend.ContentType != "" {
+			if err := validateContentType(backend.ContentType); err != nil {
+				return fmt.Errorf("backend %s: %w", backend.Name, err)
+			}
+		}
+		if backend.MaxMessageBytes < 0 {
+			return fmt.Errorf("backend %s: maxMessageBytes must not be negative", backend.Name)
+		}
+		for j, call := range backend.Warmup.Calls {
+			if call.Tool == "" {
+				return fmt.Errorf("backend %s: warmup.calls[%d].tool is required", backend.Name, j)
+			}
+		}
+		switch backend.HealthCheck.probe() {
+		case healthProbeInitialize, healthProbeToolsList:
+		case healthProbeTool:
+			if backend.HealthCheck.Tool == "" {
+				return fmt.Errorf("backend %s: healthCheck.tool is required for the tool probe", backend.Name)
+			}
+		default:
+			return fmt.Errorf("backend %s: healthCheck.probe must be %s, %s or %s",
+				backend.Name, healthProbeInitialize, healthProbeToolsList, healthProbeTool)
+		}
+		if err := backend.Batching.Validate(backend); err != nil {
+			return fmt.Errorf("backend %s: %w", backend.Name, err)
+		}
+		if backend.Discovery.ToolThreshold < 0 {
+			return fmt.Errorf("backend %s: discovery.toolThreshold must not be negative", backend.Name)
+		}
+		seen[backend.Name] = true
+	}
+	return validateDependencies(backends)
+}
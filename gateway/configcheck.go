@@ -0,0 +1,277 @@
+package gateway
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is a config problem located in the YAML file, when its line is known
+type ConfigError struct {
+	// Path is the field path, like backends[0].timeouts.dial
+	Path string
+	// Line is the YAML line of the field, or 0 when unknown
+	Line int
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// checkKnownFields reports every mapping key in the document that doesn't name a field
+// of the type it is decoded into, with a suggestion when the key looks like a typo
+func checkKnownFields(root *yaml.Node) error {
+	var problems []string
+	var check func(node *yaml.Node, t reflect.Type, path string)
+	check = func(node *yaml.Node, t reflect.Type, path string) {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch {
+		case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+			check(node.Content[0], t, path)
+		case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+			for i, item := range node.Content {
+				check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+			}
+		case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				check(node.Content[i+1], t.Elem(), joinConfigPath(path, node.Content[i].Value))
+			}
+		case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+			fields := yamlFields(t)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				if key.Value == "<<" {
+					continue
+				}
+				fieldPath := joinConfigPath(path, key.Value)
+				field, ok := fields[key.Value]
+				if !ok {
+					problems = append(problems, (&ConfigError{
+						Path: fieldPath,
+						Line: key.Line,
+						Err:  fmt.Errorf("%s: unknown field%s", fieldPath, suggestField(key.Value, fields)),
+					}).Error())
+					continue
+				}
+				check(node.Content[i+1], field, fieldPath)
+			}
+		}
+	}
+	check(root, reflect.TypeOf(Config{}), "")
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// yamlFields maps the YAML keys of a struct to their field types, including the keys of
+// inlined structs
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if options == "inline" && field.Type.Kind() == reflect.Struct {
+			for name, inlined := range yamlFields(field.Type) {
+				fields[name] = inlined
+			}
+			continue
+		}
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggestField returns a "did you mean" hint naming the known field closest to key
+func suggestField(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.EqualFold(name, key) {
+			return fmt.Sprintf(" (did you mean %s?)", name)
+		}
+		if distance := editDistance(strings.ToLower(key), strings.ToLower(name)); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", best)
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// joinConfigPath appends a key to a field path
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Leading field path of a validation error, and the backend form "backend NAME: ..."
+var (
+	configErrorPath    = regexp.MustCompile(`^([A-Za-z][\w-]*(?:\[\d+\])*(?:\.[\w-]+(?:\[\d+\])*)*)[: ]`)
+	configErrorBackend = regexp.MustCompile(`^backend ([^:]+): `)
+)
+
+// locateConfigError attaches the YAML line of the field a validation error names, as
+// closely as the document allows; errors naming no field are returned unchanged
+func locateConfigError(root *yaml.Node, err error) error {
+	message := err.Error()
+	var segments []string
+	if match := configErrorBackend.FindStringSubmatch(message); match != nil {
+		index := backendIndex(root, match[1])
+		if index < 0 {
+			return err
+		}
+		segments = []string{"backends", strconv.Itoa(index)}
+		message = message[len(match[0]):]
+	}
+	if match := configErrorPath.FindStringSubmatch(message); match != nil {
+		segments = append(segments, splitConfigPath(match[1])...)
+	}
+
+	node, found := document(root), 0
+	for _, segment := range segments {
+		next := childNode(node, segment)
+		if next == nil {
+			break
+		}
+		node = next
+		found++
+	}
+	if found == 0 || node == nil {
+		return err
+	}
+	return &ConfigError{Path: formatConfigPath(segments[:found]), Line: node.Line, Err: err}
+}
+
+// formatConfigPath joins path segments, writing sequence indexes as [i]
+func formatConfigPath(segments []string) string {
+	var path string
+	for _, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil && path != "" {
+			path += "[" + segment + "]"
+		} else {
+			path = joinConfigPath(path, segment)
+		}
+	}
+	return path
+}
+
+// splitConfigPath splits a field path like backends[0].url into its segments
+func splitConfigPath(path string) []string {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		segments = append(segments, name)
+		for rest != "" {
+			var index string
+			index, rest, _ = strings.Cut(rest, "]")
+			segments = append(segments, index)
+			rest = strings.TrimPrefix(rest, "[")
+		}
+	}
+	return segments
+}
+
+// document returns the top-level node of a parsed document
+func document(root *yaml.Node) *yaml.Node {
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return root.Content[0]
+	}
+	return root
+}
+
+// childNode returns the value under a mapping key, or the item at a sequence index.
+// Lines of mapping values point at their key.
+func childNode(node *yaml.Node, segment string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				value := *node.Content[i+1]
+				value.Line = node.Content[i].Line
+				return &value
+			}
+		}
+	case yaml.SequenceNode:
+		if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(node.Content) {
+			return node.Content[index]
+		}
+	}
+	return nil
+}
+
+// backendIndex returns the position of the named backend in the document, or -1
+func backendIndex(root *yaml.Node, name string) int {
+	backends := childNode(document(root), "backends")
+	if backends == nil || backends.Kind != yaml.SequenceNode {
+		return -1
+	}
+	for i, backend := range backends.Content {
+		if value := childNode(backend, "name"); value != nil && value.Value == name {
+			return i
+		}
+	}
+	return -1
+}
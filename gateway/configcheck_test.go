@@ -0,0 +1,123 @@
+package gateway
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestConfigErrors verifies malformed configs are reported with the field path and the
+// YAML line of the problem
+func TestConfigErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		yaml     string
+		wantErrs []string
+	}{
+		"unknown backend field": {
+			yaml: `
+backends:
+  - name: server1
+    urll: http://localhost:8081
+`,
+			wantErrs: []string{"line 4: backends[0].urll: unknown field (did you mean url?)"},
+		},
+		"unknown fields are all reported": {
+			yaml: `
+backends:
+  - name: server1
+    url: http://localhost:8081
+    timeouts:
+      dail: 2s
+notifcations:
+  forward: true
+`,
+			wantErrs: []string{
+				"line 6: backends[0].timeouts.dail: unknown field (did you mean dial?)",
+				"line 7: notifcations: unknown field (did you mean notifications?)",
+			},
+		},
+		"unknown field under a map": {
+			yaml: `
+backends:
+  - name: server1
+    url: http://localhost:8081
+cache:
+  tools:
+    server1-echo:
+      ttl: 1m
+      size: 10
+`,
+			wantErrs: []string{"line 9: cache.tools.server1-echo.size: unknown field"},
+		},
+		"invalid enum": {
+			yaml: `
+backends:
+  - name: server1
+    url: http://localhost:8081
+connections:
+  overflow: drop
+`,
+			wantErrs: []string{`line 6: connections.overflow must be "queue" or "reject", got "drop"`},
+		},
+		"missing required field": {
+			yaml: `
+backends:
+  - name: server1
+    url: http://localhost:8081
+  - name: server2
+    transport: http
+`,
+			wantErrs: []string{"line 5: backend server2: url is required"},
+		},
+		"invalid backend field": {
+			yaml: `
+backends:
+  - name: server1
+    url: http://localhost:8081
+    maxConnections: -1
+`,
+			wantErrs: []string{"line 5: backend server1: maxConnections must not be negative"},
+		},
+		"wrong type": {
+			yaml: `
+backends:
+  - name: server1
+    url: http://localhost:8081
+    maxConnections: lots
+`,
+			wantErrs: []string{"line 5: cannot unmarshal !!str `lots` into int"},
+		},
+		"no field to locate": {
+			yaml: `
+backends: []
+`,
+			wantErrs: []string{"invalid config", "at least one backend is required"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadTestConfig(t, tc.yaml)
+			if err == nil {
+				t.Fatalf("Expected an error")
+			}
+			for _, want := range tc.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error containing %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// TestConfigErrorLocation verifies located validation errors keep the underlying error
+func TestConfigErrorLocation(t *testing.T) {
+	_, err := loadTestConfig(t, `
+backends:
+  - name: a
+    url: http://a
+    dependsOn: [missing]
+`)
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Path != "backends[0].dependsOn" || configErr.Line != 5 {
+		t.Fatalf("Expected the error at backends[0].dependsOn on line 5, got %+v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// JSON Schema dialect of the config schema
+const configSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Durations are strings in Go duration syntax, e.g. 1m30s
+const durationPattern = `^-?(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// ConfigSchema returns the JSON Schema of the YAML config file, for editor completion and
+// validation. It is generated from the config structs the same way LoadConfig checks
+// field names, so it can't drift from what the gateway accepts. It describes the file's
+// shape only: constraints between values are left to Validate.
+func ConfigSchema() ([]byte, error) {
+	defs := make(map[string]any)
+	root := typeSchema(reflect.TypeOf(Config{}), defs)
+	root["$schema"] = configSchemaDialect
+	root["title"] = "MCP Gateway config"
+	root["$defs"] = defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// typeSchema returns the schema of values decoded into t. Named structs are described
+// once under defs and referenced, so shared and recursive types stay small.
+func typeSchema(t reflect.Type, defs map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), defs)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, defs)
+		}
+		if _, ok := defs[t.Name()]; !ok {
+			// Reserve the name first in case the struct refers to itself
+			defs[t.Name()] = nil
+			defs[t.Name()] = structSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		// Interfaces take any value
+		return map[string]any{}
+	}
+}
+
+// structSchema describes a struct as an object with exactly its YAML fields
+func structSchema(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	for name, field := range yamlFields(t) {
+		properties[name] = typeSchema(field, defs)
+	}
+	return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+}
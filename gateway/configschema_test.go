@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaValidator checks a decoded YAML document against the subset of JSON Schema the
+// config schema uses
+type schemaValidator struct {
+	defs map[string]any
+}
+
+// validate appends a problem for each value that doesn't match its schema
+func (v schemaValidator) validate(schema map[string]any, value any, path string, problems []string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, _ := v.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		return v.validate(def, value, path, problems)
+	}
+	if types, ok := schema["type"]; ok && !matchesSchemaType(types, value) {
+		return append(problems, fmt.Sprintf("%s: %v is not of type %v", path, value, types))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if text, isString := value.(string); isString && !regexp.MustCompile(pattern).MatchString(text) {
+			problems = append(problems, fmt.Sprintf("%s: %q does not match %s", path, text, pattern))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for key, item := range value {
+			if property, ok := properties[key].(map[string]any); ok {
+				problems = v.validate(property, item, path+"."+key, problems)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, fmt.Sprintf("%s: unknown property %s", path, key))
+				}
+			case map[string]any:
+				problems = v.validate(additional, item, path+"."+key, problems)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				problems = v.validate(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+	return problems
+}
+
+// matchesSchemaType reports whether a YAML value has one of the schema's types
+func matchesSchemaType(types any, value any) bool {
+	var names []string
+	switch types := types.(type) {
+	case string:
+		names = []string{types}
+	case []any:
+		for _, name := range types {
+			names = append(names, name.(string))
+		}
+	}
+	for _, name := range names {
+		switch value.(type) {
+		case map[string]any:
+			if name == "object" {
+				return true
+			}
+		case []any:
+			if name == "array" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case int:
+			if name == "integer" || name == "number" {
+				return true
+			}
+		case float64:
+			if name == "number" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateWithSchema validates a YAML config document against the generated schema
+func validateWithSchema(t *testing.T, document string) []string {
+	t.Helper()
+
+	data, err := ConfigSchema()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	defs, _ := schema["$defs"].(map[string]any)
+
+	var value any
+	if err := yaml.Unmarshal([]byte(document), &value); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	return schemaValidator{defs: defs}.validate(schema, value, "config", nil)
+}
+
+// Config exercising nested structs, lists, maps, pointers, durations and inlined fields
+const schemaGoodConfig = `
+backends:
+  - name: server1
+    url: http://localhost:8081/mcp
+    timeouts:
+      dial: 5s
+      request: 1m30s
+    tools:
+      default: deny
+      except: ["echo", "get_*"]
+    responseHeaders: [X-Request-Id]
+  - name: server2
+    url: http://localhost:8082/mcp
+    namespace: second
+cache:
+  tools:
+    server1-echo:
+      ttl: 30s
+admin:
+  enabled: true
+  drainTimeout: "0"
+annotations:
+  server1-echo:
+    readOnlyHint: true
+defaultAnnotations:
+  - tool: "*-get_*"
+    readOnlyHint: true
+    openWorldHint: false
+notFoundFallback: true
+`
+
+// TestConfigSchemaAcceptsGoodConfig verifies a config the gateway loads is valid under
+// the schema
+func TestConfigSchemaAcceptsGoodConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	if err := os.WriteFile(path, []byte(schemaGoodConfig), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(path); err != nil {
+		t.Fatalf("Expected the config to load, got %v", err)
+	}
+
+	if problems := validateWithSchema(t, schemaGoodConfig); len(problems) > 0 {
+		t.Errorf("Expected the config to be valid under the schema, got:\n%s", strings.Join(problems, "\n"))
+	}
+}
+
+// TestConfigSchemaRejectsBadConfig verifies unknown fields and wrong types are reported
+func TestConfigSchemaRejectsBadConfig(t *testing.T) {
+	problems := validateWithSchema(t, `
+backends:
+  - name: server1
+    urll: http://localhost:8081/mcp
+    timeouts:
+      request: soon
+      dial: 5000
+admin:
+  enabled: "yes"
+cache:
+  tools:
+    server1-echo: 30s
+notFoundFallback: 1
+`)
+	joined := strings.Join(problems, "\n")
+	for _, expected := range []string{
+		"config.backends[0]: unknown property urll",
+		"config.backends[0].timeouts.request",
+		"config.backends[0].timeouts.dial",
+		"config.admin.enabled",
+		"config.cache.tools.server1-echo",
+		"config.notFoundFallback",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected a problem for %s, got:\n%s", expected, joined)
+		}
+	}
+}
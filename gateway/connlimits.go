@@ -0,0 +1,239 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Connection cap overflow policies
+const (
+	connectionsQueue  = "queue"
+	connectionsReject = "reject"
+)
+
+// How often a queued connection retries reclaiming idle sockets
+const connectionsRecheckInterval = 50 * time.Millisecond
+
+// ConnectionsConfig caps the backend sockets the gateway keeps open. Each client session
+// has its own backend connections, so without a cap open sockets grow with sessions.
+// This bounds sockets, not in-flight calls: a socket counts while it is open, idle or not.
+type ConnectionsConfig struct {
+	// MaxOpen caps open sockets to all backends together; 0 is unlimited.
+	// Backends cap their own with maxConnections.
+	MaxOpen int `yaml:"maxOpen"`
+	// Overflow is what a new connection does at a cap: "queue" (default) waits for a
+	// socket to close, within the dial timeout; "reject" fails at once
+	Overflow string `yaml:"overflow"`
+}
+
+// overflow returns the overflow policy, defaulting to queue
+func (c ConnectionsConfig) overflow() string {
+	if c.Overflow == "" {
+		return connectionsQueue
+	}
+	return c.Overflow
+}
+
+// Validate checks the caps and the overflow policy
+func (c ConnectionsConfig) Validate() error {
+	if c.MaxOpen < 0 {
+		return fmt.Errorf("connections.maxOpen must not be negative")
+	}
+	switch c.overflow() {
+	case connectionsQueue, connectionsReject:
+		return nil
+	default:
+		return fmt.Errorf("connections.overflow must be %q or %q, got %q", connectionsQueue, connectionsReject, c.Overflow)
+	}
+}
+
+// ConnectionLimitError is returned when a backend connection can't be opened because
+// a connection cap is reached
+type ConnectionLimitError struct {
+	Backend string
+	Limit   int
+	// Global is set when the gateway-wide cap, rather than the backend's, was reached
+	Global bool
+}
+
+func (e *ConnectionLimitError) Error() string {
+	if e.Global {
+		return fmt.Sprintf("connection limit reached: %d backend connections open", e.Limit)
+	}
+	return fmt.Sprintf("connection limit reached for %s: %d connections open", e.Backend, e.Limit)
+}
+
+// connectionLimiter counts open backend sockets against the global and per-backend caps
+type connectionLimiter struct {
+	maxOpen    int
+	maxBackend map[string]int
+	overflow   string
+	record     func(backend string, open int)
+
+	total int
+	open  map[string]int
+	// transports with open sockets, per backend, so idle sockets can be reclaimed
+	transports map[string]map[*http.Transport]int
+	// released is closed and replaced whenever a socket closes
+	released chan struct{}
+	lock     sync.Mutex
+}
+
+// newConnectionLimiter creates a limiter for the configured caps; record is told the
+// open socket count of a backend whenever it changes, and must not block
+func newConnectionLimiter(config *Config, record func(backend string, open int)) *connectionLimiter {
+	limiter := &connectionLimiter{
+		maxOpen:    config.Connections.MaxOpen,
+		maxBackend: make(map[string]int),
+		overflow:   config.Connections.overflow(),
+		record:     record,
+		open:       make(map[string]int),
+		transports: make(map[string]map[*http.Transport]int),
+		released:   make(chan struct{}),
+	}
+	for _, backend := range config.Backends {
+		if backend.MaxConnections > 0 {
+			limiter.maxBackend[backend.Name] = backend.MaxConnections
+		}
+	}
+	return limiter
+}
+
+// limit counts the sockets a backend transport dials against the caps; queued
+// connections wait up to the backend's dial timeout
+func (l *connectionLimiter) limit(t *http.Transport, backendConfig BackendConfig) {
+	backend := backendConfig.Name
+	dial := t.DialContext
+	t.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		queueCtx, cancel := context.WithTimeout(ctx, backendConfig.Timeouts.dial())
+		err := l.acquire(queueCtx, backend, t)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			l.release(backend, t)
+			return nil, err
+		}
+		return &limitedConn{Conn: conn, release: func() { l.release(backend, t) }}, nil
+	}
+}
+
+// acquire takes a socket slot for a backend. At a cap, idle keep-alive sockets (which
+// count against it, including those of ended sessions) are closed first; if that frees
+// no slot the connection queues or fails.
+func (l *connectionLimiter) acquire(ctx context.Context, backend string, t *http.Transport) error {
+	reclaimed, queued := false, false
+	for {
+		l.lock.Lock()
+		limitErr := l.full(backend)
+		if limitErr == nil {
+			l.total++
+			l.open[backend]++
+			if l.transports[backend] == nil {
+				l.transports[backend] = make(map[*http.Transport]int)
+			}
+			l.transports[backend][t]++
+			l.record(backend, l.open[backend])
+			l.lock.Unlock()
+			return nil
+		}
+		if !reclaimed {
+			reclaim := l.reclaimable(backend, limitErr.Global)
+			l.lock.Unlock()
+			for _, idle := range reclaim {
+				idle.CloseIdleConnections()
+			}
+			reclaimed = true
+			continue
+		}
+		if l.overflow == connectionsReject {
+			l.lock.Unlock()
+			log.Printf("❌ Rejecting %s connection: %v", backend, limitErr)
+			return limitErr
+		}
+		released := l.released
+		l.lock.Unlock()
+
+		if !queued {
+			log.Printf("⚠️ Queueing %s connection: %v", backend, limitErr)
+			queued = true
+		}
+		select {
+		case <-released:
+			reclaimed = false
+		case <-time.After(connectionsRecheckInterval):
+			// A busy socket may have gone idle since: reclaim again
+			reclaimed = false
+		case <-ctx.Done():
+			return fmt.Errorf("%w (gave up waiting: %v)", limitErr, ctx.Err())
+		}
+	}
+}
+
+// full returns the cap a new socket to backend would exceed, if any.
+// The caller must hold l.lock.
+func (l *connectionLimiter) full(backend string) *ConnectionLimitError {
+	if limit, ok := l.maxBackend[backend]; ok && l.open[backend] >= limit {
+		return &ConnectionLimitError{Backend: backend, Limit: limit}
+	}
+	if l.maxOpen > 0 && l.total >= l.maxOpen {
+		return &ConnectionLimitError{Backend: backend, Limit: l.maxOpen, Global: true}
+	}
+	return nil
+}
+
+// reclaimable returns the transports whose idle sockets could free a slot: the backend's
+// own for its cap, every backend's for the global cap. The caller must hold l.lock.
+func (l *connectionLimiter) reclaimable(backend string, global bool) []*http.Transport {
+	var transports []*http.Transport
+	for name, open := range l.transports {
+		if !global && name != backend {
+			continue
+		}
+		for t := range open {
+			transports = append(transports, t)
+		}
+	}
+	return transports
+}
+
+// release frees a socket slot and wakes queued connections
+func (l *connectionLimiter) release(backend string, t *http.Transport) {
+	l.lock.Lock()
+	l.total--
+	l.open[backend]--
+	if l.transports[backend][t]--; l.transports[backend][t] <= 0 {
+		delete(l.transports[backend], t)
+	}
+	l.record(backend, l.open[backend])
+	close(l.released)
+	l.released = make(chan struct{})
+	l.lock.Unlock()
+}
+
+// openConnections returns the number of open sockets to a backend
+func (l *connectionLimiter) openConnections(backend string) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.open[backend]
+}
+
+// limitedConn releases its slot when closed
+type limitedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
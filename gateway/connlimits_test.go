@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestConnectionCap verifies a backend's connection cap holds across client sessions:
+// with reject, a session needing a new socket while the only one is busy fails; with
+// queue, it waits for the socket to free up
+func TestConnectionCap(t *testing.T) {
+	for _, overflow := range []string{connectionsReject, connectionsQueue} {
+		t.Run(overflow, func(t *testing.T) {
+			backend := newStubBackend(t, "Backend", slowTool("slow", 300*time.Millisecond), textTool(mcp.NewTool("echo"), "hello"))
+
+			config := DefaultConfig()
+			config.Metrics.Sinks = []MetricsSinkConfig{{Type: "recording"}}
+			config.Connections.Overflow = overflow
+			config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, MaxConnections: 1}}
+			if err := config.Validate(); err != nil {
+				t.Fatalf("Expected a valid config, got %v", err)
+			}
+			gateway, gatewayURL := startTestGateway(t, config)
+
+			first := newTestClient(t, gatewayURL)
+			second := newTestClient(t, gatewayURL)
+
+			// The first session's call keeps the only socket busy
+			done := make(chan string, 1)
+			go func() {
+				done <- extractTextFromResult(callTool(t, first, "server1-slow", nil))
+			}()
+			waitForInFlight(t, gateway, "server1", 1)
+			time.Sleep(50 * time.Millisecond)
+
+			result := callTool(t, second, "server1-echo", nil)
+			text := extractTextFromResult(result)
+			if overflow == connectionsReject {
+				if !result.IsError || !strings.Contains(text, "connection limit reached for server1") {
+					t.Errorf("Expected the second session to be rejected, got %+v", result)
+				}
+			} else if result.IsError || text != "hello" {
+				t.Errorf("Expected the second session to wait for a socket, got %+v", result)
+			}
+
+			if text := <-done; text != "done" {
+				t.Errorf("Expected the first session's call to complete, got %q", text)
+			}
+			if open := gateway.connectionLimiter.openConnections("server1"); open > 1 {
+				t.Errorf("Expected at most 1 open connection, got %d", open)
+			}
+			if recorded := gateway.metrics.(*recordingSink).recorded(); !containsString(recorded, "gauge backend_connections_open backend=server1") {
+				t.Errorf("Expected open connection gauges, got %v", recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package gateway
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// validateContentType checks a backend's request content type is a media type with
+// optional parameters, such as application/json; charset=utf-8
+func validateContentType(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid contentType %q: %w", contentType, err)
+	}
+	if kind, subtype, ok := strings.Cut(mediaType, "/"); !ok || kind == "" || subtype == "" {
+		return fmt.Errorf("invalid contentType %q: expected type/subtype", contentType)
+	}
+	return nil
+}
+
+// contentTypeRoundTripper replaces the Content-Type of outbound requests that have a body
+type contentTypeRoundTripper struct {
+	next        http.RoundTripper
+	contentType string
+}
+
+func (c *contentTypeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Content-Type") == "" {
+		return c.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Type", c.contentType)
+	return c.next.RoundTrip(req)
+}
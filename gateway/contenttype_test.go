@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestBackendContentType verifies a backend receives requests with its configured content type
+func TestBackendContentType(t *testing.T) {
+	stub := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	// The picky backend requires a charset; the stub itself only accepts application/json
+	var lock sync.Mutex
+	var received []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			lock.Lock()
+			received = append(received, r.Header.Get("Content-Type"))
+			lock.Unlock()
+			if mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" && params["charset"] == "utf-8" {
+				r.Header.Set("Content-Type", "application/json")
+			}
+		}
+		stub.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, ContentType: "application/json; charset=utf-8"}}
+	_, gatewayURL := startTestGateway(t, config)
+
+	if text := extractTextFromResult(callTool(t, newTestClient(t, gatewayURL), "server1-echo", nil)); text != "hello" {
+		t.Fatalf("Expected the call to succeed, got %q", text)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(received) == 0 {
+		t.Fatal("Expected the backend to receive requests")
+	}
+	for _, contentType := range received {
+		if contentType != "application/json; charset=utf-8" {
+			t.Errorf("Expected the configured content type, got %q", contentType)
+		}
+	}
+}
+
+// TestBackendContentTypeValidation verifies content types that aren't media types are rejected
+func TestBackendContentTypeValidation(t *testing.T) {
+	for _, contentType := range []string{"json", "application/json; charset", "/json"} {
+		config := DefaultConfig()
+		config.Backends = []BackendConfig{{Name: "server1", URL: "http://localhost:1", ContentType: contentType}}
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected content type %q to be rejected", contentType)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// permissiveSchema accepts any arguments object
+var permissiveSchema = json.RawMessage(`{"type":"object","additionalProperties":true}`)
+
+// DefaultSchemasConfig fills in input schemas for tools whose backend omits one (opt-in)
+type DefaultSchemasConfig struct {
+	// Synthesize gives schema-less tools a permissive schema accepting any arguments object
+	Synthesize bool `yaml:"synthesize"`
+	// Tools supplies the schema for individual schema-less gateway tool names, taking
+	// precedence over Synthesize
+	Tools map[string]map[string]interface{} `yaml:"tools"`
+}
+
+// Validate checks that configured schemas are JSON objects of type object
+func (c DefaultSchemasConfig) Validate() error {
+	for tool, schema := range c.Tools {
+		if schema["type"] != "object" {
+			return fmt.Errorf("defaultSchemas.tools.%s: type must be object", tool)
+		}
+		if _, err := json.Marshal(schema); err != nil {
+			return fmt.Errorf("defaultSchemas.tools.%s: %w", tool, err)
+		}
+	}
+	return nil
+}
+
+// hasInputSchema reports whether the backend supplied an input schema for the tool
+func hasInputSchema(tool mcp.Tool) bool {
+	return tool.RawInputSchema != nil || tool.InputSchema.Type != ""
+}
+
+// applyDefaultSchema gives a schema-less tool its configured or synthesized schema.
+// Tools that already have a schema are returned unchanged.
+func (g *Gateway) applyDefaultSchema(tool mcp.Tool) mcp.Tool {
+	if hasInputSchema(tool) {
+		return tool
+	}
+
+	config := g.config.DefaultSchemas
+	if schema, ok := config.Tools[tool.Name]; ok {
+		raw, err := json.Marshal(schema)
+		if err == nil {
+			log.Printf("🔧 Using configured input schema for %s", tool.Name)
+			tool.RawInputSchema = raw
+			return tool
+		}
+		log.Printf("❌ Invalid configured input schema for %s: %v", tool.Name, err)
+	}
+
+	if config.Synthesize {
+		log.Printf("🔧 Synthesizing a permissive input schema for %s", tool.Name)
+		tool.RawInputSchema = permissiveSchema
+		return tool
+	}
+
+	log.Printf("⚠️ Tool %s has no input schema", tool.Name)
+	return tool
+}
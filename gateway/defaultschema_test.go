@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestDefaultSchemas verifies schema-less tools get a synthesized or configured schema
+// while tools with a schema are untouched
+func TestDefaultSchemas(t *testing.T) {
+	// A zero InputSchema is what the gateway sees when a backend omits inputSchema
+	backend := newStubBackend(t, "Legacy",
+		textTool(mcp.Tool{Name: "legacy"}, "legacy"),
+		textTool(mcp.Tool{Name: "configured"}, "configured"),
+		textTool(mcp.NewTool("typed", mcp.WithString("name")), "typed"),
+	)
+
+	config := DefaultConfig()
+	config.DefaultSchemas = DefaultSchemasConfig{
+		Synthesize: true,
+		Tools: map[string]map[string]interface{}{
+			"server1-configured": {
+				"type":       "object",
+				"properties": map[string]interface{}{"query": map[string]interface{}{"type": "string"}},
+			},
+		},
+	}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	assertSchema := func(name string, expected string) {
+		t.Helper()
+		tool, ok := gateway.lookupTool(name)
+		if !ok {
+			t.Fatalf("Tool %s not found", name)
+		}
+		assertJSON(t, tool.RawInputSchema, expected)
+	}
+	assertSchema("server1-legacy", `{"type":"object","additionalProperties":true}`)
+	assertSchema("server1-configured", `{"type":"object","properties":{"query":{"type":"string"}}}`)
+
+	typed, _ := gateway.lookupTool("server1-typed")
+	if typed.RawInputSchema != nil || typed.InputSchema.Properties["name"] == nil {
+		t.Errorf("Expected the typed tool's schema to be untouched, got %+v", typed.InputSchema)
+	}
+
+	// Clients see an object schema for every tool
+	mcpClient := newTestClient(t, gatewayURL)
+	result, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		if tool.InputSchema.Type != "object" {
+			t.Errorf("Expected %s to have an object input schema, got %q", tool.Name, tool.InputSchema.Type)
+		}
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-legacy", map[string]interface{}{"anything": 1})); text != "legacy" {
+		t.Errorf("Expected the schema-less tool to be callable, got %q", text)
+	}
+}
+
+// TestDefaultSchemasOptIn verifies schema-less tools are left alone unless enabled
+func TestDefaultSchemasOptIn(t *testing.T) {
+	backend := newStubBackend(t, "Legacy", textTool(mcp.Tool{Name: "legacy"}, "legacy"))
+	gateway, _ := startTestGateway(t, DefaultConfig(), backend)
+
+	tool, _ := gateway.lookupTool("server1-legacy")
+	if hasInputSchema(tool) {
+		t.Errorf("Expected no schema to be synthesized by default, got %s", tool.RawInputSchema)
+	}
+}
@@ -0,0 +1,124 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependencyError reports a backend that is unavailable because a backend it depends
+// on is unavailable or unhealthy
+type DependencyError struct {
+	Backend    string
+	Dependency string
+	Reason     string
+}
+
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("backend %s is unavailable: dependency %s is %s", e.Backend, e.Dependency, e.Reason)
+}
+
+// validateDependencies checks that backends depend only on other known backends and
+// that the dependencies have no cycles
+func validateDependencies(backends []BackendConfig) error {
+	byName := make(map[string]BackendConfig, len(backends))
+	for _, backend := range backends {
+		byName[backend.Name] = backend
+	}
+	for _, backend := range backends {
+		for _, dependency := range backend.DependsOn {
+			if dependency == backend.Name {
+				return fmt.Errorf("backend %s: depends on itself", backend.Name)
+			}
+			if _, ok := byName[dependency]; !ok {
+				return fmt.Errorf("backend %s: dependsOn unknown backend %s", backend.Name, dependency)
+			}
+		}
+	}
+
+	// Depth-first search; a backend reached again while on the path closes a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(backends))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			return fmt.Errorf("backend dependency cycle: %s -> %s", strings.Join(path[start:], " -> "), name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range byName[name].DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, backend := range backends {
+		if err := visit(backend.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startupWaves groups backends so that each backend comes in a later wave than its
+// dependencies; backends keep their config order within a wave. Dependencies must
+// have been validated.
+func startupWaves(backends []BackendConfig) [][]BackendConfig {
+	byName := make(map[string]BackendConfig, len(backends))
+	for _, backend := range backends {
+		byName[backend.Name] = backend
+	}
+
+	depths := make(map[string]int, len(backends))
+	var depth func(backend BackendConfig) int
+	depth = func(backend BackendConfig) int {
+		if d, ok := depths[backend.Name]; ok {
+			return d
+		}
+		d := 0
+		for _, dependency := range backend.DependsOn {
+			d = max(d, depth(byName[dependency])+1)
+		}
+		depths[backend.Name] = d
+		return d
+	}
+
+	var waves [][]BackendConfig
+	for _, backend := range backends {
+		d := depth(backend)
+		for len(waves) <= d {
+			waves = append(waves, nil)
+		}
+		waves[d] = append(waves[d], backend)
+	}
+	return waves
+}
+
+// unhealthyDependency returns an error when one of a backend's dependencies failed its
+// latest health probe. Health outcomes already account for transitive dependencies.
+func (g *Gateway) unhealthyDependency(backend BackendConfig) error {
+	if len(backend.DependsOn) == 0 {
+		return nil
+	}
+	health := g.healthResults()
+	for _, dependency := range backend.DependsOn {
+		if result, ok := health[dependency]; ok && !result.Healthy {
+			return &DependencyError{Backend: backend.Name, Dependency: dependency, Reason: "unhealthy"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// initializeLog records the order in which backends receive initialize requests
+type initializeLog struct {
+	names []string
+	lock  sync.Mutex
+}
+
+func (l *initializeLog) order() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.names...)
+}
+
+// newOrderedBackend serves an echo tool, logging initialize requests after delay and
+// failing every request while down is set
+func newOrderedBackend(t *testing.T, name string, delay time.Duration, initialized *initializeLog, down *atomic.Bool) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer(name, "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(name), nil
+	})
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		var message jsonRPCMessage
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodInitialize) {
+			time.Sleep(delay)
+			initialized.lock.Lock()
+			initialized.names = append(initialized.names, name)
+			initialized.lock.Unlock()
+		}
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// TestBackendDependencies verifies a backend is initialized after the backend it depends
+// on, and is unavailable while that backend is unhealthy
+func TestBackendDependencies(t *testing.T) {
+	var initialized initializeLog
+	var aDown, bDown atomic.Bool
+	a := newOrderedBackend(t, "a", 0, &initialized, &aDown)
+	b := newOrderedBackend(t, "b", 100*time.Millisecond, &initialized, &bDown)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "a", URL: a.URL, DependsOn: []string{"b"}},
+		{Name: "b", URL: b.URL},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid config, got %v", err)
+	}
+	gateway, gatewayURL := startTestGateway(t, config)
+
+	if order := initialized.order(); len(order) < 2 || order[0] != "b" || order[1] != "a" {
+		t.Errorf("Expected b to be initialized before a, got %v", order)
+	}
+
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "a-echo", nil)); text != "a" {
+		t.Errorf("Expected a to answer while b is healthy, got %q", text)
+	}
+
+	// a is gated while b is unhealthy, though a itself is up
+	bDown.Store(true)
+	health := gateway.checkHealth(context.Background())
+	if health["a"].Healthy || !strings.Contains(health["a"].Error, "dependency b is unhealthy") {
+		t.Errorf("Expected a to be unhealthy because of b, got %+v", health["a"])
+	}
+	result := callTool(t, mcpClient, "a-echo", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "dependency b is unhealthy") {
+		t.Errorf("Expected a to be unavailable while b is unhealthy, got %+v", result)
+	}
+
+	bDown.Store(false)
+	gateway.checkHealth(context.Background())
+	if text := extractTextFromResult(callTool(t, mcpClient, "a-echo", nil)); text != "a" {
+		t.Errorf("Expected a to answer once b is healthy again, got %q", text)
+	}
+}
+
+// TestBackendDependencyUnavailableAtStartup verifies a backend whose dependency fails to
+// start is not initialized and is reported unavailable
+func TestBackendDependencyUnavailableAtStartup(t *testing.T) {
+	var initialized initializeLog
+	var aDown, bDown atomic.Bool
+	bDown.Store(true)
+	a := newOrderedBackend(t, "a", 0, &initialized, &aDown)
+	b := newOrderedBackend(t, "b", 0, &initialized, &bDown)
+
+	config := DefaultConfig()
+	config.PartialToolLists = true
+	config.Backends = []BackendConfig{
+		{Name: "a", URL: a.URL, DependsOn: []string{"b"}},
+		{Name: "b", URL: b.URL},
+		{Name: "c", URL: a.URL},
+	}
+	gateway, _ := startTestGateway(t, config)
+
+	var dependencyErr *DependencyError
+	if err := gateway.unavailableBackends["a"]; !errors.As(err, &dependencyErr) || dependencyErr.Dependency != "b" {
+		t.Errorf("Expected a to be unavailable because of b, got %v", err)
+	}
+	if order := initialized.order(); len(order) != 1 || order[0] != "a" {
+		t.Errorf("Expected only c (served by a's server) to be initialized, got %v", order)
+	}
+}
+
+// TestBackendDependencyValidation verifies unknown dependencies and cycles are rejected
+func TestBackendDependencyValidation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		backends []BackendConfig
+		wantErr  string
+	}{
+		"unknown": {
+			backends: []BackendConfig{{Name: "a", URL: "http://a", DependsOn: []string{"missing"}}},
+			wantErr:  "dependsOn unknown backend missing",
+		},
+		"self": {
+			backends: []BackendConfig{{Name: "a", URL: "http://a", DependsOn: []string{"a"}}},
+			wantErr:  "depends on itself",
+		},
+		"cycle": {
+			backends: []BackendConfig{
+				{Name: "a", URL: "http://a", DependsOn: []string{"b"}},
+				{Name: "b", URL: "http://b", DependsOn: []string{"c"}},
+				{Name: "c", URL: "http://c", DependsOn: []string{"a"}},
+			},
+			wantErr: "backend dependency cycle: a -> b -> c -> a",
+		},
+	} {
+		config := DefaultConfig()
+		config.Backends = tc.backends
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.wantErr, err)
+		}
+	}
+}
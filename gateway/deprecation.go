@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Deprecated tool handling modes
+const (
+	deprecationPassthrough = "passthrough"
+	deprecationWarn        = "warn"
+	deprecationHide        = "hide"
+)
+
+// Suffix added to the annotation title of deprecated tools
+const deprecatedTitleSuffix = " (deprecated)"
+
+// deprecatedPrefixes mark a tool deprecated when its description starts with one
+// of them (case-insensitive); MCP has no deprecation annotation, so backends use
+// a description convention instead
+var deprecatedPrefixes = []string{"[deprecated]", "deprecated:", "deprecated."}
+
+// DeprecationConfig controls how tools deprecated by their backend are exposed
+type DeprecationConfig struct {
+	// Mode is "passthrough" (default: listed and callable as-is), "warn" (a
+	// client is sent a warning the first time its session calls the tool) or
+	// "hide" (left out of tools/list and rejected as unknown)
+	Mode string `yaml:"mode"`
+	// Tools are gateway tool names treated as deprecated whatever their description
+	Tools []string `yaml:"tools"`
+}
+
+// mode returns the configured mode, defaulting to passthrough
+func (c DeprecationConfig) mode() string {
+	if c.Mode == "" {
+		return deprecationPassthrough
+	}
+	return c.Mode
+}
+
+// isDeprecatedTool reports whether a backend or the config marks an aggregated tool deprecated
+func (g *Gateway) isDeprecatedTool(tool mcp.Tool) bool {
+	for _, name := range g.config.Deprecation.Tools {
+		if name == tool.Name {
+			return true
+		}
+	}
+	description := strings.ToLower(strings.TrimSpace(tool.Description))
+	for _, prefix := range deprecatedPrefixes {
+		if strings.HasPrefix(description, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// markDeprecated surfaces a tool's deprecation in its annotation title, the only
+// free-form annotation mcp-go carries
+func markDeprecated(tool mcp.Tool) mcp.Tool {
+	title := tool.Annotations.Title
+	if title == "" {
+		title = tool.Name
+	}
+	if !strings.HasSuffix(title, deprecatedTitleSuffix) {
+		tool.Annotations.Title = title + deprecatedTitleSuffix
+	}
+	return tool
+}
+
+// deprecationMiddleware sends a warning the first time a session calls a deprecated tool
+func (g *Gateway) deprecationMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if g.config.Deprecation.mode() != deprecationWarn {
+			return next(ctx, req)
+		}
+
+		tool, ok := g.lookupTool(req.Params.Name)
+		session := server.ClientSessionFromContext(ctx)
+		if !ok || session == nil || !g.isDeprecatedTool(tool) {
+			return next(ctx, req)
+		}
+
+		if g.sessions.firstDeprecatedCall(session.SessionID(), tool.Name) {
+			log.Printf("⚠️ Client %s called deprecated tool %s", session.SessionID(), tool.Name)
+			err := g.mcpServer.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+				"level":  "warning",
+				"logger": "gateway",
+				"data":   fmt.Sprintf("Tool %s is deprecated: %s", tool.Name, tool.Description),
+			})
+			if err != nil {
+				log.Printf("⚠️ Failed to warn client %s about deprecated tool %s: %v", session.SessionID(), tool.Name, err)
+			}
+		}
+
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestDeprecatedToolWarning verifies a deprecated tool is marked in its annotations
+// and each session is warned the first time it calls it
+func TestDeprecatedToolWarning(t *testing.T) {
+	slowText := func(name, description string) stubTool {
+		return stubTool{
+			tool: mcp.NewTool(name, mcp.WithDescription(description)),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				// Let the gateway's stream write the warning before the response
+				time.Sleep(100 * time.Millisecond)
+				return mcp.NewToolResultText(name), nil
+			},
+		}
+	}
+	backend := newStubBackend(t, "Legacy",
+		slowText("old_echo", "[DEPRECATED] Use echo instead"),
+		slowText("echo", "Echoes the message"),
+	)
+
+	config := DefaultConfig()
+	config.Deprecation = DeprecationConfig{Mode: deprecationWarn}
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	var lock sync.Mutex
+	var warnings []string
+	mcpClient := newTestClient(t, gatewayURL)
+	mcpClient.GetTransport().SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		if notification.Method != "notifications/message" {
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		data, _ := notification.Params.AdditionalFields["data"].(string)
+		warnings = append(warnings, data)
+	})
+
+	tools, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		deprecated := strings.HasSuffix(tool.Annotations.Title, deprecatedTitleSuffix)
+		if deprecated != (tool.Name == "server1-old_echo") {
+			t.Errorf("Unexpected annotation title %q for %s", tool.Annotations.Title, tool.Name)
+		}
+	}
+
+	for range 2 {
+		callTool(t, mcpClient, "server1-old_echo", nil)
+		callTool(t, mcpClient, "server1-echo", nil)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "server1-old_echo is deprecated") {
+		t.Fatalf("Expected one warning for server1-old_echo, got %q", warnings)
+	}
+
+	// Another session is warned again
+	other := newTestClient(t, gatewayURL)
+	otherWarnings := make(chan string, 2)
+	other.GetTransport().SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		data, _ := notification.Params.AdditionalFields["data"].(string)
+		otherWarnings <- data
+	})
+	callTool(t, other, "server1-old_echo", nil)
+	if len(otherWarnings) != 1 {
+		t.Errorf("Expected the second session to be warned once, got %d warnings", len(otherWarnings))
+	}
+}
+
+// TestDeprecatedToolHidden verifies hide mode removes deprecated tools, including
+// those only marked deprecated by config
+func TestDeprecatedToolHidden(t *testing.T) {
+	backend := newStubBackend(t, "Legacy",
+		textTool(mcp.NewTool("old_echo", mcp.WithDescription("Deprecated: use echo")), "old"),
+		textTool(mcp.NewTool("legacy_time"), "then"),
+		textTool(mcp.NewTool("echo"), "new"),
+	)
+
+	config := DefaultConfig()
+	config.Deprecation = DeprecationConfig{Mode: deprecationHide, Tools: []string{"server1-legacy_time"}}
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	mcpClient := newTestClient(t, gatewayURL)
+	tools, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if tool.Name == "server1-old_echo" || tool.Name == "server1-legacy_time" {
+			t.Errorf("Expected deprecated tool %s to be hidden", tool.Name)
+		}
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "server1-old_echo"
+	if _, err := mcpClient.CallTool(context.Background(), req); err == nil {
+		t.Error("Expected a call to a hidden deprecated tool to fail")
+	}
+}
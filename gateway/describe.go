@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// backendDescription is the raw view of a backend returned by gateway_describe_backend
+type backendDescription struct {
+	Name       string                `json:"name"`
+	URL        string                `json:"url"`
+	Initialize *mcp.InitializeResult `json:"initialize"`
+	Tools      []mcp.Tool            `json:"tools"`
+	Resources  []mcp.Resource        `json:"resources"`
+	Prompts    []mcp.Prompt          `json:"prompts"`
+}
+
+// findBackend returns the configuration of the named backend, unless it was removed
+func (g *Gateway) findBackend(name string) (BackendConfig, bool) {
+	if g.drains.isRemoved(name) {
+		return BackendConfig{}, false
+	}
+	return g.configuredBackend(name)
+}
+
+// configuredBackend returns the configuration of the named backend, including removed
+// backends whose in-flight calls are still draining
+func (g *Gateway) configuredBackend(name string) (BackendConfig, bool) {
+	for _, backend := range g.config.Backends {
+		if backend.Name == name {
+			return backend, true
+		}
+	}
+	return BackendConfig{}, false
+}
+
+// describeBackend connects to a backend and collects everything it offers, bypassing tool policy
+func (g *Gateway) describeBackend(ctx context.Context, backend BackendConfig) (*backendDescription, error) {
+	backendClient, serverInfo, err := g.connectBackend(ctx, backend, "MCP Gateway (Describe)", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer backendClient.Close()
+
+	description := &backendDescription{
+		Name:       backend.Name,
+		URL:        backend.URL,
+		Initialize: serverInfo,
+		Tools:      []mcp.Tool{},
+		Resources:  []mcp.Resource{},
+		Prompts:    []mcp.Prompt{},
+	}
+
+	if serverInfo.Capabilities.Tools != nil {
+		tools, err := backendClient.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		description.Tools = tools.Tools
+	}
+
+	lister, canList := backendClient.(resourceLister)
+
+	if serverInfo.Capabilities.Resources != nil && canList {
+		resources, err := lister.ListResources(ctx, mcp.ListResourcesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", err)
+		}
+		description.Resources = resources.Resources
+	}
+
+	if serverInfo.Capabilities.Prompts != nil && canList {
+		prompts, err := lister.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prompts: %w", err)
+		}
+		description.Prompts = prompts.Prompts
+	}
+
+	return description, nil
+}
+
+// handleDescribeBackend handles the gateway_describe_backend tool
+func (g *Gateway) handleDescribeBackend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("backend")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	backend, ok := g.findBackend(name)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown backend %s", name)), nil
+	}
+
+	description, err := g.describeBackend(ctx, backend)
+	if err != nil {
+		log.Printf("❌ Failed to describe backend %s: %v", name, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe backend %s: %v", name, err)), nil
+	}
+
+	data, err := json.MarshalIndent(description, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode description: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestDescribeBackendShowsFilteredTools verifies gateway_describe_backend reports tools hidden by policy
+func TestDescribeBackendShowsFilteredTools(t *testing.T) {
+	backend := newStubBackend(t, "Stub",
+		textTool(mcp.NewTool("visible"), "visible"),
+		textTool(mcp.NewTool("secret"), "secret"),
+	)
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	config.Backends = []BackendConfig{
+		{Name: "stub", URL: backend.URL, Tools: ToolPolicy{Default: ToolPolicyAllow, Except: []string{"secret"}}},
+	}
+
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	names := listToolNames(t, mcpClient)
+	if containsString(names, "stub-secret") {
+		t.Fatalf("Expected stub-secret to be hidden from ListTools, got %v", names)
+	}
+
+	result := callTool(t, mcpClient, "gateway_describe_backend", map[string]interface{}{"backend": "stub"})
+	if result.IsError {
+		t.Fatalf("Describe failed: %s", extractTextFromResult(result))
+	}
+
+	var description backendDescription
+	if err := json.Unmarshal([]byte(extractTextFromResult(result)), &description); err != nil {
+		t.Fatalf("Expected JSON description: %v", err)
+	}
+
+	var rawNames []string
+	for _, tool := range description.Tools {
+		rawNames = append(rawNames, tool.Name)
+	}
+	if !containsString(rawNames, "secret") || !containsString(rawNames, "visible") {
+		t.Errorf("Expected unprefixed raw tools visible and secret, got %v", rawNames)
+	}
+	if description.Initialize == nil || description.Initialize.ServerInfo.Name != "Stub" {
+		t.Errorf("Expected raw initialize result from Stub, got %+v", description.Initialize)
+	}
+
+	unknown := callTool(t, mcpClient, "gateway_describe_backend", map[string]interface{}{"backend": "missing"})
+	if !unknown.IsError {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
+
+// TestDescribeBackendRequiresAdmin verifies the tool is not exposed unless admin is enabled
+func TestDescribeBackendRequiresAdmin(t *testing.T) {
+	backend := newStubBackend(t, "Stub", textTool(mcp.NewTool("visible"), "visible"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "stub", URL: backend.URL}}
+
+	_, gatewayURL := startTestGateway(t, config)
+	names := listToolNames(t, newTestClient(t, gatewayURL))
+	if containsString(names, "gateway_describe_backend") {
+		t.Error("Expected gateway_describe_backend to be hidden when admin is disabled")
+	}
+}
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DialRetriesConfig retries connecting to a backend when the connection itself fails
+// (DNS lookup, refused or reset connections), before the backend is reported
+// unavailable. Unlike tool call retries it applies to every connection the gateway makes.
+type DialRetriesConfig struct {
+	// MaxAttempts per connection, including the first (default 1: no retries)
+	MaxAttempts int `yaml:"maxAttempts"`
+	// Backoff before the first retry, doubling for each further retry (default 100ms)
+	Backoff time.Duration `yaml:"backoff"`
+}
+
+// Validate checks the dial retry settings are not negative
+func (c DialRetriesConfig) Validate() error {
+	if c.MaxAttempts < 0 || c.Backoff < 0 {
+		return fmt.Errorf("retries.dial settings must not be negative")
+	}
+	return nil
+}
+
+// backoff returns the configured delay before the first retry
+func (c DialRetriesConfig) backoff() time.Duration {
+	if c.Backoff == 0 {
+		return 100 * time.Millisecond
+	}
+	return c.Backoff
+}
+
+// isDialError reports whether a connection attempt failed before reaching the backend's
+// MCP server, such as a failed DNS lookup or a refused or reset connection
+func isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) ||
+		(errors.As(err, &opErr) && opErr.Op == "dial") ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// connectBackendTransport creates and initializes a connection using the backend's
+// transport, retrying dial failures with exponential backoff
+func (g *Gateway) connectBackendTransport(ctx context.Context, backend BackendConfig, clientName string, wrap func(transport.Interface) transport.Interface) (BackendTransport, *mcp.InitializeResult, error) {
+	retries := g.retries(backend)
+	attempts := max(retries.Dial.MaxAttempts, 1)
+	backoff := retries.Dial.backoff()
+
+	for attempt := 1; ; attempt++ {
+		backendClient, serverInfo, err := g.dialBackendTransport(ctx, backend, clientName, wrap)
+		if err == nil || attempt >= attempts || !isDialError(err) {
+			return backendClient, serverInfo, err
+		}
+
+		log.Printf("⚠️ Retrying connection to %s after dial failure (attempt %d of %d): %v", backend.Name, attempt, attempts, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, nil, err
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"net"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// refusingListener resets the first refusals connections it accepts
+type refusingListener struct {
+	net.Listener
+	refusals atomic.Int32
+	accepted atomic.Int32
+}
+
+func (l *refusingListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.accepted.Add(1) > l.refusals.Load() {
+			return conn, nil
+		}
+		conn.(*net.TCPConn).SetLinger(0)
+		conn.Close()
+	}
+}
+
+// newRefusingBackend starts a stub backend that resets its first refusals connections
+func newRefusingBackend(t *testing.T, refusals int32) (*httptest.Server, *refusingListener) {
+	t.Helper()
+
+	stub := newStubBackend(t, "Restarting", textTool(mcp.NewTool("echo"), "hello"))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	refusing := &refusingListener{Listener: listener}
+	refusing.refusals.Store(refusals)
+	backend := httptest.NewUnstartedServer(stub.Config.Handler)
+	backend.Listener = refusing
+	backend.Start()
+	t.Cleanup(backend.Close)
+	return backend, refusing
+}
+
+// TestDialRetries verifies the gateway connects to a backend that refuses its first dials
+// when dial retries are configured, and fails without them
+func TestDialRetries(t *testing.T) {
+	backend, refusing := newRefusingBackend(t, 2)
+	config := DefaultConfig()
+	config.Retries.Dial = DialRetriesConfig{MaxAttempts: 3, Backoff: 10 * time.Millisecond}
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	if accepted := refusing.accepted.Load(); accepted < 3 {
+		t.Errorf("Expected the gateway to dial again after two refusals, got %d connections", accepted)
+	}
+	if text := extractTextFromResult(callTool(t, newTestClient(t, gatewayURL), "server1-echo", nil)); text != "hello" {
+		t.Errorf("Expected calls to reach the backend, got %q", text)
+	}
+
+	backend, _ = newRefusingBackend(t, 2)
+	config = DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL}}
+	if _, err := New(config); err == nil {
+		t.Error("Expected startup to fail without dial retries")
+	}
+}
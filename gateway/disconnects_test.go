@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestClientDisconnectCancelsCall verifies a client dropping its connection mid-call
+// cancels the backend call, frees the call's resources and doesn't count against the
+// backend's health
+func TestClientDisconnectCancelsCall(t *testing.T) {
+	var started, cancelled atomic.Bool
+	backend := newStubBackend(t, "Backend", stubTool{
+		tool: mcp.NewTool("slow"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			started.Store(true)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+				cancelled.Store(true)
+			}
+			return mcp.NewToolResultText("done"), nil
+		},
+	})
+	config := DefaultConfig()
+	config.CircuitBreaker.FailureThreshold = 1
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, gatewayURL,
+		strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-slow"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	waitFor(t, "the backend call to start", started.Load)
+
+	disconnect()
+	<-done
+	waitForInFlight(t, gateway, "server1", 0)
+
+	waitFor(t, "the backend call to be cancelled", cancelled.Load)
+	if !gateway.breakers["server1"].allow() {
+		t.Error("Expected the disconnect not to count as a backend failure")
+	}
+	if score := gateway.scores["server1"].score(); score != 100 {
+		t.Errorf("Expected the disconnect not to lower the backend's health score, got %d", score)
+	}
+}
+
+// waitFor polls condition until it holds, failing after 5s
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestAbandonedTrialReleasesCircuit verifies a half-open trial call abandoned by its client
+// lets the next call be the trial, rather than leaving the circuit open
+func TestAbandonedTrialReleasesCircuit(t *testing.T) {
+	breaker := newCircuitBreaker("server1", CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Millisecond})
+	breaker.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	if !breaker.allow() {
+		t.Fatal("Expected a half-open trial call")
+	}
+	if breaker.allow() {
+		t.Fatal("Expected calls to be rejected while the trial is in flight")
+	}
+	breaker.release()
+	if !breaker.allow() {
+		t.Error("Expected the next call to be the trial once the abandoned one was released")
+	}
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Name of a backend's discovery tool, under the backend's namespace
+const discoveryToolName = "discover_tools"
+
+// Default number of tools a discovery tool returns
+const defaultDiscoveryLimit = 20
+
+// DiscoveryConfig replaces a backend's tools in tools/list with a single discovery tool
+// once it exposes too many of them. Clients search the tools by keyword with the
+// discovery tool and call the tools it returns by name as usual.
+type DiscoveryConfig struct {
+	// ToolThreshold enables discovery when the backend exposes more tools than this;
+	// 0 (default) lists every tool
+	ToolThreshold int `yaml:"toolThreshold"`
+}
+
+// discoveryResult is the text of a discovery tool's result
+type discoveryResult struct {
+	// Total is the number of matching tools, before the limit
+	Total int        `json:"total"`
+	Tools []mcp.Tool `json:"tools"`
+}
+
+// newDiscoveryTool creates the discovery tool standing in for a backend's tools
+func (g *Gateway) newDiscoveryTool(backend BackendConfig, tools int) mcp.Tool {
+	return mcp.NewTool(g.gatewayToolName(backend, discoveryToolName),
+		mcp.WithDescription(fmt.Sprintf("Search the %d tools of %s by keyword. Matching tools are returned with their input schemas and can then be called by name.", tools, backend.Name)),
+		mcp.WithString("query", mcp.Description("Keywords that must all appear in a tool's name or description; empty matches every tool")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of tools returned (default %d)", defaultDiscoveryLimit))),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// discoveryToolFilter hides tools listed behind a discovery tool from tools/list; they
+// stay registered, so they can be called once discovered
+func (g *Gateway) discoveryToolFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	g.toolsLock.RLock()
+	defer g.toolsLock.RUnlock()
+	if len(g.discoverableTools) == 0 {
+		return tools
+	}
+
+	hidden := make(map[string]bool)
+	for _, discoverable := range g.discoverableTools {
+		for _, tool := range discoverable {
+			hidden[tool.Name] = true
+		}
+	}
+	listed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if !hidden[tool.Name] {
+			listed = append(listed, tool)
+		}
+	}
+	return listed
+}
+
+// handleDiscoverTools returns the tools behind a discovery tool matching the query
+func (g *Gateway) handleDiscoverTools(ctx context.Context, toolName string, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	g.toolsLock.RLock()
+	discoverable := g.discoverableTools[toolName]
+	g.toolsLock.RUnlock()
+
+	keywords := strings.Fields(strings.ToLower(req.GetString("query", "")))
+	limit := req.GetInt("limit", defaultDiscoveryLimit)
+	if limit <= 0 {
+		limit = defaultDiscoveryLimit
+	}
+
+	var matches []mcp.Tool
+	for _, tool := range g.readOnlyToolFilter(ctx, discoverable) {
+		if matchesKeywords(tool, keywords) {
+			matches = append(matches, tool)
+		}
+	}
+	result := discoveryResult{Total: len(matches), Tools: matches[:min(limit, len(matches))]}
+	if result.Tools == nil {
+		result.Tools = []mcp.Tool{}
+	}
+	log.Printf("🔧 %s matched %d tools for %q", toolName, result.Total, req.GetString("query", ""))
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode tools: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// matchesKeywords reports whether every keyword appears in the tool's name or description
+func matchesKeywords(tool mcp.Tool, keywords []string) bool {
+	text := strings.ToLower(tool.Name + " " + tool.Description)
+	for _, keyword := range keywords {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestDiscoveryTool verifies a backend over its tool threshold is listed as a discovery
+// tool that returns matching tools, and the discovered tools can be called
+func TestDiscoveryTool(t *testing.T) {
+	backend := newStubBackend(t, "Tracker",
+		textTool(mcp.NewTool("create_issue", mcp.WithDescription("Open a new issue")), "created"),
+		textTool(mcp.NewTool("close_issue", mcp.WithDescription("Close an issue")), "closed"),
+		textTool(mcp.NewTool("list_repos", mcp.WithDescription("List repositories")), "repos"),
+	)
+	small := newStubBackend(t, "Small", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	discovery := DiscoveryConfig{ToolThreshold: 2}
+	config.Backends = []BackendConfig{
+		{Name: "server1", URL: backend.URL, Discovery: discovery},
+		{Name: "server2", URL: small.URL, Discovery: discovery},
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	listed, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	var names []string
+	for _, tool := range listed.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, name := range []string{"server1-discover_tools", "server2-echo"} {
+		if !containsString(names, name) {
+			t.Errorf("Expected tools/list to include %s, got %v", name, names)
+		}
+	}
+	if containsString(names, "server1-create_issue") || containsString(names, "server2-discover_tools") {
+		t.Errorf("Expected only the backend over its threshold to be listed behind a discovery tool, got %v", names)
+	}
+
+	var discovered discoveryResult
+	text := extractTextFromResult(callTool(t, mcpClient, "server1-discover_tools", map[string]interface{}{"query": "Issue"}))
+	if err := json.Unmarshal([]byte(text), &discovered); err != nil {
+		t.Fatalf("Failed to parse discovery result %s: %v", text, err)
+	}
+	var matched []string
+	for _, tool := range discovered.Tools {
+		matched = append(matched, tool.Name)
+	}
+	if discovered.Total != 2 || len(matched) != 2 || !containsString(matched, "server1-create_issue") || !containsString(matched, "server1-close_issue") {
+		t.Errorf("Expected the two issue tools, got %d %v", discovered.Total, matched)
+	}
+
+	text = extractTextFromResult(callTool(t, mcpClient, "server1-discover_tools", map[string]interface{}{"limit": 1}))
+	if err := json.Unmarshal([]byte(text), &discovered); err != nil || discovered.Total != 3 || len(discovered.Tools) != 1 {
+		t.Errorf("Expected 1 of 3 tools with a limit, got %s", text)
+	}
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-close_issue", nil)); text != "closed" {
+		t.Errorf("Expected the discovered tool to be callable, got %q", text)
+	}
+}
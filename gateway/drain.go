@@ -0,0 +1,245 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default time a removed backend's in-flight calls get to finish
+const defaultDrainTimeout = 30 * time.Second
+
+// drainTimeout returns the configured drain timeout, or the default
+func (c AdminConfig) drainTimeout() time.Duration {
+	if c.DrainTimeout > 0 {
+		return c.DrainTimeout
+	}
+	return defaultDrainTimeout
+}
+
+// backendDrains tracks in-flight calls per backend and the backends that were removed
+type backendDrains struct {
+	removed  map[string]bool
+	inFlight map[string]map[*inFlightCall]bool
+	lock     sync.Mutex
+}
+
+// inFlightCall is a call being proxied to a backend
+type inFlightCall struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// drainResult reports the outcome of a backend removal
+type drainResult struct {
+	Backend string `json:"backend"`
+	Drained int    `json:"drained"`
+	Killed  int    `json:"killed"`
+}
+
+// BackendRemovedError is returned for calls routed to a removed backend
+type BackendRemovedError struct {
+	Backend string
+}
+
+func (e *BackendRemovedError) Error() string {
+	return fmt.Sprintf("Backend %s has been removed", e.Backend)
+}
+
+// isRemoved reports whether a backend was removed
+func (d *backendDrains) isRemoved(backend string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.removed[backend]
+}
+
+// track registers a call to a backend, returning a context cancelled if the backend's
+// drain times out and a function to call when the call finishes. Calls to removed
+// backends are rejected.
+func (d *backendDrains) track(ctx context.Context, backend string) (context.Context, func(), error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.removed[backend] {
+		return nil, nil, &BackendRemovedError{Backend: backend}
+	}
+	if d.inFlight == nil {
+		d.inFlight = make(map[string]map[*inFlightCall]bool)
+	}
+	if d.inFlight[backend] == nil {
+		d.inFlight[backend] = make(map[*inFlightCall]bool)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	call := &inFlightCall{cancel: cancel, done: make(chan struct{})}
+	d.inFlight[backend][call] = true
+
+	return ctx, func() {
+		d.lock.Lock()
+		delete(d.inFlight[backend], call)
+		d.lock.Unlock()
+		cancel()
+		close(call.done)
+	}, nil
+}
+
+// remove marks a backend removed, rejecting new calls, and returns its in-flight calls
+func (d *backendDrains) remove(backend string) ([]*inFlightCall, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.removed[backend] {
+		return nil, false
+	}
+	if d.removed == nil {
+		d.removed = make(map[string]bool)
+	}
+	d.removed[backend] = true
+
+	calls := make([]*inFlightCall, 0, len(d.inFlight[backend]))
+	for call := range d.inFlight[backend] {
+		calls = append(calls, call)
+	}
+	return calls, true
+}
+
+// removeBackend stops routing to a backend and unlists its tools at once, gives its
+// in-flight calls up to timeout to finish, then cancels the rest and closes every
+// client's connection to it
+func (g *Gateway) removeBackend(name string, timeout time.Duration) (*drainResult, error) {
+	calls, ok := g.drains.remove(name)
+	if !ok {
+		return nil, fmt.Errorf("backend %s was already removed", name)
+	}
+	log.Printf("🔧 Removing backend %s: draining %d in-flight calls for up to %s", name, len(calls), timeout)
+	g.mergeTools()
+
+	result := &drainResult{Backend: name}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	expired := false
+	for _, call := range calls {
+		if !expired {
+			select {
+			case <-call.done:
+				result.Drained++
+				continue
+			case <-deadline.C:
+				expired = true
+			}
+		}
+		// The grace period is over: cancel whatever is still running
+		select {
+		case <-call.done:
+			result.Drained++
+		default:
+			call.cancel()
+			result.Killed++
+		}
+	}
+
+	g.closeBackendConnections(name)
+	log.Printf("✅ Removed backend %s: %d calls drained, %d killed", name, result.Drained, result.Killed)
+	return result, nil
+}
+
+// closeBackendConnections closes every connection to a backend: each client session's
+// own and pinned replica connections, and the shared and startup connections
+func (g *Gateway) closeBackendConnections(backend string) {
+	g.connectionsLock.RLock()
+	sessions := make([]*ClientBackendConnections, 0, len(g.clientConnections))
+	for _, connections := range g.clientConnections {
+		sessions = append(sessions, connections)
+	}
+	g.connectionsLock.RUnlock()
+
+	for _, connections := range sessions {
+		connections.lock.Lock()
+		if backendClient, ok := connections.Clients[backend]; ok {
+			if err := backendClient.Close(); err != nil {
+				log.Printf("❌ Failed to close %s connection for client %s: %v", backend, connections.ClientSessionID, err)
+			}
+			delete(connections.Clients, backend)
+		}
+		for key, backendClient := range connections.pinned {
+			if key.backend != backend {
+				continue
+			}
+			if err := backendClient.Close(); err != nil {
+				log.Printf("❌ Failed to close %s replica %s connection for client %s: %v", backend, key.replica, connections.ClientSessionID, err)
+			}
+			delete(connections.pinned, key)
+		}
+		connections.lock.Unlock()
+	}
+
+	g.toolsLock.Lock()
+	sharedClient, shared := g.sharedClients[backend]
+	if shared {
+		remaining := make(map[string]BackendTransport, len(g.sharedClients))
+		for name, backendClient := range g.sharedClients {
+			if name != backend {
+				remaining[name] = backendClient
+			}
+		}
+		g.sharedClients = remaining
+	}
+	startupClient, startup := g.startupClients[backend]
+	if startup {
+		remaining := make(map[string]BackendTransport, len(g.startupClients))
+		for name, backendClient := range g.startupClients {
+			if name != backend {
+				remaining[name] = backendClient
+			}
+		}
+		g.startupClients = remaining
+	}
+	g.toolsLock.Unlock()
+
+	if shared {
+		if err := sharedClient.Close(); err != nil {
+			log.Printf("❌ Failed to close %s shared connection: %v", backend, err)
+		}
+	}
+	// A stateless backend's startup connection may also be its shared one
+	if startup && (!shared || startupClient != sharedClient) {
+		if err := startupClient.Close(); err != nil {
+			log.Printf("❌ Failed to close %s startup connection: %v", backend, err)
+		}
+	}
+}
+
+// handleRemoveBackend handles DELETE /admin/backends/{name}, with an optional
+// ?drainTimeout=10s overriding admin.drainTimeout
+func (g *Gateway) handleRemoveBackend(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := g.findBackend(name); !ok {
+		http.Error(w, fmt.Sprintf("unknown backend %s", name), http.StatusNotFound)
+		return
+	}
+
+	timeout := g.config.Admin.drainTimeout()
+	if value := r.URL.Query().Get("drainTimeout"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, fmt.Sprintf("invalid drainTimeout %q", value), http.StatusBadRequest)
+			return
+		}
+		timeout = parsed
+	}
+
+	result, err := g.removeBackend(name, timeout)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("❌ Failed to write removal result: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// deleteBackend removes a backend through the admin endpoint
+func deleteBackend(t *testing.T, gatewayURL, backend, drainTimeout string) (int, drainResult) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodDelete, gatewayURL+"/admin/backends/"+backend+"?drainTimeout="+drainTimeout, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Removal request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result drainResult
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode removal result: %v", err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+// waitForInFlight waits until a backend has the given number of in-flight calls
+func waitForInFlight(t *testing.T, gateway *Gateway, backend string, calls int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		gateway.drains.lock.Lock()
+		inFlight := len(gateway.drains.inFlight[backend])
+		gateway.drains.lock.Unlock()
+		if inFlight == calls {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d in-flight calls to %s", calls, backend)
+}
+
+// TestRemoveBackendDrains verifies removing a backend lets an in-flight call finish
+// within the grace period while new calls are rejected at once
+func TestRemoveBackendDrains(t *testing.T) {
+	slow := newStubBackend(t, "Slow", slowTool("slow", 300*time.Millisecond))
+	other := newStubBackend(t, "Other", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	gateway, gatewayURL := startTestGateway(t, config, slow, other)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	results := make(chan *mcp.CallToolResult, 1)
+	go func() {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "server1-slow"
+		result, _ := mcpClient.CallTool(context.Background(), req)
+		results <- result
+	}()
+	waitForInFlight(t, gateway, "server1", 1)
+
+	removed := make(chan drainResult, 1)
+	go func() {
+		status, result := deleteBackend(t, gatewayURL, "server1", "5s")
+		if status != http.StatusOK {
+			t.Errorf("Expected the removal to succeed, got HTTP %d", status)
+		}
+		removed <- result
+	}()
+
+	// New calls are rejected while the in-flight call drains
+	deadline := time.Now().Add(time.Second)
+	for !gateway.drains.isRemoved("server1") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if result, _ := gateway.proxyToolCall(context.Background(), "drain-test", "server1-slow", toolRoute{Backend: "server1", ToolName: "slow"}, mcp.CallToolRequest{}); !result.IsError || extractTextFromResult(result) != "Backend server1 has been removed" {
+		t.Errorf("Expected new calls to be rejected, got %+v", result)
+	}
+
+	if result := <-results; result == nil || result.IsError || extractTextFromResult(result) != "done" {
+		t.Errorf("Expected the in-flight call to complete, got %+v", result)
+	}
+	if result := <-removed; result.Drained != 1 || result.Killed != 0 {
+		t.Errorf("Expected 1 drained and 0 killed calls, got %+v", result)
+	}
+
+	tools := listToolNames(t, mcpClient)
+	if slices.Contains(tools, "server1-slow") || !slices.Contains(tools, "server2-echo") {
+		t.Errorf("Expected only the removed backend's tools to be unlisted, got %v", tools)
+	}
+	if status, _ := deleteBackend(t, gatewayURL, "server1", "1s"); status != http.StatusNotFound {
+		t.Errorf("Expected a second removal to find no backend, got HTTP %d", status)
+	}
+}
+
+// TestRemoveBackendKills verifies calls still running when the grace period ends are
+// cancelled and reported as killed
+func TestRemoveBackendKills(t *testing.T) {
+	slow := newStubBackend(t, "Slow", slowTool("slow", time.Minute))
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	gateway, gatewayURL := startTestGateway(t, config, slow)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	results := make(chan *mcp.CallToolResult, 1)
+	go func() {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "server1-slow"
+		result, _ := mcpClient.CallTool(context.Background(), req)
+		results <- result
+	}()
+	waitForInFlight(t, gateway, "server1", 1)
+
+	status, result := deleteBackend(t, gatewayURL, "server1", "50ms")
+	if status != http.StatusOK || result.Drained != 0 || result.Killed != 1 {
+		t.Errorf("Expected 1 killed call, got HTTP %d %+v", status, result)
+	}
+
+	select {
+	case result := <-results:
+		if result == nil || !result.IsError {
+			t.Errorf("Expected the killed call to fail, got %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the killed call to return")
+	}
+}
+
+// TestRemoveStatelessBackend verifies removing a stateless backend closes and forgets its
+// shared connection, which is also its startup connection
+func TestRemoveStatelessBackend(t *testing.T) {
+	mcpServer := server.NewMCPServer("Stateless", "1.0.0", server.WithToolCapabilities(true))
+	echo := textTool(mcp.NewTool("echo"), "hello")
+	mcpServer.AddTool(echo.tool, echo.handler)
+	stateless := httptest.NewServer(server.NewStreamableHTTPServer(mcpServer, server.WithStateLess(true)))
+	t.Cleanup(stateless.Close)
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	gateway, gatewayURL := startTestGateway(t, config, stateless)
+	if _, ok := gateway.sharedClient("server1"); !ok {
+		t.Fatal("Expected the stateless backend to be shared")
+	}
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); text != "hello" {
+		t.Fatalf("Expected the shared connection to serve the call, got %q", text)
+	}
+
+	if status, _ := deleteBackend(t, gatewayURL, "server1", "1s"); status != http.StatusOK {
+		t.Fatalf("Expected the removal to succeed, got HTTP %d", status)
+	}
+	if _, ok := gateway.sharedClient("server1"); ok {
+		t.Error("Expected the shared connection to be forgotten")
+	}
+	gateway.toolsLock.RLock()
+	_, startup := gateway.startupClients["server1"]
+	gateway.toolsLock.RUnlock()
+	if startup {
+		t.Error("Expected the startup connection to be forgotten")
+	}
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Policies for a backend listing the same tool name more than once
+const (
+	// duplicateToolsKeepFirst keeps the first definition (default)
+	duplicateToolsKeepFirst = "keep-first"
+	// duplicateToolsKeepLast keeps the last definition, in the first one's place
+	duplicateToolsKeepLast = "keep-last"
+	// duplicateToolsError treats the backend's tool list as failed
+	duplicateToolsError = "error"
+)
+
+// DuplicateToolError reports a backend listing the same tool name more than once
+type DuplicateToolError struct {
+	Backend string
+	Tool    string
+}
+
+func (e *DuplicateToolError) Error() string {
+	return fmt.Sprintf("%s lists tool %s more than once", e.Backend, e.Tool)
+}
+
+// dedupeTools applies the duplicateTools policy to a backend's tool list, logging each
+// duplicate name
+func (g *Gateway) dedupeTools(backend string, tools []mcp.Tool) ([]mcp.Tool, error) {
+	positions := make(map[string]int, len(tools))
+	deduped := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		position, duplicate := positions[tool.Name]
+		if !duplicate {
+			positions[tool.Name] = len(deduped)
+			deduped = append(deduped, tool)
+			continue
+		}
+
+		switch g.config.DuplicateTools {
+		case duplicateToolsError:
+			log.Printf("❌ %s lists tool %s more than once", backend, tool.Name)
+			return nil, &DuplicateToolError{Backend: backend, Tool: tool.Name}
+		case duplicateToolsKeepLast:
+			log.Printf("⚠️ %s lists tool %s more than once, keeping the last definition", backend, tool.Name)
+			deduped[position] = tool
+		default:
+			log.Printf("⚠️ %s lists tool %s more than once, keeping the first definition", backend, tool.Name)
+		}
+	}
+	return deduped, nil
+}
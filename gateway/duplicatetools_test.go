@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newDuplicatingBackend serves an echo tool that its tools/list reports twice, with the
+// descriptions "first" and "last"
+func newDuplicatingBackend(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	hooks := &server.Hooks{}
+	hooks.AddAfterListTools(func(ctx context.Context, id any, message *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+		result.Tools = []mcp.Tool{
+			mcp.NewTool("echo", mcp.WithDescription("first")),
+			mcp.NewTool("ping"),
+			mcp.NewTool("echo", mcp.WithDescription("last")),
+		}
+	})
+	mcpServer := server.NewMCPServer("Duplicating", "1.0.0", server.WithHooks(hooks))
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	backend := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// TestDuplicateTools verifies a tool a backend lists twice is kept once under the
+// keep-first and keep-last policies, and fails the backend's list under the error policy
+func TestDuplicateTools(t *testing.T) {
+	backend := newDuplicatingBackend(t)
+
+	for policy, want := range map[string]string{"": "first", duplicateToolsKeepFirst: "first", duplicateToolsKeepLast: "last"} {
+		t.Run("policy "+policy, func(t *testing.T) {
+			config := DefaultConfig()
+			config.DuplicateTools = policy
+			_, gatewayURL := startTestGateway(t, config, backend)
+
+			result, err := newTestClient(t, gatewayURL).ListTools(context.Background(), mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatalf("Failed to list tools: %v", err)
+			}
+			var descriptions []string
+			for _, tool := range result.Tools {
+				if tool.Name == "server1-echo" {
+					descriptions = append(descriptions, tool.Description)
+				}
+			}
+			if len(descriptions) != 1 || descriptions[0] != want {
+				t.Errorf("Expected one server1-echo described %q, got %v", want, descriptions)
+			}
+		})
+	}
+
+	t.Run("policy error", func(t *testing.T) {
+		config := DefaultConfig()
+		config.DuplicateTools = duplicateToolsError
+		config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL}}
+		_, err := New(config)
+		var duplicate *DuplicateToolError
+		if !errors.As(err, &duplicate) || duplicate.Backend != "server1" || duplicate.Tool != "echo" {
+			t.Fatalf("Expected a duplicate tool error for server1 tool echo, got %v", err)
+		}
+
+		// With partial tool lists the gateway starts without the backend
+		config.PartialToolLists = true
+		gateway, err := New(config)
+		if err != nil {
+			t.Fatalf("Expected the gateway to start without the backend, got %v", err)
+		}
+		t.Cleanup(func() { gateway.Shutdown(context.Background()) })
+		gateway.toolsLock.RLock()
+		_, routed := gateway.toolRoutes["server1-echo"]
+		gateway.toolsLock.RUnlock()
+		if routed {
+			t.Error("Expected no tools from the backend with duplicate tools")
+		}
+	})
+}
+
+// TestDuplicateToolsConfig verifies unknown policies are rejected
+func TestDuplicateToolsConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.DuplicateTools = "keep-both"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an unknown duplicateTools policy to be rejected")
+	}
+}
@@ -0,0 +1,347 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Elicitation lets a backend ask the user for structured input while handling a tool
+// call. mcp-go neither delivers server-to-client requests to the backend client nor sends
+// them from the server, so the gateway handles them at the HTTP level: backend requests
+// are taken out of the tool call's SSE response, sent to the client on its own tool
+// call's SSE response, and the client's answer is posted back to the backend.
+const (
+	methodElicitationCreate = "elicitation/create"
+
+	// elicitationNotification carries an elicitation request through mcp-go's session
+	// notification channel; it is rewritten into the request as it is written to the client
+	elicitationNotification = "notifications/gateway/elicitation"
+
+	// elicitationIDPrefix marks the request IDs of elicitations sent to clients
+	elicitationIDPrefix = "gateway-elicitation-"
+)
+
+// elicitationBroker correlates elicitation requests sent to clients with their responses
+type elicitationBroker struct {
+	nextID  atomic.Int64
+	pending map[string]*pendingElicitation
+	lock    sync.Mutex
+}
+
+// pendingElicitation is an elicitation request awaiting the client's response
+type pendingElicitation struct {
+	sessionID string
+	response  chan json.RawMessage
+}
+
+// register allocates a request ID for an elicitation sent to a client session
+func (b *elicitationBroker) register(sessionID string) (string, *pendingElicitation) {
+	id := fmt.Sprintf("%s%d", elicitationIDPrefix, b.nextID.Add(1))
+	pending := &pendingElicitation{sessionID: sessionID, response: make(chan json.RawMessage, 1)}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.pending == nil {
+		b.pending = make(map[string]*pendingElicitation)
+	}
+	b.pending[id] = pending
+	return id, pending
+}
+
+// release forgets an elicitation request
+func (b *elicitationBroker) release(id string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.pending, id)
+}
+
+// waiting reports whether any elicitation requests await a response
+func (b *elicitationBroker) waiting() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.pending) > 0
+}
+
+// deliver hands a client's response to the elicitation it answers, reporting whether
+// the session has such an elicitation pending
+func (b *elicitationBroker) deliver(sessionID, id string, message json.RawMessage) bool {
+	b.lock.Lock()
+	pending, ok := b.pending[id]
+	delete(b.pending, id)
+	b.lock.Unlock()
+
+	if !ok || pending.sessionID != sessionID {
+		return false
+	}
+	pending.response <- message
+	return true
+}
+
+// jsonRPCMessage holds the fields of any JSON-RPC message
+type jsonRPCMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// elicitationResult builds an elicitation result with the given action, for
+// elicitations the gateway answers itself
+func elicitationResult(action string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"action":%q}`, action))
+}
+
+// recordElicitationSupport remembers which client sessions can answer elicitations
+func (g *Gateway) recordElicitationSupport(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	request := initializeRequestFromContext(ctx)
+	session := server.ClientSessionFromContext(ctx)
+	if request == nil || session == nil {
+		return
+	}
+	if _, ok := request.Capabilities["elicitation"]; ok {
+		g.sessions.setElicitation(session.SessionID())
+	}
+}
+
+// elicit sends an elicitation request to the client session handling ctx's tool call and
+// returns its response, a JSON-RPC message. Clients without elicitation support get no
+// request: the backend is told the user declined.
+func (g *Gateway) elicit(ctx context.Context, sessionID string, params json.RawMessage) jsonRPCMessage {
+	if sessionID == "" || !g.sessions.supportsElicitation(sessionID) {
+		log.Printf("⚠️ Declining elicitation for client %q: elicitation not supported", sessionID)
+		return jsonRPCMessage{Result: elicitationResult("decline")}
+	}
+
+	id, pending := g.elicitations.register(sessionID)
+	defer g.elicitations.release(id)
+
+	log.Printf("🔗 Forwarding elicitation %s to client %s", id, sessionID)
+	err := g.mcpServer.SendNotificationToClient(ctx, elicitationNotification, map[string]any{"id": id, "request": params})
+	if err != nil {
+		log.Printf("❌ Failed to forward elicitation to client %s: %v", sessionID, err)
+		return jsonRPCMessage{Result: elicitationResult("decline")}
+	}
+
+	select {
+	case response := <-pending.response:
+		var message jsonRPCMessage
+		if err := json.Unmarshal(response, &message); err != nil {
+			return jsonRPCMessage{Result: elicitationResult("cancel")}
+		}
+		return message
+	case <-ctx.Done():
+		return jsonRPCMessage{Result: elicitationResult("cancel")}
+	}
+}
+
+// elicitationMiddleware routes clients' responses to elicitation requests back to the
+// waiting backend, and rewrites elicitation requests into the client's SSE responses
+func (g *Gateway) elicitationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get("Mcp-Session-Id")
+		if r.Method != http.MethodPost || sessionID == "" || !g.sessions.supportsElicitation(sessionID) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if g.elicitations.waiting() {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			var message jsonRPCMessage
+			var id string
+			if json.Unmarshal(body, &message) == nil && message.Method == "" && json.Unmarshal(message.ID, &id) == nil &&
+				strings.HasPrefix(id, elicitationIDPrefix) {
+				if !g.elicitations.deliver(sessionID, id, body) {
+					http.Error(w, "Unknown elicitation "+id, http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+		}
+
+		next.ServeHTTP(&elicitationResponseWriter{ResponseWriter: w}, r)
+	})
+}
+
+// elicitationResponseWriter turns elicitation notifications written to a client's SSE
+// response into elicitation/create requests. mcp-go writes each SSE event in one Write.
+type elicitationResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *elicitationResponseWriter) Write(data []byte) (int, error) {
+	const prefix = "event: message\ndata: "
+	if !bytes.HasPrefix(data, []byte(prefix)) || !bytes.Contains(data, []byte(elicitationNotification)) {
+		return w.ResponseWriter.Write(data)
+	}
+
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			ID      string          `json:"id"`
+			Request json.RawMessage `json:"request"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data[len(prefix):]), &notification); err != nil || notification.Method != elicitationNotification {
+		return w.ResponseWriter.Write(data)
+	}
+
+	id, _ := json.Marshal(notification.Params.ID)
+	request, err := json.Marshal(jsonRPCMessage{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Method:  methodElicitationCreate,
+		Params:  notification.Params.Request,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := fmt.Fprintf(w.ResponseWriter, "%s%s\n\n", prefix, request); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Flush keeps streaming (SSE) responses working
+func (w *elicitationResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap supports http.ResponseController
+func (w *elicitationResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// elicitationRoundTripper takes elicitation requests out of a backend's SSE responses
+// and answers them with the response of the client session the connection serves
+type elicitationRoundTripper struct {
+	next      http.RoundTripper
+	gateway   *Gateway
+	sessionID string
+}
+
+func (e *elicitationRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := e.next.RoundTrip(req)
+	if err != nil || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return resp, err
+	}
+	resp.Body = &elicitationReader{
+		source:    bufio.NewReader(resp.Body),
+		body:      resp.Body,
+		handle:    func(request jsonRPCMessage) { go e.answer(req, request) },
+		sessionID: e.sessionID,
+	}
+	return resp, nil
+}
+
+// answer forwards a backend's elicitation request to the client and posts the
+// client's response back to the backend session that asked
+func (e *elicitationRoundTripper) answer(original *http.Request, request jsonRPCMessage) {
+	ctx := original.Context()
+	response := e.gateway.elicit(ctx, e.sessionID, request.Params)
+	response.JSONRPC = mcp.JSONRPC_VERSION
+	response.ID = request.ID
+	response.Method = ""
+	response.Params = nil
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("❌ Failed to encode elicitation response: %v", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, original.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		log.Printf("❌ Failed to create elicitation response: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if backendSession := original.Header.Get("Mcp-Session-Id"); backendSession != "" {
+		req.Header.Set("Mcp-Session-Id", backendSession)
+	}
+
+	resp, err := e.next.RoundTrip(req)
+	if err != nil {
+		log.Printf("❌ Failed to send elicitation response to backend: %v", err)
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// elicitationReader passes SSE events through, except elicitation requests, which it
+// hands to handle
+type elicitationReader struct {
+	source    *bufio.Reader
+	body      io.Closer
+	handle    func(request jsonRPCMessage)
+	sessionID string
+
+	buffered []byte
+	err      error
+}
+
+func (r *elicitationReader) Read(p []byte) (int, error) {
+	for len(r.buffered) == 0 && r.err == nil {
+		r.readEvent()
+	}
+	if len(r.buffered) > 0 {
+		n := copy(p, r.buffered)
+		r.buffered = r.buffered[n:]
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// readEvent reads one SSE event, buffering it unless it is an elicitation request
+func (r *elicitationReader) readEvent() {
+	var event, data []byte
+	for {
+		line, err := r.source.ReadBytes('\n')
+		event = append(event, line...)
+		if bytes.HasPrefix(line, []byte("data:")) {
+			data = append(data, bytes.TrimSpace(line[len("data:"):])...)
+		}
+		if err != nil {
+			r.err = err
+			break
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			break
+		}
+	}
+
+	var message jsonRPCMessage
+	if len(data) > 0 && json.Unmarshal(data, &message) == nil && message.Method == methodElicitationCreate && len(message.ID) > 0 {
+		log.Printf("🔗 Backend requested elicitation for client %q", r.sessionID)
+		r.handle(message)
+		return
+	}
+	r.buffered = append(r.buffered, event...)
+}
+
+func (r *elicitationReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newElicitingBackend serves a "greet" tool that asks the user for their name with an
+// elicitation request on the tool call's SSE response, and greets them with the answer
+func newElicitingBackend(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Eliciting", "1.0.0", server.WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("greet"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("greet is answered over SSE")
+	})
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	responses := make(chan jsonRPCMessage, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message jsonRPCMessage
+		if json.Unmarshal(body, &message) != nil {
+			streamable.ServeHTTP(w, r)
+			return
+		}
+		switch {
+		case message.Method == "" && string(message.ID) == `"ask-name"`:
+			responses <- message
+			w.WriteHeader(http.StatusAccepted)
+		case message.Method == string(mcp.MethodToolsCall):
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n",
+				`{"jsonrpc":"2.0","id":"ask-name","method":"elicitation/create","params":{"message":"What is your name?","requestedSchema":{"type":"object","properties":{"name":{"type":"string"}}}}}`)
+			w.(http.Flusher).Flush()
+
+			text := "no answer"
+			select {
+			case response := <-responses:
+				var result struct {
+					Action  string `json:"action"`
+					Content struct {
+						Name string `json:"name"`
+					} `json:"content"`
+				}
+				json.Unmarshal(response.Result, &result)
+				if result.Action == "accept" {
+					text = "Hello " + result.Content.Name
+				} else {
+					text = result.Action
+				}
+			case <-time.After(10 * time.Second):
+			}
+			final, _ := json.Marshal(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      message.ID,
+				"result":  mcp.NewToolResultText(text),
+			})
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", final)
+		default:
+			streamable.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// postMessage posts a JSON-RPC message to the gateway in the given session
+func postMessage(t *testing.T, url, sessionID, body string) *http.Response {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	return resp
+}
+
+// readEvent reads the next JSON-RPC message from an SSE response
+func readEvent(t *testing.T, reader *bufio.Reader) jsonRPCMessage {
+	t.Helper()
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read SSE event: %v", err)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var message jsonRPCMessage
+			if err := json.Unmarshal([]byte(data), &message); err != nil {
+				t.Fatalf("Invalid SSE event %q: %v", data, err)
+			}
+			return message
+		}
+	}
+}
+
+// initializeWithCapabilities initializes a raw session with the given client capabilities
+func initializeWithCapabilities(t *testing.T, url, capabilities string) string {
+	t.Helper()
+
+	resp := postMessage(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+
+		mcp.LATEST_PROTOCOL_VERSION+`","capabilities":`+capabilities+`,"clientInfo":{"name":"raw","version":"1.0.0"}}}`)
+	resp.Body.Close()
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		t.Fatalf("Initialize returned no session (HTTP %d)", resp.StatusCode)
+	}
+	return sessionID
+}
+
+// TestElicitationForwarding verifies a backend's elicitation request reaches the client
+// whose tool call triggered it, and the client's answer reaches the backend
+func TestElicitationForwarding(t *testing.T) {
+	backend := newElicitingBackend(t)
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{"elicitation":{}}`)
+	resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-greet"}}`)
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+
+	request := readEvent(t, events)
+	if request.Method != methodElicitationCreate || !strings.Contains(string(request.Params), "What is your name?") {
+		t.Fatalf("Expected the backend's elicitation request, got %+v", request)
+	}
+
+	answer := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":`+string(request.ID)+`,"result":{"action":"accept","content":{"name":"Ada"}}}`)
+	answer.Body.Close()
+	if answer.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected the answer to be accepted, got HTTP %d", answer.StatusCode)
+	}
+
+	result := readEvent(t, events)
+	if string(result.ID) != "2" || !strings.Contains(string(result.Result), "Hello Ada") {
+		t.Errorf("Expected the tool result to use the answer, got %+v", result)
+	}
+}
+
+// TestElicitationDeclinedWithoutSupport verifies the backend is told the user declined
+// when the client didn't declare elicitation support
+func TestElicitationDeclinedWithoutSupport(t *testing.T) {
+	backend := newElicitingBackend(t)
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+
+	client := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, client, "server1-greet", nil)); text != "decline" {
+		t.Errorf("Expected the elicitation to be declined, got %q", text)
+	}
+}
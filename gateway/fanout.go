@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Default number of concurrent backend operations in a fan-out
+const defaultFanOutConcurrency = 8
+
+// FanOutConfig bounds concurrency of operations that touch every backend at once
+type FanOutConfig struct {
+	// Concurrency is the maximum number of backend operations running at once
+	// across all fan-outs (startup, tool refresh, per-client connects); default 8
+	Concurrency int `yaml:"concurrency"`
+}
+
+// size returns the effective worker pool size
+func (c FanOutConfig) size() int {
+	if c.Concurrency > 0 {
+		return c.Concurrency
+	}
+	return defaultFanOutConcurrency
+}
+
+// fanOutPool limits concurrent backend operations. Its slots are shared by every
+// fan-out, so overlapping fan-outs together stay within the limit.
+type fanOutPool struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+// newFanOutPool creates a pool running at most size operations at once
+func newFanOutPool(size int) *fanOutPool {
+	return &fanOutPool{slots: make(chan struct{}, size)}
+}
+
+// run calls fn for every backend, bounded by the pool, and waits for all of them.
+// fn receives the backend's index so results can be collected in config order.
+func (p *fanOutPool) run(backends []BackendConfig, fn func(i int, backend BackendConfig)) {
+	var wg sync.WaitGroup
+	for i, backend := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.waiting.Add(1)
+			p.slots <- struct{}{}
+			p.waiting.Add(-1)
+			defer func() { <-p.slots }()
+			fn(i, backend)
+		}()
+	}
+	wg.Wait()
+}
+
+// queueDepth returns the number of operations waiting for a worker
+func (p *fanOutPool) queueDepth() int64 {
+	return p.waiting.Load()
+}
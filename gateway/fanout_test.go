@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// inFlightTracker records the peak number of concurrent requests across backends
+type inFlightTracker struct {
+	current atomic.Int64
+	peak    atomic.Int64
+}
+
+// wrap slows down connect and tools/list requests and tracks how many are in flight.
+// Tool calls and session deletes happen outside fan-outs and pass straight through.
+func (t *inFlightTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if r.Method != http.MethodPost || bytes.Contains(body, []byte(`"tools/call"`)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		n := t.current.Add(1)
+		defer t.current.Add(-1)
+		for {
+			peak := t.peak.Load()
+			if n <= peak || t.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TestFanOutConcurrency verifies no more than the configured number of backends are contacted at once
+func TestFanOutConcurrency(t *testing.T) {
+	tracker := &inFlightTracker{}
+
+	config := DefaultConfig()
+	config.FanOut.Concurrency = 2
+	config.Backends = nil
+	for i := 1; i <= 6; i++ {
+		stub := newStubBackend(t, "Stub", textTool(mcp.NewTool("echo"), "ok"))
+		backend := httptest.NewServer(tracker.wrap(stub.Config.Handler))
+		t.Cleanup(backend.Close)
+		config.Backends = append(config.Backends, BackendConfig{Name: fmt.Sprintf("server%d", i), URL: backend.URL})
+	}
+
+	_, gatewayURL := startTestGateway(t, config)
+	if peak := tracker.peak.Load(); peak > 2 {
+		t.Fatalf("Expected at most 2 concurrent backend requests during startup, saw %d", peak)
+	}
+	if peak := tracker.peak.Load(); peak < 2 {
+		t.Errorf("Expected startup to use the worker pool concurrently, peak was %d", peak)
+	}
+
+	// Two clients opening backend sessions at once share the same pool
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		mcpClient := newTestClient(t, gatewayURL)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "server1-echo"
+			if _, err := mcpClient.CallTool(context.Background(), req); err != nil {
+				t.Errorf("Failed to call server1-echo: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := tracker.peak.Load(); peak > 2 {
+		t.Fatalf("Expected at most 2 concurrent backend requests, saw %d", peak)
+	}
+}
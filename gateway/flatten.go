@@ -0,0 +1,131 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resultMetaOriginalContent is the _meta key holding the content a result had before it
+// was flattened
+const resultMetaOriginalContent = "originalContent"
+
+// Default rendering of a flattened content item: a line naming it, then its text if any
+const defaultFlattenTemplate = `[{{.Type}}{{if .URI}} {{.URI}}{{end}}{{if .MIMEType}} ({{.MIMEType}}){{end}}{{if .Size}}, {{.Size}} bytes{{end}}]{{if .Text}}
+{{.Text}}{{end}}`
+
+// FlattenConfig turns the non-text content of a tool's results (embedded resources,
+// images, audio) into text, for clients that only understand text content
+type FlattenConfig struct {
+	// Template renders each non-text content item as text. It is a Go template with
+	// .Type, .URI, .MIMEType, .Text (a text resource's text), .Size (decoded bytes of
+	// binary data) and .JSON (the item as JSON). The default names the item and
+	// includes its text.
+	Template string `yaml:"template"`
+	// PreserveOriginal keeps the original content items in _meta.originalContent
+	PreserveOriginal bool `yaml:"preserveOriginal"`
+}
+
+// flattenData is the data of a flatten template
+type flattenData struct {
+	Type     string
+	URI      string
+	MIMEType string
+	Text     string
+	Size     int
+	JSON     string
+}
+
+// compileFlattenTemplates parses the flatten template of each tool
+func compileFlattenTemplates(tools map[string]FlattenConfig) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(tools))
+	for toolName, config := range tools {
+		text := config.Template
+		if text == "" {
+			text = defaultFlattenTemplate
+		}
+		tmpl, err := template.New(toolName).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("flatten.%s.template: %w", toolName, err)
+		}
+		templates[toolName] = tmpl
+	}
+	return templates, nil
+}
+
+// flattenResult replaces a result with non-text content by a result with a single text
+// item joining the text of every item. A result that can't be flattened is returned
+// unchanged.
+func (g *Gateway) flattenResult(toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	tmpl, ok := g.flatteners[toolName]
+	if !ok || result == nil {
+		return result
+	}
+
+	flattened := false
+	parts := make([]string, 0, len(result.Content))
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+			continue
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, newFlattenData(content)); err != nil {
+			log.Printf("❌ Failed to flatten result of %s: %v", toolName, err)
+			return result
+		}
+		parts = append(parts, rendered.String())
+		flattened = true
+	}
+	if !flattened {
+		return result
+	}
+
+	copied := *result
+	copied.Content = []mcp.Content{mcp.NewTextContent(strings.Join(parts, "\n\n"))}
+	if g.config.Flatten[toolName].PreserveOriginal {
+		copied.Meta = make(map[string]any, len(result.Meta)+1)
+		for key, value := range result.Meta {
+			copied.Meta[key] = value
+		}
+		copied.Meta[resultMetaOriginalContent] = result.Content
+	}
+	return &copied
+}
+
+// newFlattenData describes a non-text content item for a flatten template
+func newFlattenData(content mcp.Content) flattenData {
+	encoded, _ := json.Marshal(content)
+	data := flattenData{JSON: string(encoded)}
+	switch content := content.(type) {
+	case mcp.EmbeddedResource:
+		data.Type = "resource"
+		switch resource := content.Resource.(type) {
+		case mcp.TextResourceContents:
+			data.URI, data.MIMEType, data.Text = resource.URI, resource.MIMEType, resource.Text
+		case mcp.BlobResourceContents:
+			data.URI, data.MIMEType, data.Size = resource.URI, resource.MIMEType, base64Size(resource.Blob)
+		}
+	case mcp.ImageContent:
+		data.Type, data.MIMEType, data.Size = "image", content.MIMEType, base64Size(content.Data)
+	case mcp.AudioContent:
+		data.Type, data.MIMEType, data.Size = "audio", content.MIMEType, base64Size(content.Data)
+	default:
+		var item struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(encoded, &item)
+		data.Type = item.Type
+	}
+	return data
+}
+
+// base64Size returns the decoded size of base64 data
+func base64Size(data string) int {
+	return len(strings.TrimRight(data, "=")) * 3 / 4
+}
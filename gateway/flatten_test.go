@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// richTool returns text, an embedded text resource and an image
+func richTool(name string) stubTool {
+	return stubTool{
+		tool: mcp.NewTool(name),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{
+				mcp.NewTextContent("Report ready"),
+				mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "file:///report.csv", MIMEType: "text/csv", Text: "a,b\n1,2"}),
+				mcp.NewImageContent("aGVsbG8=", "image/png"),
+			}}, nil
+		},
+	}
+}
+
+// TestFlattenResult verifies non-text content of a configured tool is rendered to text
+// with its template, keeping the original content in _meta
+func TestFlattenResult(t *testing.T) {
+	backend := newStubBackend(t, "Backend", richTool("report"), richTool("raw"))
+	config := DefaultConfig()
+	config.Flatten = map[string]FlattenConfig{
+		"server1-report": {
+			Template:         `{{.Type}} {{.MIMEType}}{{if .URI}} at {{.URI}}{{end}}: {{if .Text}}{{.Text}}{{else}}{{.Size}} bytes{{end}}`,
+			PreserveOriginal: true,
+		},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-report", nil)
+	if len(result.Content) != 1 {
+		t.Fatalf("Expected a single text item, got %d items", len(result.Content))
+	}
+	expected := "Report ready\n\nresource text/csv at file:///report.csv: a,b\n1,2\n\nimage image/png: 5 bytes"
+	if text := extractTextFromResult(result); text != expected {
+		t.Errorf("Expected flattened text %q, got %q", expected, text)
+	}
+	original, _ := result.Meta[resultMetaOriginalContent].([]any)
+	if len(original) != 3 {
+		t.Errorf("Expected the 3 original content items in _meta, got %v", result.Meta[resultMetaOriginalContent])
+	}
+
+	// Tools without a flatten config keep their content
+	if raw := callTool(t, mcpClient, "server1-raw", nil); len(raw.Content) != 3 {
+		t.Errorf("Expected an unconfigured tool's content unchanged, got %d items", len(raw.Content))
+	}
+}
+
+// TestFlattenDefaultTemplate verifies the default template names each item and
+// includes its text, without _meta unless asked for
+func TestFlattenDefaultTemplate(t *testing.T) {
+	backend := newStubBackend(t, "Backend", richTool("report"))
+	config := DefaultConfig()
+	config.Flatten = map[string]FlattenConfig{"server1-report": {}}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-report", nil)
+	expected := "Report ready\n\n[resource file:///report.csv (text/csv)]\na,b\n1,2\n\n[image (image/png), 5 bytes]"
+	if text := extractTextFromResult(result); text != expected {
+		t.Errorf("Expected flattened text %q, got %q", expected, text)
+	}
+	if _, ok := result.Meta[resultMetaOriginalContent]; ok {
+		t.Error("Expected no original content in _meta without preserveOriginal")
+	}
+}
+
+// TestFlattenInvalidTemplate verifies template syntax errors are reported by Validate
+func TestFlattenInvalidTemplate(t *testing.T) {
+	config := DefaultConfig()
+	config.Flatten = map[string]FlattenConfig{"server1-report": {Template: "{{.Type"}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an invalid flatten template to be rejected")
+	}
+}
@@ -0,0 +1,1151 @@
+// Package gateway implements an MCP gateway that aggregates tools from multiple
+// backend MCP servers and routes calls through per-client backend sessions.
+// It can be embedded in another binary by mounting Handler on an HTTP mux.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// ClientBackendConnections holds the backend client connections for a specific client session
+type ClientBackendConnections struct {
+	ClientSessionID string
+	Clients         map[string]BackendTransport // keyed by backend name
+	CreatedAt       time.Time
+
+	// lastUsed records the last call to each backend, for idle timeouts
+	lastUsed map[string]time.Time
+	// protocolVersions records the protocol version negotiated with each backend
+	protocolVersions map[string]string
+	// pinned holds connections to the replicas calls were pinned to, keyed by
+	// backend and replica name
+	pinned map[pinnedReplicaKey]BackendTransport
+	// notifications forwards backend notifications to the client, when enabled
+	notifications *notificationForwarder
+	// partials relays notifications of calls streaming partial results, when enabled
+	partials func(mcp.JSONRPCNotification) bool
+	lock     sync.Mutex
+}
+
+// Close closes all backend connections held for the client
+func (c *ClientBackendConnections) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.notifications != nil {
+		c.notifications.close()
+	}
+	for name, backendClient := range c.Clients {
+		if err := backendClient.Close(); err != nil {
+			log.Printf("❌ Failed to close %s connection for client %s: %v", name, c.ClientSessionID, err)
+		}
+	}
+	for key, backendClient := range c.pinned {
+		if err := backendClient.Close(); err != nil {
+			log.Printf("❌ Failed to close %s replica %s connection for client %s: %v", key.backend, key.replica, c.ClientSessionID, err)
+		}
+	}
+}
+
+// toolRoute identifies the backend and original tool name behind an aggregated tool
+type toolRoute struct {
+	Backend  string
+	ToolName string
+}
+
+// Gateway is an MCP server that acts as a client to each configured backend
+type Gateway struct {
+	// Gateway configuration
+	config *Config
+
+	// Server side
+	mcpServer *server.MCPServer
+
+	// Tools implemented by the gateway itself
+	builtinTools []mcp.Tool
+
+	// Tool aggregation
+	aggregatedTools []mcp.Tool
+	toolRoutes      map[string]toolRoute
+	toolsLock       sync.RWMutex
+
+	// Position of each aggregated tool in aggregatedTools, keyed by gateway tool name
+	toolIndex map[string]int
+
+	// Each backend's tools as prepared by the last merge, reused while its tool list is
+	// unchanged; guarded by mergeLock, which serializes merges
+	contributions map[string]*backendContribution
+	mergeLock     sync.Mutex
+
+	// Tools hidden from tools/list behind each discovery tool, keyed by discovery tool name
+	discoverableTools map[string][]mcp.Tool
+
+	// Backends that could not be queried for tools, with partial tool lists enabled
+	unavailableBackends map[string]error
+
+	// Latest tool list of each backend, as listed by the backend, keyed by backend name
+	backendTools map[string][]mcp.Tool
+	refreshLock  sync.Mutex
+
+	// Latest refresh of each backend after a not-found error, keyed by backend name
+	notFoundRefreshes   map[string]*notFoundRefresh
+	notFoundRefreshLock sync.Mutex
+
+	// Cancels the backend connections of an eager startup still in progress
+	stopStartup context.CancelFunc
+
+	// Session management - maps client session ID to backend client connections
+	clientConnections map[string]*ClientBackendConnections
+	connectionsLock   sync.RWMutex
+
+	// Client sessions whose backend connections are being created, closed when done,
+	// so concurrent requests of a session wait for one set of connections
+	connecting map[string]chan struct{}
+
+	// Circuit breakers keyed by backend name
+	breakers map[string]*circuitBreaker
+
+	// Health scorers keyed by backend name
+	scores map[string]*healthScorer
+
+	// Throttles paused by backend 429 responses, keyed by backend name
+	throttles map[string]*backendThrottle
+
+	// In-flight calls per backend, and backends removed through the admin API
+	drains backendDrains
+
+	// Open backend sockets, counted against the connection caps
+	connectionLimiter *connectionLimiter
+
+	// Output schemas declared in backend tool lists
+	outputSchemas outputSchemaStore
+
+	// Elicitation requests forwarded to clients, awaiting their responses
+	elicitations elicitationBroker
+
+	// Tool calls relaying partial results to their clients
+	partials partialRelays
+
+	// In-flight tool calls clients can cancel
+	clientCalls clientCalls
+
+	// Tenant label values of tool call metrics; nil unless tenant labels are enabled
+	tenantLabels *tenantLabeler
+
+	// Failed tool calls reported by gateway_recent_errors; nil unless admin is enabled
+	recentErrors *recentErrorLog
+	// Per-tool call statistics reported by gateway_tool_stats; nil unless admin is enabled
+	toolStats *toolStatsLog
+
+	// Providers of extra gateway_info fields
+	infoProviders []GatewayInfoProvider
+
+	// Replicas of replicated backends in routing order, keyed by backend name
+	replicas map[string][]*replica
+
+	// Cached tool results
+	cache *resultCache
+
+	// Key-value store for state such as idempotency records
+	store KVStore
+
+	// Mirrors sampled tool calls to canary backends
+	shadows *shadower
+
+	// Compiled argument and result rewrite rules
+	rewrites []rewriteRule
+
+	// Compiled flatten templates, by gateway tool name
+	flatteners map[string]*template.Template
+
+	// Client session lifecycle, including resumption after disconnect
+	sessions *sessionStore
+
+	// Request ID remapping shared by all backend connections
+	requestIDs *requestIDRemapper
+
+	// Worker pool bounding operations that fan out to every backend
+	fanOut *fanOutPool
+
+	// Forwarded and dropped backend notification counts
+	notificationStats *notificationStats
+
+	// Load tracked for admission control
+	admission admissionStats
+
+	// Fault injection for chaos testing; nil unless enabled
+	chaos *chaosInjector
+
+	// Receives session and backend metrics; metricsHandler serves them when the sink can
+	metrics        MetricsSink
+	metricsHandler http.Handler
+
+	// Latest backend health probe outcomes
+	health *healthMonitor
+
+	// Per-client limit on session creation; nil when unlimited
+	initializeLimiter *initializeLimiter
+
+	// Gateways for TLS server name tenants, keyed by lowercase hostname
+	tenants map[string]*Gateway
+
+	// Instance name, when the gateway is one of several in the process
+	name string
+
+	// Startup clients keyed by backend name (used only for initial tool discovery, then discarded)
+	startupClients map[string]BackendTransport
+
+	// Connections of stateless backends shared by all client sessions, keyed by backend name
+	sharedClients map[string]BackendTransport
+	// rebalanceLock serializes replacing shared connections whose affinity TTL expired
+	rebalanceLock sync.Mutex
+
+	// Protocol version each backend negotiated at startup, keyed by backend name
+	backendProtocolVersions map[string]string
+	protocolVersionsLock    sync.RWMutex
+}
+
+// loggingMiddleware adds comprehensive logging for all HTTP requests
+func (g *Gateway) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Log all headers for debugging
+		log.Printf("=== GATEWAY REQUEST ===")
+		log.Printf("Method: %s, URL: %s", r.Method, r.URL.String())
+		log.Printf("Headers:")
+		for name, values := range r.Header {
+			for _, value := range values {
+				log.Printf("  %s: %s", name, value)
+			}
+		}
+
+		// Specifically log session header
+		sessionID := r.Header.Get("mcp-session-id")
+		if sessionID != "" {
+			log.Printf("🔑 MCP-SESSION-ID: %s", sessionID)
+		} else {
+			log.Printf("❌ No mcp-session-id header found")
+		}
+
+		log.Printf("======================")
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// New creates a gateway from config and connects to its backends, ready to serve
+func New(config *Config) (*Gateway, error) {
+	metrics, metricsHandler, err := newMetricsSink(config.Metrics)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics config: %w", err)
+	}
+	return newNamed(config, "", metrics, metricsHandler)
+}
+
+// newNamed creates a gateway recording to the given metrics sink; name is set for
+// one of several gateway instances in the process
+func newNamed(config *Config, name string, metrics MetricsSink, metricsHandler http.Handler) (*Gateway, error) {
+	if err := config.ResolveGroups(); err != nil {
+		return nil, fmt.Errorf("invalid policy groups: %w", err)
+	}
+
+	rewrites, err := compileRewriteRules(config.Rewrites)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rewrite rules: %w", err)
+	}
+
+	flatteners, err := compileFlattenTemplates(config.Flatten)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flatten config: %w", err)
+	}
+
+	comparators, err := newResultComparators(config.Shadow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow config: %w", err)
+	}
+
+	store, err := newStore(config.Store)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store config: %w", err)
+	}
+
+	infoProviders, err := newGatewayInfoProviders(config.GatewayInfo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gatewayInfo config: %w", err)
+	}
+	sessionIDs, err := newSessionIDs(config.Sessions.IDs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sessions config: %w", err)
+	}
+
+	gateway := newGateway(config)
+	gateway.name = name
+	gateway.rewrites = rewrites
+	gateway.flatteners = flatteners
+	gateway.store = store
+	gateway.infoProviders = infoProviders
+	gateway.metrics = metrics
+	gateway.metricsHandler = metricsHandler
+	gateway.sessions.metrics = metrics
+	gateway.sessions.ids = sessionIDs
+	gateway.shadows.comparators = comparators
+	gateway.logBackendPolicies()
+	gateway.logBackendPipelines()
+	log.Printf("🔧 Backend fan-out concurrency: %d", config.FanOut.size())
+	if err := gateway.initializeBackends(); err != nil {
+		return nil, err
+	}
+	gateway.startProber()
+
+	if gateway.tenants, err = newTenants(config); err != nil {
+		return nil, err
+	}
+	return gateway, nil
+}
+
+// Handler returns the gateway's HTTP handler (streamable HTTP MCP, plus admin endpoints when enabled)
+func (g *Gateway) Handler() http.Handler {
+	streamableServer := server.NewStreamableHTTPServer(g.mcpServer, server.WithSessionIdManager(g.sessions))
+
+	mux := http.NewServeMux()
+	if g.config.Admin.Enabled && g.config.Admin.Listen == "" {
+		g.registerAdminRoutes(mux)
+	}
+	if g.config.Notifications.LongPoll.Enabled {
+		mux.HandleFunc("/notifications", g.handleNotificationPoll)
+	}
+	mux.HandleFunc("GET /readyz", g.handleReadyz)
+	if g.metricsHandler != nil {
+		mux.Handle("GET /metrics", g.metricsHandler)
+	}
+	mux.Handle("/", g.elicitationMiddleware(g.initializeMetaMiddleware(g.clientVersionMiddleware(g.admissionMiddleware(g.sessionResumeMiddleware(g.reinitializeMiddleware(g.responseHeadersMiddleware(g.quotaTierMiddleware(g.metricsTenantMiddleware(g.clientRequestMiddleware(g.sessionIDMiddleware(streamableServer))))))))))))
+
+	// Wrap everything with logging middleware
+	handler := g.loggingMiddleware(g.bodyLimitMiddleware(mux))
+
+	// Tenant handlers do their own logging
+	if len(g.tenants) > 0 {
+		handler = g.tenantMiddleware(handler)
+	}
+	return handler
+}
+
+// Shutdown closes all per-client backend connections. The gateway must not be used afterwards.
+func (g *Gateway) Shutdown(ctx context.Context) error {
+	g.stopProber()
+	if g.stopStartup != nil {
+		g.stopStartup()
+	}
+
+	g.connectionsLock.Lock()
+	connections := g.clientConnections
+	g.clientConnections = make(map[string]*ClientBackendConnections)
+	g.connectionsLock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, clientConnections := range connections {
+			clientConnections.Close()
+		}
+		g.shadows.close()
+		for _, tenant := range g.tenants {
+			tenant.Shutdown(ctx)
+		}
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Gateway shut down, closed backend connections for %d clients", len(connections))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newGateway creates a new MCP Gateway instance without connecting to backends
+func newGateway(config *Config) *Gateway {
+	gateway := &Gateway{
+		config:            config,
+		aggregatedTools:   make([]mcp.Tool, 0),
+		toolRoutes:        make(map[string]toolRoute),
+		clientConnections: make(map[string]*ClientBackendConnections),
+		connecting:        make(map[string]chan struct{}),
+		requestIDs:        newRequestIDRemapper(),
+		fanOut:            newFanOutPool(config.FanOut.size()),
+		notificationStats: &notificationStats{},
+		health:            &healthMonitor{results: make(map[string]backendHealth)},
+		metrics:           multiSink{},
+	}
+	gateway.cache = newResultCache(config.Cache)
+	gateway.initializeLimiter = newInitializeLimiter(config.Admission.InitializeRate)
+	gateway.chaos = newChaosInjector(config.Chaos)
+	gateway.shadows = &shadower{gateway: gateway, clients: make(map[string]BackendTransport)}
+	gateway.breakers = make(map[string]*circuitBreaker)
+	gateway.throttles = make(map[string]*backendThrottle)
+	gateway.scores = make(map[string]*healthScorer)
+	for _, backend := range config.Backends {
+		gateway.breakers[backend.Name] = newCircuitBreaker(backend.Name, config.CircuitBreaker)
+		gateway.scores[backend.Name] = newHealthScorer(config.Health.Scoring, gateway.breakers[backend.Name])
+		gateway.throttles[backend.Name] = &backendThrottle{backend: backend.Name}
+	}
+	gateway.replicas = make(map[string][]*replica)
+	for _, backend := range config.Backends {
+		if len(backend.Replicas) > 0 {
+			gateway.replicas[backend.Name] = newReplicas(backend, config.CircuitBreaker, config.Health.Scoring)
+		}
+	}
+	gateway.sessions = newSessionStore(config.Sessions, gateway.closeClientConnections)
+	gateway.connectionLimiter = newConnectionLimiter(config, gateway.recordOpenConnections)
+	if config.Metrics.Tenants.Header != "" {
+		gateway.tenantLabels = newTenantLabeler(config.Metrics.Tenants)
+	}
+	if config.Admin.Enabled {
+		gateway.recentErrors = newRecentErrorLog(config.Admin.recentErrors())
+		gateway.toolStats = newToolStatsLog(config.Admin.toolStats())
+	}
+
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(gateway.recordToolPriority)
+	hooks.AddAfterInitialize(gateway.recordElicitationSupport)
+	hooks.AddAfterInitialize(gateway.recordInitializeResult)
+	if config.PartialToolLists {
+		hooks.AddAfterListTools(gateway.annotateToolList)
+	}
+
+	// Create MCP server with tool capabilities
+	options := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithToolHandlerMiddleware(gateway.toolErrorsMiddleware),
+		server.WithToolHandlerMiddleware(gateway.readOnlyMiddleware),
+		server.WithToolHandlerMiddleware(gateway.destructiveMiddleware),
+		server.WithToolHandlerMiddleware(gateway.deprecationMiddleware),
+		server.WithHooks(hooks),
+	}
+	for _, filter := range gateway.toolFilters() {
+		options = append(options, server.WithToolFilter(filter))
+	}
+	gateway.mcpServer = server.NewMCPServer("MCP Gateway", Version, options...)
+
+	// Setup gateway handlers
+	gateway.setupHandlers()
+	gateway.setupClientNotifications()
+
+	return gateway
+}
+
+// setupHandlers configures the MCP server handlers
+func (g *Gateway) setupHandlers() {
+	// Gateway info tool
+	g.addBuiltinTool(mcp.NewTool("gateway_info",
+		mcp.WithDescription("Get information about the MCP Gateway"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), g.handleGatewayInfo)
+
+	// Admin tools reveal tools hidden by policy, so they are opt-in
+	if g.config.Admin.Enabled {
+		g.addBuiltinTool(mcp.NewTool("gateway_describe_backend",
+			mcp.WithDescription("Describe a backend's raw capabilities, tools, resources and prompts, ignoring tool policy"),
+			mcp.WithString("backend", mcp.Required(), mcp.Description("Backend name")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), g.handleDescribeBackend)
+		g.addBuiltinTool(mcp.NewTool("gateway_refresh_backend",
+			mcp.WithDescription("Re-fetch a backend's tools and update the aggregated tools"),
+			mcp.WithString("backend", mcp.Required(), mcp.Description("Backend name")),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+		), g.handleRefreshBackendTool)
+		g.addBuiltinTool(mcp.NewTool("gateway_recent_errors",
+			mcp.WithDescription("List the most recent failed tool calls, newest first, with their backend, tool, error code and time"),
+			mcp.WithNumber("limit", mcp.Description("Maximum number of errors to return (default: all remembered)")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), g.handleRecentErrors)
+		g.addBuiltinTool(mcp.NewTool("gateway_tool_stats",
+			mcp.WithDescription("Get each tool's call count, error count, average and percentile latency, and last call time, most called first"),
+			mcp.WithString("tool", mcp.Description("Only return this tool's stats")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		), g.handleToolStats)
+	}
+}
+
+// addBuiltinTool registers a tool implemented by the gateway itself
+func (g *Gateway) addBuiltinTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	g.builtinTools = append(g.builtinTools, tool)
+	g.mcpServer.AddTool(tool, handler)
+}
+
+// isBuiltinTool reports whether name is reserved by a tool implemented by the gateway itself
+func (g *Gateway) isBuiltinTool(name string) bool {
+	for _, tool := range g.builtinTools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupTool finds a built-in or aggregated tool definition by its gateway name
+func (g *Gateway) lookupTool(name string) (mcp.Tool, bool) {
+	for _, tool := range g.builtinTools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+
+	g.toolsLock.RLock()
+	defer g.toolsLock.RUnlock()
+	if i, ok := g.toolIndex[name]; ok {
+		return g.aggregatedTools[i], true
+	}
+	return mcp.Tool{}, false
+}
+
+// initializeBackends connects to backend servers for initial tool discovery only
+func (g *Gateway) initializeBackends() error {
+	log.Println("Initializing backend server connections for tool discovery...")
+
+	if g.config.Startup.eager() {
+		g.initializeBackendsEagerly()
+		return nil
+	}
+
+	// Initialize startup clients (these will be discarded after tool discovery)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := g.initializeStartupClients(ctx, nil); err != nil {
+		return fmt.Errorf("failed to initialize startup clients: %w", err)
+	}
+	g.detectStatelessBackends()
+
+	// Aggregate tools from all backend servers
+	if err := g.aggregateTools(); err != nil {
+		return fmt.Errorf("failed to aggregate tools: %w", err)
+	}
+
+	// Cache keys must refer to arguments the tools accept
+	if err := g.validateCacheTools(); err != nil {
+		return fmt.Errorf("invalid cache config: %w", err)
+	}
+	if err := g.validateIdempotencyTools(); err != nil {
+		return fmt.Errorf("invalid idempotency config: %w", err)
+	}
+
+	// Only idempotent tools may be mirrored to a canary
+	if err := g.validateShadowTools(); err != nil {
+		return fmt.Errorf("invalid shadow config: %w", err)
+	}
+
+	log.Printf("Successfully initialized. Aggregated %d tools from backend servers.", len(g.aggregatedTools))
+	log.Println("Startup clients will be discarded - per-client sessions will be created on demand.")
+	return nil
+}
+
+// initializeStartupClients creates temporary clients for tool discovery.
+// Every backend is attempted and all failures are reported together. connected, if
+// set, is called with each backend's client as soon as it is initialized.
+func (g *Gateway) initializeStartupClients(ctx context.Context, connected func(backend BackendConfig, backendClient BackendTransport)) error {
+	clients := make(map[string]BackendTransport, len(g.config.Backends))
+	failures := make(map[string]error)
+
+	// Backends are initialized after the backends they depend on
+	for _, wave := range startupWaves(g.config.Backends) {
+		waveClients := make([]BackendTransport, len(wave))
+		waveErrs := make([]error, len(wave))
+		g.fanOut.run(wave, func(i int, backend BackendConfig) {
+			for _, dependency := range backend.DependsOn {
+				if clients[dependency] == nil {
+					waveErrs[i] = &DependencyError{Backend: backend.Name, Dependency: dependency, Reason: "unavailable"}
+					log.Printf("❌ %v", waveErrs[i])
+					return
+				}
+			}
+			log.Printf("Creating startup connection to %s...", backend.Name)
+
+			backendClient, serverInfo, err := g.connectBackend(ctx, backend, "MCP Gateway (Startup)", nil)
+			if err != nil {
+				log.Printf("❌ %v", err)
+				waveErrs[i] = err
+				return
+			}
+
+			waveClients[i] = backendClient
+			log.Printf("Startup connection to %s: %s (version %s)", backend.Name, serverInfo.ServerInfo.Name, serverInfo.ServerInfo.Version)
+			g.recordBackendProtocolVersion(backend.Name, serverInfo.ProtocolVersion)
+			if connected != nil {
+				connected(backend, backendClient)
+			}
+		})
+		for i, backend := range wave {
+			if waveClients[i] != nil {
+				clients[backend.Name] = waveClients[i]
+			} else {
+				failures[backend.Name] = waveErrs[i]
+			}
+		}
+	}
+
+	startupClients := make(map[string]BackendTransport)
+	unavailable := make(map[string]error)
+	var errs []error
+	for _, backend := range g.config.Backends {
+		if backendClient, ok := clients[backend.Name]; ok {
+			startupClients[backend.Name] = backendClient
+			continue
+		}
+		errs = append(errs, failures[backend.Name])
+		if g.config.PartialToolLists {
+			unavailable[backend.Name] = failures[backend.Name]
+		}
+	}
+	if connected != nil {
+		// The caller has taken each connected backend's client as it came
+		return errors.Join(errs...)
+	}
+
+	g.toolsLock.Lock()
+	g.startupClients = startupClients
+	g.unavailableBackends = unavailable
+	g.toolsLock.Unlock()
+
+	// With partial tool lists, start as long as one backend is reachable
+	if g.config.PartialToolLists && len(startupClients) > 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// aggregateTools fetches and aggregates tools from all backend servers using startup clients
+func (g *Gateway) aggregateTools() error {
+	log.Println("Aggregating tools from backend servers using startup clients...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// List tools from all backends concurrently, then merge in config order
+	listed := make([]*mcp.ListToolsResult, len(g.config.Backends))
+	errs := make([]error, len(g.config.Backends))
+	g.fanOut.run(g.config.Backends, func(i int, backend BackendConfig) {
+		startupClient, ok := g.startupClients[backend.Name]
+		if !ok {
+			errs[i] = g.unavailableBackends[backend.Name]
+			return
+		}
+		listed[i], errs[i] = startupClient.ListTools(ctx, mcp.ListToolsRequest{})
+		if errs[i] == nil {
+			listed[i].Tools, errs[i] = g.dedupeTools(backend.Name, listed[i].Tools)
+		}
+	})
+
+	lists := make(map[string][]mcp.Tool)
+	unavailable := make(map[string]error)
+	for i, backend := range g.config.Backends {
+		if err := errs[i]; err != nil {
+			if !g.config.PartialToolLists {
+				return fmt.Errorf("failed to list tools from %s: %w", backend.Name, err)
+			}
+			log.Printf("⚠️ Listing tools without %s: %v", backend.Name, err)
+			unavailable[backend.Name] = err
+			continue
+		}
+		lists[backend.Name] = listed[i].Tools
+	}
+
+	g.toolsLock.Lock()
+	g.backendTools = lists
+	g.unavailableBackends = unavailable
+	g.toolsLock.Unlock()
+
+	g.mergeTools()
+	return nil
+}
+
+// getOrCreateClientConnections gets existing backend connections or creates new ones for a client
+func (g *Gateway) getOrCreateClientConnections(ctx context.Context, clientSessionID string) (*ClientBackendConnections, error) {
+	for {
+		g.connectionsLock.Lock()
+		existing, exists := g.clientConnections[clientSessionID]
+		pending, creating := g.connecting[clientSessionID]
+		if !exists && !creating {
+			pending = make(chan struct{})
+			g.connecting[clientSessionID] = pending
+		}
+		g.connectionsLock.Unlock()
+
+		if exists {
+			log.Printf("✅ Using existing backend connections for client %s", clientSessionID)
+			return existing, nil
+		}
+		if !creating {
+			break
+		}
+
+		// Another request of the session is connecting; use its connections, or try
+		// again if it failed
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	defer func() {
+		g.connectionsLock.Lock()
+		pending := g.connecting[clientSessionID]
+		delete(g.connecting, clientSessionID)
+		g.connectionsLock.Unlock()
+		close(pending)
+	}()
+
+	log.Printf("🆕 Creating new backend connections for client %s", clientSessionID)
+
+	// Create new backend connections for this client
+	connections := &ClientBackendConnections{
+		ClientSessionID:  clientSessionID,
+		Clients:          make(map[string]BackendTransport),
+		CreatedAt:        time.Now(),
+		lastUsed:         make(map[string]time.Time),
+		protocolVersions: make(map[string]string),
+		pinned:           make(map[pinnedReplicaKey]BackendTransport),
+	}
+	if g.config.Notifications.Forward {
+		connections.notifications = g.newNotificationForwarder(clientSessionID)
+	}
+	if g.config.Notifications.PartialResults {
+		connections.partials = g.relayPartialResult
+	}
+
+	// Initialize a dedicated connection to each backend for this client
+	var connectErr error
+	var connectLock sync.Mutex
+	g.fanOut.run(g.config.Backends, func(i int, backend BackendConfig) {
+		if _, ok := g.sharedClient(backend.Name); ok || g.drains.isRemoved(backend.Name) || g.startupPending(backend.Name) {
+			return
+		}
+		backendClient, serverInfo, err := g.dialClientBackend(ctx, backend, clientSessionID)
+
+		connectLock.Lock()
+		defer connectLock.Unlock()
+		if err != nil && g.config.PartialToolLists {
+			// The backend is connected on first use instead
+			log.Printf("⚠️ Client %s continues without %s: %v", clientSessionID, backend.Name, err)
+			return
+		}
+		if err != nil {
+			if connectErr == nil {
+				connectErr = fmt.Errorf("failed to create %s connection for client %s: %w", backend.Name, clientSessionID, err)
+			}
+			return
+		}
+		connections.add(backend.Name, backendClient, serverInfo)
+	})
+	if connectErr != nil {
+		connections.Close()
+		return nil, connectErr
+	}
+
+	// Store the connections
+	g.connectionsLock.Lock()
+	g.clientConnections[clientSessionID] = connections
+	g.connectionsLock.Unlock()
+
+	log.Printf("✅ Created backend connections for client %s", clientSessionID)
+
+	return connections, nil
+}
+
+// createClientBackendConnection creates a dedicated backend connection for a client.
+// The caller must hold connections.lock or otherwise own connections.
+func (g *Gateway) createClientBackendConnection(ctx context.Context, backend BackendConfig, connections *ClientBackendConnections) error {
+	backendClient, serverInfo, err := g.dialClientBackend(ctx, backend, connections.ClientSessionID)
+	if err != nil {
+		return err
+	}
+	connections.add(backend.Name, backendClient, serverInfo)
+	return nil
+}
+
+// dialClientBackend connects and initializes a dedicated backend client for a client session
+func (g *Gateway) dialClientBackend(ctx context.Context, backend BackendConfig, clientSessionID string) (BackendTransport, *mcp.InitializeResult, error) {
+	log.Printf("🔗 Creating dedicated %s connection for client %s", backend.Name, clientSessionID)
+
+	clientName := fmt.Sprintf("MCP Gateway (Client %s)", clientSessionID)
+	ctx = context.WithValue(ctx, clientSessionKey{}, clientSessionID)
+	wrap := func(t transport.Interface) transport.Interface {
+		return g.wrapBackendTransport(backend, t)
+	}
+	if backend.Batching.enabled() {
+		httpClient, err := g.newBackendHTTPClient(backend, clientSessionID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create HTTP client: %w", err)
+		}
+		wrap = func(t transport.Interface) transport.Interface {
+			return g.wrapBackendTransport(backend, newBatchingTransport(t, backend, httpClient))
+		}
+	}
+	backendClient, serverInfo, err := g.connectBackend(ctx, backend, clientName, wrap)
+	g.recordBackendConnection(backend.Name, err)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("✅ Client %s connected to %s: %s (protocol %s, session maintained by client)",
+		clientSessionID, backend.Name, serverInfo.ServerInfo.Name, serverInfo.ProtocolVersion)
+	return backendClient, serverInfo, nil
+}
+
+// add records a backend client for the session
+func (c *ClientBackendConnections) add(backend string, backendClient BackendTransport, serverInfo *mcp.InitializeResult) {
+	c.Clients[backend] = backendClient
+	if source, ok := backendClient.(notificationSource); ok && (c.notifications != nil || c.partials != nil) {
+		source.OnNotification(c.notify)
+	}
+	c.lastUsed[backend] = time.Now()
+	c.protocolVersions[backend] = serverInfo.ProtocolVersion
+}
+
+// notify handles a backend notification: those of calls relaying partial results go to
+// the call's response stream, the rest are forwarded when enabled
+func (c *ClientBackendConnections) notify(notification mcp.JSONRPCNotification) {
+	if c.partials != nil && c.partials(notification) {
+		return
+	}
+	if c.notifications != nil {
+		c.notifications.enqueue(notification)
+	}
+}
+
+// wrapBackendTransport applies gateway-level transport behavior to a per-client backend transport
+func (g *Gateway) wrapBackendTransport(backend BackendConfig, t transport.Interface) transport.Interface {
+	t = &numberTransport{Interface: t}
+	if len(g.retries(backend).ErrorCodes) > 0 {
+		t = &errorCodeTransport{Interface: t}
+	}
+	if g.config.remapRequestIDs() {
+		return newRemappingTransport(t, g.requestIDs)
+	}
+	return t
+}
+
+// routeToolCall routes tool calls to the appropriate backend server using per-client connections
+func (g *Gateway) routeToolCall(ctx context.Context, toolName string, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Printf("🔧 Tool call started: %s", toolName)
+	g.admission.activeCalls.Add(1)
+	defer g.admission.activeCalls.Add(-1)
+
+	// Extract client session from context
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		log.Printf("❌ No client session found in context")
+		return mcp.NewToolResultError("No active session"), nil
+	}
+
+	clientSessionID := session.SessionID()
+	log.Printf("🔑 Client session ID: %s", clientSessionID)
+
+	// Pass numbers on as the client wrote them
+	req.Params.Arguments = preciseArguments(ctx, req.Params.Arguments)
+
+	// Look up the backend that owns this tool
+	g.toolsLock.RLock()
+	route, ok := g.toolRoutes[toolName]
+	g.toolsLock.RUnlock()
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown tool %s", toolName)), nil
+	}
+
+	route = g.routeByArgument(toolName, route, req.Params.Arguments)
+
+	// Sessions past their quota for the tool are refused
+	quotaRemaining, err := g.takeQuota(ctx, clientSessionID, toolName)
+	if err != nil {
+		log.Printf("❌ Rejecting %s for client %s: %v", toolName, clientSessionID, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// The client may cancel the call with notifications/cancelled
+	ctx, release := g.clientCalls.track(ctx, clientSessionID)
+	defer release()
+
+	// Retries carrying the same idempotency key get the first call's result
+	started := time.Now()
+	result, err := g.callIdempotent(ctx, toolName, req, func(req mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+		result, route, err = g.proxyWithFallback(ctx, clientSessionID, toolName, route, req)
+		return result, err
+	})
+	if errors.Is(context.Cause(ctx), errCancelledByClient) {
+		log.Printf("❌ Tool call %s cancelled by client", toolName)
+		result, err = mcp.NewToolResultError("Request cancelled by client"), nil
+	} else if ctx.Err() != nil {
+		// Nobody is left to receive the result
+		log.Printf("❌ Client %s disconnected during %s", clientSessionID, toolName)
+	}
+	g.recordToolCall(ctx, toolName, route.Backend, time.Since(started), result, err)
+	return annotateQuota(result, quotaRemaining), err
+}
+
+// proxyToolCall forwards a tool call to the route's backend over the client session's connection
+func (g *Gateway) proxyToolCall(ctx context.Context, clientSessionID, toolName string, route toolRoute, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Oversized arguments never reach the backend
+	if err := g.checkInputSize(toolName, req.Params.Arguments); err != nil {
+		log.Printf("❌ Rejecting %s: %v", toolName, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Track the call so removing its backend can drain it
+	ctx, done, err := g.drains.track(ctx, route.Backend)
+	if err != nil {
+		log.Printf("❌ Rejecting %s: %v", toolName, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer done()
+
+	// Backends are unavailable while a backend they depend on is unhealthy
+	backend, _ := g.configuredBackend(route.Backend)
+	if err := g.unhealthyDependency(backend); err != nil {
+		log.Printf("❌ Rejecting %s: %v", toolName, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Clients on a protocol version the backend's can't be translated to are refused
+	// before a backend session is opened for them
+	if err := g.checkProtocolCompatibility(route.Backend, g.backendProtocolVersion(route.Backend), g.sessions.protocolVersion(clientSessionID)); err != nil {
+		log.Printf("❌ Rejecting %s: %v", toolName, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Get or create backend connections for this client
+	connections, err := g.getOrCreateClientConnections(ctx, clientSessionID)
+	if err != nil {
+		log.Printf("❌ Failed to get client connections: %v", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Connection error: %v", err)), nil
+	}
+
+	// A permitted client may pin the call to a replica, over a connection of its own
+	pinned, err := g.pinnedReplica(backend, req)
+	if err != nil {
+		log.Printf("❌ Rejecting %s: %v", toolName, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var backendClient BackendTransport
+	if pinned != nil {
+		backendClient, err = g.pinnedReplicaClient(ctx, connections, backend, pinned)
+	} else {
+		backendClient, err = g.backendClient(ctx, connections, backend)
+	}
+	if err != nil {
+		log.Printf("❌ Failed to connect to %s: %v", route.Backend, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Connection error: %v", err)), nil
+	}
+
+	originalToolName := route.ToolName
+
+	// Create call request with original tool name
+	backendReq := mcp.CallToolRequest{}
+	backendReq.Params.Name = originalToolName
+	backendReq.Params.Arguments, err = g.rewriteArguments(toolName, req.Params.Arguments)
+	if err != nil {
+		log.Printf("❌ Failed to rewrite arguments for %s: %v", toolName, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to rewrite arguments: %v", err)), nil
+	}
+
+	// Serve repeated calls from the result cache
+	cacheKey, cacheable := g.cache.key(toolName, clientSessionID, req.Params.Arguments)
+	cacheable = cacheable && pinned == nil
+	if cacheable {
+		if cached, ok := g.cache.get(cacheKey); ok {
+			log.Printf("✅ Tool call %s served from cache", toolName)
+			g.truncateResult(toolName, cached)
+			g.annotateResult(toolName, route, 0, cached)
+			return cached, nil
+		}
+	}
+
+	// Back off while the backend is rate limiting us
+	bypass := isAdminBypass(ctx)
+	if wait := g.throttles[route.Backend].remaining(); wait > 0 && !bypass {
+		log.Printf("❌ %s is rate limited, rejecting %s", route.Backend, toolName)
+		return mcp.NewToolResultError((&BackendThrottledError{Backend: route.Backend, RetryAfter: wait}).Error()), nil
+	}
+
+	// Fail fast while the backend's circuit is open. Admin calls get a breaker of their
+	// own that never opens, so they neither wait for nor affect the backend's.
+	breaker := g.breakers[route.Backend]
+	if bypass {
+		breaker = newCircuitBreaker(route.Backend, CircuitBreakerConfig{})
+	}
+	var allowed bool
+	if g.config.CircuitBreaker.ProbeWithHealthCheck {
+		allowed = breaker.allowProbed(ctx, func(ctx context.Context) error { return g.probeBackend(ctx, backend) })
+	} else {
+		allowed = breaker.allow()
+	}
+	if !allowed {
+		log.Printf("❌ Circuit open for %s, rejecting %s", route.Backend, toolName)
+		return mcp.NewToolResultError(fmt.Sprintf("Backend %s is unavailable (circuit open)", route.Backend)), nil
+	}
+
+	// Stream partial results to the client while the call runs
+	finishPartials := g.relayPartialResults(ctx, req, &backendReq)
+	defer finishPartials()
+
+	// Call backend server (client maintains its own session internally)
+	callCtx, cancel := context.WithTimeout(ctx, backend.Timeouts.request())
+	defer cancel()
+
+	log.Printf("🚀 Routing %s -> %s (client: %s, session maintained by backend client)",
+		toolName, originalToolName, clientSessionID)
+
+	started := time.Now()
+	callCtx, rawResult := withRawResult(callCtx)
+	result, err := g.callToolWithRetries(callCtx, toolName, route.Backend, backendClient, backendReq)
+	if err != nil && ctx.Err() != nil {
+		// The client went away, so the backend call was cancelled with its request.
+		// That says nothing about the backend's health.
+		log.Printf("⚠️ Abandoned %s for client %s, backend call cancelled: %v", toolName, clientSessionID, context.Cause(ctx))
+		breaker.release()
+		return mcp.NewToolResultError("Request cancelled by client"), nil
+	}
+	g.scores[route.Backend].record(time.Since(started), err != nil)
+	if isSessionTerminated(err) && pinned != nil {
+		// Pinned calls have a connection of their own: drop it so the next one redials,
+		// leaving the session's connection to the backend in place
+		connections.dropPinned(backend, pinned, backendClient)
+	} else if isSessionTerminated(err) {
+		// The backend lost our session (e.g. it restarted): renegotiate and retry once
+		var previous, current string
+		backendClient, previous, current, err = g.reconnectBackend(callCtx, connections, backend)
+		if err == nil {
+			err = checkProtocolVersion(backend.Name, previous, current)
+		}
+		if err == nil {
+			result, err = backendClient.CallTool(callCtx, backendReq)
+		}
+	}
+	if err != nil {
+		breaker.recordFailure()
+		log.Printf("❌ Backend call failed for %s: %v", toolName, err)
+		markToolNotFound(ctx, err)
+
+		var throttled *BackendThrottledError
+		if errors.As(err, &throttled) {
+			return mcp.NewToolResultError(throttled.Error()), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Backend call failed: %v", err)), nil
+	}
+	breaker.recordSuccess()
+	preciseResultMeta(result, *rawResult)
+	g.shadows.maybeShadow(toolName, backend, backendReq, result)
+
+	result, err = g.rewriteResult(toolName, result)
+	if err != nil {
+		log.Printf("❌ Failed to rewrite result for %s: %v", toolName, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to rewrite result: %v", err)), nil
+	}
+	result = g.flattenResult(toolName, result)
+
+	if cacheable {
+		g.cache.put(cacheKey, toolName, result)
+	}
+
+	g.truncateResult(toolName, result)
+	g.annotateResult(toolName, route, time.Since(started), result)
+
+	log.Printf("✅ Tool call %s completed successfully", toolName)
+	return result, nil
+}
+
+// handleGatewayInfo handles the gateway_info tool. It reports a snapshot of gateway state,
+// consistent even while backend tool lists or health change concurrently.
+func (g *Gateway) handleGatewayInfo(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Tool counts and availability come from one consistent view of the tool registry
+	g.toolsLock.RLock()
+	toolCount := len(g.aggregatedTools)
+	backendToolCounts := make(map[string]int)
+	for _, route := range g.toolRoutes {
+		backendToolCounts[route.Backend]++
+	}
+	unavailable := make(map[string]bool, len(g.unavailableBackends))
+	for name := range g.unavailableBackends {
+		unavailable[name] = true
+	}
+	g.toolsLock.RUnlock()
+
+	health := g.healthResults()
+
+	g.connectionsLock.RLock()
+	connectionCount := len(g.clientConnections)
+	g.connectionsLock.RUnlock()
+
+	backends := make(map[string]interface{}, len(g.config.Backends))
+	for _, backend := range g.config.Backends {
+		status := map[string]interface{}{
+			"tools":            backendToolCounts[backend.Name],
+			"available":        !unavailable[backend.Name],
+			"open_connections": g.connectionLimiter.openConnections(backend.Name),
+			"health_score":     g.scores[backend.Name].score(),
+		}
+		if result, ok := health[backend.Name]; ok {
+			status["healthy"] = result.Healthy
+		}
+		backends[backend.Name] = status
+	}
+
+	info := map[string]interface{}{
+		"gateway_name":        "MCP Gateway",
+		"version":             Version,
+		"backend_servers":     g.BackendURLs(),
+		"aggregated_tools":    toolCount,
+		"backends":            backends,
+		"active_connections":  connectionCount,
+		"status":              "running",
+		"session_management":  "per-client backend connections (sessions maintained by clients)",
+		"fan_out_concurrency": g.config.FanOut.size(),
+	}
+	if g.name != "" {
+		info["instance"] = g.name
+	}
+
+	if g.config.Admission.MaxActiveCalls > 0 || g.config.Admission.MaxQueueDepth > 0 || g.initializeLimiter != nil {
+		info["admission"] = map[string]int64{
+			"active_calls":          g.admission.activeCalls.Load(),
+			"queue_depth":           g.fanOut.queueDepth(),
+			"rejected":              g.admission.rejected.Load(),
+			"throttled_initializes": g.admission.throttled.Load(),
+		}
+	}
+
+	if g.config.Notifications.Forward {
+		info["notifications"] = g.notificationStats.snapshot()
+	}
+
+	if len(g.config.Shadow) > 0 {
+		info["shadow"] = map[string]interface{}{
+			"calls":                g.shadows.stats.calls.Load(),
+			"discrepancies":        g.shadows.stats.discrepancies.Load(),
+			"errors":               g.shadows.stats.errors.Load(),
+			"recent_discrepancies": g.shadows.recentDiscrepancies(),
+		}
+	}
+
+	g.addProvidedInfo(ctx, info)
+
+	return mcp.NewToolResultText(fmt.Sprintf("Gateway Info: %+v", info)), nil
+}
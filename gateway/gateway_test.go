@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestGatewayEmbedded verifies a Gateway can be constructed programmatically and mounted on an existing mux
+func TestGatewayEmbedded(t *testing.T) {
+	backend := newStubBackend(t, "Embedded Backend", textTool(mcp.NewTool("hello"), "hi there"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "embedded", URL: backend.URL}}
+
+	mcpGateway, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	// Mount the gateway alongside another service on the caller's mux
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcpGateway.Handler())
+	mux.HandleFunc("/other", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("other service"))
+	})
+	httpServer := httptest.NewServer(mux)
+	t.Cleanup(httpServer.Close)
+
+	mcpClient := newTestClient(t, httpServer.URL+"/mcp")
+
+	names := listToolNames(t, mcpClient)
+	if !containsString(names, "embedded-hello") || !containsString(names, "gateway_info") {
+		t.Fatalf("Expected aggregated and built-in tools, got %v", names)
+	}
+
+	result := callTool(t, mcpClient, "embedded-hello", nil)
+	if text := extractTextFromResult(result); text != "hi there" {
+		t.Fatalf("Expected backend result, got %q", text)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := mcpGateway.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	mcpGateway.connectionsLock.RLock()
+	remaining := len(mcpGateway.clientConnections)
+	mcpGateway.connectionsLock.RUnlock()
+	if remaining != 0 {
+		t.Fatalf("Expected all client connections to be closed, %d remain", remaining)
+	}
+}
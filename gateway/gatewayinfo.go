@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Built-in gateway_info providers
+const gatewayInfoProviderHTTP = "http"
+
+// Default time the http provider waits for its endpoint
+const defaultGatewayInfoProviderTimeout = 2 * time.Second
+
+// GatewayInfoProvider supplies extra fields for the gateway_info result, such as
+// deployment metadata. Implementations must be safe for concurrent use.
+type GatewayInfoProvider interface {
+	// GatewayInfo returns fields to merge into gateway_info; built-in fields of the
+	// same name take precedence
+	GatewayInfo(ctx context.Context) (map[string]any, error)
+}
+
+// GatewayInfoConfig selects providers of extra gateway_info fields
+type GatewayInfoConfig struct {
+	// Providers are queried in order on every gateway_info call
+	Providers []GatewayInfoProviderConfig `yaml:"providers"`
+}
+
+// GatewayInfoProviderConfig configures one gateway_info provider
+type GatewayInfoProviderConfig struct {
+	// Type names a registered provider: http or a custom one
+	Type string `yaml:"type"`
+	// Options are passed to the provider factory (e.g. the http provider's url)
+	Options map[string]string `yaml:"options"`
+}
+
+// GatewayInfoProviderFactory creates a gateway_info provider from its options
+type GatewayInfoProviderFactory func(options map[string]string) (GatewayInfoProvider, error)
+
+var (
+	gatewayInfoProvidersLock sync.RWMutex
+	gatewayInfoProviders     = make(map[string]GatewayInfoProviderFactory)
+)
+
+func init() {
+	RegisterGatewayInfoProvider(gatewayInfoProviderHTTP, func(options map[string]string) (GatewayInfoProvider, error) {
+		return newHTTPInfoProvider(options)
+	})
+}
+
+// RegisterGatewayInfoProvider makes a gateway_info provider available by name to the
+// gatewayInfo providers setting. It is meant to be called from init functions and panics
+// if the name is empty or already registered, or if factory is nil.
+func RegisterGatewayInfoProvider(name string, factory GatewayInfoProviderFactory) {
+	gatewayInfoProvidersLock.Lock()
+	defer gatewayInfoProvidersLock.Unlock()
+
+	if name == "" || factory == nil {
+		panic("gateway: RegisterGatewayInfoProvider requires a name and a factory")
+	}
+	if _, exists := gatewayInfoProviders[name]; exists {
+		panic(fmt.Sprintf("gateway: gateway_info provider %s registered twice", name))
+	}
+	gatewayInfoProviders[name] = factory
+}
+
+// lookupGatewayInfoProvider returns the factory registered under name
+func lookupGatewayInfoProvider(name string) (GatewayInfoProviderFactory, error) {
+	gatewayInfoProvidersLock.RLock()
+	defer gatewayInfoProvidersLock.RUnlock()
+
+	factory, ok := gatewayInfoProviders[name]
+	if !ok {
+		names := make([]string, 0, len(gatewayInfoProviders))
+		for registered := range gatewayInfoProviders {
+			names = append(names, registered)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown gateway_info provider %q (registered: %v)", name, names)
+	}
+	return factory, nil
+}
+
+// Validate checks that every provider is registered
+func (c GatewayInfoConfig) Validate() error {
+	for i, provider := range c.Providers {
+		if _, err := lookupGatewayInfoProvider(provider.Type); err != nil {
+			return fmt.Errorf("gatewayInfo.providers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// newGatewayInfoProviders creates the configured providers
+func newGatewayInfoProviders(config GatewayInfoConfig) ([]GatewayInfoProvider, error) {
+	var providers []GatewayInfoProvider
+	for _, providerConfig := range config.Providers {
+		factory, err := lookupGatewayInfoProvider(providerConfig.Type)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := factory(providerConfig.Options)
+		if err != nil {
+			return nil, fmt.Errorf("gateway_info provider %s: %w", providerConfig.Type, err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// addProvidedInfo merges the providers' fields into info without replacing built-in
+// fields; a failing provider is logged and skipped
+func (g *Gateway) addProvidedInfo(ctx context.Context, info map[string]interface{}) {
+	for i, provider := range g.infoProviders {
+		fields, err := provider.GatewayInfo(ctx)
+		if err != nil {
+			log.Printf("❌ gateway_info provider %d failed: %v", i, err)
+			continue
+		}
+		for name, value := range fields {
+			if _, builtin := info[name]; builtin {
+				log.Printf("⚠️ Ignoring gateway_info field %s from provider %d: it is built in", name, i)
+				continue
+			}
+			info[name] = value
+		}
+	}
+}
+
+// httpInfoProvider fetches gateway_info fields as a JSON object from a URL, such as a
+// deployment metadata endpoint
+type httpInfoProvider struct {
+	url    string
+	client *http.Client
+}
+
+// newHTTPInfoProvider creates an http provider; options are url (required) and
+// timeout (a duration, default 2s)
+func newHTTPInfoProvider(options map[string]string) (*httpInfoProvider, error) {
+	if options["url"] == "" {
+		return nil, fmt.Errorf("url option is required")
+	}
+	timeout := defaultGatewayInfoProviderTimeout
+	if value := options["timeout"]; value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout option: %w", err)
+		}
+		timeout = parsed
+	}
+	return &httpInfoProvider{url: options["url"], client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (p *httpInfoProvider) GatewayInfo(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP %d", p.url, resp.StatusCode)
+	}
+	var fields map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("%s returned invalid JSON: %w", p.url, err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func init() {
+	RegisterGatewayInfoProvider("static", func(options map[string]string) (GatewayInfoProvider, error) {
+		fields := make(map[string]any, len(options))
+		for name, value := range options {
+			fields[name] = value
+		}
+		return staticInfoProvider(fields), nil
+	})
+	RegisterGatewayInfoProvider("failing", func(options map[string]string) (GatewayInfoProvider, error) {
+		return failingInfoProvider{}, nil
+	})
+}
+
+// staticInfoProvider returns its options as gateway_info fields
+type staticInfoProvider map[string]any
+
+func (p staticInfoProvider) GatewayInfo(ctx context.Context) (map[string]any, error) {
+	return p, nil
+}
+
+// failingInfoProvider always fails
+type failingInfoProvider struct{}
+
+func (failingInfoProvider) GatewayInfo(ctx context.Context) (map[string]any, error) {
+	return nil, errors.New("metadata unavailable")
+}
+
+// TestGatewayInfoProviders verifies provider fields are added to gateway_info without
+// replacing built-in fields, and a failing provider doesn't fail the call
+func TestGatewayInfoProviders(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"region":"eu-west-1"}`))
+	}))
+	t.Cleanup(metadata.Close)
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.GatewayInfo.Providers = []GatewayInfoProviderConfig{
+		{Type: "static", Options: map[string]string{"build": "abc123", "status": "overridden"}},
+		{Type: "failing"},
+		{Type: "http", Options: map[string]string{"url": metadata.URL}},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	text := extractTextFromResult(callTool(t, newTestClient(t, gatewayURL), "gateway_info", nil))
+	for _, field := range []string{"build:abc123", "region:eu-west-1", "backends:map[server1:"} {
+		if !strings.Contains(text, field) {
+			t.Errorf("Expected gateway_info to include %s, got %s", field, text)
+		}
+	}
+	if strings.Contains(text, "overridden") {
+		t.Errorf("Expected built-in fields to take precedence over providers, got %s", text)
+	}
+}
+
+// TestGatewayInfoProviderConfig verifies unknown providers and bad options are rejected
+func TestGatewayInfoProviderConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.GatewayInfo.Providers = []GatewayInfoProviderConfig{{Type: "missing"}}
+	if err := config.GatewayInfo.Validate(); err == nil || !strings.Contains(err.Error(), "unknown gateway_info provider") {
+		t.Errorf("Expected an unknown provider error, got %v", err)
+	}
+
+	if _, err := newGatewayInfoProviders(GatewayInfoConfig{Providers: []GatewayInfoProviderConfig{{Type: "http"}}}); err == nil {
+		t.Error("Expected the http provider to require a url")
+	}
+}
+
+// TestGatewayInfoDuringBackendChanges hammers gateway_info while a backend's tools are
+// added and removed by refreshes and backend health is probed; run with -race
+func TestGatewayInfoDuringBackendChanges(t *testing.T) {
+	mcpServer := server.NewMCPServer("Changing", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	changing := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(changing.Close)
+	stable := newStubBackend(t, "Stable", textTool(mcp.NewTool("ping"), "pong"))
+
+	config := DefaultConfig()
+	gateway, gatewayURL := startTestGateway(t, config, changing, stable)
+	backend, _ := gateway.findBackend("server1")
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	// Add and remove a backend tool, refreshing after each change
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 20 {
+			if i%2 == 0 {
+				mcpServer.AddTool(mcp.NewTool("extra"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return mcp.NewToolResultText("extra"), nil
+				})
+			} else {
+				mcpServer.DeleteTools("extra")
+			}
+			if _, err := gateway.refreshBackend(context.Background(), backend); err != nil {
+				t.Errorf("Refresh failed: %v", err)
+			}
+		}
+	}()
+
+	// Probe backend health
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 5 {
+			gateway.checkHealth(context.Background())
+		}
+	}()
+
+	// Call gateway_info from several clients until the changes are done
+	var infoWG sync.WaitGroup
+	for range 4 {
+		mcpClient := newTestClient(t, gatewayURL)
+		infoWG.Add(1)
+		go func() {
+			defer infoWG.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				result, err := mcpClient.CallTool(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "gateway_info"}})
+				if err != nil || result.IsError {
+					t.Errorf("gateway_info failed: %v", err)
+					return
+				}
+				if text := extractTextFromResult(result); !strings.Contains(text, "backends:map[server1:") {
+					t.Errorf("Unexpected gateway_info result: %s", text)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	infoWG.Wait()
+}
@@ -0,0 +1,221 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Health probes
+const (
+	healthProbeInitialize = "initialize"
+	healthProbeToolsList  = "tools/list"
+	healthProbeTool       = "tool"
+)
+
+// HealthCheckConfig selects how a backend's health is probed. Every probe opens a
+// new connection, so a backend that restarted is seen as healthy again.
+type HealthCheckConfig struct {
+	// Probe is "tools/list" (default), "initialize" or "tool"
+	Probe string `yaml:"probe"`
+	// Tool is the backend's tool called by the tool probe (without the gateway prefix)
+	Tool string `yaml:"tool"`
+	// Arguments are passed to the tool as-is
+	Arguments map[string]any `yaml:"arguments"`
+	// ExpectText must appear in the tool result's text; when empty any result
+	// that isn't an error is healthy
+	ExpectText string `yaml:"expectText"`
+}
+
+// probe returns the configured probe, defaulting to tools/list
+func (c HealthCheckConfig) probe() string {
+	if c.Probe == "" {
+		return healthProbeToolsList
+	}
+	return c.Probe
+}
+
+// HealthConfig configures the background prober of backend health
+type HealthConfig struct {
+	// Interval between probes of every backend. 0 (default) disables the prober,
+	// and /readyz probes the backends on each request instead.
+	Interval time.Duration `yaml:"interval"`
+	// Scoring weighs the health scores reported for backends and used to prefer replicas
+	Scoring HealthScoreConfig `yaml:"scoring"`
+}
+
+// backendHealth is the outcome of a backend's latest probe
+type backendHealth struct {
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// healthMonitor holds the latest probe outcome of every backend
+type healthMonitor struct {
+	results map[string]backendHealth
+	lock    sync.Mutex
+
+	// stop ends the background prober; nil when it isn't running
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// probeBackend runs a backend's health probe over a new connection
+func (g *Gateway) probeBackend(ctx context.Context, backend BackendConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, backend.Timeouts.request())
+	defer cancel()
+
+	backendClient, _, err := g.dialBackend(ctx, backend, "MCP Gateway (Health)", nil)
+	if err != nil {
+		return err
+	}
+	defer backendClient.Close()
+
+	check := backend.HealthCheck
+	switch check.probe() {
+	case healthProbeToolsList:
+		_, err := backendClient.ListTools(ctx, mcp.ListToolsRequest{})
+		return err
+	case healthProbeTool:
+		req := mcp.CallToolRequest{}
+		req.Params.Name = check.Tool
+		req.Params.Arguments = check.Arguments
+		result, err := backendClient.CallTool(ctx, req)
+		if err != nil {
+			return err
+		}
+		text := resultText(result)
+		if result.IsError {
+			return fmt.Errorf("health tool %s returned an error: %s", check.Tool, text)
+		}
+		if !strings.Contains(text, check.ExpectText) {
+			return fmt.Errorf("health tool %s returned %q, expected %q", check.Tool, text, check.ExpectText)
+		}
+	}
+	return nil
+}
+
+// checkHealth probes every backend concurrently and records the outcomes
+func (g *Gateway) checkHealth(ctx context.Context) map[string]backendHealth {
+	outcomes := make([]backendHealth, len(g.config.Backends))
+	g.fanOut.run(g.config.Backends, func(i int, backend BackendConfig) {
+		outcomes[i] = backendHealth{Healthy: true, CheckedAt: time.Now()}
+		if err := g.probeBackend(ctx, backend); err != nil {
+			outcomes[i] = backendHealth{Error: err.Error(), CheckedAt: time.Now()}
+		}
+	})
+
+	// A backend whose dependency is unhealthy is unhealthy too; waves put
+	// dependencies first, so this covers transitive dependencies
+	byName := make(map[string]*backendHealth, len(outcomes))
+	for i, backend := range g.config.Backends {
+		byName[backend.Name] = &outcomes[i]
+	}
+	for _, wave := range startupWaves(g.config.Backends) {
+		for _, backend := range wave {
+			outcome := byName[backend.Name]
+			for _, dependency := range backend.DependsOn {
+				if outcome.Healthy && !byName[dependency].Healthy {
+					*outcome = backendHealth{
+						Error:     (&DependencyError{Backend: backend.Name, Dependency: dependency, Reason: "unhealthy"}).Error(),
+						CheckedAt: outcome.CheckedAt,
+					}
+				}
+			}
+		}
+	}
+
+	g.health.lock.Lock()
+	defer g.health.lock.Unlock()
+	for i, backend := range g.config.Backends {
+		previous, probed := g.health.results[backend.Name]
+		outcome := outcomes[i]
+		if !probed || previous.Healthy != outcome.Healthy {
+			if outcome.Healthy {
+				log.Printf("✅ Backend %s is healthy", backend.Name)
+			} else {
+				log.Printf("❌ Backend %s is unhealthy: %s", backend.Name, outcome.Error)
+			}
+		}
+		g.health.results[backend.Name] = outcome
+	}
+	return g.healthResultsLocked()
+}
+
+// healthResults returns the latest probe outcome of every backend
+func (g *Gateway) healthResults() map[string]backendHealth {
+	g.health.lock.Lock()
+	defer g.health.lock.Unlock()
+	return g.healthResultsLocked()
+}
+
+// healthResultsLocked copies the recorded outcomes; the health lock must be held
+func (g *Gateway) healthResultsLocked() map[string]backendHealth {
+	results := make(map[string]backendHealth, len(g.health.results))
+	for name, result := range g.health.results {
+		results[name] = result
+	}
+	return results
+}
+
+// startProber probes the backends now and then every configured interval until the gateway shuts down
+func (g *Gateway) startProber() {
+	interval := g.config.Health.Interval
+	if interval <= 0 {
+		return
+	}
+
+	g.health.stop = make(chan struct{})
+	g.checkHealth(context.Background())
+	log.Printf("🔧 Probing backend health every %s", interval)
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				g.checkHealth(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}(g.health.stop)
+}
+
+// stopProber ends the background prober, if running
+func (g *Gateway) stopProber() {
+	if g.health.stop != nil {
+		g.health.stopOnce.Do(func() { close(g.health.stop) })
+	}
+}
+
+// handleReadyz reports whether every backend passed its latest health probe
+// (or, without the prober, a probe made now) with 200 or 503
+func (g *Gateway) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	var results map[string]backendHealth
+	if g.health.stop != nil {
+		results = g.healthResults()
+	} else {
+		results = g.checkHealth(r.Context())
+	}
+
+	status, code := "ready", http.StatusOK
+	for _, backend := range g.config.Backends {
+		if !results[backend.Name].Healthy {
+			status, code = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{"status": status, "backends": results, "scores": g.healthScores()})
+}
@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getReadyz fetches /readyz and returns its status code and per-backend health
+func getReadyz(t *testing.T, gatewayURL string) (int, map[string]backendHealth) {
+	t.Helper()
+
+	resp, err := http.Get(gatewayURL + "/readyz")
+	if err != nil {
+		t.Fatalf("Failed to get /readyz: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Backends map[string]backendHealth `json:"backends"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode /readyz: %v", err)
+	}
+	return resp.StatusCode, body.Backends
+}
+
+// TestHealthCheckToolProbe verifies a backend's health is judged by its health tool's result,
+// both on demand and by the background prober
+func TestHealthCheckToolProbe(t *testing.T) {
+	var status atomic.Value
+	status.Store("status: ok")
+	backend := newStubBackend(t, "Checked",
+		textTool(mcp.NewTool("echo"), "hello"),
+		stubTool{
+			tool: mcp.NewTool("health", mcp.WithString("depth")),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if req.GetString("depth", "") != "full" {
+					return mcp.NewToolResultError("missing depth"), nil
+				}
+				return mcp.NewToolResultText(status.Load().(string)), nil
+			},
+		},
+	)
+	healthCheck := HealthCheckConfig{
+		Probe:      healthProbeTool,
+		Tool:       "health",
+		Arguments:  map[string]any{"depth": "full"},
+		ExpectText: "status: ok",
+	}
+
+	t.Run("on demand", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, HealthCheck: healthCheck}}
+		_, gatewayURL := startTestGateway(t, config)
+
+		status.Store("status: ok")
+		if code, backends := getReadyz(t, gatewayURL); code != http.StatusOK || !backends["server1"].Healthy {
+			t.Fatalf("Expected a ready gateway, got %d %+v", code, backends)
+		}
+
+		// The backend still answers tools/list, but its health tool reports a problem
+		status.Store("status: degraded")
+		code, backends := getReadyz(t, gatewayURL)
+		if code != http.StatusServiceUnavailable || backends["server1"].Healthy || backends["server1"].Error == "" {
+			t.Fatalf("Expected an unavailable gateway with the probe error, got %d %+v", code, backends)
+		}
+	})
+
+	t.Run("prober", func(t *testing.T) {
+		status.Store("status: ok")
+		config := DefaultConfig()
+		config.Health.Interval = 20 * time.Millisecond
+		config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, HealthCheck: healthCheck}}
+		gateway, gatewayURL := startTestGateway(t, config)
+		t.Cleanup(func() { gateway.Shutdown(context.Background()) })
+
+		if code, _ := getReadyz(t, gatewayURL); code != http.StatusOK {
+			t.Fatalf("Expected a ready gateway after the startup probe, got %d", code)
+		}
+
+		status.Store("status: degraded")
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			code, _ := getReadyz(t, gatewayURL)
+			if code == http.StatusServiceUnavailable {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("Prober did not mark the backend unhealthy")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// TestHealthCheckConfigValidation verifies the tool probe requires a tool
+func TestHealthCheckConfigValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: "http://localhost:1", HealthCheck: HealthCheckConfig{Probe: healthProbeTool}}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected the tool probe without a tool to be rejected")
+	}
+
+	config.Backends[0].HealthCheck = HealthCheckConfig{Probe: "ping"}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an unknown probe to be rejected")
+	}
+}
+
+// TestHalfOpenHealthProbe verifies a half-open circuit is probed with the backend's health
+// check rather than a client call, and client calls resume once the probe succeeds
+func TestHalfOpenHealthProbe(t *testing.T) {
+	var healthy atomic.Bool
+	var echoCalls, healthCalls atomic.Int32
+	backend := newStubBackend(t, "Recovering",
+		stubTool{
+			tool: mcp.NewTool("echo"),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				echoCalls.Add(1)
+				return mcp.NewToolResultText("hello"), nil
+			},
+		},
+		stubTool{
+			tool: mcp.NewTool("health"),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				healthCalls.Add(1)
+				if !healthy.Load() {
+					return mcp.NewToolResultText("status: down"), nil
+				}
+				return mcp.NewToolResultText("status: ok"), nil
+			},
+		},
+	)
+
+	config := DefaultConfig()
+	config.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: 10 * time.Millisecond, ProbeWithHealthCheck: true}
+	config.Backends = []BackendConfig{{
+		Name:        "server1",
+		URL:         backend.URL,
+		HealthCheck: HealthCheckConfig{Probe: healthProbeTool, Tool: "health", ExpectText: "status: ok"},
+	}}
+	gateway, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Open the circuit and wait for it to go half-open
+	gateway.breakers["server1"].recordFailure()
+	time.Sleep(20 * time.Millisecond)
+	if state := gateway.breakers["server1"].state(); state != circuitHalfOpen {
+		t.Fatalf("Expected a half-open circuit, got %s", state)
+	}
+
+	// The probe fails, so the client's call is rejected without reaching the backend
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); !strings.Contains(text, "circuit open") {
+		t.Errorf("Expected the call to be rejected after a failed probe, got %q", text)
+	}
+	if echoCalls.Load() != 0 || healthCalls.Load() != 1 {
+		t.Errorf("Expected only the health check to be called, got %d echo and %d health calls", echoCalls.Load(), healthCalls.Load())
+	}
+
+	// Once the backend recovers the probe succeeds and the call goes through
+	healthy.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); text != "hello" {
+		t.Errorf("Expected the call to succeed after a successful probe, got %q", text)
+	}
+	if echoCalls.Load() != 1 || healthCalls.Load() != 2 {
+		t.Errorf("Expected the health check before the call, got %d echo and %d health calls", echoCalls.Load(), healthCalls.Load())
+	}
+	if state := gateway.breakers["server1"].state(); state != circuitClosed {
+		t.Errorf("Expected the circuit to close, got %s", state)
+	}
+}
@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Health scoring defaults
+const (
+	defaultHealthScoreWindow        = 20
+	defaultHealthScoreLatencyTarget = time.Second
+	defaultHealthScoreErrorWeight   = 50
+	defaultHealthScoreLatencyWeight = 30
+	defaultHealthScoreCircuitWeight = 20
+)
+
+// HealthScoreConfig weighs the signals combined into a backend's 0-100 health score:
+// the error rate and average latency of its recent calls, and its circuit state
+type HealthScoreConfig struct {
+	// Window is how many recent calls the error rate and latency are taken over (default 20)
+	Window int `yaml:"window"`
+	// LatencyTarget is the average latency that still scores full marks; slower averages
+	// score in proportion, so twice the target scores half (default 1s)
+	LatencyTarget time.Duration `yaml:"latencyTarget"`
+	// The weights are relative; all zero (default) means 50 errors, 30 latency, 20 circuit
+	ErrorWeight   float64 `yaml:"errorWeight"`
+	LatencyWeight float64 `yaml:"latencyWeight"`
+	CircuitWeight float64 `yaml:"circuitWeight"`
+}
+
+// Validate checks the window, target and weights are not negative
+func (c HealthScoreConfig) Validate() error {
+	if c.Window < 0 || c.LatencyTarget < 0 {
+		return fmt.Errorf("health.scoring settings must not be negative")
+	}
+	if c.ErrorWeight < 0 || c.LatencyWeight < 0 || c.CircuitWeight < 0 {
+		return fmt.Errorf("health.scoring weights must not be negative")
+	}
+	return nil
+}
+
+// withDefaults fills in the defaults of unset settings
+func (c HealthScoreConfig) withDefaults() HealthScoreConfig {
+	if c.Window == 0 {
+		c.Window = defaultHealthScoreWindow
+	}
+	if c.LatencyTarget == 0 {
+		c.LatencyTarget = defaultHealthScoreLatencyTarget
+	}
+	if c.ErrorWeight == 0 && c.LatencyWeight == 0 && c.CircuitWeight == 0 {
+		c.ErrorWeight = defaultHealthScoreErrorWeight
+		c.LatencyWeight = defaultHealthScoreLatencyWeight
+		c.CircuitWeight = defaultHealthScoreCircuitWeight
+	}
+	return c
+}
+
+// callSample is the outcome of one call
+type callSample struct {
+	latency time.Duration
+	failed  bool
+}
+
+// healthScorer keeps the recent calls of a backend or replica and scores its health
+type healthScorer struct {
+	config  HealthScoreConfig
+	breaker *circuitBreaker
+
+	lock    sync.Mutex
+	samples []callSample
+	next    int
+}
+
+// newHealthScorer creates a scorer; breaker supplies the circuit state
+func newHealthScorer(config HealthScoreConfig, breaker *circuitBreaker) *healthScorer {
+	config = config.withDefaults()
+	return &healthScorer{config: config, breaker: breaker, samples: make([]callSample, 0, config.Window)}
+}
+
+// record adds a call outcome, replacing the oldest once the window is full
+func (s *healthScorer) record(latency time.Duration, failed bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sample := callSample{latency: latency, failed: failed}
+	if len(s.samples) < s.config.Window {
+		s.samples = append(s.samples, sample)
+		return
+	}
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % s.config.Window
+}
+
+// score returns the weighted health score from 0 (down) to 100 (healthy). With no
+// recent calls the error and latency signals count as healthy.
+func (s *healthScorer) score() int {
+	s.lock.Lock()
+	errorSignal, latencySignal := 1.0, 1.0
+	if len(s.samples) > 0 {
+		var failures int
+		var total time.Duration
+		for _, sample := range s.samples {
+			if sample.failed {
+				failures++
+			}
+			total += sample.latency
+		}
+		errorSignal = 1 - float64(failures)/float64(len(s.samples))
+		if average := total / time.Duration(len(s.samples)); average > s.config.LatencyTarget {
+			latencySignal = float64(s.config.LatencyTarget) / float64(average)
+		}
+	}
+	s.lock.Unlock()
+
+	circuitSignal := 1.0
+	switch s.breaker.state() {
+	case circuitOpen:
+		circuitSignal = 0
+	case circuitHalfOpen:
+		circuitSignal = 0.5
+	}
+
+	weights := s.config.ErrorWeight + s.config.LatencyWeight + s.config.CircuitWeight
+	weighted := s.config.ErrorWeight*errorSignal + s.config.LatencyWeight*latencySignal + s.config.CircuitWeight*circuitSignal
+	return int(math.Round(100 * weighted / weights))
+}
+
+// healthScores returns the health score of every backend
+func (g *Gateway) healthScores() map[string]int {
+	scores := make(map[string]int, len(g.scores))
+	for name, scorer := range g.scores {
+		scores[name] = scorer.score()
+	}
+	return scores
+}
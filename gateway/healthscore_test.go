@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHealthScore verifies the score falls with slow calls, failed calls and an open
+// circuit, and recovers as healthy calls replace them in the window
+func TestHealthScore(t *testing.T) {
+	breaker := newCircuitBreaker("server1", CircuitBreakerConfig{FailureThreshold: 3})
+	scorer := newHealthScorer(HealthScoreConfig{Window: 4, LatencyTarget: 100 * time.Millisecond}, breaker)
+
+	if score := scorer.score(); score != 100 {
+		t.Errorf("Expected a backend without calls to score 100, got %d", score)
+	}
+
+	// Mostly healthy but slow: twice the latency target halves the latency signal
+	for i := 0; i < 4; i++ {
+		scorer.record(200*time.Millisecond, false)
+	}
+	slow := scorer.score()
+	if slow != 85 {
+		t.Errorf("Expected slow calls to score 85, got %d", slow)
+	}
+
+	// Half the window failing lowers the score further
+	scorer.record(200*time.Millisecond, true)
+	scorer.record(200*time.Millisecond, true)
+	failing := scorer.score()
+	if failing >= slow {
+		t.Errorf("Expected failures to lower the score below %d, got %d", slow, failing)
+	}
+
+	// An open circuit lowers it again
+	for i := 0; i < 3; i++ {
+		breaker.recordFailure()
+	}
+	if open := scorer.score(); open >= failing {
+		t.Errorf("Expected an open circuit to lower the score below %d, got %d", failing, open)
+	}
+
+	// Fast, successful calls push the old samples out of the window
+	breaker.recordSuccess()
+	for i := 0; i < 4; i++ {
+		scorer.record(10*time.Millisecond, false)
+	}
+	if score := scorer.score(); score != 100 {
+		t.Errorf("Expected a recovered backend to score 100, got %d", score)
+	}
+}
+
+// TestHealthScoreWeights verifies the configured weights decide how much each signal counts
+func TestHealthScoreWeights(t *testing.T) {
+	breaker := newCircuitBreaker("server1", CircuitBreakerConfig{})
+	errorsOnly := newHealthScorer(HealthScoreConfig{Window: 2, ErrorWeight: 1}, breaker)
+	errorsOnly.record(10*time.Second, false)
+	errorsOnly.record(10*time.Second, true)
+	if score := errorsOnly.score(); score != 50 {
+		t.Errorf("Expected only the error rate to count, got %d", score)
+	}
+}
+
+// TestHealthScoreRouting verifies new sessions prefer the healthier of two replicas in a tier
+func TestHealthScoreRouting(t *testing.T) {
+	var failing atomic.Bool
+	var firstRequests, secondRequests atomic.Int64
+	first := newReplicaBackend(t, "first", &failing, &firstRequests)
+	second := newReplicaBackend(t, "second", &failing, &secondRequests)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:     "server1",
+		Replicas: []ReplicaConfig{{URL: first.URL}, {URL: second.URL}},
+	}}
+	gateway, gatewayURL := startTestGateway(t, config)
+
+	before := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, before, "server1-whoami", nil)); text != "first" {
+		t.Fatalf("Expected equally healthy replicas to keep config order, got %q", text)
+	}
+
+	// The first replica turns error-prone, though its circuit stays closed
+	for i := 0; i < 10; i++ {
+		gateway.replicas["server1"][0].score.record(time.Millisecond, i%2 == 0)
+	}
+	after := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, after, "server1-whoami", nil)); text != "second" {
+		t.Errorf("Expected the healthier replica to be preferred, got %q", text)
+	}
+}
@@ -0,0 +1,21 @@
+package gateway
+
+import (
+	"net/http"
+)
+
+// newBackendHTTPClient builds the HTTP client for a backend's outbound requests from
+// its pipeline (see backendPipeline for the stages and their order). Request signing
+// wraps the base transport directly so that it runs last and the signature covers the
+// body exactly as sent. Response checking is outermost so non-MCP responses and
+// malformed JSON-RPC messages are turned into errors before the MCP client parses
+// them, apart from header propagation, which only sees responses that passed the check.
+// The backend's throttle is paused by 429 responses. sessionID is the client session
+// the connection serves, empty for the gateway's own.
+func (g *Gateway) newBackendHTTPClient(backend BackendConfig, sessionID string) (*http.Client, error) {
+	roundTripper, err := composePipeline(g.backendPipeline(backend, sessionID))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: roundTripper}, nil
+}
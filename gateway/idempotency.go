@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// Name of the _meta field carrying a tool call's idempotency key
+	idempotencyKeyMeta = "idempotencyKey"
+	// Result _meta field marking a result replayed from an earlier call
+	idempotentReplayMeta = "idempotentReplay"
+)
+
+// IdempotencyConfig deduplicates retried tool calls that carry an idempotency key
+type IdempotencyConfig struct {
+	// Tools enables deduplication for individual gateway tool names
+	Tools map[string]ToolIdempotencyConfig `yaml:"tools"`
+}
+
+// ToolIdempotencyConfig configures deduplication for a single tool
+type ToolIdempotencyConfig struct {
+	// TTL is how long a key is remembered after the first call
+	TTL time.Duration `yaml:"ttl"`
+	// KeyArgument names an argument accepted as the key in addition to _meta.idempotencyKey;
+	// it is removed before the call is forwarded
+	KeyArgument string `yaml:"keyArgument"`
+}
+
+// idempotencyRecord is the stored state of a keyed call
+type idempotencyRecord struct {
+	// Pending is set while the first call is running
+	Pending bool `json:"pending,omitempty"`
+	// Arguments fingerprints the arguments the key was first used with
+	Arguments string `json:"arguments"`
+	// Result is the first call's result
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// idempotencyKey returns a call's idempotency key, if any, and the request with the key argument removed
+func idempotencyKey(config ToolIdempotencyConfig, req mcp.CallToolRequest) (string, mcp.CallToolRequest) {
+	var key string
+	if req.Params.Meta != nil {
+		key, _ = req.Params.Meta.AdditionalFields[idempotencyKeyMeta].(string)
+	}
+
+	args, ok := req.Params.Arguments.(map[string]any)
+	if config.KeyArgument == "" || !ok {
+		return key, req
+	}
+	if value, ok := args[config.KeyArgument]; ok {
+		if key == "" {
+			key, _ = value.(string)
+		}
+		stripped := make(map[string]any, len(args))
+		for name, value := range args {
+			if name != config.KeyArgument {
+				stripped[name] = value
+			}
+		}
+		req.Params.Arguments = stripped
+	}
+	return key, req
+}
+
+// argumentsFingerprint hashes call arguments so a key reused with different arguments is detected
+func argumentsFingerprint(arguments any) (string, error) {
+	// encoding/json sorts map keys, giving a canonical encoding
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// callIdempotent runs call once per idempotency key within the tool's TTL, replaying the
+// first successful result to retries. Failed calls are forgotten so they can be retried.
+func (g *Gateway) callIdempotent(ctx context.Context, toolName string, req mcp.CallToolRequest, call func(mcp.CallToolRequest) (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+	toolConfig, ok := g.config.Idempotency.Tools[toolName]
+	if !ok {
+		return call(req)
+	}
+	key, req := idempotencyKey(toolConfig, req)
+	if key == "" {
+		return call(req)
+	}
+
+	fingerprint, err := argumentsFingerprint(req.Params.Arguments)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	storeKey := "idempotency\x00" + toolName + "\x00" + key
+
+	pending, _ := json.Marshal(idempotencyRecord{Pending: true, Arguments: fingerprint})
+	first, err := g.store.SetIfAbsent(ctx, storeKey, pending, toolConfig.TTL)
+	if err != nil {
+		log.Printf("❌ Idempotency store failed for %s: %v", toolName, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Idempotency store error: %v", err)), nil
+	}
+	if !first {
+		return g.replayIdempotent(ctx, toolName, storeKey, fingerprint)
+	}
+
+	result, err := call(req)
+
+	// The store must be updated even if the client has gone away
+	storeCtx := context.WithoutCancel(ctx)
+	if err != nil || result.IsError {
+		if deleteErr := g.store.Delete(storeCtx, storeKey); deleteErr != nil {
+			log.Printf("❌ Failed to release idempotency key for %s: %v", toolName, deleteErr)
+		}
+		return result, err
+	}
+
+	data, err := json.Marshal(result)
+	if err == nil {
+		record, _ := json.Marshal(idempotencyRecord{Arguments: fingerprint, Result: data})
+		err = g.store.Set(storeCtx, storeKey, record, toolConfig.TTL)
+	}
+	if err != nil {
+		log.Printf("❌ Failed to record idempotent result of %s: %v", toolName, err)
+	}
+	return result, nil
+}
+
+// replayIdempotent returns the stored result of an earlier call with the same key
+func (g *Gateway) replayIdempotent(ctx context.Context, toolName, storeKey, fingerprint string) (*mcp.CallToolResult, error) {
+	data, ok, err := g.store.Get(ctx, storeKey)
+	if err != nil {
+		log.Printf("❌ Idempotency store failed for %s: %v", toolName, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Idempotency store error: %v", err)), nil
+	}
+	if !ok {
+		// The first call failed or its key expired in between
+		return mcp.NewToolResultError("A call with this idempotency key was not completed: retry"), nil
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid idempotency record: %v", err)), nil
+	}
+	if record.Arguments != fingerprint {
+		return mcp.NewToolResultError("Idempotency key was already used with different arguments"), nil
+	}
+	if record.Pending {
+		return mcp.NewToolResultError("A call with this idempotency key is still in progress: retry later"), nil
+	}
+
+	raw := json.RawMessage(record.Result)
+	result, err := mcp.ParseCallToolResult(&raw)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid idempotency record: %v", err)), nil
+	}
+	if result.Meta == nil {
+		result.Meta = make(map[string]any)
+	}
+	result.Meta[idempotentReplayMeta] = true
+
+	log.Printf("✅ Tool call %s replayed from its idempotency key", toolName)
+	return result, nil
+}
+
+// validateIdempotencyTools checks that every deduplicated tool exists
+func (g *Gateway) validateIdempotencyTools() error {
+	for toolName := range g.config.Idempotency.Tools {
+		if _, ok := g.lookupTool(toolName); !ok {
+			return fmt.Errorf("idempotency.tools.%s: tool not found", toolName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callToolWithKey calls a tool through the gateway with an idempotency key in _meta
+func callToolWithKey(t *testing.T, mcpClient *client.Client, name, key string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{idempotencyKeyMeta: key}}
+	result, err := mcpClient.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to call %s: %v", name, err)
+	}
+	return result
+}
+
+// TestIdempotentRetry verifies retries with the same key are served without re-invoking the backend
+func TestIdempotentRetry(t *testing.T) {
+	var calls atomic.Int64
+	var lastArgs atomic.Value
+	backend := newStubBackend(t, "Orders", stubTool{
+		tool: mcp.NewTool("create_order", mcp.WithString("item")),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			n := calls.Add(1)
+			lastArgs.Store(fmt.Sprint(req.GetArguments()))
+			return mcp.NewToolResultText(fmt.Sprintf("order %d", n)), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Idempotency.Tools = map[string]ToolIdempotencyConfig{
+		"server1-create_order": {TTL: time.Minute, KeyArgument: "requestId"},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	args := map[string]any{"item": "book"}
+	first := callToolWithKey(t, mcpClient, "server1-create_order", "key-1", args)
+	retry := callToolWithKey(t, mcpClient, "server1-create_order", "key-1", args)
+	if calls.Load() != 1 {
+		t.Fatalf("Expected the backend to run once, got %d", calls.Load())
+	}
+	if extractTextFromResult(first) != "order 1" || extractTextFromResult(retry) != "order 1" {
+		t.Errorf("Expected both calls to return order 1, got %q and %q",
+			extractTextFromResult(first), extractTextFromResult(retry))
+	}
+	if retry.Meta[idempotentReplayMeta] != true {
+		t.Errorf("Expected the retry to be marked as a replay, got meta %v", retry.Meta)
+	}
+
+	// Reusing the key with other arguments is rejected
+	conflict := callToolWithKey(t, mcpClient, "server1-create_order", "key-1", map[string]any{"item": "pen"})
+	if !conflict.IsError || calls.Load() != 1 {
+		t.Errorf("Expected a reused key with different arguments to be rejected, got %q", extractTextFromResult(conflict))
+	}
+
+	// The key argument is accepted and not forwarded
+	keyed := map[string]any{"item": "pen", "requestId": "key-2"}
+	callTool(t, mcpClient, "server1-create_order", keyed)
+	callTool(t, mcpClient, "server1-create_order", keyed)
+	if calls.Load() != 2 {
+		t.Errorf("Expected a new key to run the backend once more, got %d calls", calls.Load())
+	}
+	if got := lastArgs.Load(); got != "map[item:pen]" {
+		t.Errorf("Expected the key argument to be removed, backend got %v", got)
+	}
+
+	// Calls without a key are never deduplicated
+	callTool(t, mcpClient, "server1-create_order", args)
+	if calls.Load() != 3 {
+		t.Errorf("Expected an unkeyed call to run the backend, got %d calls", calls.Load())
+	}
+}
+
+// TestIdempotencyRetriesFailures verifies a failed call doesn't hold its key
+func TestIdempotencyRetriesFailures(t *testing.T) {
+	var calls atomic.Int64
+	backend := newStubBackend(t, "Flaky", stubTool{
+		tool: mcp.NewTool("charge"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if calls.Add(1) == 1 {
+				return mcp.NewToolResultError("temporarily unavailable"), nil
+			}
+			return mcp.NewToolResultText("charged"), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Idempotency.Tools = map[string]ToolIdempotencyConfig{"server1-charge": {TTL: time.Minute}}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if result := callToolWithKey(t, mcpClient, "server1-charge", "k", nil); !result.IsError {
+		t.Fatalf("Expected the first call to fail, got %q", extractTextFromResult(result))
+	}
+	if result := callToolWithKey(t, mcpClient, "server1-charge", "k", nil); extractTextFromResult(result) != "charged" {
+		t.Fatalf("Expected the retry to reach the backend, got %q", extractTextFromResult(result))
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 backend calls, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"net/http"
+)
+
+// Version is the gateway version reported to clients and backends
+const Version = "1.0.0"
+
+// Headers identifying the gateway on outbound backend requests
+const (
+	headerForwardedBy      = "X-Forwarded-By"
+	headerGatewaySessionID = "X-Gateway-Session-Id"
+)
+
+// IdentityConfig sets the headers that identify the gateway's traffic to backends.
+// Set at the top level it applies to every backend; a backend's own settings
+// override it field by field.
+type IdentityConfig struct {
+	// UserAgent is sent as User-Agent (default mcp-gateway/<version>)
+	UserAgent string `yaml:"userAgent"`
+	// ForwardedBy is sent as X-Forwarded-By (default mcp-gateway)
+	ForwardedBy string `yaml:"forwardedBy"`
+	// IncludeSessionID sends the client session ID as X-Gateway-Session-Id on the
+	// connections made for that session (default false)
+	IncludeSessionID *bool `yaml:"includeSessionID"`
+}
+
+// merge returns the identity with every setting present in override applied on top
+func (c IdentityConfig) merge(override IdentityConfig) IdentityConfig {
+	if override.UserAgent != "" {
+		c.UserAgent = override.UserAgent
+	}
+	if override.ForwardedBy != "" {
+		c.ForwardedBy = override.ForwardedBy
+	}
+	if override.IncludeSessionID != nil {
+		c.IncludeSessionID = override.IncludeSessionID
+	}
+	return c
+}
+
+// userAgent returns the configured User-Agent, defaulting to the gateway name and version
+func (c IdentityConfig) userAgent() string {
+	if c.UserAgent == "" {
+		return "mcp-gateway/" + Version
+	}
+	return c.UserAgent
+}
+
+// forwardedBy returns the configured X-Forwarded-By value
+func (c IdentityConfig) forwardedBy() string {
+	if c.ForwardedBy == "" {
+		return "mcp-gateway"
+	}
+	return c.ForwardedBy
+}
+
+// identifyingRoundTripper adds the gateway's identifying headers to outbound requests
+type identifyingRoundTripper struct {
+	next      http.RoundTripper
+	identity  IdentityConfig
+	sessionID string
+}
+
+func (i *identifyingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", i.identity.userAgent())
+	req.Header.Set(headerForwardedBy, i.identity.forwardedBy())
+	if i.sessionID != "" && i.identity.IncludeSessionID != nil && *i.identity.IncludeSessionID {
+		req.Header.Set(headerGatewaySessionID, i.sessionID)
+	}
+	return i.next.RoundTrip(req)
+}
+
+// identity returns the backend's identifying headers, its own settings overriding the gateway's
+func (g *Gateway) identity(backend BackendConfig) IdentityConfig {
+	return g.config.Identity.merge(backend.Identity)
+}
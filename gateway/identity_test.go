@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newHeaderRecordingBackend serves a stub backend and records the headers of every request
+func newHeaderRecordingBackend(t *testing.T) (*httptest.Server, func() []http.Header) {
+	t.Helper()
+	stub := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	var lock sync.Mutex
+	var headers []http.Header
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		headers = append(headers, r.Header.Clone())
+		lock.Unlock()
+		stub.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	return backend, func() []http.Header {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]http.Header(nil), headers...)
+	}
+}
+
+// TestIdentityHeaders verifies backends see the default identity, and that a backend's
+// own settings override the gateway-wide ones
+func TestIdentityHeaders(t *testing.T) {
+	plain, plainHeaders := newHeaderRecordingBackend(t)
+	custom, customHeaders := newHeaderRecordingBackend(t)
+
+	includeSessionID := true
+	config := DefaultConfig()
+	config.Identity = IdentityConfig{ForwardedBy: "edge-gateway", IncludeSessionID: &includeSessionID}
+	config.Backends = []BackendConfig{
+		{Name: "server1", URL: plain.URL},
+		{Name: "server2", URL: custom.URL, Identity: IdentityConfig{UserAgent: "billing-gateway/2.0"}},
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+	callTool(t, mcpClient, "server1-echo", nil)
+	callTool(t, mcpClient, "server2-echo", nil)
+
+	sessionIDs := make(map[string]bool)
+	for _, header := range plainHeaders() {
+		if got := header.Get("User-Agent"); got != "mcp-gateway/"+Version {
+			t.Errorf("Expected the default User-Agent, got %q", got)
+		}
+		if got := header.Get(headerForwardedBy); got != "edge-gateway" {
+			t.Errorf("Expected X-Forwarded-By edge-gateway, got %q", got)
+		}
+		if id := header.Get(headerGatewaySessionID); id != "" {
+			sessionIDs[id] = true
+		}
+	}
+	if len(sessionIDs) != 1 {
+		t.Errorf("Expected the client session ID on the session's requests, got %v", sessionIDs)
+	}
+	for id := range sessionIDs {
+		if !strings.HasPrefix(id, "mcp-session-") {
+			t.Errorf("Expected a gateway client session ID, got %q", id)
+		}
+	}
+
+	for _, header := range customHeaders() {
+		if got := header.Get("User-Agent"); got != "billing-gateway/2.0" {
+			t.Errorf("Expected the backend's User-Agent, got %q", got)
+		}
+		if got := header.Get(headerForwardedBy); got != "edge-gateway" {
+			t.Errorf("Expected the gateway-wide X-Forwarded-By, got %q", got)
+		}
+	}
+}
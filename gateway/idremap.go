@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// requestIDRemapper assigns gateway-unique JSON-RPC IDs to outbound backend requests
+// and remembers the original ID so the response can be mapped back to it
+type requestIDRemapper struct {
+	nextID  atomic.Int64
+	pending map[int64]mcp.RequestId
+	lock    sync.Mutex
+}
+
+// newRequestIDRemapper creates an empty request ID remapper
+func newRequestIDRemapper() *requestIDRemapper {
+	return &requestIDRemapper{
+		pending: make(map[int64]mcp.RequestId),
+	}
+}
+
+// assign allocates a gateway-unique ID for an outbound request and records the original ID
+func (r *requestIDRemapper) assign(original mcp.RequestId) int64 {
+	gatewayID := r.nextID.Add(1)
+
+	r.lock.Lock()
+	r.pending[gatewayID] = original
+	r.lock.Unlock()
+
+	return gatewayID
+}
+
+// release removes the mapping for a gateway ID and returns the original request ID
+func (r *requestIDRemapper) release(gatewayID int64) (mcp.RequestId, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	original, ok := r.pending[gatewayID]
+	delete(r.pending, gatewayID)
+	return original, ok
+}
+
+// inFlight returns the number of requests currently awaiting a response
+func (r *requestIDRemapper) inFlight() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.pending)
+}
+
+// remappingTransport wraps a backend transport so that every request it sends
+// carries a gateway-unique ID, regardless of which client session issued it
+type remappingTransport struct {
+	transport.Interface
+	remapper *requestIDRemapper
+}
+
+// newRemappingTransport wraps a backend transport with the given ID remapper
+func newRemappingTransport(next transport.Interface, remapper *requestIDRemapper) *remappingTransport {
+	return &remappingTransport{
+		Interface: next,
+		remapper:  remapper,
+	}
+}
+
+// SendRequest rewrites the request ID before forwarding and restores it on the response
+func (t *remappingTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	originalID := request.ID
+	gatewayID := t.remapper.assign(originalID)
+	defer t.remapper.release(gatewayID)
+
+	request.ID = mcp.NewRequestId(gatewayID)
+
+	response, err := t.Interface.SendRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.ID.IsNil() && response.ID.String() != request.ID.String() {
+		return nil, fmt.Errorf("backend responded with ID %s to request %s", response.ID.String(), request.ID.String())
+	}
+
+	response.ID = originalID
+	return response, nil
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// collisionDetectingTransport is a fake backend that fails if two in-flight requests share an ID
+// and echoes the request params back as the result
+type collisionDetectingTransport struct {
+	transport.Interface
+	inFlight map[string]bool
+	lock     sync.Mutex
+}
+
+func (f *collisionDetectingTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	key := request.ID.String()
+
+	f.lock.Lock()
+	if f.inFlight[key] {
+		f.lock.Unlock()
+		return nil, fmt.Errorf("request ID collision on %s", key)
+	}
+	f.inFlight[key] = true
+	f.lock.Unlock()
+
+	// Hold the request open so concurrent sessions overlap
+	time.Sleep(5 * time.Millisecond)
+
+	f.lock.Lock()
+	delete(f.inFlight, key)
+	f.lock.Unlock()
+
+	result, err := json.Marshal(request.Params)
+	if err != nil {
+		return nil, err
+	}
+	return &transport.JSONRPCResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      request.ID,
+		Result:  result,
+	}, nil
+}
+
+// TestRequestIDRemappingConcurrentSessions verifies that sessions reusing the same request ID are demuxed correctly
+func TestRequestIDRemappingConcurrentSessions(t *testing.T) {
+	backend := &collisionDetectingTransport{inFlight: make(map[string]bool)}
+	remapper := newRequestIDRemapper()
+
+	const sessions = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, sessions)
+
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func(sessionNum int) {
+			defer wg.Done()
+
+			// Each session has its own transport wrapper but shares the gateway remapper
+			sessionTransport := newRemappingTransport(backend, remapper)
+			request := transport.JSONRPCRequest{
+				JSONRPC: mcp.JSONRPC_VERSION,
+				ID:      mcp.NewRequestId(int64(1)),
+				Method:  "tools/call",
+				Params:  map[string]int{"session": sessionNum},
+			}
+
+			response, err := sessionTransport.SendRequest(context.Background(), request)
+			if err != nil {
+				errs <- fmt.Errorf("session %d: %v", sessionNum, err)
+				return
+			}
+
+			if response.ID.String() != mcp.NewRequestId(int64(1)).String() {
+				errs <- fmt.Errorf("session %d: expected original ID 1, got %s", sessionNum, response.ID.String())
+				return
+			}
+
+			var result map[string]int
+			if err := json.Unmarshal(response.Result, &result); err != nil {
+				errs <- fmt.Errorf("session %d: %v", sessionNum, err)
+				return
+			}
+			if result["session"] != sessionNum {
+				errs <- fmt.Errorf("session %d received response for session %d", sessionNum, result["session"])
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if n := remapper.inFlight(); n != 0 {
+		t.Fatalf("Expected all ID mappings to be cleaned up, %d remain", n)
+	}
+}
@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeRequestKey is the context key for the initialize request details
+type initializeRequestKey struct{}
+
+// initializeRequest holds the parts of an initialize request mcp-go does not expose
+type initializeRequest struct {
+	ID         json.RawMessage
+	Meta       map[string]any
+	ClientInfo mcp.Implementation
+	// Capabilities holds the client capabilities as sent; mcp-go's ClientCapabilities
+	// has no field for newer ones such as elicitation
+	Capabilities map[string]json.RawMessage
+}
+
+// initializeMetaMiddleware marks initialize requests and makes their _meta available to
+// initialize hooks. mcp-go's InitializeRequest does not decode params._meta.
+func (g *Gateway) initializeMetaMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Mcp-Session-Id") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				Meta         map[string]any             `json:"_meta"`
+				ClientInfo   mcp.Implementation         `json:"clientInfo"`
+				Capabilities map[string]json.RawMessage `json:"capabilities"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodInitialize) {
+			request := &initializeRequest{ID: message.ID, Meta: message.Params.Meta, ClientInfo: message.Params.ClientInfo, Capabilities: message.Params.Capabilities}
+			r = r.WithContext(context.WithValue(r.Context(), initializeRequestKey{}, request))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// initializeMetaFromContext returns the _meta sent with the initialize request, if any
+func initializeMetaFromContext(ctx context.Context) map[string]any {
+	if request := initializeRequestFromContext(ctx); request != nil {
+		return request.Meta
+	}
+	return nil
+}
+
+// initializeRequestFromContext returns the initialize request being handled, or nil for other requests
+func initializeRequestFromContext(ctx context.Context) *initializeRequest {
+	request, _ := ctx.Value(initializeRequestKey{}).(*initializeRequest)
+	return request
+}
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// InputLimitsConfig caps the size of tool call arguments before calls are forwarded
+type InputLimitsConfig struct {
+	// MaxArgumentBytes caps each argument's JSON-encoded size; 0 disables the limit
+	MaxArgumentBytes int `yaml:"maxArgumentBytes"`
+	// Tools overrides MaxArgumentBytes for individual gateway tool names (0 disables)
+	Tools map[string]int `yaml:"tools"`
+}
+
+// Validate checks the limits are not negative
+func (c InputLimitsConfig) Validate() error {
+	if c.MaxArgumentBytes < 0 {
+		return fmt.Errorf("inputLimits.maxArgumentBytes must not be negative")
+	}
+	for tool, limit := range c.Tools {
+		if limit < 0 {
+			return fmt.Errorf("inputLimits.tools.%s must not be negative", tool)
+		}
+	}
+	return nil
+}
+
+// InputTooLargeError is returned for a tool call with an argument over the tool's limit
+type InputTooLargeError struct {
+	Tool     string
+	Argument string
+	Size     int
+	Limit    int
+}
+
+func (e *InputTooLargeError) Error() string {
+	return fmt.Sprintf("Argument %q of %s is %d bytes, over the tool's %d byte limit", e.Argument, e.Tool, e.Size, e.Limit)
+}
+
+// maxArgumentBytes returns the argument size limit for a gateway tool name
+func (g *Gateway) maxArgumentBytes(toolName string) int {
+	if limit, ok := g.config.InputLimits.Tools[toolName]; ok {
+		return limit
+	}
+	return g.config.InputLimits.MaxArgumentBytes
+}
+
+// checkInputSize returns an error for the first argument, in name order, over the tool's
+// size limit
+func (g *Gateway) checkInputSize(toolName string, arguments any) error {
+	limit := g.maxArgumentBytes(toolName)
+	args, ok := arguments.(map[string]any)
+	if limit <= 0 || !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		encoded, err := json.Marshal(args[name])
+		if err != nil {
+			return fmt.Errorf("failed to encode argument %q: %w", name, err)
+		}
+		if len(encoded) > limit {
+			return &InputTooLargeError{Tool: toolName, Argument: name, Size: len(encoded), Limit: limit}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestInputLimits verifies an oversized argument to a tool with a small limit is rejected
+// before reaching the backend, while a tool with a generous limit accepts it
+func TestInputLimits(t *testing.T) {
+	backend := newStubBackend(t, "Backend",
+		textTool(mcp.NewTool("lookup"), "found"),
+		textTool(mcp.NewTool("summarize"), "summary"),
+	)
+
+	config := DefaultConfig()
+	config.InputLimits = InputLimitsConfig{
+		MaxArgumentBytes: 64,
+		Tools:            map[string]int{"server1-summarize": 1 << 20},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	document := strings.Repeat("lorem ipsum ", 100)
+
+	result := callTool(t, mcpClient, "server1-lookup", map[string]any{"key": "id", "query": document})
+	if text := extractTextFromResult(result); !result.IsError || !strings.Contains(text, `Argument "query" of server1-lookup is 1202 bytes, over the tool's 64 byte limit`) {
+		t.Errorf("Expected the oversized argument to be rejected, got %+v", result)
+	}
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-lookup", map[string]any{"query": "id"})); text != "found" {
+		t.Errorf("Expected a small argument to be accepted, got %q", text)
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-summarize", map[string]any{"document": document})); text != "summary" {
+		t.Errorf("Expected the generous limit to accept the document, got %q", text)
+	}
+}
@@ -0,0 +1,124 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// InstanceConfig is a named gateway with its own backends and listen address, run in
+// the same process as the other instances
+type InstanceConfig struct {
+	// Name identifies the instance in logs and labels its metrics
+	Name string `yaml:"name"`
+	// Listen is the address the instance serves on, e.g. :8080
+	Listen string `yaml:"listen"`
+	// Backends replace the top-level backends for this instance
+	Backends []BackendConfig `yaml:"backends"`
+}
+
+// Instance is a running gateway instance
+type Instance struct {
+	Name    string
+	Listen  string
+	Gateway *Gateway
+}
+
+// instanceConfig derives an instance's config from the top-level one, sharing every
+// setting except the backends. Instances don't have tenants, and serve admin endpoints
+// on their own listener when enabled.
+func (c *Config) instanceConfig(instance InstanceConfig) *Config {
+	config := *c
+	config.Backends = instance.Backends
+	config.Tenants = nil
+	config.Gateways = nil
+	config.Admin.Listen = ""
+	return &config
+}
+
+// validateInstances checks that instances are named, listen on distinct addresses
+// and have valid configs
+func (c *Config) validateInstances() error {
+	names := make(map[string]bool)
+	addresses := make(map[string]bool)
+	for i, instance := range c.Gateways {
+		if instance.Name == "" {
+			return fmt.Errorf("gateways[%d]: name is required", i)
+		}
+		if instance.Listen == "" {
+			return fmt.Errorf("gateway %s: listen is required", instance.Name)
+		}
+		if names[instance.Name] {
+			return fmt.Errorf("gateway %s: duplicate name", instance.Name)
+		}
+		if addresses[instance.Listen] {
+			return fmt.Errorf("gateway %s: listen address %s is already used", instance.Name, instance.Listen)
+		}
+		names[instance.Name] = true
+		addresses[instance.Listen] = true
+
+		config := c.instanceConfig(instance)
+		if err := config.ResolveGroups(); err != nil {
+			return fmt.Errorf("gateway %s: %w", instance.Name, err)
+		}
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("gateway %s: %w", instance.Name, err)
+		}
+	}
+	return nil
+}
+
+// NewInstances creates a gateway for each configured instance. The instances share one
+// metrics sink, labelling their metrics with the instance name; everything else,
+// including sessions and backend connections, is separate.
+func NewInstances(config *Config) ([]*Instance, error) {
+	metrics, metricsHandler, err := newMetricsSink(config.Metrics)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics config: %w", err)
+	}
+
+	instances := make([]*Instance, 0, len(config.Gateways))
+	for _, instanceConfig := range config.Gateways {
+		log.Printf("🔧 Initializing gateway %s", instanceConfig.Name)
+		gateway, err := newNamed(config.instanceConfig(instanceConfig), instanceConfig.Name, labelledSink{next: metrics, gateway: instanceConfig.Name}, metricsHandler)
+		if err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			for _, created := range instances {
+				created.Gateway.Shutdown(ctx)
+			}
+			cancel()
+			return nil, fmt.Errorf("gateway %s: %w", instanceConfig.Name, err)
+		}
+		instances = append(instances, &Instance{Name: instanceConfig.Name, Listen: instanceConfig.Listen, Gateway: gateway})
+	}
+	return instances, nil
+}
+
+// labelledSink adds the gateway instance name to every metric
+type labelledSink struct {
+	next    MetricsSink
+	gateway string
+}
+
+// labels returns a copy of labels with the gateway label added
+func (l labelledSink) labels(labels map[string]string) map[string]string {
+	labelled := make(map[string]string, len(labels)+1)
+	for name, value := range labels {
+		labelled[name] = value
+	}
+	labelled["gateway"] = l.gateway
+	return labelled
+}
+
+func (l labelledSink) Counter(name string, value float64, labels map[string]string) {
+	l.next.Counter(name, value, l.labels(labels))
+}
+
+func (l labelledSink) Timing(name string, duration time.Duration, labels map[string]string) {
+	l.next.Timing(name, duration, l.labels(labels))
+}
+
+func (l labelledSink) Gauge(name string, value float64, labels map[string]string) {
+	l.next.Gauge(name, value, l.labels(labels))
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// TestGatewayInstances verifies two instances in one process serve disjoint backends on
+// different ports without sharing sessions, while sharing a labelled metrics sink
+func TestGatewayInstances(t *testing.T) {
+	alpha := newStubBackend(t, "Alpha", textTool(mcp.NewTool("echo"), "alpha"))
+	beta := newStubBackend(t, "Beta", textTool(mcp.NewTool("echo"), "beta"))
+
+	config := DefaultConfig()
+	err := yaml.Unmarshal([]byte(`
+metrics:
+  sinks:
+    - type: recording
+gateways:
+  - name: public
+    listen: ":18080"
+    backends:
+      - name: alpha
+        url: `+alpha.URL+`
+  - name: internal
+    listen: ":18081"
+    backends:
+      - name: beta
+        url: `+beta.URL+`
+`), config)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid config, got %v", err)
+	}
+
+	instances, err := NewInstances(config)
+	if err != nil {
+		t.Fatalf("Failed to create instances: %v", err)
+	}
+	urls := make(map[string]string)
+	for _, instance := range instances {
+		server := httptest.NewServer(instance.Gateway.Handler())
+		t.Cleanup(func() {
+			server.Close()
+			instance.Gateway.Shutdown(context.Background())
+		})
+		urls[instance.Name] = server.URL
+	}
+
+	public := newTestClient(t, urls["public"])
+	internal := newTestClient(t, urls["internal"])
+
+	if tools := listToolNames(t, public); !slices.Contains(tools, "alpha-echo") || slices.Contains(tools, "beta-echo") {
+		t.Errorf("Expected only alpha's tools on the public gateway, got %v", tools)
+	}
+	if tools := listToolNames(t, internal); !slices.Contains(tools, "beta-echo") || slices.Contains(tools, "alpha-echo") {
+		t.Errorf("Expected only beta's tools on the internal gateway, got %v", tools)
+	}
+	if text := extractTextFromResult(callTool(t, public, "alpha-echo", nil)); text != "alpha" {
+		t.Errorf("Expected alpha, got %q", text)
+	}
+	if text := extractTextFromResult(callTool(t, internal, "beta-echo", nil)); text != "beta" {
+		t.Errorf("Expected beta, got %q", text)
+	}
+
+	// A session of one instance is unknown to the other
+	req, _ := http.NewRequest(http.MethodPost, urls["internal"], strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Mcp-Session-Id", public.GetTransport().(*transport.StreamableHTTP).GetSessionId())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the public session to be unknown to the internal gateway, got HTTP %d", resp.StatusCode)
+	}
+
+	// Both instances record to the shared sink, labelled with their names
+	recorded := instances[0].Gateway.metrics.(labelledSink).next.(*recordingSink).recorded()
+	for _, want := range []string{
+		"counter tool_calls backend=alpha,gateway=public,status=ok,tool=alpha-echo",
+		"counter tool_calls backend=beta,gateway=internal,status=ok,tool=beta-echo",
+	} {
+		if !containsString(recorded, want) {
+			t.Errorf("Expected metric %q, got %v", want, recorded)
+		}
+	}
+}
+
+// TestGatewayInstancesValidation verifies instances need distinct names and addresses
+func TestGatewayInstancesValidation(t *testing.T) {
+	for name, instances := range map[string][]InstanceConfig{
+		"missing name":      {{Listen: ":1", Backends: []BackendConfig{{Name: "a", URL: "http://localhost:1"}}}},
+		"missing listen":    {{Name: "a", Backends: []BackendConfig{{Name: "a", URL: "http://localhost:1"}}}},
+		"missing backends":  {{Name: "a", Listen: ":1"}},
+		"duplicate address": {{Name: "a", Listen: ":1", Backends: []BackendConfig{{Name: "a", URL: "http://localhost:1"}}}, {Name: "b", Listen: ":1", Backends: []BackendConfig{{Name: "b", URL: "http://localhost:2"}}}},
+	} {
+		config := DefaultConfig()
+		config.Gateways = instances
+		if err := config.Validate(); err == nil {
+			t.Errorf("%s: expected the config to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a parsed JSONPath: an object key, an array index or a wildcard
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the JSONPath subset used by rewrite rules:
+// $.a.b, $['a'], $.a[0], $.a[*] and $.a.*
+func parseJSONPath(expr string) ([]pathSegment, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("path %q must start with $", expr)
+	}
+
+	var segments []pathSegment
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("path %q has an empty key", expr)
+			}
+			if name == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				segments = append(segments, pathSegment{key: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("path %q has an unterminated [", expr)
+			}
+			inner := rest[1:end]
+			switch {
+			case inner == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, pathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("path %q has an invalid index [%s]", expr, inner)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q has unexpected character %q", expr, rest[0])
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path %q must select a field", expr)
+	}
+	return segments, nil
+}
+
+// pathOp transforms the value selected by a path. It returns the new value and
+// whether the field should be kept; exists is false when the field is missing.
+type pathOp func(value any, exists bool) (any, bool)
+
+// applyPath applies op to every value selected by segments in node and returns the updated node.
+// With create set, missing object keys along the path are created.
+func applyPath(node any, segments []pathSegment, create bool, op pathOp) any {
+	segment, rest := segments[0], segments[1:]
+	last := len(rest) == 0
+
+	// step applies the remainder of the path to a child value
+	step := func(child any, exists bool) (any, bool) {
+		if last {
+			return op(child, exists)
+		}
+		if !exists {
+			if !create || rest[0].isIndex || rest[0].wildcard {
+				return nil, false
+			}
+			child = map[string]any{}
+		}
+		return applyPath(child, rest, create, op), true
+	}
+
+	switch value := node.(type) {
+	case map[string]any:
+		if segment.isIndex {
+			return node
+		}
+		if segment.wildcard {
+			for key, child := range value {
+				if updated, keep := step(child, true); keep {
+					value[key] = updated
+				} else {
+					delete(value, key)
+				}
+			}
+			return value
+		}
+		child, exists := value[segment.key]
+		if updated, keep := step(child, exists); keep {
+			value[segment.key] = updated
+		} else if exists {
+			delete(value, segment.key)
+		}
+		return value
+	case []any:
+		if segment.wildcard {
+			kept := value[:0]
+			for _, child := range value {
+				if updated, keep := step(child, true); keep {
+					kept = append(kept, updated)
+				}
+			}
+			return kept
+		}
+		if !segment.isIndex || segment.index >= len(value) {
+			return node
+		}
+		if updated, keep := step(value[segment.index], true); keep {
+			value[segment.index] = updated
+			return value
+		}
+		return append(value[:segment.index], value[segment.index+1:]...)
+	default:
+		return node
+	}
+}
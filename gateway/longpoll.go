@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Default longest time a notification poll waits for a notification
+const defaultLongPollMaxWait = 30 * time.Second
+
+// LongPollConfig serves notifications to clients without a listening stream
+type LongPollConfig struct {
+	// Enabled buffers notifications that can't be pushed to a client's stream and
+	// serves them from GET /notifications
+	Enabled bool `yaml:"enabled"`
+	// MaxWait caps how long a poll blocks while no notification is buffered (default 30s)
+	MaxWait time.Duration `yaml:"maxWait"`
+	// BufferSize is how many notifications are kept per session between polls,
+	// dropping the oldest when full (default 100)
+	BufferSize int `yaml:"bufferSize"`
+}
+
+// maxWait returns the longest poll wait
+func (c LongPollConfig) maxWait() time.Duration {
+	if c.MaxWait > 0 {
+		return c.MaxWait
+	}
+	return defaultLongPollMaxWait
+}
+
+// notificationMailbox holds a session's notifications until the client polls for them
+type notificationMailbox struct {
+	size   int
+	maxAge time.Duration
+	stats  *notificationStats
+	lock   sync.Mutex
+	items  []bufferedNotification
+	wake   chan struct{}
+}
+
+// newNotificationMailbox creates an empty mailbox whose notifications older than maxAge
+// (if set) are dropped, and counted in stats, rather than returned
+func newNotificationMailbox(size int, maxAge time.Duration, stats *notificationStats) *notificationMailbox {
+	if size <= 0 {
+		size = defaultNotificationBufferSize
+	}
+	return &notificationMailbox{size: size, maxAge: maxAge, stats: stats, wake: make(chan struct{}, 1)}
+}
+
+// put stores a notification, returning the oldest one if it had to be evicted
+func (m *notificationMailbox) put(buffered bufferedNotification) (evicted *mcp.JSONRPCNotification) {
+	m.lock.Lock()
+	if len(m.items) >= m.size {
+		evicted = &m.items[0].notification
+		m.items = m.items[1:]
+	}
+	m.items = append(m.items, buffered)
+	m.lock.Unlock()
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+	return evicted
+}
+
+// take returns every stored notification that isn't stale, waiting up to wait for one
+// to arrive
+func (m *notificationMailbox) take(ctx context.Context, wait time.Duration) []mcp.JSONRPCNotification {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		m.lock.Lock()
+		items := m.items
+		m.items = nil
+		m.lock.Unlock()
+
+		notifications := make([]mcp.JSONRPCNotification, 0, len(items))
+		for _, item := range items {
+			if item.stale(m.maxAge) {
+				m.stats.record(item.notification.Method, false)
+				continue
+			}
+			notifications = append(notifications, item.notification)
+		}
+		if len(notifications) > 0 {
+			return notifications
+		}
+
+		select {
+		case <-m.wake:
+		case <-timer.C:
+			return []mcp.JSONRPCNotification{}
+		case <-ctx.Done():
+			return []mcp.JSONRPCNotification{}
+		}
+	}
+}
+
+// handleNotificationPoll serves GET /notifications: the notifications buffered for the
+// session named by Mcp-Session-Id, waiting up to ?wait= (capped by maxWait) for one
+func (g *Gateway) handleNotificationPoll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header is required", http.StatusBadRequest)
+		return
+	}
+	if terminated, _ := g.sessions.Validate(sessionID); terminated {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	maxWait := g.config.Notifications.LongPoll.maxWait()
+	wait := maxWait
+	if value := r.URL.Query().Get("wait"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid wait duration", http.StatusBadRequest)
+			return
+		}
+		wait = min(parsed, maxWait)
+	}
+
+	connections, err := g.getOrCreateClientConnections(r.Context(), sessionID)
+	if err != nil {
+		log.Printf("❌ Failed to get client connections: %v", err)
+		http.Error(w, "Backend connection error", http.StatusBadGateway)
+		return
+	}
+
+	if connections.notifications == nil || connections.notifications.mailbox == nil {
+		http.Error(w, "Notification forwarding is disabled", http.StatusNotFound)
+		return
+	}
+
+	notifications := connections.notifications.mailbox.take(r.Context(), wait)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"notifications": notifications})
+}
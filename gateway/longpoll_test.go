@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// pollNotifications long-polls the gateway for a session's notifications
+func pollNotifications(t *testing.T, gatewayURL, sessionID, wait string) []mcp.JSONRPCNotification {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, gatewayURL+"/notifications?wait="+wait, nil)
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("Poll failed: %v", err)
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 from poll, got %d", resp.StatusCode)
+		return nil
+	}
+
+	var body struct {
+		Notifications []mcp.JSONRPCNotification `json:"notifications"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Errorf("Failed to decode poll response: %v", err)
+	}
+	return body.Notifications
+}
+
+// TestLongPollNotifications verifies a client without a listening stream receives a
+// backend notification by long polling
+func TestLongPollNotifications(t *testing.T) {
+	backend := newStubBackend(t, "Logger", stubTool{
+		tool: mcp.NewTool("work"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			server.ServerFromContext(ctx).SendNotificationToClient(ctx, "notifications/message", map[string]any{"level": "info", "data": "halfway"})
+			// Let the backend's stream write the notification before the response
+			time.Sleep(100 * time.Millisecond)
+			return mcp.NewToolResultText("done"), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Notifications = NotificationsConfig{Forward: true, LongPoll: LongPollConfig{Enabled: true}}
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	mcpClient := newTestClient(t, gatewayURL)
+	sessionID := mcpClient.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+
+	// A poll started before the notification blocks until it arrives
+	polled := make(chan []mcp.JSONRPCNotification, 1)
+	go func() {
+		polled <- pollNotifications(t, gatewayURL, sessionID, "5s")
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-work", nil)); text != "done" {
+		t.Fatalf("Expected the tool call to complete, got %q", text)
+	}
+
+	select {
+	case notifications := <-polled:
+		if len(notifications) != 1 || notifications[0].Method != "notifications/message" ||
+			notifications[0].Params.AdditionalFields["data"] != "halfway" {
+			t.Fatalf("Expected the backend's log notification, got %+v", notifications)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Poll did not return the notification")
+	}
+
+	// With nothing buffered, a poll returns empty after its wait
+	started := time.Now()
+	if notifications := pollNotifications(t, gatewayURL, sessionID, "100ms"); len(notifications) != 0 {
+		t.Errorf("Expected no more notifications, got %+v", notifications)
+	}
+	if elapsed := time.Since(started); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the poll to wait, returned after %s", elapsed)
+	}
+}
+
+// TestLongPollMaxAge verifies notifications kept for polling are dropped once older than
+// maxAge
+func TestLongPollMaxAge(t *testing.T) {
+	stats := &notificationStats{}
+	mailbox := newNotificationMailbox(10, time.Minute, stats)
+	mailbox.put(bufferedNotification{notification: progressNotification(1), received: time.Now().Add(-2 * time.Minute)})
+	mailbox.put(bufferedNotification{notification: progressNotification(2), received: time.Now()})
+
+	notifications := mailbox.take(context.Background(), 0)
+	if len(notifications) != 1 || notifications[0].Params.AdditionalFields["progress"] != 2 {
+		t.Errorf("Expected only the fresh notification, got %v", notifications)
+	}
+	if counts := stats.snapshot()["notifications/progress"]; counts.Dropped != 1 {
+		t.Errorf("Expected the stale notification to be counted as dropped, got %+v", counts)
+	}
+}
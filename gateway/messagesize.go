@@ -0,0 +1,237 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Bytes kept from the end of an oversized stdio message to find a trailing id
+const oversizedTailBytes = 128
+
+// Trailing top-level id of a JSON-RPC message, for SDKs that encode it after the result
+var trailingMessageID = regexp.MustCompile(`"id"\s*:\s*(-?[0-9]+|"(?:[^"\\]|\\.)*")\s*}\s*$`)
+
+// MessageTooLargeError reports a backend message over the backend's maxMessageBytes.
+// The message is discarded as it is read rather than buffered.
+type MessageTooLargeError struct {
+	Backend string
+	Limit   int
+}
+
+func (e *MessageTooLargeError) Error() string {
+	return fmt.Sprintf("backend %s sent a message larger than %d bytes (maxMessageBytes); it was discarded", e.Backend, e.Limit)
+}
+
+// boundedLine is a line read without holding more of it than its first and last bytes
+type boundedLine struct {
+	head []byte
+	tail []byte
+	size int
+}
+
+// readBoundedLine reads the next line, keeping at most keep bytes of its start and, when
+// tail is set, its last oversizedTailBytes bytes
+func readBoundedLine(r *bufio.Reader, keep int, tail bool) (boundedLine, error) {
+	var line boundedLine
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line.size += len(chunk)
+		if room := keep - len(line.head); room > 0 {
+			line.head = append(line.head, chunk[:min(room, len(chunk))]...)
+		}
+		if tail && line.size > keep {
+			line.tail = append(line.tail, chunk...)
+			line.tail = line.tail[max(0, len(line.tail)-oversizedTailBytes):]
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// oversizedMessageID finds the id of a JSON-RPC response from the start and end of its
+// encoding, since SDKs write the id before or after the result. Requests and
+// notifications from the backend have no id to answer.
+func oversizedMessageID(head, tail []byte) json.RawMessage {
+	decoder := json.NewDecoder(bytes.NewReader(head))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			break
+		}
+		switch key {
+		case "method":
+			return nil
+		case "id":
+			if isJSONRPCID(value, false) {
+				return value
+			}
+			return nil
+		}
+	}
+	if match := trailingMessageID.FindSubmatch(bytes.TrimSpace(tail)); match != nil {
+		return json.RawMessage(match[1])
+	}
+	return nil
+}
+
+// tooLargeResponse is the error response replacing an oversized message
+func tooLargeResponse(id json.RawMessage, err error) []byte {
+	response, _ := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"error":   map[string]any{"code": mcp.INTERNAL_ERROR, "message": err.Error()},
+	})
+	return response
+}
+
+// stdioLimitReader bounds the newline-framed messages of a stdio backend's stdout. A
+// longer message is skipped and replaced with an error response to its request, so the
+// call fails cleanly, or dropped if its id can't be found.
+type stdioLimitReader struct {
+	lines   *bufio.Reader
+	backend string
+	limit   int
+
+	pending []byte
+	err     error
+}
+
+// newStdioLimitReader bounds the messages read from a stdio backend's stdout
+func newStdioLimitReader(stdout io.Reader, backend string, limit int) *stdioLimitReader {
+	return &stdioLimitReader{lines: bufio.NewReader(stdout), backend: backend, limit: limit}
+}
+
+func (r *stdioLimitReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 && r.err == nil {
+		line, err := readBoundedLine(r.lines, r.limit+1, true)
+		r.err = err
+		if line.size <= r.limit {
+			r.pending = line.head
+			continue
+		}
+
+		tooLarge := &MessageTooLargeError{Backend: r.backend, Limit: r.limit}
+		log.Printf("❌ %v (%d bytes)", tooLarge, line.size)
+		if id := oversizedMessageID(line.head, line.tail); id != nil {
+			r.pending = append(tooLargeResponse(id, tooLarge), '\n')
+		}
+	}
+	if len(r.pending) > 0 {
+		n := copy(p, r.pending)
+		r.pending = r.pending[n:]
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// messageSizeRoundTripper bounds the messages of a backend's HTTP responses: a JSON
+// response over the limit fails the call, and an SSE event over it is replaced with an
+// error response to the request
+type messageSizeRoundTripper struct {
+	next    http.RoundTripper
+	backend string
+	limit   int
+}
+
+func (m *messageSizeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := m.next.RoundTrip(req)
+	if err != nil || resp.StatusCode == http.StatusAccepted {
+		return resp, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		resp.Body = &sseLimitReader{ReadCloser: resp.Body, lines: bufio.NewReader(resp.Body), transport: m, requestID: requestID(req)}
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(m.limit)+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > m.limit {
+		tooLarge := &MessageTooLargeError{Backend: m.backend, Limit: m.limit}
+		log.Printf("❌ %v", tooLarge)
+		return nil, tooLarge
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// sseLimitReader bounds each event of an SSE stream as it is read. An event over the
+// limit is skipped and replaced with an error response to the request, or dropped if
+// the request id is unknown.
+type sseLimitReader struct {
+	io.ReadCloser
+	lines     *bufio.Reader
+	transport *messageSizeRoundTripper
+	requestID json.RawMessage
+
+	event    bytes.Buffer
+	oversize bool
+	pending  []byte
+	err      error
+}
+
+func (r *sseLimitReader) Read(p []byte) (int, error) {
+	limit := r.transport.limit
+	for len(r.pending) == 0 && r.err == nil {
+		// Keep enough of a skipped line to recognize the blank line ending the event
+		keep := 2
+		if !r.oversize {
+			keep = limit - r.event.Len() + 1
+		}
+		line, err := readBoundedLine(r.lines, keep, false)
+		if !r.oversize && r.event.Len()+line.size > limit {
+			r.oversize = true
+			r.event.Reset()
+		}
+		if !r.oversize {
+			r.event.Write(line.head)
+		}
+		if err != nil || (line.size <= 2 && len(bytes.TrimRight(line.head, "\r\n")) == 0) {
+			r.endEvent()
+			r.err = err
+		}
+	}
+	if len(r.pending) > 0 {
+		n := copy(p, r.pending)
+		r.pending = r.pending[n:]
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// endEvent queues the event, or the replacement of an oversized one, for reading
+func (r *sseLimitReader) endEvent() {
+	event := bytes.Clone(r.event.Bytes())
+	if r.oversize {
+		tooLarge := &MessageTooLargeError{Backend: r.transport.backend, Limit: r.transport.limit}
+		log.Printf("❌ %v", tooLarge)
+		event = nil
+		if r.requestID != nil {
+			event = []byte("event: message\ndata: " + string(tooLargeResponse(r.requestID, tooLarge)) + "\n\n")
+		}
+	}
+	r.pending = append(r.pending, event...)
+	r.event.Reset()
+	r.oversize = false
+}
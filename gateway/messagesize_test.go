@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestStdioMaxMessageBytes verifies an oversized stdio message fails its call without
+// breaking the connection for later calls
+func TestStdioMaxMessageBytes(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:      "server1",
+		Transport: stdioTransport,
+		Stdio: &StdioConfig{
+			Command: os.Args[0],
+			Args:    []string{"-test.run=^TestStdioHelperBackend$"},
+			Env:     map[string]string{stdioHelperEnv: "1", "SESSION_TOKEN": "small"},
+		},
+		MaxMessageBytes: 16 << 10,
+	}}
+	gateway, gatewayURL := startTestGateway(t, config)
+	t.Cleanup(func() { gateway.Shutdown(context.Background()) })
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-big", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "larger than 16384 bytes") {
+		t.Errorf("Expected the oversized result to fail the call, got %q", extractTextFromResult(result))
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-env", nil)); text != "small" {
+		t.Errorf("Expected later calls on the connection to succeed, got %q", text)
+	}
+}
+
+// TestHTTPMaxMessageBytes verifies an oversized HTTP response fails its call
+func TestHTTPMaxMessageBytes(t *testing.T) {
+	backend := newStubBackend(t, "Backend",
+		textTool(mcp.NewTool("big"), strings.Repeat("x", 64<<10)),
+		textTool(mcp.NewTool("echo"), "hello"))
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, MaxMessageBytes: 16 << 10}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-big", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "larger than 16384 bytes") {
+		t.Errorf("Expected the oversized result to fail the call, got %q", extractTextFromResult(result))
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); text != "hello" {
+		t.Errorf("Expected small results to pass, got %q", text)
+	}
+}
+
+// TestSSELimitReader verifies an oversized event is replaced with an error response to
+// the request while the events around it pass unchanged
+func TestSSELimitReader(t *testing.T) {
+	stream := "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n" +
+		"event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":7,\"result\":\"" + strings.Repeat("x", 10000) + "\"}\n\n" +
+		"event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\n"
+	// A small buffer makes lines span several reads
+	body := io.NopCloser(strings.NewReader(stream))
+	reader := &sseLimitReader{
+		ReadCloser: body,
+		lines:      bufio.NewReaderSize(body, 64),
+		transport:  &messageSizeRoundTripper{backend: "server1", limit: 1000},
+		requestID:  []byte("7"),
+	}
+
+	read, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+	text := string(read)
+	if strings.Contains(text, "xxxx") {
+		t.Error("Expected the oversized event to be discarded")
+	}
+	for _, expected := range []string{"notifications/progress", `"id":7`, "larger than 1000 bytes", "notifications/message"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected the stream to contain %s, got %q", expected, text)
+		}
+	}
+}
+
+// TestOversizedMessageID verifies response ids are found before or after a cut-off result
+func TestOversizedMessageID(t *testing.T) {
+	cases := []struct {
+		name       string
+		head, tail string
+		expected   string
+	}{
+		{"id first", `{"jsonrpc":"2.0","id":3,"result":{"content":[{"text":"xx`, "", "3"},
+		{"id last", `{"result":{"content":[{"id":9,"text":"xx`, `xxx"}]},"jsonrpc":"2.0","id":"call-4"}`, `"call-4"`},
+		{"request", `{"jsonrpc":"2.0","method":"sampling/createMessage","id":5,"params":{"x`, "", ""},
+		{"no id", `{"jsonrpc":"2.0","result":{"content":"xx`, `xx"}}`, ""},
+	}
+	for _, tc := range cases {
+		if got := oversizedMessageID([]byte(tc.head), []byte(tc.tail)); string(got) != tc.expected {
+			t.Errorf("%s: expected id %q, got %q", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Metric names recorded by the gateway; sinks adapt them to their own conventions
+const (
+	metricToolCalls          = "tool_calls"
+	metricToolCallDuration   = "tool_call_duration"
+	metricSessionsActive     = "sessions_active"
+	metricSessionsCreated    = "sessions_created"
+	metricSessionsEnded      = "sessions_ended"
+	metricBackendConnections = "backend_connections"
+	metricOpenConnections    = "backend_connections_open"
+	metricProtocolErrors     = "backend_protocol_errors"
+)
+
+// Built-in metrics sinks
+const (
+	metricsSinkPrometheus = "prometheus"
+	metricsSinkStatsD     = "statsd"
+)
+
+// MetricsSink receives the gateway's session and backend metrics. Implementations must
+// be safe for concurrent use and should not block: they are called on the request path.
+type MetricsSink interface {
+	// Counter adds value to the counter with the given labels
+	Counter(name string, value float64, labels map[string]string)
+	// Timing records one observed duration
+	Timing(name string, duration time.Duration, labels map[string]string)
+	// Gauge sets the current value of a gauge
+	Gauge(name string, value float64, labels map[string]string)
+}
+
+// MetricsConfig selects where metrics are sent
+type MetricsConfig struct {
+	// Sinks receive every metric; none disables metrics
+	Sinks []MetricsSinkConfig `yaml:"sinks"`
+	// ResourceAttributes are labels added to every metric, identifying the gateway
+	// instance (e.g. region, environment); a metric's own labels take precedence
+	ResourceAttributes map[string]string `yaml:"resourceAttributes"`
+	// Tenants labels tool call metrics with the caller's tenant
+	Tenants MetricsTenantsConfig `yaml:"tenants"`
+}
+
+// MetricsSinkConfig configures one metrics sink
+type MetricsSinkConfig struct {
+	// Type names a registered sink: prometheus, statsd or a custom one
+	Type string `yaml:"type"`
+	// Options are passed to the sink factory (e.g. the statsd address)
+	Options map[string]string `yaml:"options"`
+}
+
+// MetricsSinkFactory creates a metrics sink from its options
+type MetricsSinkFactory func(options map[string]string) (MetricsSink, error)
+
+var (
+	metricsSinksLock sync.RWMutex
+	metricsSinks     = make(map[string]MetricsSinkFactory)
+)
+
+func init() {
+	RegisterMetricsSink(metricsSinkPrometheus, func(options map[string]string) (MetricsSink, error) {
+		return newPrometheusSink(options), nil
+	})
+	RegisterMetricsSink(metricsSinkStatsD, func(options map[string]string) (MetricsSink, error) {
+		return newStatsDSink(options)
+	})
+}
+
+// RegisterMetricsSink makes a metrics sink available by name to the metrics sinks setting.
+// It is meant to be called from init functions and panics if the name is empty or
+// already registered, or if factory is nil.
+func RegisterMetricsSink(name string, factory MetricsSinkFactory) {
+	metricsSinksLock.Lock()
+	defer metricsSinksLock.Unlock()
+
+	if name == "" || factory == nil {
+		panic("gateway: RegisterMetricsSink requires a name and a factory")
+	}
+	if _, exists := metricsSinks[name]; exists {
+		panic(fmt.Sprintf("gateway: metrics sink %s registered twice", name))
+	}
+	metricsSinks[name] = factory
+}
+
+// lookupMetricsSink returns the factory registered under name
+func lookupMetricsSink(name string) (MetricsSinkFactory, error) {
+	metricsSinksLock.RLock()
+	defer metricsSinksLock.RUnlock()
+
+	factory, ok := metricsSinks[name]
+	if !ok {
+		names := make([]string, 0, len(metricsSinks))
+		for registered := range metricsSinks {
+			names = append(names, registered)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown metrics sink %q (registered: %v)", name, names)
+	}
+	return factory, nil
+}
+
+// Validate checks that every sink is registered and attribute names are valid labels
+func (c MetricsConfig) Validate() error {
+	for i, sink := range c.Sinks {
+		if _, err := lookupMetricsSink(sink.Type); err != nil {
+			return fmt.Errorf("metrics.sinks[%d]: %w", i, err)
+		}
+	}
+	if err := c.Tenants.Validate(); err != nil {
+		return err
+	}
+	for name := range c.ResourceAttributes {
+		if !isLabelName(name) {
+			return fmt.Errorf("metrics.resourceAttributes: %q is not a valid label name", name)
+		}
+	}
+	return nil
+}
+
+// isLabelName reports whether name is a valid Prometheus label name
+func isLabelName(name string) bool {
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// newMetricsSink creates the configured sinks, multiplexing when there are several,
+// and returns the HTTP handler of the first sink that serves its metrics (if any)
+func newMetricsSink(config MetricsConfig) (MetricsSink, http.Handler, error) {
+	var sinks multiSink
+	var handler http.Handler
+	for _, sinkConfig := range config.Sinks {
+		factory, err := lookupMetricsSink(sinkConfig.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink, err := factory(sinkConfig.Options)
+		if err != nil {
+			return nil, nil, fmt.Errorf("metrics sink %s: %w", sinkConfig.Type, err)
+		}
+		if h, ok := sink.(http.Handler); ok && handler == nil {
+			handler = h
+		}
+		sinks = append(sinks, sink)
+	}
+
+	var sink MetricsSink = sinks
+	if len(sinks) == 1 {
+		sink = sinks[0]
+	}
+	if len(config.ResourceAttributes) > 0 && len(sinks) > 0 {
+		sink = &attributedSink{next: sink, attributes: config.ResourceAttributes}
+	}
+	return sink, handler, nil
+}
+
+// attributedSink adds the resource attributes to the labels of every metric
+type attributedSink struct {
+	next       MetricsSink
+	attributes map[string]string
+}
+
+// labels returns the metric's labels merged over the resource attributes
+func (a *attributedSink) labels(labels map[string]string) map[string]string {
+	merged := make(map[string]string, len(a.attributes)+len(labels))
+	for name, value := range a.attributes {
+		merged[name] = value
+	}
+	for name, value := range labels {
+		merged[name] = value
+	}
+	return merged
+}
+
+func (a *attributedSink) Counter(name string, value float64, labels map[string]string) {
+	a.next.Counter(name, value, a.labels(labels))
+}
+
+func (a *attributedSink) Timing(name string, duration time.Duration, labels map[string]string) {
+	a.next.Timing(name, duration, a.labels(labels))
+}
+
+func (a *attributedSink) Gauge(name string, value float64, labels map[string]string) {
+	a.next.Gauge(name, value, a.labels(labels))
+}
+
+// multiSink sends every metric to each of several sinks; empty, it discards them
+type multiSink []MetricsSink
+
+func (m multiSink) Counter(name string, value float64, labels map[string]string) {
+	for _, sink := range m {
+		sink.Counter(name, value, labels)
+	}
+}
+
+func (m multiSink) Timing(name string, duration time.Duration, labels map[string]string) {
+	for _, sink := range m {
+		sink.Timing(name, duration, labels)
+	}
+}
+
+func (m multiSink) Gauge(name string, value float64, labels map[string]string) {
+	for _, sink := range m {
+		sink.Gauge(name, value, labels)
+	}
+}
+
+// sortedLabelNames returns the label names in a stable order
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// prometheusSeries is the accumulated value of one metric and label set
+type prometheusSeries struct {
+	kind   string
+	name   string
+	labels string
+	value  float64
+	count  int64
+}
+
+// prometheusSink keeps metrics in memory and serves them in the Prometheus text format
+type prometheusSink struct {
+	namespace string
+	series    map[string]*prometheusSeries
+	lock      sync.Mutex
+}
+
+// newPrometheusSink creates a Prometheus sink; the namespace option prefixes metric names
+func newPrometheusSink(options map[string]string) *prometheusSink {
+	namespace := options["namespace"]
+	if namespace == "" {
+		namespace = "mcp_gateway"
+	}
+	return &prometheusSink{namespace: namespace, series: make(map[string]*prometheusSeries)}
+}
+
+// record updates a series, creating it on first use
+func (p *prometheusSink) record(kind, name string, labels map[string]string, update func(series *prometheusSeries)) {
+	var formatted []string
+	for _, label := range sortedLabelNames(labels) {
+		formatted = append(formatted, fmt.Sprintf("%s=%q", label, labels[label]))
+	}
+	labelText := strings.Join(formatted, ",")
+	key := name + "{" + labelText + "}"
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	series, ok := p.series[key]
+	if !ok {
+		series = &prometheusSeries{kind: kind, name: name, labels: labelText}
+		p.series[key] = series
+	}
+	update(series)
+}
+
+func (p *prometheusSink) Counter(name string, value float64, labels map[string]string) {
+	p.record("counter", p.namespace+"_"+name+"_total", labels, func(series *prometheusSeries) {
+		series.value += value
+	})
+}
+
+func (p *prometheusSink) Timing(name string, duration time.Duration, labels map[string]string) {
+	p.record("summary", p.namespace+"_"+name+"_seconds", labels, func(series *prometheusSeries) {
+		series.value += duration.Seconds()
+		series.count++
+	})
+}
+
+func (p *prometheusSink) Gauge(name string, value float64, labels map[string]string) {
+	p.record("gauge", p.namespace+"_"+name, labels, func(series *prometheusSeries) {
+		series.value = value
+	})
+}
+
+// ServeHTTP writes every series in the Prometheus text exposition format
+func (p *prometheusSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.lock.Lock()
+	series := make([]prometheusSeries, 0, len(p.series))
+	for _, s := range p.series {
+		series = append(series, *s)
+	}
+	p.lock.Unlock()
+
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].name != series[j].name {
+			return series[i].name < series[j].name
+		}
+		return series[i].labels < series[j].labels
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	typed := make(map[string]bool)
+	for _, s := range series {
+		if !typed[s.name] {
+			fmt.Fprintf(w, "# TYPE %s %s\n", s.name, s.kind)
+			typed[s.name] = true
+		}
+		if s.kind == "summary" {
+			fmt.Fprintf(w, "%s_sum{%s} %g\n", s.name, s.labels, s.value)
+			fmt.Fprintf(w, "%s_count{%s} %d\n", s.name, s.labels, s.count)
+			continue
+		}
+		fmt.Fprintf(w, "%s{%s} %g\n", s.name, s.labels, s.value)
+	}
+}
+
+// statsDSink sends metrics over UDP in the StatsD line format, with DogStatsD-style tags
+type statsDSink struct {
+	prefix string
+	conn   net.Conn
+}
+
+// newStatsDSink creates a StatsD sink sending to the address option (default localhost:8125);
+// the prefix option (default mcp_gateway) is prepended to metric names
+func newStatsDSink(options map[string]string) (*statsDSink, error) {
+	address := options["address"]
+	if address == "" {
+		address = "localhost:8125"
+	}
+	prefix := options["prefix"]
+	if prefix == "" {
+		prefix = "mcp_gateway"
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return &statsDSink{prefix: prefix, conn: conn}, nil
+}
+
+// send writes one metric line; delivery is best effort
+func (s *statsDSink) send(name, value, kind string, labels map[string]string) {
+	line := fmt.Sprintf("%s.%s:%s|%s", s.prefix, name, value, kind)
+	if len(labels) > 0 {
+		tags := make([]string, 0, len(labels))
+		for _, label := range sortedLabelNames(labels) {
+			tags = append(tags, label+":"+labels[label])
+		}
+		line += "|#" + strings.Join(tags, ",")
+	}
+	s.conn.Write([]byte(line))
+}
+
+func (s *statsDSink) Counter(name string, value float64, labels map[string]string) {
+	s.send(name, fmt.Sprintf("%g", value), "c", labels)
+}
+
+func (s *statsDSink) Timing(name string, duration time.Duration, labels map[string]string) {
+	s.send(name, fmt.Sprintf("%g", float64(duration.Microseconds())/1000), "ms", labels)
+}
+
+func (s *statsDSink) Gauge(name string, value float64, labels map[string]string) {
+	s.send(name, fmt.Sprintf("%g", value), "g", labels)
+}
+
+// recordToolCall records a tool call's outcome and duration
+func (g *Gateway) recordToolCall(ctx context.Context, toolName, backend string, duration time.Duration, result *mcp.CallToolResult, err error) {
+	status := "ok"
+	failed := err != nil || result == nil || result.IsError
+	if failed {
+		status = "error"
+		g.recordRecentError(toolName, backend, result, err)
+	}
+	g.recordToolStats(toolName, backend, duration, failed)
+	g.metrics.Counter(metricToolCalls, 1, g.addTenantLabel(ctx, map[string]string{"tool": toolName, "backend": backend, "status": status}))
+	g.metrics.Timing(metricToolCallDuration, duration, g.addTenantLabel(ctx, map[string]string{"tool": toolName, "backend": backend}))
+}
+
+// recordBackendConnection records an attempt to connect a client session to a backend
+func (g *Gateway) recordBackendConnection(backend string, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	g.metrics.Counter(metricBackendConnections, 1, map[string]string{"backend": backend, "status": status})
+}
+
+// recordProtocolError counts a malformed JSON-RPC message from a backend
+func (g *Gateway) recordProtocolError(backend string) {
+	g.metrics.Counter(metricProtocolErrors, 1, map[string]string{"backend": backend})
+}
+
+// recordOpenConnections records the number of open sockets to a backend
+func (g *Gateway) recordOpenConnections(backend string, open int) {
+	g.metrics.Gauge(metricOpenConnections, float64(open), map[string]string{"backend": backend})
+}
@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterMetricsSink("recording", func(options map[string]string) (MetricsSink, error) {
+		return &recordingSink{}, nil
+	})
+}
+
+// recordingSink records every metric as "kind name labels" for assertions
+type recordingSink struct {
+	metrics []string
+	lock    sync.Mutex
+}
+
+func (r *recordingSink) add(kind, name string, labels map[string]string) {
+	var formatted []string
+	for _, label := range sortedLabelNames(labels) {
+		formatted = append(formatted, label+"="+labels[label])
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.metrics = append(r.metrics, kind+" "+name+" "+strings.Join(formatted, ","))
+}
+
+func (r *recordingSink) Counter(name string, value float64, labels map[string]string) {
+	r.add("counter", name, labels)
+}
+
+func (r *recordingSink) Timing(name string, duration time.Duration, labels map[string]string) {
+	r.add("timing", name, labels)
+}
+
+func (r *recordingSink) Gauge(name string, value float64, labels map[string]string) {
+	r.add("gauge", name, labels)
+}
+
+func (r *recordingSink) recorded() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.metrics...)
+}
+
+// TestMetricsSinkRecordsToolCall verifies a tool call emits session, connection and
+// tool call metrics to the configured sink
+func TestMetricsSinkRecordsToolCall(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.Metrics.Sinks = []MetricsSinkConfig{{Type: "recording"}}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+	callTool(t, mcpClient, "server1-echo", nil)
+
+	recorded := gateway.metrics.(*recordingSink).recorded()
+	for _, want := range []string{
+		"counter sessions_created ",
+		"gauge sessions_active ",
+		"counter backend_connections backend=server1,status=ok",
+		"counter tool_calls backend=server1,status=ok,tool=server1-echo",
+		"timing tool_call_duration backend=server1,tool=server1-echo",
+	} {
+		if !containsString(recorded, want) {
+			t.Errorf("Expected metric %q, got %v", want, recorded)
+		}
+	}
+}
+
+// TestPrometheusAndStatsDSinks verifies both built-in sinks receive the same metrics
+func TestPrometheusAndStatsDSinks(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	config := DefaultConfig()
+	config.Metrics.Sinks = []MetricsSinkConfig{
+		{Type: metricsSinkPrometheus},
+		{Type: metricsSinkStatsD, Options: map[string]string{"address": listener.LocalAddr().String()}},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+	callTool(t, mcpClient, "server1-echo", nil)
+
+	resp, err := http.Get(gatewayURL + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if want := `mcp_gateway_tool_calls_total{backend="server1",status="ok",tool="server1-echo"} 1`; !strings.Contains(string(body), want) {
+		t.Errorf("Expected %s in Prometheus metrics, got:\n%s", want, body)
+	}
+
+	var lines []string
+	buffer := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		n, _, err := listener.ReadFrom(buffer)
+		if err != nil {
+			break
+		}
+		lines = append(lines, string(buffer[:n]))
+		if strings.HasPrefix(lines[len(lines)-1], "mcp_gateway.tool_call_duration:") {
+			break
+		}
+	}
+	if want := "mcp_gateway.tool_calls:1|c|#backend:server1,status:ok,tool:server1-echo"; !containsString(lines, want) {
+		t.Errorf("Expected StatsD line %q, got %v", want, lines)
+	}
+}
+
+// TestMetricsResourceAttributes verifies resource attributes label every metric without
+// overriding a metric's own labels
+func TestMetricsResourceAttributes(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	config := DefaultConfig()
+	config.Metrics.Sinks = []MetricsSinkConfig{{Type: metricsSinkPrometheus}}
+	config.Metrics.ResourceAttributes = map[string]string{"region": "eu", "environment": "prod", "tool": "ignored"}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+	callTool(t, mcpClient, "server1-echo", nil)
+
+	resp, err := http.Get(gatewayURL + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, want := range []string{
+		`mcp_gateway_tool_calls_total{backend="server1",environment="prod",region="eu",status="ok",tool="server1-echo"} 1`,
+		`mcp_gateway_sessions_created_total{environment="prod",region="eu",tool="ignored"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected %s in Prometheus metrics, got:\n%s", want, body)
+		}
+	}
+
+	config.Metrics.ResourceAttributes = map[string]string{"my-region": "eu"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `"my-region" is not a valid label name`) {
+		t.Errorf("Expected an invalid label name error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Default number of distinct tenants labeled by name when no allowlist is configured
+const defaultMetricsMaxTenants = 50
+
+// Tenant label values of calls that aren't labeled with their tenant's name
+const (
+	// metricsTenantNone labels calls whose request named no tenant
+	metricsTenantNone = "none"
+	// metricsTenantOther labels calls of tenants outside the allowlist or past the cap
+	metricsTenantOther = "other"
+)
+
+// MetricsTenantsConfig labels tool call metrics with the caller's tenant, guarding the
+// metrics' cardinality against unbounded tenant values
+type MetricsTenantsConfig struct {
+	// Header names the request header identifying the tenant; empty disables tenant labels
+	Header string `yaml:"header"`
+	// Allow lists the tenants labeled by name; any other tenant is labeled "other"
+	Allow []string `yaml:"allow"`
+	// MaxTenants caps the distinct tenants labeled by name when Allow is empty: tenants
+	// seen after the first MaxTenants are labeled "other" (default 50)
+	MaxTenants int `yaml:"maxTenants"`
+}
+
+// Validate checks the tenant cap is not negative
+func (c MetricsTenantsConfig) Validate() error {
+	if c.MaxTenants < 0 {
+		return fmt.Errorf("metrics.tenants.maxTenants must not be negative")
+	}
+	return nil
+}
+
+// maxTenants returns the cap on distinct tenant labels, defaulting to 50
+func (c MetricsTenantsConfig) maxTenants() int {
+	if c.MaxTenants > 0 {
+		return c.MaxTenants
+	}
+	return defaultMetricsMaxTenants
+}
+
+// metricsTenantKey carries the tenant of the client request being handled
+type metricsTenantKey struct{}
+
+// tenantLabeler maps tenants to metric label values, bucketing those past the
+// allowlist or cap into "other"
+type tenantLabeler struct {
+	config MetricsTenantsConfig
+	allow  map[string]bool
+	seen   map[string]bool
+	lock   sync.Mutex
+}
+
+func newTenantLabeler(config MetricsTenantsConfig) *tenantLabeler {
+	labeler := &tenantLabeler{config: config, seen: make(map[string]bool)}
+	if len(config.Allow) > 0 {
+		labeler.allow = make(map[string]bool, len(config.Allow))
+		for _, tenant := range config.Allow {
+			labeler.allow[tenant] = true
+		}
+	}
+	return labeler
+}
+
+// label returns the label value for a tenant
+func (l *tenantLabeler) label(tenant string) string {
+	if tenant == "" {
+		return metricsTenantNone
+	}
+	if l.allow != nil {
+		if l.allow[tenant] {
+			return tenant
+		}
+		return metricsTenantOther
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.seen[tenant] {
+		if len(l.seen) >= l.config.maxTenants() {
+			return metricsTenantOther
+		}
+		l.seen[tenant] = true
+	}
+	return tenant
+}
+
+// metricsTenantMiddleware records the tenant of session requests in their context
+func (g *Gateway) metricsTenantMiddleware(next http.Handler) http.Handler {
+	header := g.config.Metrics.Tenants.Header
+	if header == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Header.Get("Mcp-Session-Id") != "" {
+			r = r.WithContext(context.WithValue(r.Context(), metricsTenantKey{}, r.Header.Get(header)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// addTenantLabel labels a call's metrics with its tenant when tenant labels are enabled
+func (g *Gateway) addTenantLabel(ctx context.Context, labels map[string]string) map[string]string {
+	if g.tenantLabels == nil {
+		return labels
+	}
+	tenant, _ := ctx.Value(metricsTenantKey{}).(string)
+	labels["tenant"] = g.tenantLabels.label(tenant)
+	return labels
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestMetricsTenantLabels verifies tool call metrics are labeled with the caller's tenant,
+// and tenants past the cap are bucketed into "other"
+func TestMetricsTenantLabels(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.Metrics.Sinks = []MetricsSinkConfig{{Type: "recording"}}
+	config.Metrics.Tenants = MetricsTenantsConfig{Header: "X-Tenant", MaxTenants: 2}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	for _, tenant := range []string{"acme", "globex", "initech", "umbrella", "acme"} {
+		mcpClient := newTestClient(t, gatewayURL, transport.WithHTTPHeaders(map[string]string{"X-Tenant": tenant}))
+		callTool(t, mcpClient, "server1-echo", nil)
+	}
+	callTool(t, newTestClient(t, gatewayURL), "server1-echo", nil)
+
+	recorded := gateway.metrics.(*recordingSink).recorded()
+	counts := make(map[string]int)
+	for _, metric := range recorded {
+		counts[metric]++
+	}
+	for tenant, calls := range map[string]int{"acme": 2, "globex": 1, "other": 2, "none": 1} {
+		want := "counter tool_calls backend=server1,status=ok,tenant=" + tenant + ",tool=server1-echo"
+		if counts[want] != calls {
+			t.Errorf("Expected %d of %q, got %d in %v", calls, want, counts[want], recorded)
+		}
+		if want := "timing tool_call_duration backend=server1,tenant=" + tenant + ",tool=server1-echo"; counts[want] != calls {
+			t.Errorf("Expected %d of %q, got %d", calls, want, counts[want])
+		}
+	}
+}
+
+// TestMetricsTenantAllowlist verifies only allowlisted tenants are labeled by name
+func TestMetricsTenantAllowlist(t *testing.T) {
+	labeler := newTenantLabeler(MetricsTenantsConfig{Header: "X-Tenant", Allow: []string{"acme"}})
+	for tenant, expected := range map[string]string{"acme": "acme", "globex": metricsTenantOther, "": metricsTenantNone} {
+		if label := labeler.label(tenant); label != expected {
+			t.Errorf("Expected tenant %q to be labeled %q, got %q", tenant, expected, label)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// How long a backend's tools refreshed after a not-found error are reused, so a burst of
+// not-found calls refreshes the backend once
+const notFoundRefreshWindow = 5 * time.Second
+
+// notFoundKey carries a *bool that proxyToolCall sets when the backend reports the called
+// tool doesn't exist
+type notFoundKey struct{}
+
+// isToolNotFound reports whether a backend rejected the call because it has no such tool,
+// e.g. after its tools changed. JSON-RPC errors only keep their message, so the common
+// SDK messages are matched.
+func isToolNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "tool not found") ||
+		strings.Contains(message, "method not found") ||
+		strings.Contains(message, "unknown tool")
+}
+
+// markToolNotFound notes a not-found error for proxyWithFallback
+func markToolNotFound(ctx context.Context, err error) {
+	if notFound, ok := ctx.Value(notFoundKey{}).(*bool); ok && isToolNotFound(err) {
+		*notFound = true
+	}
+}
+
+// proxyWithFallback proxies a tool call and, with notFoundFallback enabled, retries it
+// once on the tool's current route if the backend no longer has the tool. It returns the
+// route that served the call.
+func (g *Gateway) proxyWithFallback(ctx context.Context, clientSessionID, toolName string, route toolRoute, req mcp.CallToolRequest) (*mcp.CallToolResult, toolRoute, error) {
+	if !g.config.NotFoundFallback || g.isArgumentRouted(toolName) {
+		result, err := g.proxyToolCall(ctx, clientSessionID, toolName, route, req)
+		return result, route, err
+	}
+
+	notFound := new(bool)
+	result, err := g.proxyToolCall(context.WithValue(ctx, notFoundKey{}, notFound), clientSessionID, toolName, route, req)
+	if !*notFound {
+		return result, route, err
+	}
+	moved, ok := g.movedRoute(ctx, toolName, route)
+	if !ok {
+		return result, route, err
+	}
+
+	// Only one fallback: the retry's own not-found is returned as is
+	log.Printf("🔧 %s not found on %s, retrying on %s tool %s", toolName, route.Backend, moved.Backend, moved.ToolName)
+	result, err = g.proxyToolCall(ctx, clientSessionID, toolName, moved, req)
+	return result, moved, err
+}
+
+// movedRoute re-resolves a tool whose backend reported it not found. If the registry
+// still routes it to that backend, the backend's tools are refreshed first.
+func (g *Gateway) movedRoute(ctx context.Context, toolName string, failed toolRoute) (toolRoute, bool) {
+	if current, ok := g.currentRoute(toolName); ok && current != failed {
+		return current, true
+	}
+	backend, ok := g.configuredBackend(failed.Backend)
+	if !ok {
+		return toolRoute{}, false
+	}
+	if err := g.refreshAfterNotFound(ctx, backend); err != nil {
+		log.Printf("❌ Failed to refresh %s after %s was not found: %v", failed.Backend, toolName, err)
+		return toolRoute{}, false
+	}
+	current, ok := g.currentRoute(toolName)
+	return current, ok && current != failed
+}
+
+// notFoundRefresh is a refresh of a backend's tools after a not-found error
+type notFoundRefresh struct {
+	done     chan struct{}
+	finished time.Time
+	err      error
+}
+
+// refreshAfterNotFound refreshes a backend's tools after a not-found error. Calls arriving
+// while a refresh runs wait for it, and a refresh finished within notFoundRefreshWindow
+// is reused rather than repeated.
+func (g *Gateway) refreshAfterNotFound(ctx context.Context, backend BackendConfig) error {
+	g.notFoundRefreshLock.Lock()
+	if g.notFoundRefreshes == nil {
+		g.notFoundRefreshes = make(map[string]*notFoundRefresh)
+	}
+	refresh, ok := g.notFoundRefreshes[backend.Name]
+	if ok {
+		select {
+		case <-refresh.done:
+			ok = time.Since(refresh.finished) < notFoundRefreshWindow
+		default:
+		}
+	}
+	if ok {
+		g.notFoundRefreshLock.Unlock()
+		select {
+		case <-refresh.done:
+			return refresh.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	refresh = &notFoundRefresh{done: make(chan struct{})}
+	g.notFoundRefreshes[backend.Name] = refresh
+	g.notFoundRefreshLock.Unlock()
+
+	// Other calls wait for this refresh, so it outlives this call's client
+	_, refresh.err = g.refreshBackend(context.WithoutCancel(ctx), backend)
+	refresh.finished = time.Now()
+	close(refresh.done)
+	return refresh.err
+}
+
+// currentRoute looks up a tool's route in the registry
+func (g *Gateway) currentRoute(toolName string) (toolRoute, bool) {
+	g.toolsLock.RLock()
+	defer g.toolsLock.RUnlock()
+	route, ok := g.toolRoutes[toolName]
+	return route, ok
+}
+
+// isArgumentRouted reports whether calls of a tool are routed by an argument; their
+// backend is chosen per call, so the registry can't re-resolve them
+func (g *Gateway) isArgumentRouted(toolName string) bool {
+	for _, argumentRoute := range g.config.ArgumentRoutes {
+		if argumentRoute.Tool == toolName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// startMovingToolGateway serves team/docs/search from the team backend, which shadows
+// the docs backend's tool of the same gateway name, and returns the team backend's MCP
+// server so the test can remove the tool from it
+func startMovingToolGateway(t *testing.T, fallback bool) (*server.MCPServer, string) {
+	t.Helper()
+
+	// Without list_changed the gateway doesn't learn of the removal until it asks
+	teamServer := server.NewMCPServer("Team", "1.0.0", server.WithToolCapabilities(false))
+	team := textTool(mcp.NewTool("docs/search"), "from team")
+	teamServer.AddTool(team.tool, team.handler)
+	teamBackend := httptest.NewServer(server.NewStreamableHTTPServer(teamServer))
+	t.Cleanup(teamBackend.Close)
+	docsBackend := newStubBackend(t, "Docs", textTool(mcp.NewTool("search"), "from docs"))
+
+	config := DefaultConfig()
+	config.ToolNames.Separator = toolNameSeparatorPath
+	config.NotFoundFallback = fallback
+	config.Backends = []BackendConfig{
+		{Name: "team", URL: teamBackend.URL},
+		{Name: "docs", Namespace: "team/docs", URL: docsBackend.URL},
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	return teamServer, gatewayURL
+}
+
+// TestNotFoundFallback verifies a call whose backend no longer has the tool is retried
+// once on the backend that now offers it
+func TestNotFoundFallback(t *testing.T) {
+	teamServer, gatewayURL := startMovingToolGateway(t, true)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "team/docs/search", nil)); text != "from team" {
+		t.Fatalf("Expected the team backend to serve the tool first, got %q", text)
+	}
+
+	teamServer.DeleteTools("docs/search")
+	result := callTool(t, mcpClient, "team/docs/search", nil)
+	if result.IsError || extractTextFromResult(result) != "from docs" {
+		t.Fatalf("Expected the call to fall back to the docs backend, got %q", extractTextFromResult(result))
+	}
+
+	// The registry now routes the tool to its new backend directly
+	if text := extractTextFromResult(callTool(t, mcpClient, "team/docs/search", nil)); text != "from docs" {
+		t.Errorf("Expected later calls to go to the docs backend, got %q", text)
+	}
+}
+
+// TestNotFoundFallbackDisabled verifies the not-found error is returned without the setting
+func TestNotFoundFallbackDisabled(t *testing.T) {
+	teamServer, gatewayURL := startMovingToolGateway(t, false)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	teamServer.DeleteTools("docs/search")
+	result := callTool(t, mcpClient, "team/docs/search", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "not found") {
+		t.Errorf("Expected the backend's not-found error, got %q", extractTextFromResult(result))
+	}
+}
+
+// TestIsToolNotFound verifies the not-found messages of common backends are recognized
+func TestIsToolNotFound(t *testing.T) {
+	cases := map[string]bool{
+		"tool 'search' not found: tool not found": true,
+		"Method not found":                        true,
+		"Unknown tool: search":                    true,
+		"session terminated":                      false,
+		"file not found":                          false,
+	}
+	for message, expected := range cases {
+		if got := isToolNotFound(errors.New(message)); got != expected {
+			t.Errorf("isToolNotFound(%q) = %v, expected %v", message, got, expected)
+		}
+	}
+	if isToolNotFound(nil) {
+		t.Error("Expected nil not to be a not-found error")
+	}
+}
+
+// TestNotFoundRefreshDebounce verifies repeated not-found errors from a backend within
+// the refresh window refresh its tools once
+func TestNotFoundRefreshDebounce(t *testing.T) {
+	// The backend keeps listing the tool but reports it not found when called
+	backend, initializes := newInitializeCountingBackend(t, newStubBackend(t, "Stale", stubTool{
+		tool: mcp.NewTool("search"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("tool not found")
+		},
+	}))
+	config := DefaultConfig()
+	config.NotFoundFallback = true
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// The first call opens the session's connection and refreshes the backend
+	callTool(t, mcpClient, "server1-search", nil)
+	refreshed := initializes.Load()
+	for range 3 {
+		if result := callTool(t, mcpClient, "server1-search", nil); !result.IsError {
+			t.Fatalf("Expected the backend's not-found error, got %q", extractTextFromResult(result))
+		}
+	}
+	if refreshes := initializes.Load() - refreshed; refreshes != 0 {
+		t.Errorf("Expected the recent refresh to be reused, got %d more refreshes", refreshes)
+	}
+}
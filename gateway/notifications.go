@@ -0,0 +1,290 @@
+package gateway
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Default number of notifications queued per client session
+const defaultNotificationBufferSize = 100
+
+// Notification buffer overflow policies
+const (
+	overflowDropOldest = "dropOldest"
+	overflowDropNewest = "dropNewest"
+)
+
+// NotificationsConfig controls forwarding of backend notifications to clients
+type NotificationsConfig struct {
+	// Forward relays notifications (progress, logging, resource updates) from a client's
+	// backend sessions to the client's listening stream
+	Forward bool `yaml:"forward"`
+	// RatePerSecond limits notifications forwarded per client session; 0 is unlimited
+	RatePerSecond float64 `yaml:"ratePerSecond"`
+	// Burst is how many notifications may be forwarded at once above the rate (default 1)
+	Burst int `yaml:"burst"`
+	// BufferSize is how many notifications are queued per session while the client catches up (default 100)
+	BufferSize int `yaml:"bufferSize"`
+	// Overflow picks what to drop when the buffer is full: dropOldest (default) or dropNewest
+	Overflow string `yaml:"overflow"`
+	// MaxAge discards buffered notifications received longer ago than this instead of
+	// delivering them, including those kept for polling; 0 delivers them however old
+	MaxAge time.Duration `yaml:"maxAge"`
+	// LongPoll keeps notifications that can't be pushed for clients to poll
+	LongPoll LongPollConfig `yaml:"longPoll"`
+	// PartialResults relays a tool call's progress notifications, including partial
+	// result chunks, to the calling client on the call's own response stream
+	PartialResults bool `yaml:"partialResults"`
+}
+
+// notificationCounts counts notifications of one method
+type notificationCounts struct {
+	Forwarded int64 `json:"forwarded"`
+	Dropped   int64 `json:"dropped"`
+}
+
+// notificationStats counts forwarded and dropped notifications by method across all sessions
+type notificationStats struct {
+	lock     sync.Mutex
+	byMethod map[string]*notificationCounts
+}
+
+// record counts one notification as forwarded or dropped
+func (s *notificationStats) record(method string, forwarded bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.byMethod == nil {
+		s.byMethod = make(map[string]*notificationCounts)
+	}
+	counts, ok := s.byMethod[method]
+	if !ok {
+		counts = &notificationCounts{}
+		s.byMethod[method] = counts
+	}
+	if forwarded {
+		counts.Forwarded++
+	} else {
+		counts.Dropped++
+	}
+}
+
+// snapshot returns a copy of the counts keyed by method
+func (s *notificationStats) snapshot() map[string]notificationCounts {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot := make(map[string]notificationCounts, len(s.byMethod))
+	for method, counts := range s.byMethod {
+		snapshot[method] = *counts
+	}
+	return snapshot
+}
+
+// tokenBucket is a simple rate limiter refilling at rate tokens per second up to burst
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full token bucket
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// take consumes a token, reporting false when none is available
+func (b *tokenBucket) take() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// untilNext returns how long until a token is available
+func (b *tokenBucket) untilNext() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	missing := 1 - min(b.burst, b.tokens+time.Since(b.last).Seconds()*b.rate)
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// idleSince returns when the bucket last gave out a token
+func (b *tokenBucket) idleSince() time.Time {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.last
+}
+
+// bufferedNotification is a notification waiting for delivery, with when it was received
+type bufferedNotification struct {
+	notification mcp.JSONRPCNotification
+	received     time.Time
+}
+
+// stale reports whether the notification was received longer than maxAge ago
+func (b bufferedNotification) stale(maxAge time.Duration) bool {
+	return maxAge > 0 && time.Since(b.received) > maxAge
+}
+
+// notificationForwarder queues a client session's backend notifications and delivers them
+// in the background, so a slow client or chatty backend never blocks the backend read loop
+type notificationForwarder struct {
+	sessionID  string
+	bufferSize int
+	dropNewest bool
+	maxAge     time.Duration
+	limiter    *tokenBucket
+	send       func(mcp.JSONRPCNotification) error
+	stats      *notificationStats
+	// mailbox, if set, keeps notifications send fails to deliver for polling
+	mailbox *notificationMailbox
+
+	lock  sync.Mutex
+	queue []bufferedNotification
+	wake  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// newNotificationForwarder creates a forwarder and starts its delivery loop
+func newNotificationForwarder(sessionID string, config NotificationsConfig, stats *notificationStats, send func(mcp.JSONRPCNotification) error) *notificationForwarder {
+	f := &notificationForwarder{
+		sessionID:  sessionID,
+		bufferSize: config.BufferSize,
+		dropNewest: config.Overflow == overflowDropNewest,
+		maxAge:     config.MaxAge,
+		send:       send,
+		stats:      stats,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	if f.bufferSize == 0 {
+		f.bufferSize = defaultNotificationBufferSize
+	}
+	if config.RatePerSecond > 0 {
+		f.limiter = newTokenBucket(config.RatePerSecond, config.Burst)
+	}
+	go f.run()
+	return f
+}
+
+// enqueue queues a notification without blocking, dropping it (or the oldest queued one)
+// when the session is over its rate limit or its buffer is full
+func (f *notificationForwarder) enqueue(notification mcp.JSONRPCNotification) {
+	if f.limiter != nil && !f.limiter.take() {
+		f.stats.record(notification.Method, false)
+		return
+	}
+
+	f.lock.Lock()
+	if len(f.queue) >= f.bufferSize {
+		if f.dropNewest {
+			f.lock.Unlock()
+			f.stats.record(notification.Method, false)
+			return
+		}
+		f.stats.record(f.queue[0].notification.Method, false)
+		f.queue = f.queue[1:]
+	}
+	f.queue = append(f.queue, bufferedNotification{notification: notification, received: time.Now()})
+	f.lock.Unlock()
+
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued notifications until the forwarder is closed
+func (f *notificationForwarder) run() {
+	for {
+		select {
+		case <-f.wake:
+		case <-f.done:
+			return
+		}
+
+		for {
+			f.lock.Lock()
+			if len(f.queue) == 0 {
+				f.lock.Unlock()
+				break
+			}
+			buffered := f.queue[0]
+			f.queue = f.queue[1:]
+			f.lock.Unlock()
+
+			// Obsolete progress and log messages aren't worth delivering
+			notification := buffered.notification
+			if buffered.stale(f.maxAge) {
+				f.stats.record(notification.Method, false)
+				continue
+			}
+
+			err := f.send(notification)
+			if err != nil && f.mailbox != nil {
+				// The client has no listening stream: keep it for the client's next poll
+				if evicted := f.mailbox.put(buffered); evicted != nil {
+					f.stats.record(evicted.Method, false)
+				}
+				err = nil
+			}
+			if err != nil {
+				log.Printf("⚠️ Dropped %s notification for client %s: %v", notification.Method, f.sessionID, err)
+				f.stats.record(notification.Method, false)
+				continue
+			}
+			f.stats.record(notification.Method, true)
+		}
+	}
+}
+
+// close stops delivery; queued notifications are discarded
+func (f *notificationForwarder) close() {
+	f.once.Do(func() { close(f.done) })
+}
+
+// newNotificationForwarder creates a forwarder delivering to the client's listening stream,
+// or to its poll mailbox when it has none and long polling is enabled
+func (g *Gateway) newNotificationForwarder(clientSessionID string) *notificationForwarder {
+	forwarder := newNotificationForwarder(clientSessionID, g.config.Notifications, g.notificationStats, func(notification mcp.JSONRPCNotification) error {
+		return g.mcpServer.SendNotificationToSpecificClient(clientSessionID, notification.Method, notificationParams(notification))
+	})
+	if longPoll := g.config.Notifications.LongPoll; longPoll.Enabled {
+		forwarder.mailbox = newNotificationMailbox(longPoll.BufferSize, g.config.Notifications.MaxAge, g.notificationStats)
+	}
+	return forwarder
+}
+
+// notificationParams returns a notification's params as sent to clients
+func notificationParams(notification mcp.JSONRPCNotification) map[string]any {
+	params := make(map[string]any, len(notification.Params.AdditionalFields)+1)
+	for key, value := range notification.Params.AdditionalFields {
+		params[key] = value
+	}
+	if len(notification.Params.Meta) > 0 {
+		params["_meta"] = notification.Params.Meta
+	}
+	return params
+}
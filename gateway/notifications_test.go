@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressNotification builds a progress notification with the given sequence number
+func progressNotification(seq int) mcp.JSONRPCNotification {
+	notification := mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION}
+	notification.Method = "notifications/progress"
+	notification.Params.AdditionalFields = map[string]any{"progress": seq}
+	return notification
+}
+
+// TestNotificationFloodDoesNotBlock verifies a flood against a stalled client never blocks
+// the backend read loop and that overflow drops are counted
+func TestNotificationFloodDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	delivered := make(chan int, 100)
+	stats := &notificationStats{}
+	forwarder := newNotificationForwarder("flood", NotificationsConfig{BufferSize: 10}, stats, func(n mcp.JSONRPCNotification) error {
+		<-release
+		delivered <- n.Params.AdditionalFields["progress"].(int)
+		return nil
+	})
+	defer forwarder.close()
+
+	// The read loop enqueues 1000 notifications while the client is stalled
+	readLoopDone := make(chan struct{})
+	go func() {
+		defer close(readLoopDone)
+		for i := 0; i < 1000; i++ {
+			forwarder.enqueue(progressNotification(i))
+		}
+	}()
+	select {
+	case <-readLoopDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Backend read loop blocked on a stalled client")
+	}
+
+	close(release)
+	deadline := time.After(2 * time.Second)
+	var last int
+	for {
+		counts := stats.snapshot()["notifications/progress"]
+		if counts.Forwarded+counts.Dropped == 1000 {
+			if counts.Dropped < 980 {
+				t.Errorf("Expected overflow drops to be counted, got %+v", counts)
+			}
+			break
+		}
+		select {
+		case last = <-delivered:
+		case <-deadline:
+			t.Fatalf("Expected every notification to be forwarded or dropped, got %+v", counts)
+		}
+	}
+
+	// dropOldest keeps the newest notifications
+	for len(delivered) > 0 {
+		last = <-delivered
+	}
+	if last != 999 {
+		t.Errorf("Expected the newest notification to be delivered last, got %d", last)
+	}
+}
+
+// TestNotificationRateLimit verifies notifications over the session rate are dropped and counted
+func TestNotificationRateLimit(t *testing.T) {
+	stats := &notificationStats{}
+	forwarder := newNotificationForwarder("chatty", NotificationsConfig{RatePerSecond: 1, Burst: 5}, stats, func(mcp.JSONRPCNotification) error {
+		return nil
+	})
+	defer forwarder.close()
+
+	for i := 0; i < 50; i++ {
+		forwarder.enqueue(progressNotification(i))
+	}
+
+	counts := stats.snapshot()["notifications/progress"]
+	if counts.Dropped < 44 {
+		t.Errorf("Expected notifications over the rate to be dropped, got %+v", counts)
+	}
+}
+
+// TestNotificationMaxAge verifies notifications buffered longer than maxAge are dropped
+// while fresh ones are delivered
+func TestNotificationMaxAge(t *testing.T) {
+	release := make(chan struct{})
+	delivered := make(chan int, 10)
+	stats := &notificationStats{}
+	forwarder := newNotificationForwarder("stalled", NotificationsConfig{MaxAge: 50 * time.Millisecond}, stats, func(n mcp.JSONRPCNotification) error {
+		<-release
+		delivered <- n.Params.AdditionalFields["progress"].(int)
+		return nil
+	})
+	defer forwarder.close()
+
+	// The first notification is being sent while the others wait in the buffer
+	forwarder.enqueue(progressNotification(0))
+	for i := 1; i <= 3; i++ {
+		forwarder.enqueue(progressNotification(i))
+	}
+	time.Sleep(100 * time.Millisecond)
+	forwarder.enqueue(progressNotification(4))
+	forwarder.enqueue(progressNotification(5))
+	close(release)
+
+	var received []int
+	for len(received) < 3 {
+		select {
+		case seq := <-delivered:
+			received = append(received, seq)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected the fresh notifications to be delivered, got %v", received)
+		}
+	}
+	if fmt.Sprint(received) != "[0 4 5]" {
+		t.Errorf("Expected only fresh notifications to be delivered, got %v", received)
+	}
+	if counts := stats.snapshot()["notifications/progress"]; counts.Dropped != 3 {
+		t.Errorf("Expected the 3 stale notifications to be counted as dropped, got %+v", counts)
+	}
+}
+
+// TestNotificationForwardingFromBackend verifies a chatty backend's tool call completes and
+// its notifications are counted by method
+func TestNotificationForwardingFromBackend(t *testing.T) {
+	backend := newStubBackend(t, "Chatty", stubTool{
+		tool: mcp.NewTool("flood"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			mcpServer := server.ServerFromContext(ctx)
+			for i := 0; i < 50; i++ {
+				mcpServer.SendNotificationToClient(ctx, "notifications/message", map[string]any{"level": "info", "data": fmt.Sprint(i)})
+			}
+			// Let the backend's stream write the notifications before the response
+			time.Sleep(100 * time.Millisecond)
+			return mcp.NewToolResultText("done"), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Notifications = NotificationsConfig{Forward: true, BufferSize: 5}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-flood", nil)); text != "done" {
+		t.Fatalf("Expected the tool call to complete, got %q", text)
+	}
+
+	// The client has no listening stream, so nothing can be delivered
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		counts := gateway.notificationStats.snapshot()["notifications/message"]
+		if counts.Dropped == 50 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 50 dropped notifications/message, got %+v", counts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
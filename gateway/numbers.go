@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Numbers in tool call arguments and result _meta are passed to backends and clients as
+// they were written. mcp-go decodes them to float64, which loses integers above 2^53
+// (such as 64-bit IDs) and turns 10 into 10.0 for backends that care, so the gateway
+// decodes the raw JSON again keeping each number as a json.Number.
+
+// clientArgumentsKey is the context key of a client tools/call's raw arguments
+type clientArgumentsKey struct{}
+
+// rawResultKey is the context key of where a backend call's raw result is stored
+type rawResultKey struct{}
+
+// withClientArguments records the raw arguments of a client tools/call request's params
+func withClientArguments(ctx context.Context, params json.RawMessage) context.Context {
+	var decoded struct {
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if json.Unmarshal(params, &decoded) != nil || len(decoded.Arguments) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, clientArgumentsKey{}, decoded.Arguments)
+}
+
+// preciseArguments returns the client's arguments with their numbers as written, or
+// arguments unchanged when the raw arguments aren't known
+func preciseArguments(ctx context.Context, arguments any) any {
+	raw, ok := ctx.Value(clientArgumentsKey{}).(json.RawMessage)
+	if !ok {
+		return arguments
+	}
+	var precise any
+	if decodeNumbers(raw, &precise) != nil {
+		return arguments
+	}
+	return precise
+}
+
+// decodeNumbers decodes JSON keeping numbers as json.Number
+func decodeNumbers(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// withRawResult returns a context in which a backend's tools/call result is kept, as
+// written, in the returned message
+func withRawResult(ctx context.Context) (context.Context, *json.RawMessage) {
+	raw := new(json.RawMessage)
+	return context.WithValue(ctx, rawResultKey{}, raw), raw
+}
+
+// preciseResultMeta replaces a result's _meta with its raw encoding's, keeping numbers
+// as written
+func preciseResultMeta(result *mcp.CallToolResult, raw json.RawMessage) {
+	if result == nil || len(result.Meta) == 0 || len(raw) == 0 {
+		return
+	}
+	var decoded struct {
+		Meta map[string]any `json:"_meta"`
+	}
+	if decodeNumbers(raw, &decoded) == nil && decoded.Meta != nil {
+		result.Meta = decoded.Meta
+	}
+}
+
+// numberTransport keeps the raw result of tools/call responses for the caller's
+// preciseResultMeta
+type numberTransport struct {
+	transport.Interface
+}
+
+// SendRequest stores a tools/call response's raw result where the context asks for it
+func (t *numberTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	response, err := t.Interface.SendRequest(ctx, request)
+	if err != nil || response.Error != nil || request.Method != string(mcp.MethodToolsCall) {
+		return response, err
+	}
+	if raw, ok := ctx.Value(rawResultKey{}).(*json.RawMessage); ok {
+		*raw = response.Result
+	}
+	return response, nil
+}
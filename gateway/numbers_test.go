@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newArgumentRecordingBackend serves an "order" tool whose result _meta has a large
+// integer, recording the raw arguments of every tools/call it receives
+func newArgumentRecordingBackend(t *testing.T) (*httptest.Server, func() []string) {
+	mcpServer := server.NewMCPServer("Orders", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("order"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("ok")
+		result.Meta = map[string]any{"orderId": json.Number("9007199254740993")}
+		return result, nil
+	})
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	var lock sync.Mutex
+	var recorded []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		var message struct {
+			Method string `json:"method"`
+			Params struct {
+				Arguments json.RawMessage `json:"arguments"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodToolsCall) {
+			lock.Lock()
+			recorded = append(recorded, string(message.Params.Arguments))
+			lock.Unlock()
+		}
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	return backend, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string(nil), recorded...)
+	}
+}
+
+// TestNumberFidelity verifies large integers and float representations in arguments
+// reach the backend byte for byte, and large integers in result _meta reach the client
+func TestNumberFidelity(t *testing.T) {
+	backend, recorded := newArgumentRecordingBackend(t)
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+
+	arguments := `{"amount":10.0,"orderId":9007199254740993,"tags":[1e3,-0.5]}`
+	resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-order","arguments":`+arguments+`}}`)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if calls := recorded(); len(calls) != 1 || calls[0] != arguments {
+		t.Errorf("Expected the backend to receive %s, got %v", arguments, calls)
+	}
+	if !strings.Contains(string(body), `"orderId":9007199254740993`) {
+		t.Errorf("Expected the result _meta to keep the large integer, got %s", body)
+	}
+}
+
+// TestPreciseArgumentsWithoutRaw verifies arguments are kept when the raw arguments
+// aren't known
+func TestPreciseArgumentsWithoutRaw(t *testing.T) {
+	arguments := map[string]any{"id": float64(1)}
+	if precise := preciseArguments(context.Background(), arguments); precise.(map[string]any)["id"] != float64(1) {
+		t.Errorf("Expected arguments unchanged, got %v", precise)
+	}
+}
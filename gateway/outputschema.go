@@ -0,0 +1,320 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Output schema violation handling modes
+const (
+	outputSchemaLog  = "log"
+	outputSchemaFail = "fail"
+)
+
+// OutputSchemasConfig validates the structured content of tool results against the
+// outputSchema their tool declares
+type OutputSchemasConfig struct {
+	// Enabled checks results of tools that declare an output schema
+	Enabled bool `yaml:"enabled"`
+	// OnViolation is "log" (default: log the violation and pass the result through)
+	// or "fail" (replace the result with an error result)
+	OnViolation string `yaml:"onViolation"`
+}
+
+// onViolation returns the configured violation mode, defaulting to log
+func (c OutputSchemasConfig) onViolation() string {
+	if c.OnViolation == "" {
+		return outputSchemaLog
+	}
+	return c.OnViolation
+}
+
+// Validate checks the violation mode
+func (c OutputSchemasConfig) Validate() error {
+	switch c.onViolation() {
+	case outputSchemaLog, outputSchemaFail:
+		return nil
+	default:
+		return fmt.Errorf("outputSchemas.onViolation must be %q or %q, got %q", outputSchemaLog, outputSchemaFail, c.OnViolation)
+	}
+}
+
+// outputSchemaStore holds the output schemas backends declared in their tool lists,
+// keyed by backend and backend tool name
+type outputSchemaStore struct {
+	schemas map[string]map[string]map[string]any
+	lock    sync.RWMutex
+}
+
+// record updates a backend's output schemas from a tools/list result
+func (s *outputSchemaStore) record(backend string, result json.RawMessage) {
+	var listed struct {
+		Tools []struct {
+			Name         string         `json:"name"`
+			OutputSchema map[string]any `json:"outputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(result, &listed); err != nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.schemas == nil {
+		s.schemas = make(map[string]map[string]map[string]any)
+	}
+	if s.schemas[backend] == nil {
+		s.schemas[backend] = make(map[string]map[string]any)
+	}
+	for _, tool := range listed.Tools {
+		if tool.OutputSchema != nil {
+			s.schemas[backend][tool.Name] = tool.OutputSchema
+		} else {
+			delete(s.schemas[backend], tool.Name)
+		}
+	}
+}
+
+// lookup returns the output schema a backend declared for a tool
+func (s *outputSchemaStore) lookup(backend, tool string) (map[string]any, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	schema, ok := s.schemas[backend][tool]
+	return schema, ok
+}
+
+// outputSchemaTransport records the output schemas in a backend's tool lists and
+// validates tool results against them. mcp-go's types drop outputSchema and
+// structuredContent, so this works on the raw JSON-RPC messages.
+type outputSchemaTransport struct {
+	transport.Interface
+	gateway *Gateway
+	backend string
+}
+
+// SendRequest inspects tools/list and tools/call responses
+func (t *outputSchemaTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	response, err := t.Interface.SendRequest(ctx, request)
+	if err != nil || response.Error != nil {
+		return response, err
+	}
+
+	switch request.Method {
+	case string(mcp.MethodToolsList):
+		t.gateway.outputSchemas.record(t.backend, response.Result)
+	case string(mcp.MethodToolsCall):
+		params, _ := request.Params.(mcp.CallToolParams)
+		violation := t.gateway.checkOutputSchema(t.backend, params.Name, response.Result)
+		if violation == nil {
+			break
+		}
+		if t.gateway.config.OutputSchemas.onViolation() == outputSchemaFail {
+			log.Printf("❌ %s tool %s returned a result violating its output schema: %v", t.backend, params.Name, violation)
+			result, err := json.Marshal(mcp.NewToolResultError(fmt.Sprintf("Tool %s returned a result that violates its output schema: %v", params.Name, violation)))
+			if err != nil {
+				return nil, err
+			}
+			response.Result = result
+		} else {
+			log.Printf("⚠️ %s tool %s returned a result violating its output schema: %v", t.backend, params.Name, violation)
+		}
+	}
+	return response, nil
+}
+
+// wrapOutputSchemaValidation adds output schema validation to a backend's transport wrap
+func (g *Gateway) wrapOutputSchemaValidation(backend BackendConfig, wrap func(transport.Interface) transport.Interface) func(transport.Interface) transport.Interface {
+	if !g.config.OutputSchemas.Enabled {
+		return wrap
+	}
+	return func(t transport.Interface) transport.Interface {
+		if wrap != nil {
+			t = wrap(t)
+		}
+		return &outputSchemaTransport{Interface: t, gateway: g, backend: backend.Name}
+	}
+}
+
+// checkOutputSchema validates a successful tool result's structured content against
+// the tool's declared output schema, returning the violation if any
+func (g *Gateway) checkOutputSchema(backend, tool string, result json.RawMessage) error {
+	schema, ok := g.outputSchemas.lookup(backend, tool)
+	if !ok {
+		return nil
+	}
+
+	var decoded struct {
+		IsError           bool            `json:"isError"`
+		StructuredContent json.RawMessage `json:"structuredContent"`
+	}
+	if err := json.Unmarshal(result, &decoded); err != nil {
+		return fmt.Errorf("invalid result: %w", err)
+	}
+	if decoded.IsError {
+		return nil
+	}
+	if len(decoded.StructuredContent) == 0 || string(decoded.StructuredContent) == "null" {
+		return fmt.Errorf("structuredContent is missing")
+	}
+
+	var content any
+	if err := json.Unmarshal(decoded.StructuredContent, &content); err != nil {
+		return fmt.Errorf("invalid structuredContent: %w", err)
+	}
+	return validateSchema(schema, content, "structuredContent")
+}
+
+// validateSchema checks value against the commonly used subset of JSON Schema: type,
+// enum, const, properties, required, additionalProperties, items and numeric, length
+// and size bounds. Other keywords are ignored.
+func validateSchema(schema map[string]any, value any, path string) error {
+	if types, ok := schema["type"]; ok && !matchesType(types, value) {
+		return fmt.Errorf("%s: expected %v, got %s", path, types, jsonType(value))
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if jsonEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value is not one of %v", path, enum)
+		}
+	}
+	if constant, ok := schema["const"]; ok && !jsonEqual(constant, value) {
+		return fmt.Errorf("%s: expected %v", path, constant)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(schema, v, path)
+	case []any:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(v)) < minItems {
+			return fmt.Errorf("%s: expected at least %v items", path, minItems)
+		}
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(v)) > maxItems {
+			return fmt.Errorf("%s: expected at most %v items", path, maxItems)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+			return fmt.Errorf("%s: expected at least %v characters", path, minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+			return fmt.Errorf("%s: expected at most %v characters", path, maxLength)
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			return fmt.Errorf("%s: %v is less than %v", path, v, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			return fmt.Errorf("%s: %v is greater than %v", path, v, maximum)
+		}
+	}
+	return nil
+}
+
+// validateObject checks an object's required, declared and additional properties
+func validateObject(schema map[string]any, object map[string]any, path string) error {
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		if name, ok := name.(string); ok {
+			if _, present := object[name]; !present {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := path + "." + name
+		if property, ok := properties[name].(map[string]any); ok {
+			if err := validateSchema(property, object[name], propertyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: additional property is not allowed", propertyPath)
+			}
+		case map[string]any:
+			if err := validateSchema(additional, object[name], propertyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matchesType reports whether value has the schema type, or one of the schema types
+func matchesType(types any, value any) bool {
+	switch t := types.(type) {
+	case string:
+		return jsonType(value) == t || (t == "number" && jsonType(value) == "integer")
+	case []any:
+		for _, single := range t {
+			if matchesType(single, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return strings.ToLower(fmt.Sprintf("%T", value))
+	}
+}
+
+// jsonEqual compares two decoded JSON values
+func jsonEqual(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// weatherSchema is the output schema the structured backend declares for its weather tool
+const weatherSchema = `{"type":"object","properties":{"temperature":{"type":"number"},"conditions":{"type":"string","enum":["sunny","rainy"]}},"required":["temperature"]}`
+
+// newStructuredBackend serves a "weather" tool declaring weatherSchema as its output
+// schema and returning the given structured content. mcp-go can't declare output
+// schemas, so the tools/list response is patched on the way out.
+func newStructuredBackend(t *testing.T, structuredContent string) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Structured", "1.0.0", server.WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("weather"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("weather is answered by the test handler")
+	})
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message jsonRPCMessage
+		json.Unmarshal(body, &message)
+		switch message.Method {
+		case string(mcp.MethodToolsCall):
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"content":[{"type":"text","text":%q}],"structuredContent":%s}}`,
+				message.ID, structuredContent, structuredContent)
+		case string(mcp.MethodToolsList):
+			recorder := httptest.NewRecorder()
+			streamable.ServeHTTP(recorder, r)
+			listed := strings.Replace(recorder.Body.String(), `"name":"weather"`, `"name":"weather","outputSchema":`+weatherSchema, 1)
+			for name, values := range recorder.Header() {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(recorder.Code)
+			io.WriteString(w, listed)
+		default:
+			streamable.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// TestOutputSchemaValidation verifies results violating the tool's output schema fail
+// in fail mode, pass through in log mode, and conforming results always pass
+func TestOutputSchemaValidation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		onViolation       string
+		structuredContent string
+		wantError         string
+	}{
+		"conforming result": {
+			onViolation:       outputSchemaFail,
+			structuredContent: `{"temperature":21.5,"conditions":"sunny"}`,
+		},
+		"wrong type fails": {
+			onViolation:       outputSchemaFail,
+			structuredContent: `{"temperature":"hot"}`,
+			wantError:         "structuredContent.temperature: expected number, got string",
+		},
+		"missing property fails": {
+			onViolation:       outputSchemaFail,
+			structuredContent: `{"conditions":"rainy"}`,
+			wantError:         `missing required property "temperature"`,
+		},
+		"violation is logged and passed through": {
+			onViolation:       outputSchemaLog,
+			structuredContent: `{"temperature":20,"conditions":"foggy"}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := newStructuredBackend(t, tc.structuredContent)
+
+			config := DefaultConfig()
+			config.OutputSchemas = OutputSchemasConfig{Enabled: true, OnViolation: tc.onViolation}
+			_, gatewayURL := startTestGateway(t, config, backend)
+			mcpClient := newTestClient(t, gatewayURL)
+
+			result := callTool(t, mcpClient, "server1-weather", nil)
+			text := extractTextFromResult(result)
+			if tc.wantError == "" {
+				if result.IsError || text != tc.structuredContent {
+					t.Errorf("Expected the result to pass through, got %+v", result)
+				}
+				return
+			}
+			if !result.IsError || !strings.Contains(text, tc.wantError) {
+				t.Errorf("Expected an error result containing %q, got %+v", tc.wantError, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Prefix of the progress tokens the gateway hands backends for relayed calls
+const partialTokenPrefix = "gateway-partial-"
+
+// How long a call's final result waits for its partial chunks to reach the client
+const partialFlushTimeout = time.Second
+
+// partialCall is a tool call whose partial chunks are relayed to the calling client
+type partialCall struct {
+	ctx         context.Context
+	clientToken mcp.ProgressToken
+	chunks      int
+	finished    bool
+	lock        sync.Mutex
+}
+
+// partialRelays tracks in-flight tool calls relaying partial results, keyed by the
+// progress token sent to the backend
+type partialRelays struct {
+	calls map[string]*partialCall
+	next  atomic.Int64
+	lock  sync.Mutex
+}
+
+// register starts relaying partial results of a call whose client asked for progress with
+// clientToken. It returns the token to send the backend and a finish func to call before
+// the final result is returned.
+func (r *partialRelays) register(ctx context.Context, clientToken mcp.ProgressToken) (string, func()) {
+	token := fmt.Sprintf("%s%d", partialTokenPrefix, r.next.Add(1))
+	call := &partialCall{ctx: ctx, clientToken: clientToken}
+
+	r.lock.Lock()
+	if r.calls == nil {
+		r.calls = make(map[string]*partialCall)
+	}
+	r.calls[token] = call
+	r.lock.Unlock()
+
+	finish := func() {
+		r.lock.Lock()
+		delete(r.calls, token)
+		r.lock.Unlock()
+
+		// Chunks arriving after the final result are dropped
+		call.lock.Lock()
+		call.finished = true
+		chunks := call.chunks
+		call.lock.Unlock()
+		if chunks > 0 {
+			flushNotifications(ctx)
+		}
+	}
+	return token, finish
+}
+
+// relay sends a backend progress notification for a registered call to the calling
+// client, on the call's own response stream. It reports whether the notification
+// belonged to a registered call.
+func (r *partialRelays) relay(notification mcp.JSONRPCNotification, send func(ctx context.Context, method string, params map[string]any) error) bool {
+	if notification.Method != "notifications/progress" {
+		return false
+	}
+	token, ok := notification.Params.AdditionalFields["progressToken"].(string)
+	if !ok {
+		return false
+	}
+	r.lock.Lock()
+	call, ok := r.calls[token]
+	r.lock.Unlock()
+	if !ok {
+		return false
+	}
+
+	call.lock.Lock()
+	defer call.lock.Unlock()
+	if call.finished {
+		return true
+	}
+	params := notificationParams(notification)
+	params["progressToken"] = call.clientToken
+	if err := send(call.ctx, notification.Method, params); err != nil {
+		log.Printf("❌ Failed to relay partial result: %v", err)
+		return true
+	}
+	call.chunks++
+	return true
+}
+
+// relayPartialResults asks the backend to stream a call's partial results when the client
+// sent a progress token, returning the finish func for the call
+func (g *Gateway) relayPartialResults(ctx context.Context, req mcp.CallToolRequest, backendReq *mcp.CallToolRequest) func() {
+	if !g.config.Notifications.PartialResults || req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return func() {}
+	}
+	token, finish := g.partials.register(ctx, req.Params.Meta.ProgressToken)
+	backendReq.Params.Meta = &mcp.Meta{ProgressToken: token}
+	return finish
+}
+
+// relayPartialResult is the backend notification handler for partial results
+func (g *Gateway) relayPartialResult(notification mcp.JSONRPCNotification) bool {
+	return g.partials.relay(notification, g.mcpServer.SendNotificationToClient)
+}
+
+// flushNotifications waits for the notifications queued on the context's client session
+// to be written, so they reach the client before the response that ends its stream
+func flushNotifications(ctx context.Context) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	deadline := time.Now().Add(partialFlushTimeout)
+	for len(session.NotificationChannel()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+}
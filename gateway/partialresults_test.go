@@ -0,0 +1,114 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newAnalyzingBackend serves an "analyze" tool that streams each of chunks as a progress
+// notification carrying partial content, waiting on next before each chunk after the
+// first, then returns the full text
+func newAnalyzingBackend(t *testing.T, chunks []string, next <-chan struct{}) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Analyzer", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("analyze"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Meta != nil && req.Params.Meta.ProgressToken != nil {
+			for i, chunk := range chunks {
+				if i > 0 {
+					<-next
+				}
+				server.ServerFromContext(ctx).SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+					"progressToken": req.Params.Meta.ProgressToken,
+					"progress":      i + 1,
+					"total":         len(chunks),
+					"content":       []mcp.Content{mcp.NewTextContent(chunk)},
+				})
+			}
+			<-next
+		}
+		return mcp.NewToolResultText(strings.Join(chunks, "")), nil
+	})
+	backend := httptest.NewServer(server.NewStreamableHTTPServer(mcpServer))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// partialChunk is the params of a relayed partial result notification
+type partialChunk struct {
+	ProgressToken string `json:"progressToken"`
+	Progress      int    `json:"progress"`
+	Content       []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// TestPartialResults verifies partial result chunks reach the client one at a time, on the
+// call's stream and with the client's progress token, ahead of the full final result
+func TestPartialResults(t *testing.T) {
+	chunks := []string{"scanning... ", "found 3 issues... ", "done"}
+	next := make(chan struct{})
+	backend := newAnalyzingBackend(t, chunks, next)
+
+	config := DefaultConfig()
+	config.Notifications.PartialResults = true
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+	resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-analyze","_meta":{"progressToken":"client-token"}}}`)
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+
+	// The backend holds each chunk until the previous one has reached the client
+	for i, want := range chunks {
+		event := readEvent(t, events)
+		var chunk partialChunk
+		json.Unmarshal(event.Params, &chunk)
+		if event.Method != "notifications/progress" || chunk.ProgressToken != "client-token" || chunk.Progress != i+1 ||
+			len(chunk.Content) != 1 || chunk.Content[0].Text != want {
+			t.Fatalf("Expected chunk %d %q, got %s %s", i+1, want, event.Method, event.Params)
+		}
+		next <- struct{}{}
+	}
+
+	final := readEvent(t, events)
+	result, err := mcp.ParseCallToolResult(&final.Result)
+	if err != nil || string(final.ID) != "2" || extractTextFromResult(result) != strings.Join(chunks, "") {
+		t.Errorf("Expected the full result after the chunks, got %+v", final)
+	}
+}
+
+// TestPartialResultsWithoutProgressToken verifies calls without a progress token get a
+// plain result
+func TestPartialResultsWithoutProgressToken(t *testing.T) {
+	backend := newAnalyzingBackend(t, []string{"a", "b"}, nil)
+
+	config := DefaultConfig()
+	config.Notifications.PartialResults = true
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+	resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-analyze"}}`)
+	defer resp.Body.Close()
+
+	var message jsonRPCMessage
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Fatalf("Expected a plain JSON response, got %s", contentType)
+	}
+	json.NewDecoder(resp.Body).Decode(&message)
+	result, err := mcp.ParseCallToolResult(&message.Result)
+	if err != nil || extractTextFromResult(result) != "ab" {
+		t.Errorf("Expected the full result, got %s", message.Result)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected HTTP 200, got %d", resp.StatusCode)
+	}
+}
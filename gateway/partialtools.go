@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"context"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// degradedBackend describes a backend whose tools may be missing from or failing in tools/list
+type degradedBackend struct {
+	Backend string `json:"backend"`
+	Reason  string `json:"reason"`
+}
+
+// degradedBackends lists backends that could not be queried for tools, or whose circuit
+// is open or that are rate limiting the gateway, ordered by name
+func (g *Gateway) degradedBackends() []degradedBackend {
+	g.toolsLock.RLock()
+	var degraded []degradedBackend
+	// Error details stay in the gateway log; they may name internal addresses
+	for name := range g.unavailableBackends {
+		degraded = append(degraded, degradedBackend{Backend: name, Reason: "unavailable"})
+	}
+	g.toolsLock.RUnlock()
+
+	for _, backend := range g.config.Backends {
+		switch {
+		case g.breakers[backend.Name].isOpen():
+			degraded = append(degraded, degradedBackend{Backend: backend.Name, Reason: "circuit open"})
+		case g.throttles[backend.Name].remaining() > 0:
+			degraded = append(degraded, degradedBackend{Backend: backend.Name, Reason: "rate limited"})
+		}
+	}
+
+	sort.Slice(degraded, func(i, j int) bool { return degraded[i].Backend < degraded[j].Backend })
+	return degraded
+}
+
+// annotateToolList adds degraded backends to the tools/list result _meta so clients know
+// the list may be incomplete
+func (g *Gateway) annotateToolList(ctx context.Context, id any, request *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+	degraded := g.degradedBackends()
+	if len(degraded) == 0 {
+		return
+	}
+	if result.Meta == nil {
+		result.Meta = make(map[string]any)
+	}
+	result.Meta["degradedBackends"] = degraded
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestPartialToolLists verifies a down backend doesn't prevent startup or hide healthy
+// tools, and is named in the tools/list _meta
+func TestPartialToolLists(t *testing.T) {
+	healthy := newStubBackend(t, "Healthy", textTool(mcp.NewTool("echo"), "ok"))
+	down := httptest.NewServer(nil)
+	down.Close()
+
+	config := DefaultConfig()
+	config.PartialToolLists = true
+	config.Backends = []BackendConfig{
+		{Name: "healthy", URL: healthy.URL},
+		{Name: "down", URL: down.URL},
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	if !containsString(names, "healthy-echo") {
+		t.Errorf("Expected the healthy backend's tools, got %v", names)
+	}
+
+	degraded, _ := json.Marshal(result.Meta["degradedBackends"])
+	if string(degraded) != `[{"backend":"down","reason":"unavailable"}]` {
+		t.Errorf("Expected _meta to list the down backend, got %s", degraded)
+	}
+
+	// Healthy tools remain callable in the same session
+	if text := extractTextFromResult(callTool(t, mcpClient, "healthy-echo", nil)); text != "ok" {
+		t.Errorf("Expected the healthy tool to be callable, got %q", text)
+	}
+}
+
+// TestPartialToolListsDisabled verifies a down backend still fails startup by default
+func TestPartialToolListsDisabled(t *testing.T) {
+	healthy := newStubBackend(t, "Healthy", textTool(mcp.NewTool("echo"), "ok"))
+	down := httptest.NewServer(nil)
+	down.Close()
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "healthy", URL: healthy.URL},
+		{Name: "down", URL: down.URL},
+	}
+	if _, err := New(config); err == nil {
+		t.Fatal("Expected startup to fail with a down backend")
+	}
+}
@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Stages of a backend's outbound request pipeline. Requests pass through the stages
+// in this order and responses pass back in reverse, so e.g. the signature covers the
+// rewritten body and the final Content-Type, and response checks run before headers
+// are propagated to the client.
+const (
+	// stageRewrite applies request rewrite rules to the call's arguments, before the
+	// request is encoded
+	stageRewrite = "rewrite"
+	// stageElicitation relays the backend's elicitation requests to the calling client
+	stageElicitation = "elicitation"
+	// stageResponseHeaders copies allowed response headers to the client response
+	stageResponseHeaders = "responseHeaders"
+	// stageProtocolCheck fails calls on malformed JSON-RPC messages
+	stageProtocolCheck = "protocolCheck"
+	// stageResponseCheck fails calls on non-MCP responses and pauses the throttle on 429
+	stageResponseCheck = "responseCheck"
+	// stageIdentity sets User-Agent and the gateway's identifying headers
+	stageIdentity = "identity"
+	// stageContentType replaces the request Content-Type
+	stageContentType = "contentType"
+	// stageSigning adds the HMAC signature of the body as sent
+	stageSigning = "signing"
+	// stageMessageSize discards response messages over the backend's maxMessageBytes as
+	// they are read, before any other stage buffers them
+	stageMessageSize = "messageSize"
+	// stageTransport sends the request, within the backend's timeouts and connection limit
+	stageTransport = "transport"
+)
+
+// pipelineStage is one active stage of a backend's outbound request pipeline
+type pipelineStage struct {
+	Name string
+	// wrap adds the stage in front of next; stages applied before encoding have none
+	wrap func(next http.RoundTripper) (http.RoundTripper, error)
+}
+
+// backendPipeline returns the active stages of a backend's outbound requests in the
+// order requests pass through them. sessionID is the client session the connection
+// serves, empty for the gateway's own.
+func (g *Gateway) backendPipeline(backend BackendConfig, sessionID string) []pipelineStage {
+	var stages []pipelineStage
+	for _, rule := range g.rewrites {
+		if rule.Phase == RewritePhaseRequest {
+			stages = append(stages, pipelineStage{Name: stageRewrite})
+			break
+		}
+	}
+
+	stages = append(stages, pipelineStage{Name: stageElicitation, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+		return &elicitationRoundTripper{next: next, gateway: g, sessionID: sessionID}, nil
+	}})
+	if len(backend.ResponseHeaders) > 0 {
+		stages = append(stages, pipelineStage{Name: stageResponseHeaders, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return &propagatingRoundTripper{next: next, allow: backend.ResponseHeaders}, nil
+		}})
+	}
+	stages = append(stages,
+		pipelineStage{Name: stageProtocolCheck, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return &protocolCheckRoundTripper{next: next, gateway: g, backend: backend.Name}, nil
+		}},
+		pipelineStage{Name: stageResponseCheck, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return &responseCheckRoundTripper{next: next, backend: backend.Name, throttle: g.throttles[backend.Name]}, nil
+		}},
+		pipelineStage{Name: stageIdentity, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return &identifyingRoundTripper{next: next, identity: g.identity(backend), sessionID: sessionID}, nil
+		}},
+	)
+	if backend.ContentType != "" {
+		stages = append(stages, pipelineStage{Name: stageContentType, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return &contentTypeRoundTripper{next: next, contentType: backend.ContentType}, nil
+		}})
+	}
+	if backend.Signing != nil {
+		stages = append(stages, pipelineStage{Name: stageSigning, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return newSigningRoundTripper(next, *backend.Signing)
+		}})
+	}
+	if backend.MaxMessageBytes > 0 {
+		stages = append(stages, pipelineStage{Name: stageMessageSize, wrap: func(next http.RoundTripper) (http.RoundTripper, error) {
+			return &messageSizeRoundTripper{next: next, backend: backend.Name, limit: backend.MaxMessageBytes}, nil
+		}})
+	}
+	return append(stages, pipelineStage{Name: stageTransport, wrap: func(http.RoundTripper) (http.RoundTripper, error) {
+		baseTransport := newBackendTransport(backend.Timeouts, g.config.dialControl)
+		g.connectionLimiter.limit(baseTransport, backend)
+		return baseTransport, nil
+	}})
+}
+
+// composePipeline chains the stages into one round tripper, the first stage outermost
+func composePipeline(stages []pipelineStage) (http.RoundTripper, error) {
+	var roundTripper http.RoundTripper
+	for i := len(stages) - 1; i >= 0; i-- {
+		if stages[i].wrap == nil {
+			continue
+		}
+		wrapped, err := stages[i].wrap(roundTripper)
+		if err != nil {
+			return nil, fmt.Errorf("%s stage: %w", stages[i].Name, err)
+		}
+		roundTripper = wrapped
+	}
+	return roundTripper, nil
+}
+
+// pipelineNames returns the names of the stages in order
+func pipelineNames(stages []pipelineStage) []string {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+// logBackendPipelines logs the effective request pipeline of every backend
+func (g *Gateway) logBackendPipelines() {
+	for _, backend := range g.config.Backends {
+		log.Printf("🔧 Backend %s request pipeline: %s", backend.Name, strings.Join(pipelineNames(g.backendPipeline(backend, "")), " → "))
+	}
+}
+
+// handleBackendPipeline serves GET /admin/backends/{name}/pipeline: the stages the
+// backend's requests pass through, in order
+func (g *Gateway) handleBackendPipeline(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	backend, ok := g.findBackend(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown backend %s", name), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"backend": name,
+		"stages":  pipelineNames(g.backendPipeline(backend, "")),
+	})
+}
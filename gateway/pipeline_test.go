@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// recordingRoundTripper notes when a request enters and its response leaves a stage
+type recordingRoundTripper struct {
+	next  http.RoundTripper
+	stage string
+	trace *[]string
+	lock  *sync.Mutex
+}
+
+func (r *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.lock.Lock()
+	*r.trace = append(*r.trace, "→"+r.stage)
+	r.lock.Unlock()
+	resp, err := r.next.RoundTrip(req)
+	r.lock.Lock()
+	*r.trace = append(*r.trace, "←"+r.stage)
+	r.lock.Unlock()
+	return resp, err
+}
+
+// TestBackendPipelineOrder verifies a backend with every mutation feature active runs
+// them in the documented order, and the admin endpoint reports that order
+func TestBackendPipelineOrder(t *testing.T) {
+	t.Setenv("TEST_PIPELINE_SECRET", "secret")
+	stub := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	config.Rewrites = []RewriteRule{{Tool: "server1-*", Phase: RewritePhaseRequest, Path: "$.user", Op: RewriteOpDelete}}
+	config.Backends = []BackendConfig{{
+		Name:            "server1",
+		URL:             stub.URL,
+		ResponseHeaders: []string{"Cache-Control"},
+		ContentType:     "application/json",
+		Signing:         &SigningConfig{SecretEnv: "TEST_PIPELINE_SECRET"},
+		MaxMessageBytes: 1 << 20,
+	}}
+	gateway, gatewayURL := startTestGateway(t, config)
+
+	documented := []string{
+		stageRewrite, stageElicitation, stageResponseHeaders, stageProtocolCheck, stageResponseCheck,
+		stageIdentity, stageContentType, stageSigning, stageMessageSize, stageTransport,
+	}
+	var reported struct {
+		Stages []string `json:"stages"`
+	}
+	fetchJSON(t, gatewayURL+"/admin/backends/server1/pipeline", &reported)
+	if !reflect.DeepEqual(reported.Stages, documented) {
+		t.Fatalf("Expected the documented pipeline %v, got %v", documented, reported.Stages)
+	}
+
+	// Record each stage's request and response as they pass through the composed pipeline
+	var trace []string
+	var lock sync.Mutex
+	stages := gateway.backendPipeline(config.Backends[0], "")
+	for i, stage := range stages {
+		if stage.wrap == nil {
+			continue
+		}
+		wrap, name := stage.wrap, stage.Name
+		stages[i].wrap = func(next http.RoundTripper) (http.RoundTripper, error) {
+			roundTripper, err := wrap(next)
+			return &recordingRoundTripper{next: roundTripper, stage: name, trace: &trace, lock: &lock}, err
+		}
+	}
+	roundTripper, err := composePipeline(stages)
+	if err != nil {
+		t.Fatalf("Failed to compose the pipeline: %v", err)
+	}
+
+	var received http.Header
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}))
+	t.Cleanup(backend.Close)
+	req, _ := http.NewRequest(http.MethodPost, backend.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := roundTripper.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	var expected []string
+	for _, name := range documented[1:] {
+		expected = append(expected, "→"+name)
+	}
+	for i := len(documented) - 1; i >= 1; i-- {
+		expected = append(expected, "←"+documented[i])
+	}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Errorf("Expected the stages to run in order\n%v\ngot\n%v", expected, trace)
+	}
+	if received.Get(defaultSignatureHeader) == "" || received.Get(headerForwardedBy) == "" {
+		t.Errorf("Expected the backend to receive identified, signed requests, got %v", received)
+	}
+}
+
+// TestBackendPipelineOptionalStages verifies stages of features a backend doesn't use are left out
+func TestBackendPipelineOptionalStages(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	var reported struct {
+		Stages []string `json:"stages"`
+	}
+	fetchJSON(t, gatewayURL+"/admin/backends/server1/pipeline", &reported)
+	expected := []string{stageElicitation, stageProtocolCheck, stageResponseCheck, stageIdentity, stageTransport}
+	if !reflect.DeepEqual(reported.Stages, expected) {
+		t.Errorf("Expected pipeline %v, got %v", expected, reported.Stages)
+	}
+
+	resp, err := http.Get(gatewayURL + "/admin/backends/missing/pipeline")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown backend, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// PolicyGroup holds backend settings shared by every backend that references it.
+// Settings on a backend override its group's, which override the parent group's.
+type PolicyGroup struct {
+	// Extends names a parent group whose settings this group inherits
+	Extends string `yaml:"extends"`
+	// Tools is the default tool policy
+	Tools *ToolPolicy `yaml:"tools"`
+	// Signing is the default request signing
+	Signing *SigningConfig `yaml:"signing"`
+	// Timeouts are default timeouts; each unset field is inherited
+	Timeouts BackendTimeouts `yaml:"timeouts"`
+	// Retries are the default retries, replacing the gateway-wide ones
+	Retries *RetriesConfig `yaml:"retries"`
+	// ResponseHeaders is the default response header allowlist
+	ResponseHeaders []string `yaml:"responseHeaders"`
+	// Identity sets default identifying headers; each unset field is inherited
+	Identity IdentityConfig `yaml:"identity"`
+}
+
+// mergeTimeouts returns base with every field set in override replaced
+func mergeTimeouts(base, override BackendTimeouts) BackendTimeouts {
+	if override.Dial != 0 {
+		base.Dial = override.Dial
+	}
+	if override.Request != 0 {
+		base.Request = override.Request
+	}
+	if override.Idle != 0 {
+		base.Idle = override.Idle
+	}
+	return base
+}
+
+// merge returns the group with every setting present in override applied on top
+func (p PolicyGroup) merge(override PolicyGroup) PolicyGroup {
+	if override.Tools != nil {
+		p.Tools = override.Tools
+	}
+	if override.Signing != nil {
+		p.Signing = override.Signing
+	}
+	p.Timeouts = mergeTimeouts(p.Timeouts, override.Timeouts)
+	if override.Retries != nil {
+		p.Retries = override.Retries
+	}
+	if override.ResponseHeaders != nil {
+		p.ResponseHeaders = override.ResponseHeaders
+	}
+	p.Identity = p.Identity.merge(override.Identity)
+	return p
+}
+
+// resolveGroup flattens a group and its ancestors, detecting missing and cyclic references
+func (c *Config) resolveGroup(name string) (PolicyGroup, error) {
+	var chain []string
+	visited := make(map[string]bool)
+	for current := name; current != ""; current = c.PolicyGroups[current].Extends {
+		if visited[current] {
+			return PolicyGroup{}, fmt.Errorf("policy group cycle: %s -> %s", strings.Join(chain, " -> "), current)
+		}
+		if _, ok := c.PolicyGroups[current]; !ok {
+			if len(chain) == 0 {
+				return PolicyGroup{}, fmt.Errorf("policy group %q not found", current)
+			}
+			return PolicyGroup{}, fmt.Errorf("policy group %q extends unknown group %q", chain[len(chain)-1], current)
+		}
+		visited[current] = true
+		chain = append(chain, current)
+	}
+
+	// Apply from the root ancestor down so closer groups win
+	var resolved PolicyGroup
+	for i := len(chain) - 1; i >= 0; i-- {
+		resolved = resolved.merge(c.PolicyGroups[chain[i]])
+	}
+	return resolved, nil
+}
+
+// ResolveGroups applies each backend's policy group, keeping settings made on the backend itself.
+// Every group is checked, including ones no backend references.
+func (c *Config) ResolveGroups() error {
+	for name, group := range c.PolicyGroups {
+		if _, err := c.resolveGroup(name); err != nil {
+			return err
+		}
+		if group.Tools != nil {
+			if err := group.Tools.Validate(); err != nil {
+				return fmt.Errorf("policy group %s: %w", name, err)
+			}
+		}
+		if group.Signing != nil {
+			if err := group.Signing.Validate(); err != nil {
+				return fmt.Errorf("policy group %s: %w", name, err)
+			}
+		}
+		if group.Retries != nil {
+			if err := group.Retries.Validate(); err != nil {
+				return fmt.Errorf("policy group %s: %w", name, err)
+			}
+		}
+		if err := validateResponseHeaders(group.ResponseHeaders); err != nil {
+			return fmt.Errorf("policy group %s: %w", name, err)
+		}
+	}
+
+	for i, backend := range c.Backends {
+		if backend.Group == "" {
+			continue
+		}
+		group, err := c.resolveGroup(backend.Group)
+		if err != nil {
+			return fmt.Errorf("backend %s: %w", backend.Name, err)
+		}
+
+		own := PolicyGroup{
+			Signing:         backend.Signing,
+			Timeouts:        backend.Timeouts,
+			Retries:         backend.Retries,
+			ResponseHeaders: backend.ResponseHeaders,
+			Identity:        backend.Identity,
+		}
+		if backend.Tools.Default != "" || len(backend.Tools.Except) > 0 {
+			tools := backend.Tools
+			own.Tools = &tools
+		}
+		effective := group.merge(own)
+
+		if effective.Tools != nil {
+			c.Backends[i].Tools = *effective.Tools
+		}
+		c.Backends[i].Signing = effective.Signing
+		c.Backends[i].Timeouts = effective.Timeouts
+		c.Backends[i].Retries = effective.Retries
+		c.Backends[i].ResponseHeaders = effective.ResponseHeaders
+		c.Backends[i].Identity = effective.Identity
+	}
+	return nil
+}
+
+// logBackendPolicies logs the effective policy of each backend
+func (g *Gateway) logBackendPolicies() {
+	for _, backend := range g.config.Backends {
+		signing := "off"
+		if backend.Signing != nil {
+			signing = backend.Signing.SecretEnv
+		}
+		toolsDefault := backend.Tools.Default
+		if toolsDefault == "" {
+			toolsDefault = ToolPolicyAllow
+		}
+		retries := g.retries(backend)
+		identity := g.identity(backend)
+		log.Printf("🔧 Backend %s policy: group=%q tools=%s except=%v timeouts=dial %s/request %s/idle %s signing=%s retries=%d/%s responseHeaders=%v userAgent=%q",
+			backend.Name, backend.Group, toolsDefault, backend.Tools.Except,
+			backend.Timeouts.dial(), backend.Timeouts.request(), backend.Timeouts.idle(), signing,
+			max(retries.MaxAttempts, 1), retries.backoff(), backend.ResponseHeaders, identity.userAgent())
+	}
+}
@@ -0,0 +1,189 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadTestConfig writes YAML to a temporary file and loads it
+func loadTestConfig(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return LoadConfig(path)
+}
+
+// TestPolicyGroupInheritance verifies groups inherit from parents and backends override groups
+func TestPolicyGroupInheritance(t *testing.T) {
+	config, err := loadTestConfig(t, `
+policyGroups:
+  base:
+    timeouts:
+      dial: 2s
+      request: 20s
+    tools:
+      default: deny
+      except: ["read_*"]
+  internal:
+    extends: base
+    timeouts:
+      request: 60s
+backends:
+  - name: plain
+    url: http://localhost:9001
+    group: internal
+  - name: custom
+    url: http://localhost:9002
+    group: internal
+    timeouts:
+      dial: 5s
+    tools:
+      default: allow
+  - name: ungrouped
+    url: http://localhost:9003
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	plain, custom, ungrouped := config.Backends[0], config.Backends[1], config.Backends[2]
+
+	// The child group overrides the request timeout and inherits the rest
+	if want := (BackendTimeouts{Dial: 2 * time.Second, Request: 60 * time.Second}); plain.Timeouts != want {
+		t.Errorf("plain timeouts = %+v, want %+v", plain.Timeouts, want)
+	}
+	if want := (ToolPolicy{Default: ToolPolicyDeny, Except: []string{"read_*"}}); !reflect.DeepEqual(plain.Tools, want) {
+		t.Errorf("plain tools = %+v, want %+v", plain.Tools, want)
+	}
+
+	// Settings on the backend win over the group
+	if want := (BackendTimeouts{Dial: 5 * time.Second, Request: 60 * time.Second}); custom.Timeouts != want {
+		t.Errorf("custom timeouts = %+v, want %+v", custom.Timeouts, want)
+	}
+	if want := (ToolPolicy{Default: ToolPolicyAllow}); !reflect.DeepEqual(custom.Tools, want) {
+		t.Errorf("custom tools = %+v, want %+v", custom.Tools, want)
+	}
+
+	if !reflect.DeepEqual(ungrouped.Timeouts, BackendTimeouts{}) || ungrouped.Tools.Default != "" {
+		t.Errorf("ungrouped backend should be unchanged, got %+v", ungrouped)
+	}
+}
+
+// TestPolicyGroupReferences verifies missing and cyclic group references are rejected
+func TestPolicyGroupReferences(t *testing.T) {
+	cases := map[string]string{
+		"policy group \"nope\" not found": `
+backends:
+  - name: a
+    url: http://localhost:9001
+    group: nope
+`,
+		"extends unknown group": `
+policyGroups:
+  child:
+    extends: missing
+backends:
+  - name: a
+    url: http://localhost:9001
+`,
+		"policy group cycle": `
+policyGroups:
+  one:
+    extends: two
+  two:
+    extends: three
+  three:
+    extends: one
+backends:
+  - name: a
+    url: http://localhost:9001
+    group: one
+`,
+	}
+
+	for want, content := range cases {
+		if _, err := loadTestConfig(t, content); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+// TestPolicyGroupRetriesAndHeaders verifies retries, response headers and identity are
+// inherited from groups and overridden by backends
+func TestPolicyGroupRetriesAndHeaders(t *testing.T) {
+	config, err := loadTestConfig(t, `
+policyGroups:
+  base:
+    retries:
+      maxAttempts: 3
+    responseHeaders: [X-Backend-Region]
+    identity:
+      userAgent: base-agent
+      forwardedBy: base
+  internal:
+    extends: base
+    identity:
+      forwardedBy: internal
+backends:
+  - name: plain
+    url: http://localhost:9001
+    group: internal
+  - name: custom
+    url: http://localhost:9002
+    group: internal
+    retries:
+      maxAttempts: 5
+    responseHeaders: [Cache-Control]
+    identity:
+      userAgent: custom-agent
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	plain, custom := config.Backends[0], config.Backends[1]
+
+	if plain.Retries == nil || plain.Retries.MaxAttempts != 3 {
+		t.Errorf("plain retries = %+v, want the group's 3 attempts", plain.Retries)
+	}
+	if want := []string{"X-Backend-Region"}; !reflect.DeepEqual(plain.ResponseHeaders, want) {
+		t.Errorf("plain responseHeaders = %v, want %v", plain.ResponseHeaders, want)
+	}
+	if want := (IdentityConfig{UserAgent: "base-agent", ForwardedBy: "internal"}); !reflect.DeepEqual(plain.Identity, want) {
+		t.Errorf("plain identity = %+v, want %+v", plain.Identity, want)
+	}
+
+	if custom.Retries == nil || custom.Retries.MaxAttempts != 5 {
+		t.Errorf("custom retries = %+v, want the backend's 5 attempts", custom.Retries)
+	}
+	if want := []string{"Cache-Control"}; !reflect.DeepEqual(custom.ResponseHeaders, want) {
+		t.Errorf("custom responseHeaders = %v, want %v", custom.ResponseHeaders, want)
+	}
+	if want := (IdentityConfig{UserAgent: "custom-agent", ForwardedBy: "internal"}); !reflect.DeepEqual(custom.Identity, want) {
+		t.Errorf("custom identity = %+v, want %+v", custom.Identity, want)
+	}
+
+	for want, content := range map[string]string{
+		"retries settings must not be negative": `
+policyGroups:
+  bad:
+    retries:
+      maxAttempts: -1
+`,
+		"policy group bad": `
+policyGroups:
+  bad:
+    responseHeaders: [Connection]
+`,
+	} {
+		if _, err := loadTestConfig(t, content); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+}
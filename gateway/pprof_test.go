@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestPprofGated verifies the profiling endpoints only exist when enabled
+func TestPprofGated(t *testing.T) {
+	backend := newStubBackend(t, "Stub", textTool(mcp.NewTool("echo"), "ok"))
+
+	for _, enabled := range []bool{false, true} {
+		config := DefaultConfig()
+		config.Admin = AdminConfig{Listen: "127.0.0.1:0", Pprof: enabled}
+		if err := config.Validate(); err != nil {
+			t.Fatalf("Expected valid config: %v", err)
+		}
+		mcpGateway, _ := startTestGateway(t, config, backend)
+
+		adminServer := httptest.NewServer(mcpGateway.AdminHandler())
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/symbol"} {
+			resp, err := http.Get(adminServer.URL + path)
+			if err != nil {
+				t.Fatalf("GET %s failed: %v", path, err)
+			}
+			resp.Body.Close()
+
+			want := http.StatusNotFound
+			if enabled {
+				want = http.StatusOK
+			}
+			if resp.StatusCode != want {
+				t.Errorf("pprof enabled=%v: GET %s returned %d, want %d", enabled, path, resp.StatusCode, want)
+			}
+		}
+		adminServer.Close()
+	}
+}
+
+// TestPprofRequiresAdminListener verifies pprof can't be enabled on the main port
+func TestPprofRequiresAdminListener(t *testing.T) {
+	config := DefaultConfig()
+	config.Admin.Pprof = true
+	if err := config.Validate(); err == nil {
+		t.Error("Expected pprof without admin.listen to be rejected")
+	}
+}
@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Policies for clients whose protocol version a backend's can't be translated to
+const (
+	protocolIncompatibleError = "error"
+	protocolIncompatibleHide  = "hide"
+)
+
+// ProtocolCompatibilityConfig declares which client protocol versions the gateway can
+// translate each backend protocol version to, and how clients it can't are handled
+type ProtocolCompatibilityConfig struct {
+	// MinClientVersions maps a backend protocol version to the oldest client protocol
+	// version the gateway can translate it to. Backend versions not listed are
+	// translated to any client version.
+	MinClientVersions map[string]string `yaml:"minClientVersions"`
+	// Incompatible is "error" (default) to fail calls of an incompatible backend's
+	// tools with an error naming both versions, or "hide" to also leave them out of
+	// the client's tools/list
+	Incompatible string `yaml:"incompatible"`
+}
+
+// Validate checks the protocol versions and the policy
+func (c ProtocolCompatibilityConfig) Validate() error {
+	for backendVersion, clientVersion := range c.MinClientVersions {
+		for _, version := range []string{backendVersion, clientVersion} {
+			if _, err := time.Parse(time.DateOnly, version); err != nil {
+				return fmt.Errorf("protocolCompatibility.minClientVersions: %q is not a protocol version (YYYY-MM-DD)", version)
+			}
+		}
+	}
+	switch c.Incompatible {
+	case "", protocolIncompatibleError, protocolIncompatibleHide:
+		return nil
+	}
+	return fmt.Errorf("protocolCompatibility.incompatible must be %s or %s", protocolIncompatibleError, protocolIncompatibleHide)
+}
+
+// ProtocolIncompatibleError reports a call to a backend whose protocol version the
+// gateway can't translate to the client's
+type ProtocolIncompatibleError struct {
+	Backend          string
+	BackendVersion   string
+	ClientVersion    string
+	MinClientVersion string
+}
+
+func (e *ProtocolIncompatibleError) Error() string {
+	return fmt.Sprintf("Backend %s speaks MCP protocol %s, which the gateway can't translate to this session's protocol %s: initialize with protocol %s or newer to use its tools",
+		e.Backend, e.BackendVersion, e.ClientVersion, e.MinClientVersion)
+}
+
+// checkProtocolCompatibility returns a ProtocolIncompatibleError when the gateway can't
+// translate a backend's protocol version to a client's. Unknown versions are assumed
+// compatible. Protocol versions are dates (YYYY-MM-DD), so they order lexically.
+func (g *Gateway) checkProtocolCompatibility(backend, backendVersion, clientVersion string) error {
+	minClientVersion := g.config.ProtocolCompatibility.MinClientVersions[backendVersion]
+	if minClientVersion == "" || clientVersion == "" || clientVersion >= minClientVersion {
+		return nil
+	}
+	return &ProtocolIncompatibleError{
+		Backend:          backend,
+		BackendVersion:   backendVersion,
+		ClientVersion:    clientVersion,
+		MinClientVersion: minClientVersion,
+	}
+}
+
+// recordBackendProtocolVersion records the protocol version a backend negotiated at startup
+func (g *Gateway) recordBackendProtocolVersion(backend, version string) {
+	g.protocolVersionsLock.Lock()
+	defer g.protocolVersionsLock.Unlock()
+	if g.backendProtocolVersions == nil {
+		g.backendProtocolVersions = make(map[string]string)
+	}
+	g.backendProtocolVersions[backend] = version
+}
+
+// backendProtocolVersion returns the protocol version a backend negotiated at startup
+func (g *Gateway) backendProtocolVersion(backend string) string {
+	g.protocolVersionsLock.RLock()
+	defer g.protocolVersionsLock.RUnlock()
+	return g.backendProtocolVersions[backend]
+}
+
+// toolBackends returns the backends that can serve a listed tool: its route's, every
+// backend an argument-routed tool can be routed to, or the backend a discovery tool
+// stands in for. Built-in tools have none.
+func (g *Gateway) toolBackends(toolName string) []string {
+	for _, argumentRoute := range g.config.ArgumentRoutes {
+		if argumentRoute.Tool == toolName {
+			backends := []string{argumentRoute.Default}
+			for _, rule := range argumentRoute.Rules {
+				backends = append(backends, rule.Backend)
+			}
+			return backends
+		}
+	}
+
+	g.toolsLock.RLock()
+	defer g.toolsLock.RUnlock()
+	if route, ok := g.toolRoutes[toolName]; ok {
+		return []string{route.Backend}
+	}
+	if discoverable := g.discoverableTools[toolName]; len(discoverable) > 0 {
+		if route, ok := g.toolRoutes[discoverable[0].Name]; ok {
+			return []string{route.Backend}
+		}
+	}
+	return nil
+}
+
+// protocolCompatibilityFilter hides tools none of whose backends' protocol versions can be
+// translated to the session's, with incompatible set to hide. It checks the same startup
+// versions calls are checked against.
+func (g *Gateway) protocolCompatibilityFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	if g.config.ProtocolCompatibility.Incompatible != protocolIncompatibleHide {
+		return tools
+	}
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return tools
+	}
+	clientVersion := g.sessions.protocolVersion(session.SessionID())
+
+	filtered := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if g.toolIncompatible(tool.Name, clientVersion) {
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+	if hidden := len(tools) - len(filtered); hidden > 0 {
+		log.Printf("⚠️ Hiding %d tools from client %s: their backends' protocol versions can't be translated to %s",
+			hidden, session.SessionID(), clientVersion)
+	}
+	return filtered
+}
+
+// toolIncompatible reports whether none of a tool's backends can be translated to a
+// client protocol version
+func (g *Gateway) toolIncompatible(toolName, clientVersion string) bool {
+	backends := g.toolBackends(toolName)
+	for _, backend := range backends {
+		if g.checkProtocolCompatibility(backend, g.backendProtocolVersion(backend), clientVersion) == nil {
+			return false
+		}
+	}
+	return len(backends) > 0
+}
@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Protocol version older than the stub backends', which negotiate the latest
+const oldProtocolVersion = "2024-11-05"
+
+// initializeWithProtocol initializes a raw session on a protocol version and returns its ID
+func initializeWithProtocol(t *testing.T, url, protocolVersion string) string {
+	t.Helper()
+
+	resp := postMessage(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+
+		protocolVersion+`","capabilities":{},"clientInfo":{"name":"raw","version":"1.0.0"}}}`)
+	resp.Body.Close()
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		t.Fatalf("Initialize returned no session (HTTP %d)", resp.StatusCode)
+	}
+	return sessionID
+}
+
+// callToolRaw calls a tool over a raw session and returns the response body
+func callToolRaw(t *testing.T, url, sessionID, name string) string {
+	t.Helper()
+
+	resp := postMessage(t, url, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"`+name+`"}}`)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+// TestProtocolIncompatibleError verifies calls from a client on a protocol version the
+// backend's can't be translated to fail with an error naming both versions, without
+// opening a backend session for them
+func TestProtocolIncompatibleError(t *testing.T) {
+	backend, initializes := newInitializeCountingBackend(t, newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello")))
+	config := DefaultConfig()
+	config.ProtocolCompatibility.MinClientVersions = map[string]string{mcp.LATEST_PROTOCOL_VERSION: mcp.LATEST_PROTOCOL_VERSION}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	startup := initializes.Load()
+
+	old := initializeWithProtocol(t, gatewayURL, oldProtocolVersion)
+	if names := listToolNamesRaw(t, gatewayURL, old); !containsString(names, "server1-echo") {
+		t.Errorf("Expected the backend's tools to stay listed, got %v", names)
+	}
+	want := "Backend server1 speaks MCP protocol " + mcp.LATEST_PROTOCOL_VERSION +
+		", which the gateway can't translate to this session's protocol " + oldProtocolVersion
+	if body := callToolRaw(t, gatewayURL, old, "server1-echo"); !strings.Contains(body, want) || !strings.Contains(body, `"isError":true`) {
+		t.Errorf("Expected an error explaining the version mismatch, got %s", body)
+	}
+	if opened := initializes.Load() - startup; opened != 0 {
+		t.Errorf("Expected no backend session for a refused call, got %d", opened)
+	}
+
+	current := initializeWithProtocol(t, gatewayURL, mcp.LATEST_PROTOCOL_VERSION)
+	if body := callToolRaw(t, gatewayURL, current, "server1-echo"); !strings.Contains(body, "hello") {
+		t.Errorf("Expected a client on the backend's protocol version to call it, got %s", body)
+	}
+}
+
+// TestProtocolIncompatibleHide verifies an incompatible backend's tools are hidden from
+// clients it can't be translated to, and only from them
+func TestProtocolIncompatibleHide(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	config := DefaultConfig()
+	config.ProtocolCompatibility.MinClientVersions = map[string]string{mcp.LATEST_PROTOCOL_VERSION: mcp.LATEST_PROTOCOL_VERSION}
+	config.ProtocolCompatibility.Incompatible = protocolIncompatibleHide
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	old := initializeWithProtocol(t, gatewayURL, oldProtocolVersion)
+	names := listToolNamesRaw(t, gatewayURL, old)
+	if containsString(names, "server1-echo") || !containsString(names, "gateway_info") {
+		t.Errorf("Expected only the incompatible backend's tools to be hidden, got %v", names)
+	}
+
+	current := initializeWithProtocol(t, gatewayURL, mcp.LATEST_PROTOCOL_VERSION)
+	if names := listToolNamesRaw(t, gatewayURL, current); !containsString(names, "server1-echo") {
+		t.Errorf("Expected the backend's tools to be listed for a compatible client, got %v", names)
+	}
+}
+
+// TestProtocolIncompatibleHideRoutedTools verifies discovery tools are hidden with their
+// backend, and argument-routed tools only when none of their backends is compatible
+func TestProtocolIncompatibleHideRoutedTools(t *testing.T) {
+	tracker := newStubBackend(t, "Tracker",
+		textTool(mcp.NewTool("open_issue"), "opened"),
+		textTool(mcp.NewTool("close_issue"), "closed"),
+	)
+	us := newStubBackend(t, "US", textTool(mcp.NewTool("query", mcp.WithString("region")), "us data"))
+	eu := newStubBackend(t, "EU", textTool(mcp.NewTool("query", mcp.WithString("region")), "eu data"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "tracker", URL: tracker.URL, Discovery: DiscoveryConfig{ToolThreshold: 1}},
+		{Name: "us", URL: us.URL},
+		{Name: "eu", URL: eu.URL},
+	}
+	config.ArgumentRoutes = []ArgumentRoute{{
+		Tool:     "query",
+		Argument: "region",
+		Rules:    []ArgumentRule{{Values: []string{"eu"}, Backend: "eu"}},
+		Default:  "us",
+	}}
+	config.ProtocolCompatibility.MinClientVersions = map[string]string{mcp.LATEST_PROTOCOL_VERSION: mcp.LATEST_PROTOCOL_VERSION}
+	config.ProtocolCompatibility.Incompatible = protocolIncompatibleHide
+	gateway, gatewayURL := startTestGateway(t, config)
+
+	old := initializeWithProtocol(t, gatewayURL, oldProtocolVersion)
+	names := listToolNamesRaw(t, gatewayURL, old)
+	if containsString(names, "tracker-discover_tools") || containsString(names, "query") {
+		t.Errorf("Expected the discovery and routed tools of incompatible backends to be hidden, got %v", names)
+	}
+
+	// Once one of the routed tool's backends speaks the client's version, it is listed
+	gateway.recordBackendProtocolVersion("eu", oldProtocolVersion)
+	if names := listToolNamesRaw(t, gatewayURL, old); !containsString(names, "query") {
+		t.Errorf("Expected the routed tool to be listed with a compatible backend, got %v", names)
+	}
+}
+
+// TestProtocolCompatibilityValidation verifies malformed versions and unknown policies are rejected
+func TestProtocolCompatibilityValidation(t *testing.T) {
+	for _, compatibility := range []ProtocolCompatibilityConfig{
+		{MinClientVersions: map[string]string{"latest": oldProtocolVersion}},
+		{Incompatible: "ignore"},
+	} {
+		config := DefaultConfig()
+		config.ProtocolCompatibility = compatibility
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", compatibility)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ProtocolError reports a backend message that violates JSON-RPC 2.0, such as a missing
+// jsonrpc field or an id that is neither a string nor a number
+type ProtocolError struct {
+	Backend string
+	Reason  string
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("backend %s sent a malformed JSON-RPC message: %s", e.Backend, e.Reason)
+}
+
+// checkJSONRPC validates a JSON-RPC message or batch, returning the first violation
+func checkJSONRPC(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return fmt.Errorf("invalid JSON: %v", err)
+		}
+		for i, message := range batch {
+			if err := checkJSONRPCMessage(message); err != nil {
+				return fmt.Errorf("batch item %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+	return checkJSONRPCMessage(data)
+}
+
+// checkJSONRPCMessage validates a single JSON-RPC request, notification or response
+func checkJSONRPCMessage(data []byte) error {
+	var message map[string]json.RawMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return fmt.Errorf("not a JSON object: %v", err)
+	}
+	var version string
+	if json.Unmarshal(message["jsonrpc"], &version) != nil || version != mcp.JSONRPC_VERSION {
+		return fmt.Errorf(`jsonrpc must be "2.0"`)
+	}
+
+	id, hasID := message["id"]
+	if _, ok := message["method"]; ok {
+		if hasID && !isJSONRPCID(id, false) {
+			return fmt.Errorf("request id must be a string or number, got %s", id)
+		}
+		return nil
+	}
+
+	_, hasResult := message["result"]
+	_, hasError := message["error"]
+	switch {
+	case hasResult == hasError:
+		return fmt.Errorf("a response must have exactly one of result and error")
+	case !hasID || !isJSONRPCID(id, hasError):
+		return fmt.Errorf("response id must be a string or number, got %s", id)
+	}
+	return nil
+}
+
+// isJSONRPCID reports whether a raw id is a string or number, or null when allowed
+// (error responses to unparseable requests)
+func isJSONRPCID(id json.RawMessage, allowNull bool) bool {
+	if len(id) == 0 {
+		return false
+	}
+	switch c := id[0]; {
+	case c == '"', c == '-', c >= '0' && c <= '9':
+		return true
+	case string(id) == "null":
+		return allowNull
+	default:
+		return false
+	}
+}
+
+// protocolCheckRoundTripper rejects backend JSON responses that violate JSON-RPC, and
+// replaces malformed messages in SSE streams with an error response to the request
+type protocolCheckRoundTripper struct {
+	next    http.RoundTripper
+	gateway *Gateway
+	backend string
+}
+
+func (c *protocolCheckRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodPost || resp.StatusCode == http.StatusAccepted {
+		return resp, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if violation := checkJSONRPC(body); violation != nil {
+			return nil, c.violation(violation, body)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	case "text/event-stream":
+		resp.Body = &sseProtocolReader{ReadCloser: resp.Body, lines: bufio.NewReader(resp.Body), check: c, requestID: requestID(req)}
+	}
+	return resp, nil
+}
+
+// violation logs and counts a malformed message, returning its error
+func (c *protocolCheckRoundTripper) violation(violation error, message []byte) *ProtocolError {
+	log.Printf("❌ Malformed JSON-RPC message from %s: %v: %q",
+		c.backend, violation, strings.ToValidUTF8(string(message[:min(len(message), unexpectedBodySnippetBytes)]), ""))
+	c.gateway.recordProtocolError(c.backend)
+	return &ProtocolError{Backend: c.backend, Reason: violation.Error()}
+}
+
+// requestID returns the raw id of the JSON-RPC request in req's body, if it can be read again
+func requestID(req *http.Request) json.RawMessage {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	var request struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.NewDecoder(body).Decode(&request) != nil || !isJSONRPCID(request.ID, false) {
+		return nil
+	}
+	return request.ID
+}
+
+// sseProtocolReader validates each event of an SSE stream as it is read. A malformed
+// message is replaced with an error response to the request, so the call fails cleanly
+// instead of waiting for a response that never matches, or dropped if the request id
+// is unknown.
+type sseProtocolReader struct {
+	io.ReadCloser
+	lines     *bufio.Reader
+	check     *protocolCheckRoundTripper
+	requestID json.RawMessage
+
+	event   bytes.Buffer
+	data    bytes.Buffer
+	pending []byte
+	err     error
+}
+
+func (r *sseProtocolReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 && r.err == nil {
+		line, err := r.lines.ReadBytes('\n')
+		r.event.Write(line)
+		if field, ok := bytes.CutPrefix(bytes.TrimRight(line, "\r\n"), []byte("data:")); ok {
+			if r.data.Len() > 0 {
+				r.data.WriteByte('\n')
+			}
+			r.data.Write(bytes.TrimPrefix(field, []byte(" ")))
+		}
+		if err != nil || len(bytes.TrimRight(line, "\r\n")) == 0 {
+			r.endEvent()
+			r.err = err
+		}
+	}
+	if len(r.pending) > 0 {
+		n := copy(p, r.pending)
+		r.pending = r.pending[n:]
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// endEvent checks the buffered event and queues it, or its replacement, for reading
+func (r *sseProtocolReader) endEvent() {
+	event := bytes.Clone(r.event.Bytes())
+	if r.data.Len() > 0 {
+		if violation := checkJSONRPC(r.data.Bytes()); violation != nil {
+			err := r.check.violation(violation, r.data.Bytes())
+			event = nil
+			if r.requestID != nil {
+				response, _ := json.Marshal(map[string]any{
+					"jsonrpc": mcp.JSONRPC_VERSION,
+					"id":      r.requestID,
+					"error":   map[string]any{"code": mcp.INTERNAL_ERROR, "message": err.Error()},
+				})
+				event = []byte("event: message\ndata: " + string(response) + "\n\n")
+			}
+		}
+	}
+	r.pending = append(r.pending, event...)
+	r.event.Reset()
+	r.data.Reset()
+}
@@ -0,0 +1,111 @@
+package gateway
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestCheckJSONRPC verifies JSON-RPC violations are detected
+func TestCheckJSONRPC(t *testing.T) {
+	for message, valid := range map[string]bool{
+		`{"jsonrpc":"2.0","id":1,"result":{}}`:                              true,
+		`{"jsonrpc":"2.0","id":"a","error":{"code":-1,"message":"x"}}`:      true,
+		`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"x"}}`: true,
+		`{"jsonrpc":"2.0","method":"notifications/progress"}`:               true,
+		`[{"jsonrpc":"2.0","id":1,"result":{}}]`:                            true,
+		`{"id":1,"result":{}}`:                                              false,
+		`{"jsonrpc":"1.0","id":1,"result":{}}`:                              false,
+		`{"jsonrpc":"2.0","id":{"n":1},"result":{}}`:                        false,
+		`{"jsonrpc":"2.0","id":null,"result":{}}`:                           false,
+		`{"jsonrpc":"2.0","id":1}`:                                          false,
+		`{"jsonrpc":"2.0","id":1,"result":{},"error":{}}`:                   false,
+		`[{"jsonrpc":"2.0","id":1,"result":{}},{"id":2}]`:                   false,
+		`"hello"`: false,
+	} {
+		if err := checkJSONRPC([]byte(message)); (err == nil) != valid {
+			t.Errorf("checkJSONRPC(%s) = %v, expected valid %t", message, err, valid)
+		}
+	}
+}
+
+// newMalformedBackend serves an echo tool whose tools/call responses are rewritten by
+// malform, as JSON or, with stream, as an SSE event
+func newMalformedBackend(t *testing.T, stream bool, malform func(string) string) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Buggy", "1.0.0", server.WithToolCapabilities(true))
+	st := textTool(mcp.NewTool("echo"), "hello")
+	mcpServer.AddTool(st.tool, st.handler)
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if !strings.Contains(string(body), `"tools/call"`) {
+			streamable.ServeHTTP(w, r)
+			return
+		}
+		recorder := httptest.NewRecorder()
+		streamable.ServeHTTP(recorder, r)
+		response := malform(recorder.Body.String())
+		if stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("event: message\ndata: " + response + "\n\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// TestMalformedBackendResponse verifies malformed JSON-RPC responses become a clean error
+// and are counted, whether sent as JSON or in an SSE stream
+func TestMalformedBackendResponse(t *testing.T) {
+	missingVersion := func(response string) string {
+		return strings.Replace(response, `"jsonrpc":"2.0",`, "", 1)
+	}
+	badID := func(response string) string {
+		return regexp.MustCompile(`"id":\d+`).ReplaceAllString(response, `"id":{"n":1}`)
+	}
+
+	for name, test := range map[string]struct {
+		stream  bool
+		malform func(string) string
+		reason  string
+	}{
+		"json missing jsonrpc": {malform: missingVersion, reason: `jsonrpc must be "2.0"`},
+		"sse bad id":           {stream: true, malform: badID, reason: "response id must be a string or number"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := newMalformedBackend(t, test.stream, test.malform)
+			config := DefaultConfig()
+			config.Metrics.Sinks = []MetricsSinkConfig{{Type: "recording"}}
+			config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, Timeouts: BackendTimeouts{Request: 5 * time.Second}}}
+			gateway, gatewayURL := startTestGateway(t, config)
+
+			started := time.Now()
+			result := callTool(t, newTestClient(t, gatewayURL), "server1-echo", nil)
+			text := extractTextFromResult(result)
+			if !result.IsError || !strings.Contains(text, "backend server1 sent a malformed JSON-RPC message: "+test.reason) {
+				t.Errorf("Expected a clean protocol error, got %q", text)
+			}
+			if elapsed := time.Since(started); elapsed > 2*time.Second {
+				t.Errorf("Expected the call to fail at once, took %s", elapsed)
+			}
+			if recorded := gateway.metrics.(*recordingSink).recorded(); !containsString(recorded, "counter backend_protocol_errors backend=server1") {
+				t.Errorf("Expected a protocol error metric, got %v", recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package gateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Name of the result _meta field carrying a session's remaining quota for the tool
+const quotaRemainingMeta = "quotaRemaining"
+
+// QuotasConfig caps how many times a session may call tools, by the session's tier.
+// Unlike rate limits, a quota is a hard count: once used up, calls are rejected until
+// the window ends (or for the rest of the session without a window).
+type QuotasConfig struct {
+	// Window after a session's first counted call when its counts reset; 0 (default)
+	// counts for the session's lifetime
+	Window time.Duration `yaml:"window"`
+	// TierHeader names a request header carrying the session's tier
+	TierHeader string `yaml:"tierHeader"`
+	// TierClaim names a claim of the request's bearer JWT carrying the session's tier,
+	// used when the header is absent. The token is not verified: a proxy in front of
+	// the gateway must authenticate it.
+	TierClaim string `yaml:"tierClaim"`
+	// DefaultTier applies when the tier can't be determined
+	DefaultTier string `yaml:"defaultTier"`
+	// Rules cap calls of matching tools; every matching rule applies
+	Rules []QuotaRule `yaml:"rules"`
+}
+
+// QuotaRule caps a session's calls of the tools it matches, together
+type QuotaRule struct {
+	// Tool is a gateway tool name; path.Match globs are supported ("*" caps all calls)
+	Tool string `yaml:"tool"`
+	// Limits are the calls allowed per window by tier; tiers not listed are unlimited
+	Limits map[string]int `yaml:"limits"`
+}
+
+// Validate checks the tool patterns and limits
+func (c QuotasConfig) Validate() error {
+	if c.Window < 0 {
+		return fmt.Errorf("quotas.window must not be negative")
+	}
+	for i, rule := range c.Rules {
+		if rule.Tool == "" {
+			return fmt.Errorf("quotas.rules[%d]: tool is required", i)
+		}
+		if _, err := path.Match(rule.Tool, ""); err != nil {
+			return fmt.Errorf("quotas.rules[%d]: invalid tool pattern %q: %w", i, rule.Tool, err)
+		}
+		for tier, limit := range rule.Limits {
+			if limit < 0 {
+				return fmt.Errorf("quotas.rules[%d].limits.%s must not be negative", i, tier)
+			}
+		}
+	}
+	return nil
+}
+
+// QuotaExceededError is returned for a call past the session's quota for the tool
+type QuotaExceededError struct {
+	Tool    string
+	Tier    string
+	Limit   int
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	message := fmt.Sprintf("Quota exceeded for %s: tier %q allows %d calls", e.Tool, e.Tier, e.Limit)
+	if e.ResetAt.IsZero() {
+		return message + " per session"
+	}
+	return message + fmt.Sprintf(", resets at %s", e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// quotaUsage counts a session's calls under one quota rule in the current window
+type quotaUsage struct {
+	calls   int
+	resetAt time.Time
+}
+
+// quotaTierKey carries the tier of the client request being handled
+type quotaTierKey struct{}
+
+// quotaTierMiddleware records the tier of session requests in their context
+func (g *Gateway) quotaTierMiddleware(next http.Handler) http.Handler {
+	if len(g.config.Quotas.Rules) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Header.Get("Mcp-Session-Id") != "" {
+			r = r.WithContext(context.WithValue(r.Context(), quotaTierKey{}, g.config.Quotas.tier(r)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tier determines a request's tier from its header, then its bearer token claim,
+// then the default tier
+func (c QuotasConfig) tier(r *http.Request) string {
+	if c.TierHeader != "" {
+		if tier := r.Header.Get(c.TierHeader); tier != "" {
+			return tier
+		}
+	}
+	if c.TierClaim != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			if tier := jwtClaim(token, c.TierClaim); tier != "" {
+				return tier
+			}
+		}
+	}
+	return c.DefaultTier
+}
+
+// jwtClaim returns a string or number claim from an unverified JWT's payload
+func jwtClaim(token, claim string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims map[string]any
+	if json.Unmarshal(payload, &claims) != nil {
+		return ""
+	}
+	switch value := claims[claim].(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%g", value)
+	default:
+		return ""
+	}
+}
+
+// takeQuota counts a call against every quota rule matching the tool for the session's
+// tier, returning the fewest calls left under any of them (-1 when none applies). A call
+// past a limit is rejected without being counted.
+func (g *Gateway) takeQuota(ctx context.Context, sessionID, toolName string) (int, error) {
+	config := g.config.Quotas
+	tier, ok := ctx.Value(quotaTierKey{}).(string)
+	if !ok {
+		tier = config.DefaultTier
+	}
+
+	var rules []int
+	for i, rule := range config.Rules {
+		if _, limited := rule.Limits[tier]; !limited {
+			continue
+		}
+		if matched, _ := path.Match(rule.Tool, toolName); matched {
+			rules = append(rules, i)
+		}
+	}
+	if len(rules) == 0 {
+		return -1, nil
+	}
+
+	remaining, exceeded, resetAt := g.sessions.takeQuota(sessionID, rules, func(rule int) int {
+		return config.Rules[rule].Limits[tier]
+	}, config.Window)
+	if exceeded >= 0 {
+		return 0, &QuotaExceededError{Tool: toolName, Tier: tier, Limit: config.Rules[exceeded].Limits[tier], ResetAt: resetAt}
+	}
+	return remaining, nil
+}
+
+// takeQuota counts a call under each of a session's quota rules, unless one of them is
+// used up. It returns the fewest calls left, or the exhausted rule (-1 if none) and when
+// its window ends.
+func (s *sessionStore) takeQuota(sessionID string, rules []int, limit func(rule int) int, window time.Duration) (int, int, time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	state, ok := s.sessions[sessionID]
+	if !ok {
+		return -1, -1, time.Time{}
+	}
+	if state.quotaUsage == nil {
+		state.quotaUsage = make(map[int]*quotaUsage)
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		usage, ok := state.quotaUsage[rule]
+		if !ok || (!usage.resetAt.IsZero() && !now.Before(usage.resetAt)) {
+			usage = &quotaUsage{}
+			if window > 0 {
+				usage.resetAt = now.Add(window)
+			}
+			state.quotaUsage[rule] = usage
+		}
+		if usage.calls >= limit(rule) {
+			return 0, rule, usage.resetAt
+		}
+	}
+
+	remaining := -1
+	for _, rule := range rules {
+		usage := state.quotaUsage[rule]
+		usage.calls++
+		if left := limit(rule) - usage.calls; remaining < 0 || left < remaining {
+			remaining = left
+		}
+	}
+	return remaining, -1, time.Time{}
+}
+
+// annotateQuota returns the result with the session's remaining quota for the tool in its
+// _meta. The result is copied: it may be shared, such as an idempotent call's stored result.
+func annotateQuota(result *mcp.CallToolResult, remaining int) *mcp.CallToolResult {
+	if result == nil || remaining < 0 {
+		return result
+	}
+	annotated := *result
+	annotated.Meta = maps.Clone(result.Meta)
+	if annotated.Meta == nil {
+		annotated.Meta = make(map[string]any)
+	}
+	annotated.Meta[quotaRemainingMeta] = remaining
+	return &annotated
+}
@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newQuotaTestGateway serves two echo tools with a quota of two calls of either for the
+// free tier, and none for the pro tier
+func newQuotaTestGateway(t *testing.T, quotas QuotasConfig) string {
+	t.Helper()
+
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"), textTool(mcp.NewTool("other"), "other"))
+	config := DefaultConfig()
+	config.Quotas = quotas
+	_, gatewayURL := startTestGateway(t, config, backend)
+	return gatewayURL
+}
+
+// TestQuotaExhausted verifies a session's calls past its tier's quota are rejected, and
+// the remaining quota is reported on the calls before
+func TestQuotaExhausted(t *testing.T) {
+	gatewayURL := newQuotaTestGateway(t, QuotasConfig{
+		TierHeader: "X-Tier",
+		Rules:      []QuotaRule{{Tool: "server1-*", Limits: map[string]int{"free": 2}}},
+	})
+
+	free := newTestClient(t, gatewayURL, transport.WithHTTPHeaders(map[string]string{"X-Tier": "free"}))
+	for i, tool := range []string{"server1-echo", "server1-other"} {
+		result := callTool(t, free, tool, nil)
+		if result.IsError {
+			t.Fatalf("Expected call %d to be within quota, got %s", i+1, extractTextFromResult(result))
+		}
+		if remaining := result.Meta[quotaRemainingMeta]; remaining != float64(1-i) {
+			t.Errorf("Expected %d calls remaining after call %d, got %v", 1-i, i+1, remaining)
+		}
+	}
+	for _, tool := range []string{"server1-echo", "server1-other"} {
+		result := callTool(t, free, tool, nil)
+		if !result.IsError || !strings.Contains(extractTextFromResult(result), "Quota exceeded") {
+			t.Errorf("Expected %s past the quota to be rejected, got %s", tool, extractTextFromResult(result))
+		}
+	}
+
+	// Quotas are per session, and tiers without a limit are unlimited
+	if result := callTool(t, newTestClient(t, gatewayURL, transport.WithHTTPHeaders(map[string]string{"X-Tier": "free"})), "server1-echo", nil); result.IsError {
+		t.Errorf("Expected a new session to have its own quota, got %s", extractTextFromResult(result))
+	}
+	pro := newTestClient(t, gatewayURL, transport.WithHTTPHeaders(map[string]string{"X-Tier": "pro"}))
+	for i := 0; i < 3; i++ {
+		result := callTool(t, pro, "server1-echo", nil)
+		if result.IsError {
+			t.Fatalf("Expected the unlimited tier's call %d to succeed, got %s", i+1, extractTextFromResult(result))
+		}
+		if _, ok := result.Meta[quotaRemainingMeta]; ok {
+			t.Errorf("Expected no remaining quota for an unlimited tier, got %v", result.Meta)
+		}
+	}
+}
+
+// TestQuotaTierClaim verifies the tier is read from the bearer token's claim, and the
+// quota resets once its window ends
+func TestQuotaTierClaim(t *testing.T) {
+	gatewayURL := newQuotaTestGateway(t, QuotasConfig{
+		Window:      200 * time.Millisecond,
+		TierClaim:   "plan",
+		DefaultTier: "free",
+		Rules:       []QuotaRule{{Tool: "*", Limits: map[string]int{"free": 5, "trial": 1}}},
+	})
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","plan":"trial"}`))
+	trial := newTestClient(t, gatewayURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer e30." + payload + ".sig"}))
+	if result := callTool(t, trial, "server1-echo", nil); result.IsError || result.Meta[quotaRemainingMeta] != float64(0) {
+		t.Fatalf("Expected the trial tier's only call to succeed, got %s %v", extractTextFromResult(result), result.Meta)
+	}
+	result := callTool(t, trial, "server1-echo", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), `tier "trial" allows 1 calls, resets at`) {
+		t.Errorf("Expected the trial tier's second call to be rejected, got %s", extractTextFromResult(result))
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if result := callTool(t, trial, "server1-echo", nil); result.IsError {
+		t.Errorf("Expected the quota to reset after its window, got %s", extractTextFromResult(result))
+	}
+
+	// Without a header or claim, the default tier applies
+	if result := callTool(t, newTestClient(t, gatewayURL), "server1-echo", nil); result.Meta[quotaRemainingMeta] != float64(4) {
+		t.Errorf("Expected the default tier's quota, got %v", result.Meta)
+	}
+}
+
+// TestQuotasValidate verifies invalid tool patterns and negative limits are rejected
+func TestQuotasValidate(t *testing.T) {
+	for _, quotas := range []QuotasConfig{
+		{Rules: []QuotaRule{{Tool: "[", Limits: map[string]int{"free": 1}}}},
+		{Rules: []QuotaRule{{Tool: "*", Limits: map[string]int{"free": -1}}}},
+		{Window: -time.Second},
+	} {
+		if err := quotas.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", quotas)
+		}
+	}
+}
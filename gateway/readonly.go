@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// isReadOnlyTool reports whether a tool is read-only, using the MCP annotation and
+// falling back to the configured allowlist. mcp-go defaults readOnlyHint to false, so
+// an explicit false can't be told apart from a missing annotation and the allowlist
+// still applies.
+func (g *Gateway) isReadOnlyTool(tool mcp.Tool) bool {
+	if tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint {
+		return true
+	}
+
+	for _, name := range g.config.ReadOnly.AllowedTools {
+		if name == tool.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyMiddleware rejects calls to mutating tools while read-only mode is enabled
+func (g *Gateway) readOnlyMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !g.config.ReadOnly.Enabled {
+			return next(ctx, req)
+		}
+
+		tool, ok := g.lookupTool(req.Params.Name)
+		if !ok || !g.isReadOnlyTool(tool) {
+			log.Printf("❌ Read-only mode: rejected call to %s", req.Params.Name)
+			return mcp.NewToolResultError(fmt.Sprintf("Tool %s is not allowed: gateway is in read-only mode", req.Params.Name)), nil
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// readOnlyToolFilter hides mutating tools from tools/list when configured to
+func (g *Gateway) readOnlyToolFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	if !g.config.ReadOnly.Enabled || !g.config.ReadOnly.HideMutatingTools {
+		return tools
+	}
+
+	filtered := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if g.isReadOnlyTool(tool) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestReadOnlyMode verifies mutating tools are blocked while read-only tools still succeed
+func TestReadOnlyMode(t *testing.T) {
+	backend1 := newStubBackend(t, "Stub Server 1",
+		textTool(mcp.NewTool("get_item", mcp.WithReadOnlyHintAnnotation(true)), "item"),
+		textTool(mcp.NewTool("delete_item", mcp.WithDestructiveHintAnnotation(true)), "deleted"),
+	)
+	backend2 := newStubBackend(t, "Stub Server 2",
+		textTool(mcp.NewTool("list_items"), "items"),
+	)
+
+	config := DefaultConfig()
+	config.ReadOnly.Enabled = true
+	config.ReadOnly.AllowedTools = []string{"server2-list_items"}
+
+	_, gatewayURL := startTestGateway(t, config, backend1, backend2)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Annotated read-only tool succeeds
+	result := callTool(t, mcpClient, "server1-get_item", nil)
+	if result.IsError {
+		t.Fatalf("Expected read-only tool to succeed, got error: %s", extractTextFromResult(result))
+	}
+
+	// Allowlisted tool without annotations succeeds
+	result = callTool(t, mcpClient, "server2-list_items", nil)
+	if result.IsError {
+		t.Fatalf("Expected allowlisted tool to succeed, got error: %s", extractTextFromResult(result))
+	}
+
+	// Mutating tool is rejected
+	result = callTool(t, mcpClient, "server1-delete_item", nil)
+	if !result.IsError {
+		t.Fatalf("Expected mutating tool to be rejected in read-only mode")
+	}
+
+	// Tools stay visible unless hiding is enabled
+	if !containsString(listToolNames(t, mcpClient), "server1-delete_item") {
+		t.Fatalf("Expected mutating tool to be listed when hideMutatingTools is off")
+	}
+
+	config.ReadOnly.HideMutatingTools = true
+	names := listToolNames(t, mcpClient)
+	if containsString(names, "server1-delete_item") {
+		t.Fatalf("Expected mutating tool to be hidden, got %v", names)
+	}
+	if !containsString(names, "server1-get_item") || !containsString(names, "gateway_info") {
+		t.Fatalf("Expected read-only tools to remain listed, got %v", names)
+	}
+}
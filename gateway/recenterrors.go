@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Default number of errors gateway_recent_errors remembers
+const defaultRecentErrors = 100
+
+// Longest error message kept, so the buffer's memory stays bounded
+const maxRecentErrorMessage = 512
+
+// Error codes of recorded tool call failures
+const (
+	// recentErrorResult is a call that returned a result with isError set
+	recentErrorResult = "error_result"
+	// recentErrorProtocol is a call that failed with a JSON-RPC error
+	recentErrorProtocol = "protocol_error"
+)
+
+// recentErrors returns the size of the recent errors buffer, defaulting to 100
+func (c AdminConfig) recentErrors() int {
+	if c.RecentErrors > 0 {
+		return c.RecentErrors
+	}
+	return defaultRecentErrors
+}
+
+// recentError is a failed tool call reported by gateway_recent_errors
+type recentError struct {
+	Time    time.Time `json:"time"`
+	Backend string    `json:"backend,omitempty"`
+	Tool    string    `json:"tool"`
+	Code    string    `json:"code"`
+	Message string    `json:"message,omitempty"`
+}
+
+// recentErrorLog keeps the most recent failed tool calls in a ring buffer
+type recentErrorLog struct {
+	entries []recentError
+	next    int
+	full    bool
+	lock    sync.Mutex
+}
+
+func newRecentErrorLog(size int) *recentErrorLog {
+	return &recentErrorLog{entries: make([]recentError, size)}
+}
+
+// add records an error, replacing the oldest once the buffer is full
+func (l *recentErrorLog) add(entry recentError) {
+	if len(entry.Message) > maxRecentErrorMessage {
+		entry.Message = entry.Message[:maxRecentErrorMessage] + "..."
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// list returns up to limit errors, newest first; limit 0 returns them all
+func (l *recentErrorLog) list(limit int) []recentError {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	entries := make([]recentError, 0, count)
+	for i := 1; i <= count; i++ {
+		entries = append(entries, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return entries
+}
+
+// recordRecentError remembers a failed tool call for gateway_recent_errors; it does
+// nothing unless admin tools are enabled
+func (g *Gateway) recordRecentError(toolName, backend string, result *mcp.CallToolResult, err error) {
+	if g.recentErrors == nil {
+		return
+	}
+	entry := recentError{Time: time.Now().UTC(), Backend: backend, Tool: toolName}
+	switch {
+	case err != nil:
+		entry.Code, entry.Message = recentErrorProtocol, err.Error()
+	case result == nil:
+		entry.Code, entry.Message = recentErrorProtocol, "no result"
+	default:
+		entry.Code, entry.Message = recentErrorResult, resultText(result)
+	}
+	g.recentErrors.add(entry)
+}
+
+// handleRecentErrors handles the gateway_recent_errors tool
+func (g *Gateway) handleRecentErrors(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	limit := req.GetInt("limit", 0)
+	if limit < 0 {
+		return mcp.NewToolResultError("limit must not be negative"), nil
+	}
+	data, err := json.MarshalIndent(g.recentErrors.list(limit), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode errors: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
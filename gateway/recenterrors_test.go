@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestRecentErrors verifies failed calls appear in gateway_recent_errors, newest first and
+// capped at the configured buffer size
+func TestRecentErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	config.Admin.RecentErrors = 3
+	config.Backends = []BackendConfig{{Name: "server1", URL: newFailingBackend(t)}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	for _, tool := range []string{"server1-quota", "server1-quota", "server1-quota", "server1-crash"} {
+		callTool(t, mcpClient, tool, nil)
+	}
+	callTool(t, mcpClient, "gateway_info", nil)
+
+	recentErrors := func(args map[string]interface{}) []recentError {
+		result := callTool(t, mcpClient, "gateway_recent_errors", args)
+		if result.IsError {
+			t.Fatalf("gateway_recent_errors failed: %s", extractTextFromResult(result))
+		}
+		var entries []recentError
+		if err := json.Unmarshal([]byte(extractTextFromResult(result)), &entries); err != nil {
+			t.Fatalf("Expected a JSON list of errors: %v", err)
+		}
+		return entries
+	}
+
+	entries := recentErrors(nil)
+	if len(entries) != 3 {
+		t.Fatalf("Expected the buffer to hold the last 3 errors, got %+v", entries)
+	}
+	if entries[0].Tool != "server1-crash" || entries[0].Code != recentErrorResult || entries[0].Backend != "server1" {
+		t.Errorf("Expected the newest error first, got %+v", entries[0])
+	}
+	for _, entry := range entries[1:] {
+		if entry.Tool != "server1-quota" || entry.Message != "quota exceeded" || entry.Time.IsZero() {
+			t.Errorf("Expected the older quota errors, got %+v", entry)
+		}
+	}
+
+	if entries := recentErrors(map[string]interface{}{"limit": 1}); len(entries) != 1 || entries[0].Tool != "server1-crash" {
+		t.Errorf("Expected limit to return only the newest error, got %+v", entries)
+	}
+}
+
+// TestRecentErrorsRequiresAdmin verifies gateway_recent_errors is only offered with admin enabled
+func TestRecentErrorsRequiresAdmin(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+
+	if names := listToolNames(t, newTestClient(t, gatewayURL)); containsString(names, "gateway_recent_errors") {
+		t.Errorf("Expected gateway_recent_errors to be hidden without admin, got %v", names)
+	}
+}
@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrSessionTerminated reports a backend answering 404 to a request on its session, e.g.
+// after it restarted. mcp-go v0.32 only reports this as text, so the gateway's response
+// check returns it instead; custom transports should wrap it.
+var ErrSessionTerminated = errors.New("session terminated")
+
+// ProtocolDowngradeError reports a backend that renegotiated an older protocol version on reconnect
+type ProtocolDowngradeError struct {
+	Backend  string
+	Previous string
+	Current  string
+}
+
+func (e *ProtocolDowngradeError) Error() string {
+	return fmt.Sprintf("backend %s reconnected with older protocol version %s (was %s); the call was not retried",
+		e.Backend, e.Current, e.Previous)
+}
+
+// isSessionTerminated reports whether a backend rejected the call because its session is gone,
+// e.g. after the backend restarted
+func isSessionTerminated(err error) bool {
+	return errors.Is(err, ErrSessionTerminated)
+}
+
+// reconnectBackend replaces the client's backend connection with a newly initialized one,
+// renegotiating the protocol version. It returns the new client and the previous and new versions.
+func (g *Gateway) reconnectBackend(ctx context.Context, connections *ClientBackendConnections, backend BackendConfig) (BackendTransport, string, string, error) {
+	connections.lock.Lock()
+	defer connections.lock.Unlock()
+
+	previous := connections.protocolVersions[backend.Name]
+	if old, ok := connections.Clients[backend.Name]; ok {
+		old.Close()
+		delete(connections.Clients, backend.Name)
+	}
+
+	log.Printf("🔗 Reconnecting client %s to %s", connections.ClientSessionID, backend.Name)
+	if err := g.createClientBackendConnection(ctx, backend, connections); err != nil {
+		return nil, previous, "", err
+	}
+	return connections.Clients[backend.Name], previous, connections.protocolVersions[backend.Name], nil
+}
+
+// checkProtocolVersion reports a downgrade between the previously and newly negotiated versions.
+// Protocol versions are dates (YYYY-MM-DD), so they order lexically.
+func checkProtocolVersion(backend, previous, current string) error {
+	if previous != "" && current < previous {
+		log.Printf("⚠️ %s downgraded protocol version from %s to %s", backend, previous, current)
+		return &ProtocolDowngradeError{Backend: backend, Previous: previous, Current: current}
+	}
+	return nil
+}
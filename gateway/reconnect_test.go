@@ -0,0 +1,142 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// restartableBackend simulates a backend restart: sessions from before the restart get
+// 404, and initialize after it reports the configured protocol version
+type restartableBackend struct {
+	handler http.Handler
+
+	lock            sync.Mutex
+	generation      int
+	sessions        map[string]int // session ID -> generation it was created in
+	protocolVersion string
+}
+
+func (b *restartableBackend) restart(protocolVersion string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.generation++
+	b.protocolVersion = protocolVersion
+}
+
+func (b *restartableBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.lock.Lock()
+	generation, protocolVersion := b.generation, b.protocolVersion
+	sessionGeneration, known := b.sessions[r.Header.Get("Mcp-Session-Id")]
+	b.lock.Unlock()
+
+	if known && sessionGeneration != generation {
+		http.Error(w, "Session terminated", http.StatusNotFound)
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	b.handler.ServeHTTP(recorder, r)
+
+	body := recorder.Body.Bytes()
+	if sessionID := recorder.Header().Get("Mcp-Session-Id"); sessionID != "" {
+		b.lock.Lock()
+		b.sessions[sessionID] = generation
+		b.lock.Unlock()
+
+		if protocolVersion != "" {
+			body = bytes.Replace(body, []byte(`"protocolVersion":"`+mcp.LATEST_PROTOCOL_VERSION+`"`),
+				[]byte(`"protocolVersion":"`+protocolVersion+`"`), 1)
+		}
+	}
+
+	for name, values := range recorder.Header() {
+		w.Header()[name] = values
+	}
+	w.Header().Del("Content-Length")
+	w.WriteHeader(recorder.Code)
+	io.Copy(w, bytes.NewReader(body))
+}
+
+// newRestartableBackend starts a restartable stub backend
+func newRestartableBackend(t *testing.T) (*restartableBackend, *httptest.Server) {
+	mcpServer := server.NewMCPServer("Restartable", "1.0.0", server.WithToolCapabilities(true))
+	st := textTool(mcp.NewTool("echo"), "ok")
+	mcpServer.AddTool(st.tool, st.handler)
+
+	backend := &restartableBackend{
+		handler:  server.NewStreamableHTTPServer(mcpServer),
+		sessions: make(map[string]int),
+	}
+	httpServer := httptest.NewServer(backend)
+	t.Cleanup(httpServer.Close)
+	return backend, httpServer
+}
+
+// TestReconnectAfterRestart verifies a lost backend session is renegotiated and the call retried
+func TestReconnectAfterRestart(t *testing.T) {
+	backend, httpServer := newRestartableBackend(t)
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), httpServer)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if result := callTool(t, mcpClient, "server1-echo", nil); result.IsError {
+		t.Fatalf("Initial call failed: %s", extractTextFromResult(result))
+	}
+
+	backend.restart("")
+	if result := callTool(t, mcpClient, "server1-echo", nil); result.IsError {
+		t.Fatalf("Expected the call to succeed after reconnecting, got %s", extractTextFromResult(result))
+	}
+}
+
+// TestReconnectProtocolDowngrade verifies a downgrade on reconnect is surfaced as a clear error
+func TestReconnectProtocolDowngrade(t *testing.T) {
+	backend, httpServer := newRestartableBackend(t)
+	gateway, gatewayURL := startTestGateway(t, DefaultConfig(), httpServer)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	callTool(t, mcpClient, "server1-echo", nil)
+
+	backend.restart("2024-11-05")
+	result := callTool(t, mcpClient, "server1-echo", nil)
+	text := extractTextFromResult(result)
+	if !result.IsError || !strings.Contains(text, "older protocol version 2024-11-05") {
+		t.Fatalf("Expected a protocol downgrade error, got %q", text)
+	}
+
+	// The renegotiated version is tracked for the connection
+	gateway.connectionsLock.RLock()
+	defer gateway.connectionsLock.RUnlock()
+	for _, connections := range gateway.clientConnections {
+		connections.lock.Lock()
+		version := connections.protocolVersions["server1"]
+		connections.lock.Unlock()
+		if version != "2024-11-05" {
+			t.Errorf("Expected tracked protocol version 2024-11-05, got %q", version)
+		}
+	}
+}
+
+// TestIsSessionTerminated verifies only errors wrapping ErrSessionTerminated count as a
+// lost session, however their message reads
+func TestIsSessionTerminated(t *testing.T) {
+	wrapped := fmt.Errorf("failed to send request: %w", &url.Error{Op: "Post", URL: "http://backend/mcp", Err: fmt.Errorf("backend server1: %w", ErrSessionTerminated)})
+	if !isSessionTerminated(wrapped) {
+		t.Errorf("Expected %v to be a terminated session", wrapped)
+	}
+	for _, err := range []error{nil, errors.New("session terminated (404). need to re-initialize"), errors.New("tool failed")} {
+		if isSessionTerminated(err) {
+			t.Errorf("Expected %v not to be a terminated session", err)
+		}
+	}
+}
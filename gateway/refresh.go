@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// refreshResult reports the outcome of a backend refresh
+type refreshResult struct {
+	Backend string `json:"backend"`
+	Tools   int    `json:"tools"`
+	Changed bool   `json:"changed"`
+}
+
+// refreshBackend re-fetches one backend's tools over a new connection and re-merges the
+// aggregated tools, leaving other backends and client sessions untouched
+func (g *Gateway) refreshBackend(ctx context.Context, backend BackendConfig) (*refreshResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, backend.Timeouts.request())
+	defer cancel()
+
+	backendClient, _, err := g.dialBackend(ctx, backend, "MCP Gateway (Refresh)", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer backendClient.Close()
+
+	listed, err := backendClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+	if listed.Tools, err = g.dedupeTools(backend.Name, listed.Tools); err != nil {
+		return nil, err
+	}
+
+	// Serialize refreshes so a slower one can't overwrite a newer merge
+	g.refreshLock.Lock()
+	defer g.refreshLock.Unlock()
+
+	g.toolsLock.Lock()
+	lists := make(map[string][]mcp.Tool, len(g.backendTools)+1)
+	for name, tools := range g.backendTools {
+		lists[name] = tools
+	}
+	lists[backend.Name] = listed.Tools
+	g.backendTools = lists
+	if _, ok := g.unavailableBackends[backend.Name]; ok {
+		unavailable := make(map[string]error, len(g.unavailableBackends))
+		for name, err := range g.unavailableBackends {
+			if name != backend.Name {
+				unavailable[name] = err
+			}
+		}
+		g.unavailableBackends = unavailable
+	}
+	g.toolsLock.Unlock()
+
+	changed := g.mergeTools()
+	log.Printf("🔧 Refreshed %s: %d tools, aggregated tools changed: %t", backend.Name, len(listed.Tools), changed)
+	return &refreshResult{Backend: backend.Name, Tools: len(listed.Tools), Changed: changed}, nil
+}
+
+// handleRefreshBackend handles POST /admin/backends/{name}/refresh
+func (g *Gateway) handleRefreshBackend(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	backend, ok := g.findBackend(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown backend %s", name), http.StatusNotFound)
+		return
+	}
+
+	result, err := g.refreshBackend(r.Context(), backend)
+	if err != nil {
+		log.Printf("❌ Failed to refresh backend %s: %v", name, err)
+		http.Error(w, fmt.Sprintf("refresh failed: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("❌ Failed to write refresh result: %v", err)
+	}
+}
+
+// handleRefreshBackendTool handles the gateway_refresh_backend tool
+func (g *Gateway) handleRefreshBackendTool(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("backend")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	backend, ok := g.findBackend(name)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown backend %s", name)), nil
+	}
+
+	result, err := g.refreshBackend(ctx, backend)
+	if err != nil {
+		log.Printf("❌ Failed to refresh backend %s: %v", name, err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to refresh backend %s: %v", name, err)), nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
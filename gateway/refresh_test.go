@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// postRefresh refreshes a backend through the admin endpoint
+func postRefresh(t *testing.T, gatewayURL, backend string) (int, refreshResult) {
+	t.Helper()
+
+	resp, err := http.Post(gatewayURL+"/admin/backends/"+backend+"/refresh", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Refresh request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result refreshResult
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode refresh result: %v", err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+// TestRefreshBackend verifies a refresh picks up a tool the backend added without a
+// list_changed notification, and only reports a change when the tools changed
+func TestRefreshBackend(t *testing.T) {
+	mcpServer := server.NewMCPServer("Growing", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	growing := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(growing.Close)
+	other := newStubBackend(t, "Other", textTool(mcp.NewTool("ping"), "pong"))
+
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	_, gatewayURL := startTestGateway(t, config, growing, other)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Adding the tool doesn't notify the gateway, which keeps its old tool list
+	mcpServer.AddTool(mcp.NewTool("reverse"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("olleh"), nil
+	})
+	listToolNames := func() []string {
+		tools, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+		if err != nil {
+			t.Fatalf("Failed to list tools: %v", err)
+		}
+		var names []string
+		for _, tool := range tools.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	if containsString(listToolNames(), "server1-reverse") {
+		t.Fatal("Expected the new tool to be unknown before the refresh")
+	}
+
+	status, result := postRefresh(t, gatewayURL, "server1")
+	if status != http.StatusOK || !result.Changed || result.Tools != 2 {
+		t.Fatalf("Expected a changed refresh with 2 tools, got %d %+v", status, result)
+	}
+
+	names := listToolNames()
+	for _, name := range []string{"server1-echo", "server1-reverse", "server2-ping"} {
+		if !containsString(names, name) {
+			t.Errorf("Expected %s after the refresh, got %v", name, names)
+		}
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-reverse", nil)); text != "olleh" {
+		t.Errorf("Expected the new tool to be callable, got %q", text)
+	}
+
+	// Nothing changed since, so clients aren't notified again
+	if status, result := postRefresh(t, gatewayURL, "server1"); status != http.StatusOK || result.Changed {
+		t.Errorf("Expected an unchanged refresh, got %d %+v", status, result)
+	}
+	if status, _ := postRefresh(t, gatewayURL, "missing"); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown backend, got %d", status)
+	}
+}
@@ -0,0 +1,213 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// backendContribution is a backend's tool list prepared for aggregation: filtered by
+// tool policy, renamed for the gateway, and with deprecation, default schemas and
+// annotations applied. Name collisions with other backends are resolved on every merge.
+type backendContribution struct {
+	// source is the backend tool list the tools were prepared from
+	source []mcp.Tool
+	// tools are the prepared tools, and names their backend tool names
+	tools []mcp.Tool
+	names []string
+}
+
+// sameList reports whether two tool lists are the same slice. A backend's list is only
+// ever replaced, never modified, so an unchanged slice means an unchanged list.
+func sameList(a, b []mcp.Tool) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// prepareBackendTools prepares a backend's tool list for aggregation
+func (g *Gateway) prepareBackendTools(backend BackendConfig, source []mcp.Tool) *backendContribution {
+	contribution := &backendContribution{source: source}
+	for _, tool := range source {
+		if !backend.Tools.Allows(tool.Name) {
+			log.Printf("Excluding %s tool %s by tool policy", backend.Name, tool.Name)
+			continue
+		}
+		prefixedTool := tool
+		prefixedTool.Name = g.gatewayToolName(backend, tool.Name)
+
+		// Built-in tool names are reserved; a backend tool of the same name stays reachable under its prefix
+		if g.isBuiltinTool(tool.Name) {
+			log.Printf("⚠️ %s tool %s is shadowed by the built-in tool; exposing it as %s", backend.Name, tool.Name, prefixedTool.Name)
+		}
+		if g.isBuiltinTool(prefixedTool.Name) {
+			log.Printf("⚠️ Skipping %s tool %s: %s is reserved for a built-in tool", backend.Name, tool.Name, prefixedTool.Name)
+			continue
+		}
+
+		if g.isDeprecatedTool(prefixedTool) {
+			if g.config.Deprecation.mode() == deprecationHide {
+				log.Printf("Hiding deprecated %s tool %s", backend.Name, tool.Name)
+				continue
+			}
+			prefixedTool = markDeprecated(prefixedTool)
+		}
+
+		prefixedTool = g.applyDefaultSchema(prefixedTool)
+		prefixedTool = g.applyAnnotations(prefixedTool)
+		contribution.tools = append(contribution.tools, prefixedTool)
+		contribution.names = append(contribution.names, tool.Name)
+	}
+	return contribution
+}
+
+// mergeTools rebuilds the aggregated tools from the latest tool list of each backend and
+// registers the changes with the MCP server. Only backends whose tool list changed since
+// the last merge are prepared again, and only added or changed tools are registered.
+// It reports whether the aggregated tools changed; clients are only notified
+// (tools/list_changed) when they did.
+func (g *Gateway) mergeTools() bool {
+	g.mergeLock.Lock()
+	defer g.mergeLock.Unlock()
+
+	g.toolsLock.RLock()
+	lists := g.backendTools
+	previous, previousIndex, previousRoutes := g.aggregatedTools, g.toolIndex, g.toolRoutes
+	g.toolsLock.RUnlock()
+
+	if g.contributions == nil {
+		g.contributions = make(map[string]*backendContribution)
+	}
+	var allTools []mcp.Tool
+	routes := make(map[string]toolRoute, len(previousRoutes))
+	discoverable := make(map[string][]mcp.Tool)
+	// Tools that may differ from the previous merge's tool of the same name
+	fresh := make(map[string]bool)
+
+	for _, backend := range g.config.Backends {
+		backendTools, ok := lists[backend.Name]
+		if !ok || g.drains.isRemoved(backend.Name) {
+			delete(g.contributions, backend.Name)
+			continue
+		}
+		contribution, reused := g.contributions[backend.Name]
+		if !reused || !sameList(contribution.source, backendTools) {
+			contribution, reused = g.prepareBackendTools(backend, backendTools), false
+			g.contributions[backend.Name] = contribution
+		}
+
+		// Detect names that collide across backends; the first registration wins
+		included := 0
+		first := len(allTools)
+		for i, tool := range contribution.tools {
+			if existing, ok := routes[tool.Name]; ok {
+				log.Printf("⚠️ Skipping %s tool %s: %s already provided by %s tool %s",
+					backend.Name, contribution.names[i], tool.Name, existing.Backend, existing.ToolName)
+				continue
+			}
+			included++
+			allTools = append(allTools, tool)
+			routes[tool.Name] = toolRoute{Backend: backend.Name, ToolName: contribution.names[i]}
+			if !reused {
+				fresh[tool.Name] = true
+			}
+		}
+		if !reused {
+			log.Printf("%s contributed %d of %d tools", backend.Name, included, len(backendTools))
+		}
+
+		// List a backend with too many tools as a single discovery tool
+		if threshold := backend.Discovery.ToolThreshold; threshold > 0 && included > threshold {
+			discoveryTool := g.newDiscoveryTool(backend, included)
+			if existing, ok := routes[discoveryTool.Name]; ok {
+				log.Printf("⚠️ Listing every %s tool: %s already provided by %s tool %s",
+					backend.Name, discoveryTool.Name, existing.Backend, existing.ToolName)
+				continue
+			}
+			if !reused {
+				log.Printf("🔧 Listing %s's %d tools behind %s", backend.Name, included, discoveryTool.Name)
+			}
+			discoverable[discoveryTool.Name] = append([]mcp.Tool(nil), allTools[first:]...)
+			allTools = append(allTools, discoveryTool)
+			fresh[discoveryTool.Name] = true
+		}
+	}
+	for _, tool := range g.argumentRoutedTools(lists, routes) {
+		allTools = append(allTools, tool)
+		fresh[tool.Name] = true
+	}
+
+	// Compare with the previous merge: a tool is unchanged if it has the same route and
+	// came from a reused backend contribution, or encodes the same
+	index := make(map[string]int, len(allTools))
+	var changed []mcp.Tool
+	for i, tool := range allTools {
+		index[tool.Name] = i
+		j, existed := previousIndex[tool.Name]
+		if !existed || previousRoutes[tool.Name] != routes[tool.Name] || (fresh[tool.Name] && !sameTool(previous[j], tool)) {
+			changed = append(changed, tool)
+		}
+	}
+	var removed []string
+	for name := range previousIndex {
+		if _, ok := index[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	g.toolsLock.Lock()
+	g.aggregatedTools = allTools
+	g.toolRoutes = routes
+	g.toolIndex = index
+	g.discoverableTools = discoverable
+	g.toolsLock.Unlock()
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return false
+	}
+
+	// Unregister tools that are no longer offered
+	if len(removed) > 0 {
+		g.mcpServer.DeleteTools(removed...)
+	}
+
+	g.registerAggregatedTools(changed)
+	log.Printf("Registered %d aggregated tools with MCP server (%d added or changed, %d removed)", len(allTools), len(changed), len(removed))
+	return true
+}
+
+// sameTool reports whether two tools are identical as listed to clients
+func sameTool(a, b mcp.Tool) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+// registerAggregatedTools registers aggregated tools with the MCP server at once, so
+// clients get a single tools/list_changed notification
+func (g *Gateway) registerAggregatedTools(tools []mcp.Tool) {
+	if len(tools) == 0 {
+		return
+	}
+
+	g.toolsLock.RLock()
+	entries := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		// Create a closure to capture the tool name for routing
+		toolName := tool.Name
+		handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return g.routeToolCall(ctx, toolName, req)
+		}
+		if _, ok := g.discoverableTools[toolName]; ok {
+			handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return g.handleDiscoverTools(ctx, toolName, req)
+			}
+		}
+		entries = append(entries, server.ServerTool{Tool: tool, Handler: handler})
+	}
+	g.toolsLock.RUnlock()
+
+	g.mcpServer.AddTools(entries...)
+}
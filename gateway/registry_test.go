@@ -0,0 +1,196 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newRegistryBenchGateway returns a gateway with backends backends of tools tools each,
+// merged into the aggregated tools, without connecting to any backend
+func newRegistryBenchGateway(b testing.TB, backends, tools int) *Gateway {
+	b.Helper()
+
+	// Merging logs every backend; silence it so logging doesn't dominate the numbers
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	config := DefaultConfig()
+	config.Backends = nil
+	lists := make(map[string][]mcp.Tool, backends)
+	for i := 0; i < backends; i++ {
+		name := fmt.Sprintf("backend%d", i)
+		config.Backends = append(config.Backends, BackendConfig{Name: name, URL: "http://" + name})
+		lists[name] = benchTools(tools, "v1")
+	}
+	gateway := newGateway(config)
+	gateway.backendTools = lists
+	gateway.mergeTools()
+	return gateway
+}
+
+// benchTools returns a backend tool list whose descriptions carry version
+func benchTools(tools int, version string) []mcp.Tool {
+	list := make([]mcp.Tool, tools)
+	for i := range list {
+		list[i] = mcp.NewTool(fmt.Sprintf("tool%d", i),
+			mcp.WithDescription("Benchmark tool "+version),
+			mcp.WithString("query", mcp.Required(), mcp.Description("What to look up")),
+		)
+	}
+	return list
+}
+
+// setBackendTools replaces one backend's tool list, as a refresh does
+func setBackendTools(gateway *Gateway, backend string, tools []mcp.Tool) {
+	gateway.toolsLock.Lock()
+	defer gateway.toolsLock.Unlock()
+	lists := make(map[string][]mcp.Tool, len(gateway.backendTools))
+	for name, list := range gateway.backendTools {
+		lists[name] = list
+	}
+	if tools == nil {
+		delete(lists, backend)
+	} else {
+		lists[backend] = tools
+	}
+	gateway.backendTools = lists
+}
+
+// TestIncrementalMerge verifies merging after one backend's tool list changed updates
+// its tools, registered and looked up, and leaves the other backends' tools as they were
+func TestIncrementalMerge(t *testing.T) {
+	gateway := newRegistryBenchGateway(t, 3, 5)
+	registered := func() map[string]string {
+		response := gateway.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		listed, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult)
+		if !ok {
+			t.Fatalf("Unexpected tools/list response %+v", response)
+		}
+		descriptions := make(map[string]string)
+		for _, tool := range listed.Tools {
+			descriptions[tool.Name] = tool.Description
+		}
+		return descriptions
+	}
+	if tools := registered(); len(tools) != 15+len(gateway.builtinTools) || tools["backend2-tool4"] != "Benchmark tool v1" {
+		t.Fatalf("Expected every backend's tools to be registered, got %v", tools)
+	}
+
+	if gateway.mergeTools() {
+		t.Error("Expected a merge without tool list changes to change nothing")
+	}
+
+	setBackendTools(gateway, "backend1", benchTools(3, "v2"))
+	if !gateway.mergeTools() {
+		t.Fatal("Expected the changed tool list to change the aggregated tools")
+	}
+	tools := registered()
+	for name, expected := range map[string]string{
+		"backend0-tool0": "Benchmark tool v1",
+		"backend1-tool2": "Benchmark tool v2",
+		"backend1-tool3": "",
+		"backend2-tool4": "Benchmark tool v1",
+	} {
+		if tools[name] != expected {
+			t.Errorf("Expected %s to be registered as %q, got %q", name, expected, tools[name])
+		}
+		if tool, ok := gateway.lookupTool(name); ok != (expected != "") || tool.Description != expected {
+			t.Errorf("Expected lookup of %s to find %q, got %q (found %t)", name, expected, tool.Description, ok)
+		}
+	}
+	if route := gateway.toolRoutes["backend1-tool2"]; route.Backend != "backend1" || route.ToolName != "tool2" {
+		t.Errorf("Unexpected route for backend1-tool2: %+v", route)
+	}
+
+	// A backend's tools take over a name once the backend providing it first is gone
+	gateway.config.Backends[1].Namespace = "backend0"
+	setBackendTools(gateway, "backend1", benchTools(6, "v3"))
+	gateway.mergeTools()
+	if tool, _ := gateway.lookupTool("backend0-tool5"); tool.Description != "Benchmark tool v3" {
+		t.Errorf("Expected backend1's tool5 under the shared namespace, got %q", tool.Description)
+	}
+	if route := gateway.toolRoutes["backend0-tool0"]; route.Backend != "backend0" {
+		t.Errorf("Expected the first backend to win the collision, got %+v", route)
+	}
+	setBackendTools(gateway, "backend0", nil)
+	gateway.mergeTools()
+	if route := gateway.toolRoutes["backend0-tool0"]; route.Backend != "backend1" {
+		t.Errorf("Expected backend1 to provide the name once backend0 is gone, got %+v", route)
+	}
+	if description := registered()["backend0-tool0"]; description != "Benchmark tool v3" {
+		t.Errorf("Expected backend1's definition to be registered, got %q", description)
+	}
+}
+
+// BenchmarkMergeTools measures rebuilding the aggregated tools after every backend's
+// tool list changed.
+//
+//	go test -run '^$' -bench 'BenchmarkMergeTools|BenchmarkRefreshOneBackend|BenchmarkLookupTool'
+func BenchmarkMergeTools(b *testing.B) {
+	for _, backends := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("backends=%d", backends), func(b *testing.B) {
+			gateway := newRegistryBenchGateway(b, backends, 50)
+			versions := [2]map[string][]mcp.Tool{make(map[string][]mcp.Tool), make(map[string][]mcp.Tool)}
+			for _, backend := range gateway.config.Backends {
+				versions[0][backend.Name] = benchTools(50, "v2")
+				versions[1][backend.Name] = benchTools(50, "v3")
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				gateway.toolsLock.Lock()
+				gateway.backendTools = versions[i%2]
+				gateway.toolsLock.Unlock()
+				gateway.mergeTools()
+			}
+		})
+	}
+}
+
+// BenchmarkRefreshOneBackend measures re-merging the aggregated tools after one backend's
+// tool list changed, as on a refresh or list_changed notification
+func BenchmarkRefreshOneBackend(b *testing.B) {
+	for _, backends := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("backends=%d", backends), func(b *testing.B) {
+			gateway := newRegistryBenchGateway(b, backends, 50)
+			versions := [2][]mcp.Tool{benchTools(50, "v2"), benchTools(50, "v3")}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				gateway.toolsLock.Lock()
+				lists := make(map[string][]mcp.Tool, len(gateway.backendTools))
+				for name, tools := range gateway.backendTools {
+					lists[name] = tools
+				}
+				lists["backend0"] = versions[i%2]
+				gateway.backendTools = lists
+				gateway.toolsLock.Unlock()
+				gateway.mergeTools()
+			}
+		})
+	}
+}
+
+// BenchmarkLookupTool measures finding a tool definition by name, as the per-call
+// policy middlewares do
+func BenchmarkLookupTool(b *testing.B) {
+	for _, backends := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("backends=%d", backends), func(b *testing.B) {
+			gateway := newRegistryBenchGateway(b, backends, 50)
+			name := fmt.Sprintf("backend%d-tool49", backends-1)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, ok := gateway.lookupTool(name); !ok {
+					b.Fatalf("Expected %s to be found", name)
+				}
+			}
+		})
+	}
+}
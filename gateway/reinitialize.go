@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Longest a repeated initialize waits for the session's first initialize to finish
+const reinitializeWait = 10 * time.Second
+
+// recordInitializeResult keeps a session's initialize result for repeated initializes
+func (g *Gateway) recordInitializeResult(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	if session := server.ClientSessionFromContext(ctx); session != nil && result != nil {
+		g.sessions.setInitializeResult(session.SessionID(), result)
+	}
+}
+
+// reinitializeMiddleware answers an initialize sent on an existing session, as a client
+// retrying or a proxy replaying it would, with the session's own initialize result.
+// mcp-go would otherwise start a new session, with new backend sessions, for each one.
+func (g *Gateway) reinitializeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get("Mcp-Session-Id")
+		if r.Method != http.MethodPost || sessionID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message jsonRPCMessage
+		if json.Unmarshal(body, &message) != nil || message.Method != string(mcp.MethodInitialize) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		request := &initializeRequest{ID: message.ID}
+
+		result, ok := g.sessions.initializeResult(r.Context(), sessionID, reinitializeWait)
+		if !ok {
+			log.Printf("❌ Client %s re-sent initialize before its session was initialized", sessionID)
+			writeInitializeError(w, request, http.StatusOK, mcp.INVALID_REQUEST,
+				"Session is not initialized: initialize a new session without Mcp-Session-Id", nil)
+			return
+		}
+
+		log.Printf("🔧 Client %s re-sent initialize, answering with its session's result", sessionID)
+		w.Header().Set("Mcp-Session-Id", sessionID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      request.ID,
+			"result":  result,
+		})
+	})
+}
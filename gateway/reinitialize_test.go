@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newInitializeCountingBackend serves backend's MCP endpoint, counting the initialize
+// requests it receives
+func newInitializeCountingBackend(t *testing.T, backend *httptest.Server) (*httptest.Server, *atomic.Int32) {
+	target := backend.Config.Handler
+	var initializes atomic.Int32
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		var message jsonRPCMessage
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodInitialize) {
+			initializes.Add(1)
+		}
+		target.ServeHTTP(w, r)
+	}))
+	t.Cleanup(counting.Close)
+	return counting, &initializes
+}
+
+// TestConcurrentReinitialize verifies initializes repeated concurrently on a session get
+// the session's result, and that concurrent calls share one set of backend sessions
+func TestConcurrentReinitialize(t *testing.T) {
+	backend, initializes := newInitializeCountingBackend(t, newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello")))
+	gateway, gatewayURL := startTestGateway(t, DefaultConfig(), backend)
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+	startup := initializes.Load()
+
+	initialize := `{"jsonrpc":"2.0","id":7,"method":"initialize","params":{"protocolVersion":"` +
+		mcp.LATEST_PROTOCOL_VERSION + `","capabilities":{},"clientInfo":{"name":"raw","version":"1.0.0"}}}`
+	call := `{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"server1-echo"}}`
+
+	var wg sync.WaitGroup
+	for _, body := range []string{initialize, initialize, call, call} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := postMessage(t, gatewayURL, sessionID, body)
+			defer resp.Body.Close()
+			if body != initialize {
+				io.Copy(io.Discard, resp.Body)
+				return
+			}
+
+			if assigned := resp.Header.Get("Mcp-Session-Id"); assigned != sessionID {
+				t.Errorf("Expected the repeated initialize to keep session %s, got %s", sessionID, assigned)
+			}
+			var response struct {
+				ID     int                  `json:"id"`
+				Result mcp.InitializeResult `json:"result"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Errorf("Failed to decode initialize response: %v", err)
+				return
+			}
+			if response.ID != 7 || response.Result.ServerInfo.Name == "" {
+				t.Errorf("Expected the session's initialize result for request 7, got %+v", response)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created := initializes.Load() - startup; created != 1 {
+		t.Errorf("Expected one backend session for the client session, got %d", created)
+	}
+	gateway.connectionsLock.RLock()
+	sessions := len(gateway.clientConnections)
+	gateway.connectionsLock.RUnlock()
+	if sessions != 1 {
+		t.Errorf("Expected backend connections for one client session, got %d", sessions)
+	}
+}
+
+// TestReinitializeUnknownSession verifies an initialize on an unknown session is refused
+func TestReinitializeUnknownSession(t *testing.T) {
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), newStubBackend(t, "Backend"))
+
+	resp := postMessage(t, gatewayURL, "mcp-session-unknown", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown session, got %d", resp.StatusCode)
+	}
+}
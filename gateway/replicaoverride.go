@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Name of the _meta field pinning a tool call to a replica of its backend
+const replicaOverrideMeta = "replica"
+
+// pinnedReplica returns the replica a call's _meta pins it to, or nil when the call isn't
+// pinned. Pins are ignored unless allowReplicaOverride is set; an unknown replica is an error.
+func (g *Gateway) pinnedReplica(backend BackendConfig, req mcp.CallToolRequest) (*replica, error) {
+	if req.Params.Meta == nil {
+		return nil, nil
+	}
+	name, ok := req.Params.Meta.AdditionalFields[replicaOverrideMeta].(string)
+	if !ok || name == "" {
+		return nil, nil
+	}
+	if !g.config.AllowReplicaOverride {
+		log.Printf("⚠️ Ignoring %s replica override %s: allowReplicaOverride is off", backend.Name, name)
+		return nil, nil
+	}
+	for _, r := range g.replicas[backend.Name] {
+		if r.name() == name {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("backend %s has no replica %s", backend.Name, name)
+}
+
+// pinnedReplicaKey identifies a session's connection to a pinned replica
+type pinnedReplicaKey struct {
+	backend string
+	replica string
+}
+
+// pinnedReplicaClient returns the session's connection to a pinned replica, connecting on
+// its first pinned call. The connection bypasses the replica's capacity and circuit
+// breaker, and is closed with the session's other connections.
+func (g *Gateway) pinnedReplicaClient(ctx context.Context, connections *ClientBackendConnections, backend BackendConfig, r *replica) (BackendTransport, error) {
+	key := pinnedReplicaKey{backend: backend.Name, replica: r.name()}
+
+	connections.lock.Lock()
+	defer connections.lock.Unlock()
+	if backendClient, ok := connections.pinned[key]; ok {
+		return backendClient, nil
+	}
+
+	target := backend
+	target.URL = r.URL
+	ctx = context.WithValue(ctx, clientSessionKey{}, connections.ClientSessionID)
+	wrap := func(t transport.Interface) transport.Interface {
+		return g.wrapBackendTransport(backend, t)
+	}
+	clientName := fmt.Sprintf("MCP Gateway (Client %s, pinned)", connections.ClientSessionID)
+	backendClient, _, err := g.connectBackendTransport(ctx, target, clientName, g.wrapOutputSchemaValidation(backend, wrap))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔗 Client %s pinned to %s replica %s", connections.ClientSessionID, backend.Name, r.name())
+	connections.pinned[key] = backendClient
+	return backendClient, nil
+}
+
+// dropPinned closes and forgets a session's connection to a pinned replica, unless it
+// was already replaced
+func (c *ClientBackendConnections) dropPinned(backend BackendConfig, r *replica, backendClient BackendTransport) {
+	key := pinnedReplicaKey{backend: backend.Name, replica: r.name()}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.pinned[key] == backendClient {
+		delete(c.pinned, key)
+		backendClient.Close()
+	}
+}
@@ -0,0 +1,215 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReplicaConfig is one endpoint of a replicated backend
+type ReplicaConfig struct {
+	// Name identifies the replica in a call's replica _meta override (default: its URL)
+	Name string `yaml:"name"`
+	// URL is the replica's streamable HTTP endpoint
+	URL string `yaml:"url"`
+	// Tier orders replicas by preference; lower tiers (e.g. 0 for the local region) are tried first
+	Tier int `yaml:"tier"`
+	// MaxSessions caps the gateway's backend sessions on the replica (including its tool
+	// discovery connection), spilling to the next replica when full; 0 is unlimited
+	MaxSessions int `yaml:"maxSessions"`
+}
+
+// replica tracks the health and load of a single replica endpoint
+type replica struct {
+	ReplicaConfig
+
+	// breaker marks the replica degraded after transport failures
+	breaker *circuitBreaker
+	// score rates the replica's recent calls, to prefer the healthiest within a tier
+	score *healthScorer
+
+	lock     sync.Mutex
+	sessions int
+}
+
+// acquire reserves a session slot, failing when the replica is at capacity or degraded
+func (r *replica) acquire() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.MaxSessions > 0 && r.sessions >= r.MaxSessions {
+		return false
+	}
+	if !r.breaker.allow() {
+		return false
+	}
+	r.sessions++
+	return true
+}
+
+// name returns the replica's name, defaulting to its URL
+func (r *replica) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.URL
+}
+
+// release frees a session slot
+func (r *replica) release() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sessions--
+}
+
+// newReplicas orders a backend's replicas by tier, keeping config order within a tier.
+// A replica is degraded after the configured circuit breaker threshold of transport
+// failures (or the first one when the breaker is disabled).
+func newReplicas(backend BackendConfig, breakerConfig CircuitBreakerConfig, scoring HealthScoreConfig) []*replica {
+	if breakerConfig.FailureThreshold == 0 {
+		breakerConfig.FailureThreshold = 1
+	}
+
+	replicas := make([]*replica, 0, len(backend.Replicas))
+	for _, config := range backend.Replicas {
+		breaker := newCircuitBreaker(fmt.Sprintf("%s replica %s", backend.Name, config.URL), breakerConfig)
+		replicas = append(replicas, &replica{
+			ReplicaConfig: config,
+			breaker:       breaker,
+			score:         newHealthScorer(scoring, breaker),
+		})
+	}
+	sort.SliceStable(replicas, func(i, j int) bool { return replicas[i].Tier < replicas[j].Tier })
+	return replicas
+}
+
+// connectReplica connects to the most preferred replica that is healthy and has capacity,
+// falling back tier by tier. The session slot is held until the client is closed.
+func (g *Gateway) connectReplica(ctx context.Context, backend BackendConfig, clientName string, wrap func(transport.Interface) transport.Interface) (BackendTransport, *mcp.InitializeResult, error) {
+	var errs []error
+	for _, r := range byHealthScore(g.replicas[backend.Name]) {
+		if !r.acquire() {
+			log.Printf("⚠️ Skipping %s replica %s (tier %d): degraded or at capacity", backend.Name, r.URL, r.Tier)
+			continue
+		}
+
+		target := backend
+		target.URL = r.URL
+		transportCreated := false
+		backendClient, serverInfo, err := g.connectBackendTransport(ctx, target, clientName, func(t transport.Interface) transport.Interface {
+			transportCreated = true
+			t = &replicaTransport{Interface: t, replica: r, connected: time.Now()}
+			if wrap != nil {
+				t = wrap(t)
+			}
+			return t
+		})
+		if err != nil {
+			// A created transport releases its slot when the failed client is closed
+			if !transportCreated {
+				r.release()
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		log.Printf("🔗 Routed %s to replica %s (tier %d)", backend.Name, r.URL, r.Tier)
+		return backendClient, serverInfo, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, nil, &BackendInitError{
+			Backend:                  backend.Name,
+			RequestedProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			Err:                      fmt.Errorf("no replica available: all are degraded or at capacity"),
+		}
+	}
+	return nil, nil, errors.Join(errs...)
+}
+
+// byHealthScore orders replicas by tier and, within a tier, from the highest health score
+// to the lowest, keeping config order between equal scores
+func byHealthScore(replicas []*replica) []*replica {
+	scores := make(map[*replica]int, len(replicas))
+	for _, r := range replicas {
+		scores[r] = r.score.score()
+	}
+	ordered := append([]*replica(nil), replicas...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Tier != ordered[j].Tier {
+			return ordered[i].Tier < ordered[j].Tier
+		}
+		return scores[ordered[i]] > scores[ordered[j]]
+	})
+	return ordered
+}
+
+// replicaTransport records transport failures against a replica and frees its
+// session slot when closed
+type replicaTransport struct {
+	transport.Interface
+	replica *replica
+	once    sync.Once
+
+	// connected is when the connection to the replica was made, for its affinity TTL
+	connected time.Time
+
+	lock     sync.Mutex
+	inFlight int
+	retired  bool
+}
+
+func (t *replicaTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	t.lock.Lock()
+	t.inFlight++
+	t.lock.Unlock()
+	defer t.finish()
+
+	started := time.Now()
+	response, err := t.Interface.SendRequest(ctx, request)
+	switch {
+	case err == nil:
+		t.replica.breaker.recordSuccess()
+		t.replica.score.record(time.Since(started), false)
+	case ctx.Err() == nil:
+		t.replica.breaker.recordFailure()
+		t.replica.score.record(time.Since(started), true)
+	}
+	return response, err
+}
+
+func (t *replicaTransport) Close() error {
+	t.once.Do(t.replica.release)
+	return t.Interface.Close()
+}
+
+// retire closes the connection once its in-flight requests finish
+func (t *replicaTransport) retire() {
+	t.lock.Lock()
+	t.retired = true
+	idle := t.inFlight == 0
+	t.lock.Unlock()
+
+	if idle {
+		t.Close()
+	}
+}
+
+// finish ends an in-flight request, closing a retired connection after its last one
+func (t *replicaTransport) finish() {
+	t.lock.Lock()
+	t.inFlight--
+	done := t.retired && t.inFlight == 0
+	t.lock.Unlock()
+
+	if done {
+		t.Close()
+	}
+}
@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newReplicaBackend starts a stub replica whose whoami tool answers with its name.
+// While failing is set, every request is rejected with 503.
+func newReplicaBackend(t *testing.T, name string, failing *atomic.Bool, requests *atomic.Int64) *httptest.Server {
+	stub := newStubBackend(t, name, textTool(mcp.NewTool("whoami"), name))
+	replica := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "degraded", http.StatusServiceUnavailable)
+			return
+		}
+		stub.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(replica.Close)
+	return replica
+}
+
+// TestReplicaTierFallback verifies calls skip a degraded local replica and use the next tier
+func TestReplicaTierFallback(t *testing.T) {
+	var localFailing, remoteFailing atomic.Bool
+	var localRequests, remoteRequests atomic.Int64
+	local := newReplicaBackend(t, "local", &localFailing, &localRequests)
+	remote := newReplicaBackend(t, "remote", &remoteFailing, &remoteRequests)
+	localFailing.Store(true)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name: "server1",
+		Replicas: []ReplicaConfig{
+			{URL: remote.URL, Tier: 1},
+			{URL: local.URL, Tier: 0},
+		},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+
+	// Startup tried the local replica first, which marked it degraded
+	if localRequests.Load() == 0 {
+		t.Fatal("Expected the local replica to be tried first")
+	}
+	localAfterStartup := localRequests.Load()
+
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-whoami", nil)); text != "remote" {
+		t.Fatalf("Expected the call to fall back to the remote replica, got %q", text)
+	}
+	if localRequests.Load() != localAfterStartup {
+		t.Errorf("Expected the degraded local replica to be skipped, it got %d more requests", localRequests.Load()-localAfterStartup)
+	}
+}
+
+// TestReplicaCapacitySpill verifies sessions spill to the next tier when the local replica is full
+func TestReplicaCapacitySpill(t *testing.T) {
+	var failing atomic.Bool
+	var localRequests, remoteRequests atomic.Int64
+	local := newReplicaBackend(t, "local", &failing, &localRequests)
+	remote := newReplicaBackend(t, "remote", &failing, &remoteRequests)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name: "server1",
+		Replicas: []ReplicaConfig{
+			// One session is the gateway's tool discovery connection
+			{URL: local.URL, Tier: 0, MaxSessions: 2},
+			{URL: remote.URL, Tier: 1},
+		},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+
+	first := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, first, "server1-whoami", nil)); text != "local" {
+		t.Fatalf("Expected the first session on the local replica, got %q", text)
+	}
+
+	second := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, second, "server1-whoami", nil)); text != "remote" {
+		t.Fatalf("Expected the second session to spill to the remote replica, got %q", text)
+	}
+}
+
+// TestReplicaOverride verifies a call's _meta replica pins it to that replica when allowed,
+// and is ignored otherwise
+func TestReplicaOverride(t *testing.T) {
+	var failing atomic.Bool
+	var localRequests, remoteRequests atomic.Int64
+	local := newReplicaBackend(t, "local", &failing, &localRequests)
+	remote := newReplicaBackend(t, "remote", &failing, &remoteRequests)
+
+	pinned := func(mcpClient *client.Client, replica string) string {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "server1-whoami"
+		req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{replicaOverrideMeta: replica}}
+		result, err := mcpClient.CallTool(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Failed to call server1-whoami: %v", err)
+		}
+		return extractTextFromResult(result)
+	}
+
+	for _, allowed := range []bool{true, false} {
+		config := DefaultConfig()
+		config.AllowReplicaOverride = allowed
+		config.Backends = []BackendConfig{{
+			Name: "server1",
+			Replicas: []ReplicaConfig{
+				{URL: local.URL, Tier: 0},
+				{Name: "replica-2", URL: remote.URL, Tier: 1},
+			},
+		}}
+		_, gatewayURL := startTestGateway(t, config)
+		mcpClient := newTestClient(t, gatewayURL)
+
+		want := "local"
+		if allowed {
+			want = "remote"
+		}
+		if text := pinned(mcpClient, "replica-2"); text != want {
+			t.Errorf("With allowReplicaOverride %t, expected the pinned call to reach %s, got %q", allowed, want, text)
+		}
+		if text := extractTextFromResult(callTool(t, mcpClient, "server1-whoami", nil)); text != "local" {
+			t.Errorf("Expected unpinned calls to stay on the balanced replica, got %q", text)
+		}
+		if allowed {
+			if text := pinned(mcpClient, "replica-9"); !strings.Contains(text, "has no replica replica-9") {
+				t.Errorf("Expected an unknown replica to be rejected, got %q", text)
+			}
+		}
+	}
+}
+
+// TestReplicaOverrideConnection verifies a session's pinned calls share one connection to
+// the replica, which keeps large integer arguments as written like other connections
+func TestReplicaOverrideConnection(t *testing.T) {
+	local, _ := newArgumentRecordingBackend(t)
+	recording, recorded := newArgumentRecordingBackend(t)
+	remote, initializes := newInitializeCountingBackend(t, recording)
+
+	config := DefaultConfig()
+	config.AllowReplicaOverride = true
+	config.Backends = []BackendConfig{{
+		Name: "server1",
+		Replicas: []ReplicaConfig{
+			{URL: local.URL, Tier: 0},
+			{Name: "replica-2", URL: remote.URL, Tier: 1},
+		},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+	startup := initializes.Load()
+
+	for range 2 {
+		resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-order",`+
+			`"arguments":{"orderId":9007199254740993},"_meta":{"replica":"replica-2"}}}`)
+		resp.Body.Close()
+	}
+
+	if opened := initializes.Load() - startup; opened != 1 {
+		t.Errorf("Expected the pinned calls to share one replica connection, got %d", opened)
+	}
+	calls := recorded()
+	if len(calls) != 2 {
+		t.Fatalf("Expected both pinned calls to reach the replica, got %v", calls)
+	}
+	for _, call := range calls {
+		if call != `{"orderId":9007199254740993}` {
+			t.Errorf("Expected the pinned call to keep the large integer, got %s", call)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestBuiltinToolNameShadowing verifies a backend gateway_info stays reachable alongside the built-in
+func TestBuiltinToolNameShadowing(t *testing.T) {
+	backend1 := newStubBackend(t, "Stub Server 1", textTool(mcp.NewTool("gateway_info"), "backend gateway info"))
+	backend2 := newStubBackend(t, "Stub Server 2", textTool(mcp.NewTool("echo"), "echo"))
+
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), backend1, backend2)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	names := listToolNames(t, mcpClient)
+	if !containsString(names, "gateway_info") || !containsString(names, "server1-gateway_info") {
+		t.Fatalf("Expected both gateway_info and server1-gateway_info, got %v", names)
+	}
+
+	builtin := extractTextFromResult(callTool(t, mcpClient, "gateway_info", nil))
+	if !strings.Contains(builtin, "Gateway Info") {
+		t.Fatalf("Expected built-in gateway_info to win, got %q", builtin)
+	}
+
+	backend := extractTextFromResult(callTool(t, mcpClient, "server1-gateway_info", nil))
+	if backend != "backend gateway info" {
+		t.Fatalf("Expected backend gateway_info under its prefix, got %q", backend)
+	}
+}
+
+// TestPrefixedToolNameCollision verifies tools that collide after prefixing are detected and not double-registered
+func TestPrefixedToolNameCollision(t *testing.T) {
+	// "a" + "-" + "b-c" and "a-b" + "-" + "c" both become "a-b-c"
+	backendA := newStubBackend(t, "Backend A", textTool(mcp.NewTool("b-c"), "from a"))
+	backendAB := newStubBackend(t, "Backend AB", textTool(mcp.NewTool("c"), "from a-b"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "a", URL: backendA.URL},
+		{Name: "a-b", URL: backendAB.URL},
+	}
+	gateway, gatewayURL := startTestGateway(t, config)
+
+	gateway.toolsLock.RLock()
+	route := gateway.toolRoutes["a-b-c"]
+	count := len(gateway.aggregatedTools)
+	gateway.toolsLock.RUnlock()
+
+	if route.Backend != "a" || count != 1 {
+		t.Fatalf("Expected first registration to win with a single tool, got route %+v and %d tools", route, count)
+	}
+
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "a-b-c", nil)); text != "from a" {
+		t.Fatalf("Expected call to route to backend a, got %q", text)
+	}
+}
@@ -0,0 +1,152 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// hopByHopHeaders describe a single connection and are never propagated (RFC 9110 7.6.1)
+var hopByHopHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+// gatewayOwnedHeaders are set by the gateway for its own response and can't be propagated
+var gatewayOwnedHeaders = map[string]bool{
+	"Content-Length":   true,
+	"Content-Type":     true,
+	"Content-Encoding": true,
+	"Mcp-Session-Id":   true,
+}
+
+// validateResponseHeaders checks that no allowlisted header is hop-by-hop or owned by the gateway
+func validateResponseHeaders(names []string) error {
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		if hopByHopHeaders[canonical] {
+			return fmt.Errorf("responseHeaders: %s is a hop-by-hop header", name)
+		}
+		if gatewayOwnedHeaders[canonical] {
+			return fmt.Errorf("responseHeaders: %s is set by the gateway", name)
+		}
+	}
+	return nil
+}
+
+// responseHeadersKey is the context key for the headers collected for a client response
+type responseHeadersKey struct{}
+
+// collectedHeaders are backend response headers to add to a client response
+type collectedHeaders struct {
+	header http.Header
+	lock   sync.Mutex
+}
+
+// propagatingRoundTripper copies allowlisted backend response headers to the
+// client response the outbound request is made for
+type propagatingRoundTripper struct {
+	next  http.RoundTripper
+	allow []string
+}
+
+func (p *propagatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	collected, ok := req.Context().Value(responseHeadersKey{}).(*collectedHeaders)
+	if !ok {
+		return resp, nil
+	}
+
+	// Headers named in Connection are hop-by-hop too
+	connectionHeaders := make(map[string]bool)
+	for _, value := range resp.Header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			connectionHeaders[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+
+	collected.lock.Lock()
+	defer collected.lock.Unlock()
+	for _, name := range p.allow {
+		canonical := http.CanonicalHeaderKey(name)
+		values := resp.Header.Values(canonical)
+		if len(values) == 0 || connectionHeaders[canonical] {
+			continue
+		}
+		collected.header[canonical] = append([]string(nil), values...)
+	}
+	return resp, nil
+}
+
+// propagatingResponseWriter adds collected backend headers just before the response header is written
+type propagatingResponseWriter struct {
+	http.ResponseWriter
+	collected   *collectedHeaders
+	wroteHeader bool
+}
+
+func (w *propagatingResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.collected.lock.Lock()
+		for name, values := range w.collected.header {
+			w.Header()[name] = values
+		}
+		w.collected.lock.Unlock()
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *propagatingResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush keeps streaming (SSE) responses working
+func (w *propagatingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap supports http.ResponseController
+func (w *propagatingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// responseHeadersMiddleware adds allowlisted headers from the backend responses made
+// while handling a request to the client response. Headers arriving after the client
+// response has started (e.g. once it switched to streaming) are dropped.
+func (g *Gateway) responseHeadersMiddleware(next http.Handler) http.Handler {
+	propagating := false
+	for _, backend := range g.config.Backends {
+		propagating = propagating || len(backend.ResponseHeaders) > 0
+	}
+	if !propagating {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		collected := &collectedHeaders{header: make(http.Header)}
+		ctx := context.WithValue(r.Context(), responseHeadersKey{}, collected)
+		next.ServeHTTP(&propagatingResponseWriter{ResponseWriter: w, collected: collected}, r.WithContext(ctx))
+	})
+}
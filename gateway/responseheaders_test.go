@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestResponseHeaderPropagation verifies only allowlisted backend response headers reach the client
+func TestResponseHeaderPropagation(t *testing.T) {
+	mcpServer := server.NewMCPServer("Headers", "1.0.0", server.WithToolCapabilities(true))
+	lookup := textTool(mcp.NewTool("lookup"), "found")
+	mcpServer.AddTool(lookup.tool, lookup.handler)
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("X-Backend-Region", "eu-west")
+		w.Header().Set("X-Internal-Trace", "secret")
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:            "server1",
+		URL:             backend.URL,
+		ResponseHeaders: []string{"cache-control", "X-Backend-Region"},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+
+	mcpClient := newTestClient(t, gatewayURL)
+	sessionID := mcpClient.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"server1-lookup"}}`
+	req, _ := http.NewRequest(http.MethodPost, gatewayURL, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to call tool: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := resp.Header.Get("Cache-Control"); got != "max-age=60" {
+		t.Errorf("Expected Cache-Control to be propagated, got %q", got)
+	}
+	if got := resp.Header.Get("X-Backend-Region"); got != "eu-west" {
+		t.Errorf("Expected X-Backend-Region to be propagated, got %q", got)
+	}
+	if got := resp.Header.Get("X-Internal-Trace"); got != "" {
+		t.Errorf("Expected X-Internal-Trace not to be propagated, got %q", got)
+	}
+	if got := resp.Header.Get("Mcp-Session-Id"); got != "" && got != sessionID {
+		t.Errorf("Expected no backend session ID to leak, got %q", got)
+	}
+}
+
+// TestResponseHeadersRejectHopByHop verifies hop-by-hop and gateway-owned headers can't be allowlisted
+func TestResponseHeadersRejectHopByHop(t *testing.T) {
+	for _, name := range []string{"Connection", "transfer-encoding", "Mcp-Session-Id"} {
+		config := DefaultConfig()
+		config.Backends[0].ResponseHeaders = []string{name}
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected allowlisting %s to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Cache scopes: whose calls a cached result is served to
+const (
+	cacheScopeSession = "session"
+	cacheScopeShared  = "shared"
+)
+
+// Default number of results the cache holds
+const defaultCacheMaxEntries = 10000
+
+// Number of writes between sweeps of expired cache entries
+const resultCacheSweepInterval = 256
+
+// CacheConfig configures caching of successful tool results
+type CacheConfig struct {
+	// Tools enables caching for individual gateway tool names
+	Tools map[string]ToolCacheConfig `yaml:"tools"`
+	// MaxEntries caps the cached results (default 10000); when full, the result
+	// closest to expiry is evicted
+	MaxEntries int `yaml:"maxEntries"`
+}
+
+// ToolCacheConfig configures the result cache for a single tool
+type ToolCacheConfig struct {
+	// TTL is how long a cached result is served
+	TTL time.Duration `yaml:"ttl"`
+	// KeyArgs lists the arguments that form the cache key; empty uses all arguments
+	KeyArgs []string `yaml:"keyArgs"`
+	// Scope is "session" (default) to serve a cached result only to the client session
+	// that made the call, or "shared" to serve it to every session
+	Scope string `yaml:"scope"`
+}
+
+// Validate checks the cache size and each tool's TTL and scope
+func (c CacheConfig) Validate() error {
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("cache.maxEntries must not be negative")
+	}
+	for tool, toolCache := range c.Tools {
+		if toolCache.TTL <= 0 {
+			return fmt.Errorf("cache.tools.%s.ttl must be positive", tool)
+		}
+		switch toolCache.Scope {
+		case "", cacheScopeSession, cacheScopeShared:
+		default:
+			return fmt.Errorf("cache.tools.%s.scope must be %s or %s", tool, cacheScopeSession, cacheScopeShared)
+		}
+	}
+	return nil
+}
+
+// maxEntries returns the cache size, defaulting to 10000
+func (c CacheConfig) maxEntries() int {
+	if c.MaxEntries > 0 {
+		return c.MaxEntries
+	}
+	return defaultCacheMaxEntries
+}
+
+// cacheEntry is a cached tool result
+type cacheEntry struct {
+	result    *mcp.CallToolResult
+	expiresAt time.Time
+}
+
+// resultCache caches successful tool results keyed by tool name, selected arguments and,
+// for session-scoped tools, the client session
+type resultCache struct {
+	config  CacheConfig
+	entries map[string]cacheEntry
+	writes  int
+	lock    sync.Mutex
+}
+
+// newResultCache creates a result cache
+func newResultCache(config CacheConfig) *resultCache {
+	return &resultCache{
+		config:  config,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// key returns the cache key for a session's call, or false if the tool is not cached
+func (c *resultCache) key(toolName, sessionID string, arguments any) (string, bool) {
+	toolConfig, ok := c.config.Tools[toolName]
+	if !ok {
+		return "", false
+	}
+
+	selected := arguments
+	if len(toolConfig.KeyArgs) > 0 {
+		args, _ := arguments.(map[string]any)
+		picked := make(map[string]any, len(toolConfig.KeyArgs))
+		for _, name := range toolConfig.KeyArgs {
+			picked[name] = args[name]
+		}
+		selected = picked
+	}
+
+	// encoding/json sorts map keys, giving a canonical key
+	data, err := json.Marshal(selected)
+	if err != nil {
+		return "", false
+	}
+	key := toolName + "\x00" + string(data)
+	if toolConfig.Scope != cacheScopeShared {
+		key = sessionID + "\x00" + key
+	}
+	return key, true
+}
+
+// get returns a copy of a cached result that has not expired
+func (c *resultCache) get(key string) (*mcp.CallToolResult, bool) {
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.lock.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+	result, err := cloneResult(entry.result)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// put caches a copy of a successful result
+func (c *resultCache) put(key, toolName string, result *mcp.CallToolResult) {
+	if result.IsError {
+		return
+	}
+	cached, err := cloneResult(result)
+	if err != nil {
+		log.Printf("❌ Failed to cache result of %s: %v", toolName, err)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	c.writes++
+	if c.writes%resultCacheSweepInterval == 0 {
+		c.sweep(now)
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.config.maxEntries() {
+		c.sweep(now)
+		if len(c.entries) >= c.config.maxEntries() {
+			c.evict()
+		}
+	}
+	c.entries[key] = cacheEntry{result: cached, expiresAt: now.Add(c.config.Tools[toolName].TTL)}
+}
+
+// sweep removes expired entries; the lock must be held
+func (c *resultCache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// evict removes the entry closest to expiry; the lock must be held
+func (c *resultCache) evict() {
+	var oldest string
+	var oldestExpiry time.Time
+	for key, entry := range c.entries {
+		if oldest == "" || entry.expiresAt.Before(oldestExpiry) {
+			oldest, oldestExpiry = key, entry.expiresAt
+		}
+	}
+	delete(c.entries, oldest)
+}
+
+// cloneResult deep copies a tool result so cached results are never modified
+func cloneResult(result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(data)
+	return mcp.ParseCallToolResult(&raw)
+}
+
+// validateCacheTools checks that every cached tool exists and its key arguments are in its input schema
+func (g *Gateway) validateCacheTools() error {
+	for toolName, toolConfig := range g.config.Cache.Tools {
+		tool, ok := g.lookupTool(toolName)
+		if !ok {
+			return fmt.Errorf("cache.tools.%s: tool not found", toolName)
+		}
+
+		properties, err := schemaProperties(tool)
+		if err != nil {
+			return fmt.Errorf("cache.tools.%s: %w", toolName, err)
+		}
+		for _, name := range toolConfig.KeyArgs {
+			if _, ok := properties[name]; !ok {
+				return fmt.Errorf("cache.tools.%s: key argument %q is not in the tool's input schema", toolName, name)
+			}
+		}
+	}
+	return nil
+}
+
+// schemaProperties returns the properties of a tool's input schema
+func schemaProperties(tool mcp.Tool) (map[string]any, error) {
+	if tool.RawInputSchema == nil {
+		return tool.InputSchema.Properties, nil
+	}
+
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(tool.RawInputSchema, &schema); err != nil {
+		return nil, fmt.Errorf("invalid input schema: %w", err)
+	}
+	return schema.Properties, nil
+}
@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// expensiveTool returns a stub tool taking context and query that counts its calls
+func expensiveTool(calls *atomic.Int64) stubTool {
+	return stubTool{
+		tool: mcp.NewTool("analyze",
+			mcp.WithString("context"),
+			mcp.WithString("query", mcp.Required()),
+		),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			n := calls.Add(1)
+			return mcp.NewToolResultText(fmt.Sprintf("answer %d for %s", n, req.GetString("query", ""))), nil
+		},
+	}
+}
+
+// TestResultCacheKeyArgs verifies the cache hits when only arguments outside the key differ
+func TestResultCacheKeyArgs(t *testing.T) {
+	var calls atomic.Int64
+	backend := newStubBackend(t, "Stub", expensiveTool(&calls))
+
+	config := DefaultConfig()
+	config.Cache.Tools = map[string]ToolCacheConfig{
+		"server1-analyze": {TTL: time.Minute, KeyArgs: []string{"query"}},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	first := callTool(t, mcpClient, "server1-analyze", map[string]interface{}{"context": "blob one", "query": "q"})
+	second := callTool(t, mcpClient, "server1-analyze", map[string]interface{}{"context": "blob two", "query": "q"})
+	if extractTextFromResult(first) != extractTextFromResult(second) {
+		t.Errorf("Expected a cache hit, got %q then %q", extractTextFromResult(first), extractTextFromResult(second))
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 backend call, got %d", calls.Load())
+	}
+
+	callTool(t, mcpClient, "server1-analyze", map[string]interface{}{"context": "blob one", "query": "other"})
+	if calls.Load() != 2 {
+		t.Errorf("Expected a different query to miss the cache, got %d backend calls", calls.Load())
+	}
+}
+
+// TestResultCacheUnknownKeyArg verifies a key argument missing from the tool schema fails startup
+func TestResultCacheUnknownKeyArg(t *testing.T) {
+	var calls atomic.Int64
+	backend := newStubBackend(t, "Stub", expensiveTool(&calls))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL}}
+	config.Cache.Tools = map[string]ToolCacheConfig{
+		"server1-analyze": {TTL: time.Minute, KeyArgs: []string{"qeury"}},
+	}
+
+	_, err := New(config)
+	if err == nil || !strings.Contains(err.Error(), "qeury") {
+		t.Fatalf("Expected startup to fail for unknown key argument, got %v", err)
+	}
+}
+
+// TestResultCacheScope verifies session-scoped results are only served to the calling
+// session, and shared ones to every session
+func TestResultCacheScope(t *testing.T) {
+	for scope, want := range map[string]int64{cacheScopeSession: 2, cacheScopeShared: 1} {
+		t.Run(scope, func(t *testing.T) {
+			var calls atomic.Int64
+			backend := newStubBackend(t, "Stub", expensiveTool(&calls))
+
+			config := DefaultConfig()
+			config.Cache.Tools = map[string]ToolCacheConfig{
+				"server1-analyze": {TTL: time.Minute, Scope: scope},
+			}
+			_, gatewayURL := startTestGateway(t, config, backend)
+
+			first, second := newTestClient(t, gatewayURL), newTestClient(t, gatewayURL)
+			callTool(t, first, "server1-analyze", map[string]interface{}{"query": "q"})
+			callTool(t, first, "server1-analyze", map[string]interface{}{"query": "q"})
+			callTool(t, second, "server1-analyze", map[string]interface{}{"query": "q"})
+			if calls.Load() != want {
+				t.Errorf("Expected %d backend calls, got %d", want, calls.Load())
+			}
+		})
+	}
+}
+
+// TestResultCacheMaxEntries verifies a full cache drops expired results first, then the
+// result closest to expiry
+func TestResultCacheMaxEntries(t *testing.T) {
+	cache := newResultCache(CacheConfig{
+		MaxEntries: 2,
+		Tools: map[string]ToolCacheConfig{
+			"short": {TTL: time.Millisecond},
+			"soon":  {TTL: time.Minute},
+			"late":  {TTL: time.Hour},
+		},
+	})
+	result := mcp.NewToolResultText("ok")
+
+	cache.put("short", "short", result)
+	time.Sleep(5 * time.Millisecond)
+	cache.put("soon", "soon", result)
+	cache.put("late", "late", result)
+	if _, ok := cache.entries["short"]; ok || len(cache.entries) != 2 {
+		t.Fatalf("Expected the expired result to be swept, got %d entries", len(cache.entries))
+	}
+
+	cache.put("late2", "late", result)
+	if _, ok := cache.get("soon"); ok {
+		t.Error("Expected the result closest to expiry to be evicted")
+	}
+	if _, ok := cache.get("late"); !ok {
+		t.Error("Expected the later results to be kept")
+	}
+}
+
+// TestResultCacheValidation verifies negative sizes and unknown scopes are rejected
+func TestResultCacheValidation(t *testing.T) {
+	for _, cache := range []CacheConfig{
+		{MaxEntries: -1},
+		{Tools: map[string]ToolCacheConfig{"server1-echo": {TTL: time.Minute, Scope: "global"}}},
+	} {
+		if err := cache.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", cache)
+		}
+	}
+}
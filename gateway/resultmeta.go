@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Keys added to a tool result's _meta when origin annotation is enabled
+const (
+	resultMetaBackend    = "backend"
+	resultMetaDurationMs = "durationMs"
+)
+
+// shouldAnnotateResult reports whether results from the given tool carry origin details
+func (g *Gateway) shouldAnnotateResult(toolName string) bool {
+	if enabled, ok := g.config.ResultMeta.Tools[toolName]; ok {
+		return enabled
+	}
+	return g.config.ResultMeta.Enabled
+}
+
+// annotateResult records which backend produced a result and how long the call took.
+// Existing _meta fields from the backend are preserved.
+func (g *Gateway) annotateResult(toolName string, route toolRoute, duration time.Duration, result *mcp.CallToolResult) {
+	if result == nil || !g.shouldAnnotateResult(toolName) {
+		return
+	}
+
+	if result.Meta == nil {
+		result.Meta = make(map[string]any)
+	}
+	result.Meta[resultMetaBackend] = route.Backend
+	result.Meta[resultMetaDurationMs] = duration.Milliseconds()
+}
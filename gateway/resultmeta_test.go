@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestResultMetaAnnotation verifies _meta.backend appears only when enabled for the tool
+func TestResultMetaAnnotation(t *testing.T) {
+	backend1 := newStubBackend(t, "Stub Server 1",
+		textTool(mcp.NewTool("echo"), "hello"),
+		textTool(mcp.NewTool("quiet"), "shh"),
+	)
+	backend2 := newStubBackend(t, "Stub Server 2", textTool(mcp.NewTool("echo"), "hello"))
+
+	config := DefaultConfig()
+	_, gatewayURL := startTestGateway(t, config, backend1, backend2)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Disabled by default
+	result := callTool(t, mcpClient, "server1-echo", nil)
+	if _, ok := result.Meta[resultMetaBackend]; ok {
+		t.Fatalf("Expected no _meta.backend when disabled, got %v", result.Meta)
+	}
+
+	// Enabled globally with a per-tool opt-out
+	config.ResultMeta.Enabled = true
+	config.ResultMeta.Tools = map[string]bool{"server1-quiet": false}
+
+	result = callTool(t, mcpClient, "server2-echo", nil)
+	if backend := result.Meta[resultMetaBackend]; backend != "server2" {
+		t.Fatalf("Expected _meta.backend=server2, got %v", result.Meta)
+	}
+	if _, ok := result.Meta[resultMetaDurationMs]; !ok {
+		t.Fatalf("Expected _meta.durationMs to be set, got %v", result.Meta)
+	}
+	if extractTextFromResult(result) != "hello" {
+		t.Fatalf("Expected content to be untouched, got %q", extractTextFromResult(result))
+	}
+
+	result = callTool(t, mcpClient, "server1-quiet", nil)
+	if _, ok := result.Meta[resultMetaBackend]; ok {
+		t.Fatalf("Expected no _meta.backend for opted-out tool, got %v", result.Meta)
+	}
+}
@@ -0,0 +1,213 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RetriesConfig retries backend tool calls that fail with a transient error, such as a
+// dropped connection or a 502/503/504 from a proxy. By default only tools annotated
+// idempotent or read-only are retried; Tools overrides that classification.
+type RetriesConfig struct {
+	// MaxAttempts per call of a retryable tool, including the first (default 1: no retries)
+	MaxAttempts int `yaml:"maxAttempts"`
+	// Backoff before the first retry, doubling for each further retry (default 100ms)
+	Backoff time.Duration `yaml:"backoff"`
+	// Tools classify gateway tool names; the first rule matching a tool applies
+	Tools []ToolRetryRule `yaml:"tools"`
+	// ErrorCodes are JSON-RPC error codes backends return for transient failures, such
+	// as a "resource locked" error; calls failing with them are retried like dropped
+	// connections. Other error codes are never retried.
+	ErrorCodes []int `yaml:"errorCodes"`
+	// Dial retries failed connections to backends, whatever the tool
+	Dial DialRetriesConfig `yaml:"dial"`
+}
+
+// ToolRetryRule overrides whether calls of matching tools are retried
+type ToolRetryRule struct {
+	// Tool is a gateway tool name; path.Match globs are supported
+	Tool string `yaml:"tool"`
+	// Retryable overrides the annotation-based classification when set
+	Retryable *bool `yaml:"retryable"`
+	// MaxAttempts overrides retries.maxAttempts when positive
+	MaxAttempts int `yaml:"maxAttempts"`
+}
+
+// Validate checks the retry settings and tool patterns
+func (c RetriesConfig) Validate() error {
+	if c.MaxAttempts < 0 || c.Backoff < 0 {
+		return fmt.Errorf("retries settings must not be negative")
+	}
+	if err := c.Dial.Validate(); err != nil {
+		return err
+	}
+	for i, rule := range c.Tools {
+		if rule.Tool == "" {
+			return fmt.Errorf("retries.tools[%d]: tool is required", i)
+		}
+		if _, err := path.Match(rule.Tool, ""); err != nil {
+			return fmt.Errorf("retries.tools[%d]: invalid tool pattern %q: %w", i, rule.Tool, err)
+		}
+		if rule.MaxAttempts < 0 {
+			return fmt.Errorf("retries.tools[%d]: maxAttempts must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// backoff returns the configured delay before the first retry
+func (c RetriesConfig) backoff() time.Duration {
+	if c.Backoff == 0 {
+		return 100 * time.Millisecond
+	}
+	return c.Backoff
+}
+
+// retries returns the backend's retries, or the gateway-wide ones when it sets none
+func (g *Gateway) retries(backend BackendConfig) RetriesConfig {
+	if backend.Retries != nil {
+		return *backend.Retries
+	}
+	return g.config.Retries
+}
+
+// maxAttempts returns how many times a call of the tool may be attempted
+func (g *Gateway) maxAttempts(config RetriesConfig, toolName string) int {
+	attempts := max(config.MaxAttempts, 1)
+
+	retryable := false
+	if tool, ok := g.lookupTool(toolName); ok {
+		idempotent := tool.Annotations.IdempotentHint != nil && *tool.Annotations.IdempotentHint
+		retryable = idempotent || g.isReadOnlyTool(tool)
+	}
+
+	for _, rule := range config.Tools {
+		if ok, _ := path.Match(rule.Tool, toolName); !ok {
+			continue
+		}
+		if rule.Retryable != nil {
+			retryable = *rule.Retryable
+		}
+		if rule.MaxAttempts > 0 {
+			attempts = rule.MaxAttempts
+		}
+		break
+	}
+
+	if !retryable {
+		return 1
+	}
+	return attempts
+}
+
+// isTransientError reports whether a failed backend call may succeed if repeated
+func isTransientError(err error) bool {
+	var unexpected *UnexpectedResponseError
+	if errors.As(err, &unexpected) {
+		switch unexpected.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var chaos *ChaosError
+	if errors.As(err, &chaos) {
+		return true
+	}
+
+	// Connection failures; url.Error is a net.Error too, so only network operation
+	// errors and timeouts count
+	var opErr *net.OpError
+	var netErr net.Error
+	return errors.As(err, &opErr) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// backendErrorCodeKey is the context key where the JSON-RPC error code of a failed
+// backend call is stored
+type backendErrorCodeKey struct{}
+
+// backendErrorCode is the JSON-RPC error code a backend call failed with, if any
+type backendErrorCode struct {
+	code int
+	set  bool
+}
+
+// errorCodeTransport records the JSON-RPC error code of a failed request where the
+// context asks for it: mcp-go's client keeps only the error message
+type errorCodeTransport struct {
+	transport.Interface
+}
+
+// SendRequest stores the error code of an error response
+func (t *errorCodeTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	response, err := t.Interface.SendRequest(ctx, request)
+	if err == nil && response.Error != nil {
+		if code, ok := ctx.Value(backendErrorCodeKey{}).(*backendErrorCode); ok {
+			code.code, code.set = response.Error.Code, true
+		}
+	}
+	return response, err
+}
+
+// isRetryableErrorCode reports whether a backend error code is configured as transient
+func (c RetriesConfig) isRetryableErrorCode(code *backendErrorCode) bool {
+	return code.set && slices.Contains(c.ErrorCodes, code.code)
+}
+
+// callToolWithRetries calls a backend tool, retrying transient failures of retryable tools
+// with exponential backoff. All attempts share ctx's deadline (the call's request timeout)
+// as one budget: each attempt gets an equal share of the time remaining for the attempts
+// left, and no retry starts once the budget can't cover its backoff.
+func (g *Gateway) callToolWithRetries(ctx context.Context, toolName, backend string, backendClient BackendTransport, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	backendConfig, _ := g.configuredBackend(backend)
+	retries := g.retries(backendConfig)
+	attempts := g.maxAttempts(retries, toolName)
+	backoff := retries.backoff()
+	deadline, hasDeadline := ctx.Deadline()
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := ctx, func() {}
+		if hasDeadline && attempts > 1 {
+			share := time.Until(deadline) / time.Duration(attempts-attempt+1)
+			attemptCtx, cancel = context.WithTimeout(ctx, share)
+		}
+		code := &backendErrorCode{}
+		attemptCtx = context.WithValue(attemptCtx, backendErrorCodeKey{}, code)
+		result, err := g.chaos.callTool(attemptCtx, backend, backendClient, req)
+		cancel()
+		transient := isTransientError(err) || retries.isRetryableErrorCode(code)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !transient {
+			return result, err
+		}
+		if hasDeadline && time.Until(deadline) <= backoff {
+			log.Printf("❌ Not retrying %s: its deadline budget is exhausted (attempt %d of %d): %v", toolName, attempt, attempts, err)
+			return nil, err
+		}
+
+		log.Printf("⚠️ Retrying %s after transient failure (attempt %d of %d): %v", toolName, attempt, attempts, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,227 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newFlakyBackend serves tools whose first call each fails with a 503 HTML page,
+// as a proxy in front of a restarting backend would. It counts tools/call requests per tool.
+func newFlakyBackend(t *testing.T, tools ...stubTool) (*httptest.Server, func(tool string) int) {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Flaky", "1.0.0")
+	for _, st := range tools {
+		mcpServer.AddTool(st.tool, st.handler)
+	}
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	var lock sync.Mutex
+	calls := make(map[string]int)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message struct {
+			Method string `json:"method"`
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodToolsCall) {
+			lock.Lock()
+			calls[message.Params.Name]++
+			first := calls[message.Params.Name] == 1
+			lock.Unlock()
+			if first {
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				io.WriteString(w, "<html><body>503 Service Unavailable</body></html>")
+				return
+			}
+		}
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	return backend, func(tool string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls[tool]
+	}
+}
+
+// TestRetryToolOverrides verifies a tool marked retryable by config is retried on a transient
+// error while an unmarked (non-idempotent) one isn't
+func TestRetryToolOverrides(t *testing.T) {
+	backend, calls := newFlakyBackend(t,
+		textTool(mcp.NewTool("search"), "found"),
+		textTool(mcp.NewTool("charge"), "charged"),
+		textTool(mcp.NewTool("lookup", mcp.WithIdempotentHintAnnotation(true)), "looked up"),
+	)
+
+	retryable, notRetryable := true, false
+	config := DefaultConfig()
+	config.Retries = RetriesConfig{
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+		Tools: []ToolRetryRule{
+			{Tool: "server1-search*", Retryable: &retryable},
+			{Tool: "server1-lookup", Retryable: &notRetryable},
+		},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-search", nil)); text != "found" {
+		t.Errorf("Expected the retryable tool to succeed on retry, got %q", text)
+	}
+	if calls("search") != 2 {
+		t.Errorf("Expected 2 attempts of search, got %d", calls("search"))
+	}
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-charge", nil)); !strings.Contains(text, "HTTP 503") {
+		t.Errorf("Expected the unmarked tool's failure to be returned, got %q", text)
+	}
+	if calls("charge") != 1 {
+		t.Errorf("Expected 1 attempt of charge, got %d", calls("charge"))
+	}
+
+	// The override wins over the idempotent annotation
+	callTool(t, mcpClient, "server1-lookup", nil)
+	if calls("lookup") != 1 {
+		t.Errorf("Expected 1 attempt of lookup, got %d", calls("lookup"))
+	}
+}
+
+// newLockedBackend serves tools whose first locked calls each fail with a JSON-RPC error
+// of the given code. It counts tools/call requests per tool.
+func newLockedBackend(t *testing.T, code, locked int, tools ...stubTool) (*httptest.Server, func(tool string) int) {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("Locking", "1.0.0")
+	for _, st := range tools {
+		mcpServer.AddTool(st.tool, st.handler)
+	}
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	var lock sync.Mutex
+	calls := make(map[string]int)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(body, &message) == nil && message.Method == string(mcp.MethodToolsCall) {
+			lock.Lock()
+			calls[message.Params.Name]++
+			isLocked := calls[message.Params.Name] <= locked
+			lock.Unlock()
+			if isLocked {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]any{
+					"jsonrpc": mcp.JSONRPC_VERSION,
+					"id":      message.ID,
+					"error":   map[string]any{"code": code, "message": "resource locked"},
+				})
+				return
+			}
+		}
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	return backend, func(tool string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls[tool]
+	}
+}
+
+// TestRetryErrorCodes verifies calls failing with a configured error code are retried
+// while other error codes are returned at once
+func TestRetryErrorCodes(t *testing.T) {
+	retryable := true
+	config := DefaultConfig()
+	config.Retries = RetriesConfig{
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+		Tools:       []ToolRetryRule{{Tool: "*", Retryable: &retryable}},
+		ErrorCodes:  []int{-32050},
+	}
+
+	backend, calls := newLockedBackend(t, -32050, 2, textTool(mcp.NewTool("update"), "updated"))
+	_, gatewayURL := startTestGateway(t, config, backend)
+	if text := extractTextFromResult(callTool(t, newTestClient(t, gatewayURL), "server1-update", nil)); text != "updated" {
+		t.Errorf("Expected the call to succeed once unlocked, got %q", text)
+	}
+	if calls("update") != 3 {
+		t.Errorf("Expected 3 attempts of update, got %d", calls("update"))
+	}
+
+	other, otherCalls := newLockedBackend(t, -32051, 2, textTool(mcp.NewTool("update"), "updated"))
+	_, gatewayURL = startTestGateway(t, config, other)
+	if text := extractTextFromResult(callTool(t, newTestClient(t, gatewayURL), "server1-update", nil)); !strings.Contains(text, "resource locked") {
+		t.Errorf("Expected the unlisted error to be returned, got %q", text)
+	}
+	if otherCalls("update") != 1 {
+		t.Errorf("Expected 1 attempt for an unlisted error code, got %d", otherCalls("update"))
+	}
+}
+
+// TestRetriesShareDeadlineBudget verifies retries of a hanging tool split the request
+// timeout between attempts rather than each getting the full timeout
+func TestRetriesShareDeadlineBudget(t *testing.T) {
+	var calls atomic.Int64
+	hanging := stubTool{
+		tool: mcp.NewTool("slow", mcp.WithIdempotentHintAnnotation(true)),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls.Add(1)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+			}
+			return mcp.NewToolResultText("too late"), nil
+		},
+	}
+	backend := newStubBackend(t, "Slow", hanging)
+
+	const budget = 600 * time.Millisecond
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: backend.URL, Timeouts: BackendTimeouts{Request: budget}}}
+	config.Retries = RetriesConfig{MaxAttempts: 3, Backoff: time.Millisecond}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	started := time.Now()
+	result := callTool(t, mcpClient, "server1-slow", nil)
+	elapsed := time.Since(started)
+
+	if !result.IsError {
+		t.Errorf("Expected the call to fail, got %q", extractTextFromResult(result))
+	}
+	if elapsed > budget+300*time.Millisecond {
+		t.Errorf("Expected the call to finish within its %s budget, took %s", budget, elapsed)
+	}
+	if calls.Load() < 2 {
+		t.Errorf("Expected the budget to be split across retries, got %d attempts", calls.Load())
+	}
+}
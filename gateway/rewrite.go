@@ -0,0 +1,167 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Rewrite phases
+const (
+	RewritePhaseRequest = "request"
+	RewritePhaseResult  = "result"
+)
+
+// Rewrite operations
+const (
+	RewriteOpSet     = "set"
+	RewriteOpDelete  = "delete"
+	RewriteOpReplace = "replace"
+)
+
+// RewriteRule rewrites a JSON field in a tool's call arguments or result
+type RewriteRule struct {
+	// Tool is the gateway tool name; glob patterns are supported
+	Tool string `yaml:"tool"`
+	// Phase is request (arguments, before forwarding) or result (before returning)
+	Phase string `yaml:"phase"`
+	// Path selects the field, e.g. $.user.email or $.content[*].text
+	Path string `yaml:"path"`
+	// Op is set, delete or replace (regex replace on string values)
+	Op string `yaml:"op"`
+	// Value is the new value for set
+	Value any `yaml:"value"`
+	// Pattern and Replacement are the regular expression and replacement for replace
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
+}
+
+// rewriteRule is a validated rewrite rule ready to apply
+type rewriteRule struct {
+	RewriteRule
+	segments []pathSegment
+	pattern  *regexp.Regexp
+}
+
+// compileRewriteRules validates rewrite rules and prepares them for use
+func compileRewriteRules(rules []RewriteRule) ([]rewriteRule, error) {
+	compiled := make([]rewriteRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Tool == "" {
+			return nil, fmt.Errorf("rewrites[%d]: tool is required", i)
+		}
+		if _, err := path.Match(rule.Tool, ""); err != nil {
+			return nil, fmt.Errorf("rewrites[%d]: invalid tool pattern %q: %w", i, rule.Tool, err)
+		}
+		if rule.Phase != RewritePhaseRequest && rule.Phase != RewritePhaseResult {
+			return nil, fmt.Errorf("rewrites[%d]: phase must be %q or %q", i, RewritePhaseRequest, RewritePhaseResult)
+		}
+
+		segments, err := parseJSONPath(rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("rewrites[%d]: %w", i, err)
+		}
+		cr := rewriteRule{RewriteRule: rule, segments: segments}
+
+		switch rule.Op {
+		case RewriteOpSet:
+			if rule.Value == nil {
+				return nil, fmt.Errorf("rewrites[%d]: set requires a value", i)
+			}
+		case RewriteOpDelete:
+		case RewriteOpReplace:
+			if rule.Pattern == "" {
+				return nil, fmt.Errorf("rewrites[%d]: replace requires a pattern", i)
+			}
+			if cr.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("rewrites[%d]: invalid pattern: %w", i, err)
+			}
+		default:
+			return nil, fmt.Errorf("rewrites[%d]: op must be %q, %q or %q", i, RewriteOpSet, RewriteOpDelete, RewriteOpReplace)
+		}
+
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+// apply rewrites the document in place where possible and returns the result
+func (r rewriteRule) apply(doc any) any {
+	switch r.Op {
+	case RewriteOpSet:
+		return applyPath(doc, r.segments, true, func(any, bool) (any, bool) {
+			return r.Value, true
+		})
+	case RewriteOpDelete:
+		return applyPath(doc, r.segments, false, func(any, bool) (any, bool) {
+			return nil, false
+		})
+	default:
+		return applyPath(doc, r.segments, false, func(value any, exists bool) (any, bool) {
+			if s, ok := value.(string); ok {
+				return r.pattern.ReplaceAllString(s, r.Replacement), true
+			}
+			return value, exists
+		})
+	}
+}
+
+// rewriteDocument applies the matching rules for a tool and phase to a JSON-compatible value.
+// The value is copied first so callers' data is never modified; numbers keep their
+// written form.
+func (g *Gateway) rewriteDocument(toolName, phase string, value any) (any, bool, error) {
+	var rules []rewriteRule
+	for _, rule := range g.rewrites {
+		if rule.Phase == phase && matchesAny([]string{rule.Tool}, toolName) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return value, false, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false, err
+	}
+	var doc any
+	if err := decodeNumbers(data, &doc); err != nil {
+		return nil, false, err
+	}
+
+	for _, rule := range rules {
+		doc = rule.apply(doc)
+	}
+	return doc, true, nil
+}
+
+// rewriteArguments applies request rewrite rules to tool call arguments
+func (g *Gateway) rewriteArguments(toolName string, arguments any) (any, error) {
+	rewritten, _, err := g.rewriteDocument(toolName, RewritePhaseRequest, arguments)
+	return rewritten, err
+}
+
+// rewriteResult applies result rewrite rules to a tool call result
+func (g *Gateway) rewriteResult(toolName string, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+	rewritten, changed, err := g.rewriteDocument(toolName, RewritePhaseResult, result)
+	if err != nil || !changed {
+		return result, err
+	}
+
+	data, err := json.Marshal(rewritten)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(data)
+	parsed, err := mcp.ParseCallToolResult(&raw)
+	if err != nil {
+		return nil, err
+	}
+	preciseResultMeta(parsed, raw)
+	log.Printf("✂️ Rewrote result of %s", toolName)
+	return parsed, nil
+}
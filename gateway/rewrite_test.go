@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// applyRules compiles rules and applies them to a JSON document
+func applyRules(t *testing.T, rules []RewriteRule, doc string) any {
+	t.Helper()
+
+	compiled, err := compileRewriteRules(rules)
+	if err != nil {
+		t.Fatalf("Failed to compile rules: %v", err)
+	}
+	var value any
+	if err := json.Unmarshal([]byte(doc), &value); err != nil {
+		t.Fatalf("Invalid test document: %v", err)
+	}
+	for _, rule := range compiled {
+		value = rule.apply(value)
+	}
+	return value
+}
+
+// assertJSON compares a value against the expected JSON document
+func assertJSON(t *testing.T, got any, expected string) {
+	t.Helper()
+
+	var want, normalized any
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("Invalid expected document: %v", err)
+	}
+	data, _ := json.Marshal(got)
+	json.Unmarshal(data, &normalized)
+	if !reflect.DeepEqual(normalized, want) {
+		t.Errorf("Got %s, want %s", data, expected)
+	}
+}
+
+// TestRewriteOperations verifies set, delete and regex replace on nested fields
+func TestRewriteOperations(t *testing.T) {
+	doc := `{"user":{"email":"a@example.com","tier":"gold","tags":["x","y"]},"items":[{"id":1,"ssn":"123-45-6789"},{"id":2,"ssn":"987-65-4321"}]}`
+
+	t.Run("set", func(t *testing.T) {
+		got := applyRules(t, []RewriteRule{
+			{Tool: "*", Phase: RewritePhaseRequest, Path: "$.user.tier", Op: RewriteOpSet, Value: "GOLD"},
+			{Tool: "*", Phase: RewritePhaseRequest, Path: "$.options.region.name", Op: RewriteOpSet, Value: "eu"},
+			{Tool: "*", Phase: RewritePhaseRequest, Path: "$.items[1]['id']", Op: RewriteOpSet, Value: 20},
+		}, doc)
+		assertJSON(t, got, `{"user":{"email":"a@example.com","tier":"GOLD","tags":["x","y"]},"options":{"region":{"name":"eu"}},"items":[{"id":1,"ssn":"123-45-6789"},{"id":20,"ssn":"987-65-4321"}]}`)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		got := applyRules(t, []RewriteRule{
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.user.email", Op: RewriteOpDelete},
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.user.tags[0]", Op: RewriteOpDelete},
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.items[*].ssn", Op: RewriteOpDelete},
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.missing.field", Op: RewriteOpDelete},
+		}, doc)
+		assertJSON(t, got, `{"user":{"tier":"gold","tags":["y"]},"items":[{"id":1},{"id":2}]}`)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		got := applyRules(t, []RewriteRule{
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.items[*].ssn", Op: RewriteOpReplace, Pattern: `\d{3}-\d{2}-(\d{4})`, Replacement: "***-**-$1"},
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.user.email", Op: RewriteOpReplace, Pattern: `^[^@]+`, Replacement: "redacted"},
+			{Tool: "*", Phase: RewritePhaseResult, Path: "$.user.absent", Op: RewriteOpReplace, Pattern: `.*`, Replacement: "x"},
+		}, doc)
+		assertJSON(t, got, `{"user":{"email":"redacted@example.com","tier":"gold","tags":["x","y"]},"items":[{"id":1,"ssn":"***-**-6789"},{"id":2,"ssn":"***-**-4321"}]}`)
+	})
+}
+
+// TestRewriteValidation verifies invalid rules are rejected at startup
+func TestRewriteValidation(t *testing.T) {
+	invalid := map[string]RewriteRule{
+		"missing tool":  {Phase: RewritePhaseRequest, Path: "$.a", Op: RewriteOpDelete},
+		"bad phase":     {Tool: "*", Phase: "before", Path: "$.a", Op: RewriteOpDelete},
+		"bad path":      {Tool: "*", Phase: RewritePhaseRequest, Path: "a.b", Op: RewriteOpDelete},
+		"bad index":     {Tool: "*", Phase: RewritePhaseRequest, Path: "$.a[x]", Op: RewriteOpDelete},
+		"root path":     {Tool: "*", Phase: RewritePhaseRequest, Path: "$", Op: RewriteOpDelete},
+		"bad op":        {Tool: "*", Phase: RewritePhaseRequest, Path: "$.a", Op: "rename"},
+		"set no value":  {Tool: "*", Phase: RewritePhaseRequest, Path: "$.a", Op: RewriteOpSet},
+		"bad pattern":   {Tool: "*", Phase: RewritePhaseResult, Path: "$.a", Op: RewriteOpReplace, Pattern: "("},
+		"no pattern":    {Tool: "*", Phase: RewritePhaseResult, Path: "$.a", Op: RewriteOpReplace},
+		"bad tool glob": {Tool: "[", Phase: RewritePhaseResult, Path: "$.a", Op: RewriteOpDelete},
+	}
+	for name, rule := range invalid {
+		if _, err := compileRewriteRules([]RewriteRule{rule}); err == nil {
+			t.Errorf("%s: expected rule to be rejected", name)
+		}
+	}
+
+	config := DefaultConfig()
+	config.Rewrites = []RewriteRule{invalid["bad op"]}
+	if _, err := New(config); err == nil || !strings.Contains(err.Error(), "rewrite") {
+		t.Errorf("Expected New to reject invalid rewrite rules, got %v", err)
+	}
+}
+
+// TestRewriteToolCall verifies request rules apply before forwarding and result rules before returning
+func TestRewriteToolCall(t *testing.T) {
+	backend := newStubBackend(t, "Stub", stubTool{
+		tool: mcp.NewTool("lookup"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			data, _ := json.Marshal(req.GetArguments())
+			return mcp.NewToolResultText("email=user@example.com args=" + string(data)), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Rewrites = []RewriteRule{
+		{Tool: "server1-lookup", Phase: RewritePhaseRequest, Path: "$.filter.status", Op: RewriteOpSet, Value: "ACTIVE"},
+		{Tool: "server1-*", Phase: RewritePhaseRequest, Path: "$.debug", Op: RewriteOpDelete},
+		{Tool: "server1-lookup", Phase: RewritePhaseResult, Path: "$.content[*].text", Op: RewriteOpReplace, Pattern: `[\w.]+@[\w.]+`, Replacement: "[email]"},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-lookup", map[string]interface{}{
+		"filter": map[string]interface{}{"status": "active", "limit": 5},
+		"debug":  true,
+	})
+	text := extractTextFromResult(result)
+	if text != `email=[email] args={"filter":{"limit":5,"status":"ACTIVE"}}` {
+		t.Errorf("Unexpected rewritten result: %s", text)
+	}
+}
+
+// TestRewriteNumberFidelity verifies large integers keep their precision through tools
+// with request and result rewrite rules
+func TestRewriteNumberFidelity(t *testing.T) {
+	backend, recorded := newArgumentRecordingBackend(t)
+	config := DefaultConfig()
+	config.Rewrites = []RewriteRule{
+		{Tool: "server1-order", Phase: RewritePhaseRequest, Path: "$.debug", Op: RewriteOpDelete},
+		{Tool: "server1-order", Phase: RewritePhaseResult, Path: "$.content[*].text", Op: RewriteOpReplace, Pattern: "ok", Replacement: "done"},
+	}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+
+	resp := postMessage(t, gatewayURL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"server1-order","arguments":{"debug":true,"orderId":9007199254740993}}}`)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if calls := recorded(); len(calls) != 1 || calls[0] != `{"orderId":9007199254740993}` {
+		t.Errorf("Expected the backend to receive the rewritten arguments with the large integer, got %v", calls)
+	}
+	if !strings.Contains(string(body), `"orderId":9007199254740993`) || !strings.Contains(string(body), "done") {
+		t.Errorf("Expected the rewritten result to keep the large integer in _meta, got %s", body)
+	}
+}
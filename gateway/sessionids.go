@@ -0,0 +1,237 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Name of the built-in session ID generator
+const sessionIDGeneratorUUID = "uuid"
+
+// Default prefix of session IDs, matching the IDs mcp-go generates
+const defaultSessionIDPrefix = "mcp-session-"
+
+// Longest client-supplied session ID adopted
+const maxClientSessionIDLength = 128
+
+// Attempts at generating an unused session ID before giving up on uniqueness
+const maxSessionIDAttempts = 10
+
+// SessionIDGenerator creates the IDs of new client sessions, before the configured
+// prefix. IDs must be unique and unguessable, since a session ID is all a client needs
+// to use a session. Implementations must be safe for concurrent use.
+type SessionIDGenerator interface {
+	NewSessionID() string
+}
+
+// SessionIDGeneratorFactory creates a session ID generator from its options
+type SessionIDGeneratorFactory func(options map[string]string) (SessionIDGenerator, error)
+
+// SessionIDsConfig selects how the IDs of new client sessions are created
+type SessionIDsConfig struct {
+	// Generator names a registered generator: uuid (default, random UUIDv4) or a custom one
+	Generator string `yaml:"generator"`
+	// Options are passed to the generator factory
+	Options map[string]string `yaml:"options"`
+	// Prefix starts every session ID (default mcp-session-)
+	Prefix string `yaml:"prefix"`
+	// ClientHeader adopts the ID a client sends in this header of its initialize request,
+	// after the prefix, for correlation with external systems. Only enable it when
+	// clients are trusted: IDs are then as guessable as the clients make them. An ID
+	// that is invalid or already in use is replaced with a generated one.
+	ClientHeader string `yaml:"clientHeader"`
+}
+
+// prefix returns the session ID prefix, defaulting to mcp-session-
+func (c SessionIDsConfig) prefix() string {
+	if c.Prefix == "" {
+		return defaultSessionIDPrefix
+	}
+	return c.Prefix
+}
+
+// Validate checks the generator is registered and the prefix may appear in a header
+func (c SessionIDsConfig) Validate() error {
+	if _, err := lookupSessionIDGenerator(c.generator()); err != nil {
+		return fmt.Errorf("sessions.ids.generator: %w", err)
+	}
+	if !validSessionID(c.prefix()) {
+		return fmt.Errorf("sessions.ids.prefix must only contain visible ASCII characters")
+	}
+	return nil
+}
+
+// generator returns the generator name, defaulting to uuid
+func (c SessionIDsConfig) generator() string {
+	if c.Generator == "" {
+		return sessionIDGeneratorUUID
+	}
+	return c.Generator
+}
+
+var (
+	sessionIDGeneratorsLock sync.RWMutex
+	sessionIDGenerators     = make(map[string]SessionIDGeneratorFactory)
+)
+
+func init() {
+	RegisterSessionIDGenerator(sessionIDGeneratorUUID, func(options map[string]string) (SessionIDGenerator, error) {
+		return uuidGenerator{}, nil
+	})
+}
+
+// RegisterSessionIDGenerator makes a session ID generator available by name to the
+// sessions.ids.generator setting. It is meant to be called from init functions and
+// panics if the name is empty or already registered, or if factory is nil.
+func RegisterSessionIDGenerator(name string, factory SessionIDGeneratorFactory) {
+	sessionIDGeneratorsLock.Lock()
+	defer sessionIDGeneratorsLock.Unlock()
+
+	if name == "" || factory == nil {
+		panic("gateway: RegisterSessionIDGenerator requires a name and a factory")
+	}
+	if _, exists := sessionIDGenerators[name]; exists {
+		panic(fmt.Sprintf("gateway: session ID generator %s registered twice", name))
+	}
+	sessionIDGenerators[name] = factory
+}
+
+// lookupSessionIDGenerator returns the factory registered under name
+func lookupSessionIDGenerator(name string) (SessionIDGeneratorFactory, error) {
+	sessionIDGeneratorsLock.RLock()
+	defer sessionIDGeneratorsLock.RUnlock()
+
+	factory, ok := sessionIDGenerators[name]
+	if !ok {
+		names := make([]string, 0, len(sessionIDGenerators))
+		for registered := range sessionIDGenerators {
+			names = append(names, registered)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown session ID generator %q (registered: %v)", name, names)
+	}
+	return factory, nil
+}
+
+// uuidGenerator is the built-in generator of random (version 4) UUIDs
+type uuidGenerator struct{}
+
+func (uuidGenerator) NewSessionID() string {
+	return uuid.NewString()
+}
+
+// validSessionID reports whether id only has the visible ASCII characters a session ID
+// may contain
+func validSessionID(id string) bool {
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// sessionIDs creates session IDs with the configured generator and prefix, or adopts
+// the ID a client requested
+type sessionIDs struct {
+	generator SessionIDGenerator
+	prefix    string
+
+	// handoff is held by an initialize request from its arrival until its session ID
+	// is created, so the ID it requested reaches Generate, which gets no request
+	handoff sync.Mutex
+	pending *sessionIDRequest
+}
+
+// sessionIDRequest is the ID an initialize request asked for, possibly none
+type sessionIDRequest struct {
+	id   string
+	done bool
+}
+
+// newSessionIDs creates the configured session ID source
+func newSessionIDs(config SessionIDsConfig) (*sessionIDs, error) {
+	factory, err := lookupSessionIDGenerator(config.generator())
+	if err != nil {
+		return nil, err
+	}
+	generator, err := factory(config.Options)
+	if err != nil {
+		return nil, fmt.Errorf("session ID generator %s: %w", config.generator(), err)
+	}
+	return &sessionIDs{generator: generator, prefix: config.prefix()}, nil
+}
+
+// hold hands the ID an initialize request asked for to the next Generate. The returned
+// function must be called once the request is handled.
+func (i *sessionIDs) hold(id string) func() {
+	i.handoff.Lock()
+	request := &sessionIDRequest{id: id}
+	i.pending = request
+	return func() {
+		if !request.done {
+			request.done = true
+			i.pending = nil
+			i.handoff.Unlock()
+		}
+	}
+}
+
+// takeRequested returns the ID the request being initialized asked for, if any. It is
+// called by Generate, on the goroutine holding the handoff.
+func (i *sessionIDs) takeRequested() string {
+	request := i.pending
+	if request == nil {
+		return ""
+	}
+	request.done = true
+	i.pending = nil
+	i.handoff.Unlock()
+	return request.id
+}
+
+// newID returns the requested ID if it is valid and not taken, or else a generated one
+func (i *sessionIDs) newID(requested string, taken func(id string) bool) string {
+	if requested != "" {
+		id := i.prefix + requested
+		switch {
+		case len(requested) > maxClientSessionIDLength || !validSessionID(requested):
+			log.Printf("⚠️ Ignoring client-supplied session ID %q: it must be at most %d visible ASCII characters",
+				requested, maxClientSessionIDLength)
+		case taken(id):
+			log.Printf("⚠️ Ignoring client-supplied session ID %s: it is already in use", id)
+		default:
+			return id
+		}
+	}
+
+	id := i.prefix + i.generator.NewSessionID()
+	for attempt := 1; taken(id) && attempt < maxSessionIDAttempts; attempt++ {
+		id = i.prefix + i.generator.NewSessionID()
+	}
+	if taken(id) {
+		log.Printf("❌ Session ID generator returned IDs already in use %d times; reusing %s", maxSessionIDAttempts, id)
+	}
+	return id
+}
+
+// sessionIDMiddleware passes the session ID a client requests in its initialize request
+// to the session store. Every initialize request then waits for the one before it to
+// get its session ID.
+func (g *Gateway) sessionIDMiddleware(next http.Handler) http.Handler {
+	header := g.config.Sessions.IDs.ClientHeader
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header == "" || r.Method != http.MethodPost || r.Header.Get("Mcp-Session-Id") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		release := g.sessions.ids.hold(r.Header.Get(header))
+		defer release()
+		next.ServeHTTP(w, r)
+	})
+}
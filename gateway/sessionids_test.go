@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Header carrying the client's requested session ID in these tests
+const testSessionIDHeader = "X-Request-Session-Id"
+
+// initializeWithSessionID initializes a raw session, requesting sessionID when set, and
+// returns the session ID the gateway assigned
+func initializeWithSessionID(t *testing.T, url, sessionID string) string {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+
+		mcp.LATEST_PROTOCOL_VERSION+`","capabilities":{},"clientInfo":{"name":"raw","version":"1.0.0"}}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(testSessionIDHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	assigned := resp.Header.Get("Mcp-Session-Id")
+	if assigned == "" {
+		t.Fatalf("Initialize returned no session (HTTP %d)", resp.StatusCode)
+	}
+	return assigned
+}
+
+// TestSessionIDFormat verifies generated session IDs are the prefix and a random UUID,
+// unique across sessions
+func TestSessionIDFormat(t *testing.T) {
+	config := DefaultConfig()
+	config.Sessions.IDs.Prefix = "gw-"
+	_, gatewayURL := startTestGateway(t, config, newStubBackend(t, "Backend"))
+
+	format := regexp.MustCompile(`^gw-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		sessionID := initializeWithSessionID(t, gatewayURL, "")
+		if !format.MatchString(sessionID) {
+			t.Errorf("Expected a prefixed UUIDv4 session ID, got %s", sessionID)
+		}
+		if seen[sessionID] {
+			t.Errorf("Expected unique session IDs, got %s twice", sessionID)
+		}
+		seen[sessionID] = true
+	}
+}
+
+// TestClientSessionID verifies a trusted client's requested session ID is adopted, and
+// replaced with a generated one when invalid or already used by another session
+func TestClientSessionID(t *testing.T) {
+	config := DefaultConfig()
+	config.Sessions.IDs.ClientHeader = testSessionIDHeader
+	gateway, gatewayURL := startTestGateway(t, config, newStubBackend(t, "Backend"))
+
+	if sessionID := initializeWithSessionID(t, gatewayURL, "trace-42"); sessionID != "mcp-session-trace-42" {
+		t.Errorf("Expected the client's session ID to be adopted, got %s", sessionID)
+	}
+
+	// A second client asking for the same ID must not share the first one's session
+	reused := initializeWithSessionID(t, gatewayURL, "trace-42")
+	if reused == "mcp-session-trace-42" || !strings.HasPrefix(reused, defaultSessionIDPrefix) {
+		t.Errorf("Expected a generated session ID for a taken one, got %s", reused)
+	}
+	if invalid := initializeWithSessionID(t, gatewayURL, "has space"); strings.Contains(invalid, " ") {
+		t.Errorf("Expected a generated session ID for an invalid one, got %q", invalid)
+	}
+	if generated := initializeWithSessionID(t, gatewayURL, ""); !strings.HasPrefix(generated, defaultSessionIDPrefix) {
+		t.Errorf("Expected a generated session ID without the header, got %s", generated)
+	}
+
+	// The handoff is released between requests
+	if !gateway.sessions.ids.handoff.TryLock() {
+		t.Fatal("Expected the session ID handoff to be released after initialize")
+	}
+	gateway.sessions.ids.handoff.Unlock()
+}
+
+// TestClientSessionIDIgnoredByDefault verifies the requested session ID is ignored
+// unless clientHeader is configured
+func TestClientSessionIDIgnoredByDefault(t *testing.T) {
+	_, gatewayURL := startTestGateway(t, DefaultConfig(), newStubBackend(t, "Backend"))
+
+	if sessionID := initializeWithSessionID(t, gatewayURL, "trace-42"); sessionID == "mcp-session-trace-42" {
+		t.Error("Expected the requested session ID to be ignored without clientHeader")
+	}
+}
+
+// counterGenerator numbers sessions, for testing custom generators
+type counterGenerator struct {
+	next atomic.Int64
+}
+
+func (c *counterGenerator) NewSessionID() string {
+	return "n" + strings.Repeat("0", int(c.next.Add(1)))
+}
+
+// TestCustomSessionIDGenerator verifies a registered generator is selected by name and
+// that an unknown one is rejected
+func TestCustomSessionIDGenerator(t *testing.T) {
+	RegisterSessionIDGenerator("test-counter", func(options map[string]string) (SessionIDGenerator, error) {
+		return &counterGenerator{}, nil
+	})
+
+	config := DefaultConfig()
+	config.Sessions.IDs = SessionIDsConfig{Generator: "test-counter", Prefix: "s-"}
+	_, gatewayURL := startTestGateway(t, config, newStubBackend(t, "Backend"))
+	if sessionID := initializeWithSessionID(t, gatewayURL, ""); sessionID != "s-n0" {
+		t.Errorf("Expected the custom generator's ID, got %s", sessionID)
+	}
+
+	config = DefaultConfig()
+	config.Sessions.IDs.Generator = "missing"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an unknown session ID generator to be rejected")
+	}
+	config = DefaultConfig()
+	config.Sessions.IDs.Prefix = "bad prefix"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a session ID prefix with spaces to be rejected")
+	}
+}
+
+// TestSessionIDCollision verifies a generator returning a taken ID is asked again
+func TestSessionIDCollision(t *testing.T) {
+	ids := &sessionIDs{generator: &counterGenerator{}, prefix: "s-"}
+	taken := map[string]bool{"s-n0": true, "s-n00": true}
+	if id := ids.newID("", func(id string) bool { return taken[id] }); id != "s-n000" {
+		t.Errorf("Expected the first unused ID, got %s", id)
+	}
+}
@@ -0,0 +1,353 @@
+package gateway
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// What a new session does when the session cap is reached
+const (
+	sessionOverflowEvict  = "evict"
+	sessionOverflowReject = "reject"
+)
+
+// sessionState tracks a gateway client session
+type sessionState struct {
+	// lastUsed is when the client last made a request in the session
+	lastUsed time.Time
+	// disconnectedAt is set when the client closes the session and zero while it is connected
+	disconnectedAt time.Time
+	// toolPriority holds the client's tool priority patterns from initialize
+	toolPriority []string
+	// deprecationWarned holds the deprecated tools the client has been warned about
+	deprecationWarned map[string]bool
+	// elicitation is set when the client declared the elicitation capability
+	elicitation bool
+	// quotaUsage counts the session's calls by quota rule index
+	quotaUsage map[int]*quotaUsage
+	// initializeResult is the session's initialize result, set once initialized is closed
+	initializeResult *mcp.InitializeResult
+	initialized      chan struct{}
+}
+
+// sessionStore tracks gateway client sessions so that a client which disconnects can
+// resume its session, and keep its backend sessions, within a grace period.
+// It implements server.SessionIdManager.
+type sessionStore struct {
+	grace       time.Duration
+	maxSessions int
+	ids         *sessionIDs
+	sessions    map[string]*sessionState
+	lock        sync.Mutex
+
+	// evicted remembers recently evicted session IDs, oldest first, so their
+	// clients can be told why the session ended
+	evicted    []string
+	evictedSet map[string]bool
+
+	// onExpire releases the resources held for a session that can no longer be resumed
+	onExpire func(sessionID string)
+
+	// metrics receives session counts
+	metrics MetricsSink
+}
+
+// newSessionStore creates a session store; a grace period of zero disables resumption
+func newSessionStore(config SessionsConfig, onExpire func(sessionID string)) *sessionStore {
+	return &sessionStore{
+		ids:         &sessionIDs{generator: uuidGenerator{}, prefix: defaultSessionIDPrefix},
+		grace:       config.ResumeGracePeriod,
+		maxSessions: config.MaxSessions,
+		sessions:    make(map[string]*sessionState),
+		evictedSet:  make(map[string]bool),
+		onExpire:    onExpire,
+		metrics:     multiSink{},
+	}
+}
+
+// Generate creates a new session ID, adopting the one the client asked for when allowed,
+// expiring any disconnected sessions past their grace
+// period and evicting the least recently used sessions beyond the session cap
+func (s *sessionStore) Generate() string {
+	requested := s.ids.takeRequested()
+
+	s.lock.Lock()
+	sessionID := s.ids.newID(requested, func(id string) bool {
+		_, taken := s.sessions[id]
+		return taken || s.evictedSet[id]
+	})
+	now := time.Now()
+	s.sessions[sessionID] = &sessionState{lastUsed: now, initialized: make(chan struct{})}
+	expired := s.removeExpiredLocked(now)
+	expired = append(expired, s.evictLocked(sessionID)...)
+	active := len(s.sessions)
+	s.lock.Unlock()
+
+	s.metrics.Counter(metricSessionsCreated, 1, nil)
+	s.metrics.Gauge(metricSessionsActive, float64(active), nil)
+
+	for _, id := range expired {
+		s.expire(id)
+	}
+	return sessionID
+}
+
+// Validate reports unknown sessions as terminated so the client re-initializes
+func (s *sessionStore) Validate(sessionID string) (isTerminated bool, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.sessions[sessionID]
+	return !ok, nil
+}
+
+// Terminate marks the session disconnected, or ends it immediately when resumption is disabled
+func (s *sessionStore) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	s.lock.Lock()
+	state, ok := s.sessions[sessionID]
+	if !ok {
+		s.lock.Unlock()
+		return false, nil
+	}
+	if s.grace > 0 {
+		state.disconnectedAt = time.Now()
+		s.lock.Unlock()
+		log.Printf("🔌 Client %s disconnected, keeping backend sessions for %s", sessionID, s.grace)
+		return false, nil
+	}
+	delete(s.sessions, sessionID)
+	s.lock.Unlock()
+
+	s.expire(sessionID)
+	return false, nil
+}
+
+// resume reactivates a disconnected session within the grace period.
+// It returns false if the session is unknown or has expired.
+func (s *sessionStore) resume(sessionID string) bool {
+	s.lock.Lock()
+	state, ok := s.sessions[sessionID]
+	if !ok {
+		s.lock.Unlock()
+		return false
+	}
+	if state.disconnectedAt.IsZero() {
+		state.lastUsed = time.Now()
+		s.lock.Unlock()
+		return true
+	}
+	if time.Since(state.disconnectedAt) <= s.grace {
+		state.disconnectedAt = time.Time{}
+		state.lastUsed = time.Now()
+		s.lock.Unlock()
+		log.Printf("🔗 Client %s resumed its session", sessionID)
+		return true
+	}
+	delete(s.sessions, sessionID)
+	s.lock.Unlock()
+
+	s.expire(sessionID)
+	return false
+}
+
+// setToolPriority records a session's tool priority patterns
+func (s *sessionStore) setToolPriority(sessionID string, patterns []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if state, ok := s.sessions[sessionID]; ok {
+		state.toolPriority = patterns
+	}
+}
+
+// setElicitation records that a session's client supports elicitation
+func (s *sessionStore) setElicitation(sessionID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if state, ok := s.sessions[sessionID]; ok {
+		state.elicitation = true
+	}
+}
+
+// setInitializeResult records the result of a session's initialize
+func (s *sessionStore) setInitializeResult(sessionID string, result *mcp.InitializeResult) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if state, ok := s.sessions[sessionID]; ok && state.initializeResult == nil {
+		state.initializeResult = result
+		close(state.initialized)
+	}
+}
+
+// initializeResult returns a session's initialize result, waiting up to wait for an
+// initialize still in progress
+func (s *sessionStore) initializeResult(ctx context.Context, sessionID string, wait time.Duration) (*mcp.InitializeResult, bool) {
+	s.lock.Lock()
+	state, ok := s.sessions[sessionID]
+	s.lock.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-state.initialized:
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return state.initializeResult, true
+}
+
+// protocolVersion returns the protocol version a session's client negotiated, or "" before
+// its initialize has finished
+func (s *sessionStore) protocolVersion(sessionID string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if state, ok := s.sessions[sessionID]; ok && state.initializeResult != nil {
+		return state.initializeResult.ProtocolVersion
+	}
+	return ""
+}
+
+// supportsElicitation reports whether a session's client supports elicitation
+func (s *sessionStore) supportsElicitation(sessionID string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	state, ok := s.sessions[sessionID]
+	return ok && state.elicitation
+}
+
+// toolPriority returns a session's tool priority patterns
+func (s *sessionStore) toolPriority(sessionID string) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if state, ok := s.sessions[sessionID]; ok {
+		return state.toolPriority
+	}
+	return nil
+}
+
+// firstDeprecatedCall reports whether a session hasn't been warned about a deprecated
+// tool yet, recording that it now has
+func (s *sessionStore) firstDeprecatedCall(sessionID, tool string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	state, ok := s.sessions[sessionID]
+	if !ok || state.deprecationWarned[tool] {
+		return false
+	}
+	if state.deprecationWarned == nil {
+		state.deprecationWarned = make(map[string]bool)
+	}
+	state.deprecationWarned[tool] = true
+	return true
+}
+
+// removeExpiredLocked removes disconnected sessions past the grace period and returns their IDs
+func (s *sessionStore) removeExpiredLocked(now time.Time) []string {
+	var expired []string
+	for id, state := range s.sessions {
+		if !state.disconnectedAt.IsZero() && now.Sub(state.disconnectedAt) > s.grace {
+			delete(s.sessions, id)
+			expired = append(expired, id)
+		}
+	}
+	return expired
+}
+
+// evictLocked removes the least recently used sessions other than keep while over the
+// session cap and returns their IDs
+func (s *sessionStore) evictLocked(keep string) []string {
+	if s.maxSessions <= 0 {
+		return nil
+	}
+
+	var evicted []string
+	for len(s.sessions) > s.maxSessions {
+		oldest := ""
+		for id, state := range s.sessions {
+			if id != keep && (oldest == "" || state.lastUsed.Before(s.sessions[oldest].lastUsed)) {
+				oldest = id
+			}
+		}
+		if oldest == "" {
+			break
+		}
+		delete(s.sessions, oldest)
+		evicted = append(evicted, oldest)
+		log.Printf("⚠️ Evicting least recently used session %s: limit of %d sessions reached", oldest, s.maxSessions)
+
+		// Remember as many evicted sessions as may be active
+		s.evicted = append(s.evicted, oldest)
+		s.evictedSet[oldest] = true
+		if len(s.evicted) > s.maxSessions {
+			delete(s.evictedSet, s.evicted[0])
+			s.evicted = s.evicted[1:]
+		}
+	}
+	return evicted
+}
+
+// wasEvicted reports whether a session recently ended to make room for another
+func (s *sessionStore) wasEvicted(sessionID string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.evictedSet[sessionID]
+}
+
+// full reports whether a new session would exceed the session cap
+func (s *sessionStore) full() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.maxSessions > 0 && len(s.sessions) >= s.maxSessions
+}
+
+// expire releases the resources held for a removed session
+func (s *sessionStore) expire(sessionID string) {
+	log.Printf("⚠️ Session %s ended", sessionID)
+	s.lock.Lock()
+	active := len(s.sessions)
+	s.lock.Unlock()
+	s.metrics.Counter(metricSessionsEnded, 1, nil)
+	s.metrics.Gauge(metricSessionsActive, float64(active), nil)
+	if s.onExpire != nil {
+		s.onExpire(sessionID)
+	}
+}
+
+// sessionResumeMiddleware resumes disconnected sessions and rejects expired or unknown ones
+func (g *Gateway) sessionResumeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get("Mcp-Session-Id")
+		if sessionID != "" && r.Method != http.MethodDelete && !g.sessions.resume(sessionID) {
+			if g.sessions.wasEvicted(sessionID) {
+				http.Error(w, "Session evicted: the gateway's session limit was reached, initialize a new session", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Session expired: initialize a new session", http.StatusNotFound)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// closeClientConnections closes and forgets the backend connections held for a client session
+func (g *Gateway) closeClientConnections(clientSessionID string) {
+	g.connectionsLock.Lock()
+	connections, ok := g.clientConnections[clientSessionID]
+	delete(g.clientConnections, clientSessionID)
+	g.connectionsLock.Unlock()
+
+	if ok {
+		connections.Close()
+	}
+}
@@ -0,0 +1,230 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// counterTool returns a stub tool that counts calls per backend session
+func counterTool() stubTool {
+	var lock sync.Mutex
+	counts := make(map[string]int)
+	return stubTool{
+		tool: mcp.NewTool("count"),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			sessionID := server.ClientSessionFromContext(ctx).SessionID()
+			lock.Lock()
+			defer lock.Unlock()
+			counts[sessionID]++
+			return mcp.NewToolResultText(fmt.Sprintf("%d", counts[sessionID])), nil
+		},
+	}
+}
+
+// waitForDisconnect waits until the gateway has processed the client's session close
+func waitForDisconnect(t *testing.T, gateway *Gateway, sessionID string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		gateway.sessions.lock.Lock()
+		state, ok := gateway.sessions.sessions[sessionID]
+		disconnected := !ok || !state.disconnectedAt.IsZero()
+		gateway.sessions.lock.Unlock()
+		if disconnected {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Session %s was not disconnected", sessionID)
+}
+
+// callToolInSession calls a tool on the gateway by presenting an existing session ID without re-initializing
+func callToolInSession(t *testing.T, gatewayURL, sessionID, name string) string {
+	t.Helper()
+
+	httpTransport, err := transport.NewStreamableHTTP(gatewayURL,
+		transport.WithHTTPHeaders(map[string]string{"Mcp-Session-Id": sessionID}))
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := httpTransport.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  string(mcp.MethodToolsCall),
+		Params:  map[string]any{"name": name},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call %s in session %s: %v", name, sessionID, err)
+	}
+
+	var result struct {
+		Content []mcp.TextContent `json:"content"`
+	}
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(result.Content) == 0 {
+		return ""
+	}
+	return result.Content[0].Text
+}
+
+// TestSessionResumption verifies a client can resume its session and backend state after disconnecting
+func TestSessionResumption(t *testing.T) {
+	backend := newStubBackend(t, "Stateful", counterTool())
+
+	config := DefaultConfig()
+	config.Sessions.ResumeGracePeriod = time.Minute
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	mcpClient := newTestClient(t, gatewayURL)
+	sessionID := mcpClient.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-count", nil)); text != "1" {
+		t.Fatalf("Expected first call to return 1, got %q", text)
+	}
+
+	// Drop the connection; the gateway keeps the backend session for the grace period
+	mcpClient.Close()
+	waitForDisconnect(t, gateway, sessionID)
+
+	if text := callToolInSession(t, gatewayURL, sessionID, "server1-count"); text != "2" {
+		t.Fatalf("Expected resumed session to continue backend state with 2, got %q", text)
+	}
+}
+
+// TestSessionResumptionExpired verifies sessions past the grace period are rejected as expired
+func TestSessionResumptionExpired(t *testing.T) {
+	backend := newStubBackend(t, "Stateful", counterTool())
+
+	config := DefaultConfig()
+	config.Sessions.ResumeGracePeriod = 10 * time.Millisecond
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	mcpClient := newTestClient(t, gatewayURL)
+	sessionID := mcpClient.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+	callTool(t, mcpClient, "server1-count", nil)
+
+	mcpClient.Close()
+	waitForDisconnect(t, gateway, sessionID)
+	time.Sleep(50 * time.Millisecond)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"server1-count"}}`
+	req, _ := http.NewRequest(http.MethodPost, gatewayURL, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(string(message), "Session expired") {
+		t.Fatalf("Expected 404 session expired, got %d: %s", resp.StatusCode, message)
+	}
+
+	gateway.connectionsLock.RLock()
+	_, stillConnected := gateway.clientConnections[sessionID]
+	gateway.connectionsLock.RUnlock()
+	if stillConnected {
+		t.Error("Expected backend connections of the expired session to be closed")
+	}
+}
+
+// postInSession posts a tools/call in a session and returns the status code and body
+func postInSession(t *testing.T, gatewayURL, sessionID, name string) (int, string) {
+	t.Helper()
+
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q}}`, name)
+	req, _ := http.NewRequest(http.MethodPost, gatewayURL, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(message)
+}
+
+// TestMaxSessionsEvictsLeastRecentlyUsed verifies a session beyond the cap evicts the least
+// recently used session, whose next request is rejected with a clear error
+func TestMaxSessionsEvictsLeastRecentlyUsed(t *testing.T) {
+	backend := newStubBackend(t, "Stateful", counterTool())
+
+	config := DefaultConfig()
+	config.Sessions.MaxSessions = 2
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+
+	first := newTestClient(t, gatewayURL)
+	second := newTestClient(t, gatewayURL)
+	firstID := first.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+	secondID := second.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+	callTool(t, second, "server1-count", nil)
+
+	// Using the first session makes the second the least recently used
+	callTool(t, first, "server1-count", nil)
+	third := newTestClient(t, gatewayURL)
+
+	status, message := postInSession(t, gatewayURL, secondID, "server1-count")
+	if status != http.StatusNotFound || !strings.Contains(message, "Session evicted") {
+		t.Fatalf("Expected 404 session evicted for the LRU session, got %d: %s", status, message)
+	}
+	gateway.connectionsLock.RLock()
+	_, stillConnected := gateway.clientConnections[secondID]
+	gateway.connectionsLock.RUnlock()
+	if stillConnected {
+		t.Error("Expected backend connections of the evicted session to be closed")
+	}
+
+	if text := callToolInSession(t, gatewayURL, firstID, "server1-count"); text != "2" {
+		t.Errorf("Expected the recently used session to keep its backend state, got %q", text)
+	}
+	if text := extractTextFromResult(callTool(t, third, "server1-count", nil)); text != "1" {
+		t.Errorf("Expected the new session to work, got %q", text)
+	}
+}
+
+// TestMaxSessionsReject verifies the reject policy refuses new sessions at the cap
+func TestMaxSessionsReject(t *testing.T) {
+	backend := newStubBackend(t, "Stateful", counterTool())
+
+	config := DefaultConfig()
+	config.Sessions.MaxSessions = 1
+	config.Sessions.Overflow = sessionOverflowReject
+	_, gatewayURL := startTestGateway(t, config, backend)
+
+	first := newTestClient(t, gatewayURL)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`
+	resp, err := http.Post(gatewayURL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 for a session beyond the cap, got %d", resp.StatusCode)
+	}
+
+	if text := extractTextFromResult(callTool(t, first, "server1-count", nil)); text != "1" {
+		t.Errorf("Expected the existing session to be unaffected, got %q", text)
+	}
+}
@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ShadowConfig mirrors a fraction of a tool's calls to a canary backend
+type ShadowConfig struct {
+	// CanaryURL is the streamable HTTP endpoint of the canary backend
+	CanaryURL string `yaml:"canaryURL"`
+	// Fraction of calls to mirror, between 0 and 1
+	Fraction float64 `yaml:"fraction"`
+	// Compare controls how results are normalized before comparing
+	Compare ShadowCompareConfig `yaml:"compare"`
+}
+
+// shadowStats counts mirrored calls and their outcomes
+type shadowStats struct {
+	calls         atomic.Int64
+	discrepancies atomic.Int64
+	errors        atomic.Int64
+}
+
+// shadower sends copies of tool calls to canary backends and compares the results
+type shadower struct {
+	gateway *Gateway
+	stats   shadowStats
+
+	// Result comparators keyed by gateway tool name
+	comparators map[string]*resultComparator
+
+	// Canary clients keyed by canary URL, shared by all client sessions
+	clients map[string]BackendTransport
+	// Recent discrepancy reports, oldest first
+	reports []shadowDiscrepancy
+	lock    sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// validateShadowTools checks that every shadowed tool exists and is annotated idempotent or read-only
+func (g *Gateway) validateShadowTools() error {
+	for toolName := range g.config.Shadow {
+		tool, ok := g.lookupTool(toolName)
+		if !ok {
+			return fmt.Errorf("shadow.%s: tool not found", toolName)
+		}
+		idempotent := tool.Annotations.IdempotentHint != nil && *tool.Annotations.IdempotentHint
+		readOnly := tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+		if !idempotent && !readOnly {
+			return fmt.Errorf("shadow.%s: only idempotent or read-only tools can be shadowed", toolName)
+		}
+	}
+	return nil
+}
+
+// canaryClient returns the shared client for a canary backend, connecting on first use
+func (s *shadower) canaryClient(ctx context.Context, toolName string, canaryURL string) (BackendTransport, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if canaryClient, ok := s.clients[canaryURL]; ok {
+		return canaryClient, nil
+	}
+
+	canary := BackendConfig{Name: "canary-" + toolName, URL: canaryURL}
+	canaryClient, _, err := s.gateway.connectBackend(ctx, canary, "MCP Gateway (Canary)", nil)
+	if err != nil {
+		return nil, err
+	}
+	s.clients[canaryURL] = canaryClient
+	return canaryClient, nil
+}
+
+// maybeShadow mirrors a sampled call to the tool's canary in the background. The primary
+// result is compared with the canary's; the client never sees the canary's outcome.
+// The primary result is copied before returning, as the gateway goes on to decorate it
+// for the client.
+func (s *shadower) maybeShadow(toolName string, backend BackendConfig, req mcp.CallToolRequest, primary *mcp.CallToolResult) {
+	shadow, ok := s.gateway.config.Shadow[toolName]
+	if !ok || rand.Float64() >= shadow.Fraction {
+		return
+	}
+	primary, err := cloneResult(primary)
+	if err != nil {
+		s.stats.errors.Add(1)
+		log.Printf("⚠️ Shadow call for %s could not copy the primary result: %v", toolName, err)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.stats.calls.Add(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), backend.Timeouts.request())
+		defer cancel()
+
+		canaryClient, err := s.canaryClient(ctx, toolName, shadow.CanaryURL)
+		if err != nil {
+			s.stats.errors.Add(1)
+			log.Printf("⚠️ Shadow call for %s could not connect to canary: %v", toolName, err)
+			return
+		}
+
+		canaryResult, err := canaryClient.CallTool(ctx, req)
+		if err != nil {
+			s.stats.errors.Add(1)
+			log.Printf("⚠️ Shadow call for %s failed: %v", toolName, err)
+			return
+		}
+
+		comparator := s.comparators[toolName]
+		if comparator == nil {
+			comparator = &resultComparator{}
+		}
+		diffs, err := comparator.diff(primary, canaryResult)
+		if err != nil {
+			s.stats.errors.Add(1)
+			log.Printf("⚠️ Shadow call for %s could not be compared: %v", toolName, err)
+			return
+		}
+		if len(diffs) > 0 {
+			s.stats.discrepancies.Add(1)
+			s.recordDiscrepancy(shadowDiscrepancy{Tool: toolName, Time: time.Now(), Diffs: diffs})
+			log.Printf("⚠️ Shadow discrepancy for %s at %s", toolName, diffPaths(diffs))
+		}
+	}()
+}
+
+// close waits for in-flight shadow calls and closes the canary clients
+func (s *shadower) close() {
+	s.wg.Wait()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for url, canaryClient := range s.clients {
+		canaryClient.Close()
+		delete(s.clients, url)
+	}
+}
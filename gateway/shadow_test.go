@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestShadowCanary verifies the client gets the primary result while the canary is called and compared
+func TestShadowCanary(t *testing.T) {
+	lookup := func() mcp.Tool {
+		return mcp.NewTool("lookup", mcp.WithIdempotentHintAnnotation(true))
+	}
+
+	primary := newStubBackend(t, "Primary", textTool(lookup(), "primary answer"))
+
+	var canaryCalls atomic.Int64
+	canary := newStubBackend(t, "Canary", stubTool{
+		tool: lookup(),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			canaryCalls.Add(1)
+			return mcp.NewToolResultText("canary answer"), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Shadow = map[string]ShadowConfig{
+		"server1-lookup": {CanaryURL: canary.URL, Fraction: 1},
+	}
+	gateway, gatewayURL := startTestGateway(t, config, primary)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-lookup", nil)
+	if text := extractTextFromResult(result); text != "primary answer" {
+		t.Fatalf("Expected the primary result, got %q", text)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for gateway.shadows.stats.discrepancies.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if canaryCalls.Load() != 1 {
+		t.Errorf("Expected the canary to be called once, got %d", canaryCalls.Load())
+	}
+	if discrepancies := gateway.shadows.stats.discrepancies.Load(); discrepancies != 1 {
+		t.Errorf("Expected 1 recorded discrepancy, got %d", discrepancies)
+	}
+
+	gateway.shadows.close()
+}
+
+// TestShadowRequiresIdempotentTool verifies shadowing a tool not annotated idempotent fails startup
+func TestShadowRequiresIdempotentTool(t *testing.T) {
+	primary := newStubBackend(t, "Primary", textTool(mcp.NewTool("write"), "written"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: primary.URL}}
+	config.Shadow = map[string]ShadowConfig{
+		"server1-write": {CanaryURL: primary.URL, Fraction: 1},
+	}
+
+	if _, err := New(config); err == nil || !strings.Contains(err.Error(), "idempotent") {
+		t.Fatalf("Expected startup to reject shadowing a non-idempotent tool, got %v", err)
+	}
+}
+
+// TestShadowSemanticDiff verifies ignored paths and array order don't count as discrepancies
+// while a meaningful difference is reported with its path
+func TestShadowSemanticDiff(t *testing.T) {
+	report := func() mcp.Tool {
+		return mcp.NewTool("report", mcp.WithReadOnlyHintAnnotation(true))
+	}
+
+	primary := newStubBackend(t, "Primary", stubTool{
+		tool: report(),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(`{"generatedAt":"2024-01-01T00:00:00Z","items":["a","b"],"total":2}`), nil
+		},
+	})
+
+	var canaryTotal atomic.Int64
+	canaryTotal.Store(2)
+	canary := newStubBackend(t, "Canary", stubTool{
+		tool: report(),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(fmt.Sprintf(`{"generatedAt":"2024-06-30T12:00:00Z","items":["b","a"],"total":%d}`, canaryTotal.Load())), nil
+		},
+	})
+
+	config := DefaultConfig()
+	config.Shadow = map[string]ShadowConfig{
+		"server1-report": {
+			CanaryURL: canary.URL,
+			Fraction:  1,
+			Compare: ShadowCompareConfig{
+				IgnorePaths:     []string{"$.content[0].text.generatedAt"},
+				UnorderedArrays: true,
+			},
+		},
+	}
+	gateway, gatewayURL := startTestGateway(t, config, primary)
+	t.Cleanup(gateway.shadows.close)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Differences only in an ignored field and array order
+	callTool(t, mcpClient, "server1-report", nil)
+	gateway.shadows.wg.Wait()
+	if calls := gateway.shadows.stats.calls.Load(); calls != 1 {
+		t.Fatalf("Expected 1 shadow call, got %d", calls)
+	}
+	if discrepancies := gateway.shadows.stats.discrepancies.Load(); discrepancies != 0 {
+		t.Fatalf("Expected no discrepancy, got %d: %+v", discrepancies, gateway.shadows.recentDiscrepancies())
+	}
+
+	// A meaningful difference is reported with the differing path
+	canaryTotal.Store(3)
+	callTool(t, mcpClient, "server1-report", nil)
+	gateway.shadows.wg.Wait()
+	if discrepancies := gateway.shadows.stats.discrepancies.Load(); discrepancies != 1 {
+		t.Fatalf("Expected 1 discrepancy, got %d", discrepancies)
+	}
+	reports := gateway.shadows.recentDiscrepancies()
+	if len(reports) != 1 || len(reports[0].Diffs) != 1 {
+		t.Fatalf("Expected one report with one diff, got %+v", reports)
+	}
+	diff := reports[0].Diffs[0]
+	if diff.Path != "$.content[0].text.total" || diff.Primary != float64(2) || diff.Canary != float64(3) {
+		t.Errorf("Expected total to differ (2 vs 3), got %+v", diff)
+	}
+}
+
+// TestShadowComparesBackendResult verifies the canary is compared with the primary
+// backend's result, not the one decorated for the client
+func TestShadowComparesBackendResult(t *testing.T) {
+	lookup := textTool(mcp.NewTool("lookup", mcp.WithIdempotentHintAnnotation(true)), "same answer")
+	primary := newStubBackend(t, "Primary", lookup)
+	canary := newStubBackend(t, "Canary", lookup)
+
+	config := DefaultConfig()
+	config.ResultMeta.Enabled = true
+	config.Shadow = map[string]ShadowConfig{
+		"server1-lookup": {CanaryURL: canary.URL, Fraction: 1},
+	}
+	gateway, gatewayURL := startTestGateway(t, config, primary)
+	t.Cleanup(gateway.shadows.close)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	for range 5 {
+		if result := callTool(t, mcpClient, "server1-lookup", nil); result.Meta == nil {
+			t.Fatal("Expected the client's result to carry result meta")
+		}
+	}
+	gateway.shadows.wg.Wait()
+	if calls := gateway.shadows.stats.calls.Load(); calls != 5 {
+		t.Fatalf("Expected 5 shadow calls, got %d", calls)
+	}
+	if discrepancies := gateway.shadows.stats.discrepancies.Load(); discrepancies != 0 {
+		t.Errorf("Expected no discrepancy between identical answers, got %d: %+v", discrepancies, gateway.shadows.recentDiscrepancies())
+	}
+}
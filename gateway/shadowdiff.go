@@ -0,0 +1,220 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Number of recent discrepancy reports kept for gateway_info
+const maxShadowReports = 20
+
+// ShadowCompareConfig controls how primary and canary results are compared.
+// Text content holding JSON is compared as structured data, so paths can reach into it
+// (e.g. $.content[0].text.generatedAt).
+type ShadowCompareConfig struct {
+	// IgnorePaths are JSONPaths removed from both results before comparing
+	IgnorePaths []string `yaml:"ignorePaths"`
+	// UnorderedArrays compares arrays regardless of element order
+	UnorderedArrays bool `yaml:"unorderedArrays"`
+}
+
+// resultComparator normalizes and diffs tool results
+type resultComparator struct {
+	ignore    [][]pathSegment
+	unordered bool
+}
+
+// shadowPathDiff is a single differing value between primary and canary
+type shadowPathDiff struct {
+	Path    string `json:"path"`
+	Primary any    `json:"primary,omitempty"`
+	Canary  any    `json:"canary,omitempty"`
+}
+
+// shadowDiscrepancy reports how a canary result differed from the primary
+type shadowDiscrepancy struct {
+	Tool  string           `json:"tool"`
+	Time  time.Time        `json:"time"`
+	Diffs []shadowPathDiff `json:"diffs"`
+}
+
+// newResultComparator compiles a tool's comparison settings
+func newResultComparator(config ShadowCompareConfig) (*resultComparator, error) {
+	comparator := &resultComparator{unordered: config.UnorderedArrays}
+	for _, path := range config.IgnorePaths {
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		comparator.ignore = append(comparator.ignore, segments)
+	}
+	return comparator, nil
+}
+
+// newResultComparators compiles the comparators of all shadowed tools
+func newResultComparators(shadows map[string]ShadowConfig) (map[string]*resultComparator, error) {
+	comparators := make(map[string]*resultComparator, len(shadows))
+	for tool, shadow := range shadows {
+		comparator, err := newResultComparator(shadow.Compare)
+		if err != nil {
+			return nil, fmt.Errorf("shadow.%s.compare: %w", tool, err)
+		}
+		comparators[tool] = comparator
+	}
+	return comparators, nil
+}
+
+// normalize converts a result to generic JSON, expands JSON text content and drops ignored paths
+func (c *resultComparator) normalize(result *mcp.CallToolResult) (any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var node any
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+
+	if object, ok := node.(map[string]any); ok {
+		if content, ok := object["content"].([]any); ok {
+			for _, item := range content {
+				if item, ok := item.(map[string]any); ok {
+					if text, ok := item["text"].(string); ok {
+						var structured any
+						if json.Unmarshal([]byte(text), &structured) == nil {
+							item["text"] = structured
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, segments := range c.ignore {
+		node = applyPath(node, segments, false, func(any, bool) (any, bool) { return nil, false })
+	}
+	return node, nil
+}
+
+// diff returns the paths at which the primary and canary results differ
+func (c *resultComparator) diff(primary, canary *mcp.CallToolResult) ([]shadowPathDiff, error) {
+	primaryNode, err := c.normalize(primary)
+	if err != nil {
+		return nil, err
+	}
+	canaryNode, err := c.normalize(canary)
+	if err != nil {
+		return nil, err
+	}
+	return c.diffNodes("$", primaryNode, canaryNode, nil), nil
+}
+
+// diffNodes appends the differences between two JSON values under path
+func (c *resultComparator) diffNodes(path string, primary, canary any, diffs []shadowPathDiff) []shadowPathDiff {
+	switch p := primary.(type) {
+	case map[string]any:
+		q, ok := canary.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(p)+len(q))
+		for key := range p {
+			keys = append(keys, key)
+		}
+		for key := range q {
+			if _, ok := p[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffs = c.diffNodes(path+"."+key, p[key], q[key], diffs)
+		}
+		return diffs
+	case []any:
+		q, ok := canary.([]any)
+		if !ok || len(p) != len(q) {
+			break
+		}
+		if c.unordered {
+			p, q = sortedByJSON(p), sortedByJSON(q)
+		}
+		for i := range p {
+			diffs = c.diffNodes(fmt.Sprintf("%s[%d]", path, i), p[i], q[i], diffs)
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(primary, canary) {
+		diffs = append(diffs, shadowPathDiff{Path: path, Primary: primary, Canary: canary})
+	}
+	return diffs
+}
+
+// sortedByJSON returns a copy of values ordered by their JSON encoding. Nested arrays
+// are sorted first so equal elements encode identically.
+func sortedByJSON(values []any) []any {
+	type keyed struct {
+		key   string
+		value any
+	}
+	entries := make([]keyed, len(values))
+	for i, value := range values {
+		value = sortNestedArrays(value)
+		encoded, _ := json.Marshal(value)
+		entries[i] = keyed{key: string(encoded), value: value}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	sorted := make([]any, len(values))
+	for i, entry := range entries {
+		sorted[i] = entry.value
+	}
+	return sorted
+}
+
+// sortNestedArrays orders every array within value by element encoding
+func sortNestedArrays(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = sortNestedArrays(child)
+		}
+	case []any:
+		return sortedByJSON(v)
+	}
+	return value
+}
+
+// recordDiscrepancy keeps a discrepancy report, dropping the oldest beyond the limit
+func (s *shadower) recordDiscrepancy(report shadowDiscrepancy) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.reports = append(s.reports, report)
+	if len(s.reports) > maxShadowReports {
+		s.reports = s.reports[len(s.reports)-maxShadowReports:]
+	}
+}
+
+// recentDiscrepancies returns a copy of the recent discrepancy reports, oldest first
+func (s *shadower) recentDiscrepancies() []shadowDiscrepancy {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]shadowDiscrepancy(nil), s.reports...)
+}
+
+// diffPaths lists the differing paths of a report for logging
+func diffPaths(diffs []shadowPathDiff) string {
+	paths := make([]string, len(diffs))
+	for i, diff := range diffs {
+		paths[i] = diff.Path
+	}
+	return strings.Join(paths, ", ")
+}
@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Default header carrying the request signature
+const defaultSignatureHeader = "X-Signature"
+
+// SigningConfig configures HMAC signing of outbound requests to a backend
+type SigningConfig struct {
+	// SecretEnv names the environment variable holding the shared secret
+	SecretEnv string `yaml:"secretEnv"`
+	// Header carries the hex-encoded signature (default X-Signature)
+	Header string `yaml:"header"`
+	// Algorithm is sha256 (default) or sha512
+	Algorithm string `yaml:"algorithm"`
+}
+
+// Validate checks the signing configuration
+func (c SigningConfig) Validate() error {
+	if c.SecretEnv == "" {
+		return fmt.Errorf("signing.secretEnv is required")
+	}
+	if _, err := signingHash(c.Algorithm); err != nil {
+		return err
+	}
+	return nil
+}
+
+// signingHash returns the hash constructor for a signing algorithm name
+func signingHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+// signBody computes the hex-encoded HMAC of body
+func signBody(newHash func() hash.Hash, secret, body []byte) string {
+	mac := hmac.New(newHash, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signingRoundTripper attaches an HMAC signature over the request body
+type signingRoundTripper struct {
+	next    http.RoundTripper
+	secret  []byte
+	header  string
+	newHash func() hash.Hash
+}
+
+// newSigningRoundTripper creates a signing round tripper, reading the secret from the environment
+func newSigningRoundTripper(next http.RoundTripper, config SigningConfig) (*signingRoundTripper, error) {
+	newHash, err := signingHash(config.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := os.Getenv(config.SecretEnv)
+	if secret == "" {
+		return nil, fmt.Errorf("signing secret environment variable %s is not set", config.SecretEnv)
+	}
+
+	header := config.Header
+	if header == "" {
+		header = defaultSignatureHeader
+	}
+
+	return &signingRoundTripper{
+		next:    next,
+		secret:  []byte(secret),
+		header:  header,
+		newHash: newHash,
+	}, nil
+}
+
+func (s *signingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+	}
+
+	// RoundTrippers must not modify the caller's request
+	signed := req.Clone(req.Context())
+	signed.Body = io.NopCloser(bytes.NewReader(body))
+	signed.ContentLength = int64(len(body))
+	signed.Header.Set(s.header, signBody(s.newHash, s.secret, body))
+
+	return s.next.RoundTrip(signed)
+}
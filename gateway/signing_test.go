@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestRequestSigning verifies the backend can verify the signature on every request it receives
+func TestRequestSigning(t *testing.T) {
+	const secret = "shared-secret"
+	t.Setenv("TEST_BACKEND_SECRET", secret)
+
+	mcpServer := server.NewMCPServer("Signed Backend", "1.0.0", server.WithToolCapabilities(true))
+	st := textTool(mcp.NewTool("echo"), "signed ok")
+	mcpServer.AddTool(st.tool, st.handler)
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	var verified, rejected atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Compute the signature the way the backend would
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+
+		if !hmac.Equal([]byte(r.Header.Get("X-Body-Signature")), []byte(expected)) {
+			rejected.Add(1)
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		verified.Add(1)
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name: "signed",
+		URL:  backend.URL,
+		Signing: &SigningConfig{
+			SecretEnv: "TEST_BACKEND_SECRET",
+			Header:    "X-Body-Signature",
+			Algorithm: "sha256",
+		},
+	}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected valid config: %v", err)
+	}
+
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "signed-echo", nil)
+	if result.IsError || extractTextFromResult(result) != "signed ok" {
+		t.Fatalf("Expected signed call to succeed, got %+v", result)
+	}
+
+	if rejected.Load() != 0 {
+		t.Fatalf("Backend rejected %d requests with bad signatures", rejected.Load())
+	}
+	if verified.Load() == 0 {
+		t.Fatal("Expected backend to verify at least one signed request")
+	}
+}
+
+// TestRequestSigningMissingSecret verifies a missing secret fails the backend connection
+func TestRequestSigningMissingSecret(t *testing.T) {
+	_, err := newGateway(DefaultConfig()).newBackendHTTPClient(BackendConfig{
+		Name:    "signed",
+		Signing: &SigningConfig{SecretEnv: "TEST_UNSET_BACKEND_SECRET"},
+	}, "")
+	if err == nil {
+		t.Fatal("Expected an error when the signing secret is not set")
+	}
+}
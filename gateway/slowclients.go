@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Client-facing server timeout defaults
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultServerIdleTimeout = 120 * time.Second
+	defaultStallTimeout      = 10 * time.Second
+)
+
+// ServerConfig sets the timeouts of the client-facing HTTP servers, so clients that open
+// connections and send requests slowly (slow-loris) can't tie them up
+type ServerConfig struct {
+	// ReadHeaderTimeout bounds reading a request's headers (default 10s)
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	// ReadTimeout bounds reading a whole request, body included; 0 (default) leaves
+	// bodies to stallTimeout
+	ReadTimeout time.Duration `yaml:"readTimeout"`
+	// IdleTimeout closes keep-alive connections idle between requests (default 120s)
+	IdleTimeout time.Duration `yaml:"idleTimeout"`
+	// StallTimeout closes a connection whose request body makes no progress for this
+	// long, however long the body takes overall (default 10s)
+	StallTimeout time.Duration `yaml:"stallTimeout"`
+}
+
+// Validate checks the timeouts are not negative
+func (c ServerConfig) Validate() error {
+	if c.ReadHeaderTimeout < 0 || c.ReadTimeout < 0 || c.IdleTimeout < 0 || c.StallTimeout < 0 {
+		return fmt.Errorf("server timeouts must not be negative")
+	}
+	return nil
+}
+
+// readHeaderTimeout returns the header timeout, defaulting to 10s
+func (c ServerConfig) readHeaderTimeout() time.Duration {
+	if c.ReadHeaderTimeout > 0 {
+		return c.ReadHeaderTimeout
+	}
+	return defaultReadHeaderTimeout
+}
+
+// idleTimeout returns the keep-alive idle timeout, defaulting to 120s
+func (c ServerConfig) idleTimeout() time.Duration {
+	if c.IdleTimeout > 0 {
+		return c.IdleTimeout
+	}
+	return defaultServerIdleTimeout
+}
+
+// stallTimeout returns the body stall timeout, defaulting to 10s
+func (c ServerConfig) stallTimeout() time.Duration {
+	if c.StallTimeout > 0 {
+		return c.StallTimeout
+	}
+	return defaultStallTimeout
+}
+
+// NewServer creates a client-facing HTTP server for handler on addr with the configured
+// timeouts. There is no write timeout: responses may stream for as long as a call runs.
+func NewServer(config *Config, addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           stallMiddleware(config.Server, handler),
+		ReadHeaderTimeout: config.Server.readHeaderTimeout(),
+		ReadTimeout:       config.Server.ReadTimeout,
+		IdleTimeout:       config.Server.idleTimeout(),
+	}
+}
+
+// stallMiddleware closes connections whose request body stops making progress: each read
+// of the body must return within the stall timeout
+func stallMiddleware(config ServerConfig, next http.Handler) http.Handler {
+	stall := config.stallTimeout()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Body = &stallReader{
+			ReadCloser: r.Body,
+			w:          w,
+			controller: http.NewResponseController(w),
+			stall:      stall,
+			// Leave the server's own deadline in place once the body is read
+			clearDeadline: config.ReadTimeout == 0,
+			remote:        r.RemoteAddr,
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// stallReader moves the connection's read deadline forward before every body read
+type stallReader struct {
+	io.ReadCloser
+	w             http.ResponseWriter
+	controller    *http.ResponseController
+	stall         time.Duration
+	clearDeadline bool
+	remote        string
+	done          bool
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	if !s.done {
+		s.controller.SetReadDeadline(time.Now().Add(s.stall))
+	}
+	n, err := s.ReadCloser.Read(p)
+	if err == nil || s.done {
+		return n, err
+	}
+
+	s.done = true
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		log.Printf("⚠️ Closing stalled client connection from %s: no request body bytes for %s", s.remote, s.stall)
+		s.w.Header().Set("Connection", "close")
+		return n, err
+	}
+	if s.clearDeadline {
+		s.controller.SetReadDeadline(time.Time{})
+	}
+	return n, err
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// startSlowClientServer serves a test gateway through NewServer with the given timeouts
+func startSlowClientServer(t *testing.T, serverConfig ServerConfig) string {
+	t.Helper()
+
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	config := DefaultConfig()
+	config.Server = serverConfig
+	gateway, _ := startTestGateway(t, config, backend)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := NewServer(config, listener.Addr().String(), gateway.Handler())
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().String()
+}
+
+// waitForClose reads from conn until the server closes it, failing if that takes longer than within
+func waitForClose(t *testing.T, conn net.Conn, within time.Duration) string {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(within))
+	received, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatalf("Expected the server to close the connection within %s, got %v", within, err)
+	}
+	return string(received)
+}
+
+// TestSlowClientStalledBody verifies a connection whose request body stops arriving is closed
+func TestSlowClientStalledBody(t *testing.T) {
+	addr := startSlowClientServer(t, ServerConfig{StallTimeout: 100 * time.Millisecond})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "POST / HTTP/1.1\r\nHost: gateway\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{\"jsonrpc\"")
+
+	received := waitForClose(t, conn, 2*time.Second)
+	if received != "" && !strings.Contains(received, "Connection: close") {
+		t.Errorf("Expected any response to close the connection, got %q", received)
+	}
+}
+
+// TestSlowClientStalledHeaders verifies a connection whose headers never finish is closed
+func TestSlowClientStalledHeaders(t *testing.T) {
+	addr := startSlowClientServer(t, ServerConfig{ReadHeaderTimeout: 100 * time.Millisecond})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "POST / HTTP/1.1\r\nHost: gat")
+
+	waitForClose(t, conn, 2*time.Second)
+}
+
+// TestSlowClientMakingProgress verifies a body that arrives slowly but steadily is served,
+// even though it takes longer than the stall timeout overall
+func TestSlowClientMakingProgress(t *testing.T) {
+	addr := startSlowClientServer(t, ServerConfig{StallTimeout: 100 * time.Millisecond})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"` + mcp.LATEST_PROTOCOL_VERSION +
+		`","capabilities":{},"clientInfo":{"name":"slow","version":"1.0.0"}}}`
+	fmt.Fprintf(conn, "POST / HTTP/1.1\r\nHost: gateway\r\nContent-Type: application/json\r\nAccept: application/json, text/event-stream\r\nContent-Length: %d\r\n\r\n", len(body))
+	for i := 0; i < len(body); i += 40 {
+		time.Sleep(30 * time.Millisecond)
+		fmt.Fprint(conn, body[i:min(i+40, len(body))])
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the slow but steady request to succeed, got HTTP %d", resp.StatusCode)
+	}
+}
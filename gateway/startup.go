@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Startup modes
+const (
+	startupWait  = "wait"
+	startupEager = "eager"
+)
+
+// errBackendConnecting marks a backend that hasn't finished its startup connection
+var errBackendConnecting = errors.New("still connecting")
+
+// StartupConfig controls how the gateway waits for backends at startup
+type StartupConfig struct {
+	// Mode is "wait" (default) to list every backend's tools before serving clients, or
+	// "eager" to serve clients at once and add each backend's tools as it connects.
+	// Clients initialized before then are sent tools/list_changed as tools are added.
+	Mode string `yaml:"mode"`
+}
+
+// eager reports whether clients are served before every backend has connected
+func (c StartupConfig) eager() bool {
+	return c.Mode == startupEager
+}
+
+// Validate checks the startup mode
+func (c StartupConfig) Validate() error {
+	switch c.Mode {
+	case "", startupWait, startupEager:
+		return nil
+	}
+	return fmt.Errorf("startup.mode must be %s or %s", startupWait, startupEager)
+}
+
+// initializeBackendsEagerly connects to backends in the background, adding each one's
+// tools as soon as it has listed them. Backends are listed as unavailable until then.
+func (g *Gateway) initializeBackendsEagerly() {
+	log.Println("🚀 Serving clients while backends connect; their tools are added as they are listed")
+
+	connecting := make(map[string]error, len(g.config.Backends))
+	for _, backend := range g.config.Backends {
+		connecting[backend.Name] = errBackendConnecting
+	}
+	g.toolsLock.Lock()
+	g.backendTools = make(map[string][]mcp.Tool)
+	g.unavailableBackends = connecting
+	g.toolsLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	g.stopStartup = cancel
+	go func() {
+		defer cancel()
+		if err := g.initializeStartupClients(ctx, g.publishStartupBackend); err != nil {
+			log.Printf("⚠️ Serving without some backends: %v", err)
+		}
+
+		// Backends that never connected stay unavailable until refreshed
+		g.toolsLock.Lock()
+		unavailable := make(map[string]error, len(g.unavailableBackends))
+		for name, err := range g.unavailableBackends {
+			if err == errBackendConnecting {
+				err = fmt.Errorf("failed to connect at startup")
+			}
+			unavailable[name] = err
+		}
+		g.unavailableBackends = unavailable
+		toolCount := len(g.aggregatedTools)
+		g.toolsLock.Unlock()
+
+		for _, validate := range []func() error{g.validateCacheTools, g.validateIdempotencyTools, g.validateShadowTools} {
+			if err := validate(); err != nil {
+				log.Printf("❌ Invalid config for the tools listed at startup: %v", err)
+			}
+		}
+		log.Printf("Startup finished. Aggregated %d tools from backend servers.", toolCount)
+	}()
+}
+
+// publishStartupBackend lists the tools of a backend that just connected at startup and
+// adds them to the aggregated tools, notifying initialized clients
+func (g *Gateway) publishStartupBackend(backend BackendConfig, startupClient BackendTransport) {
+	ctx, cancel := context.WithTimeout(context.Background(), backend.Timeouts.request())
+	defer cancel()
+
+	listed, err := startupClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err == nil {
+		listed.Tools, err = g.dedupeTools(backend.Name, listed.Tools)
+	}
+
+	g.refreshLock.Lock()
+	defer g.refreshLock.Unlock()
+
+	g.toolsLock.Lock()
+	unavailable := make(map[string]error, len(g.unavailableBackends))
+	for name, unavailableErr := range g.unavailableBackends {
+		if name != backend.Name {
+			unavailable[name] = unavailableErr
+		}
+	}
+	if err != nil {
+		log.Printf("⚠️ Listing tools without %s: %v", backend.Name, err)
+		unavailable[backend.Name] = err
+		g.unavailableBackends = unavailable
+		g.toolsLock.Unlock()
+		return
+	}
+	g.unavailableBackends = unavailable
+	lists := make(map[string][]mcp.Tool, len(g.backendTools)+1)
+	for name, tools := range g.backendTools {
+		lists[name] = tools
+	}
+	lists[backend.Name] = listed.Tools
+	g.backendTools = lists
+	if isStateless(startupClient) {
+		log.Printf("🔗 %s is stateless (no session ID), sharing one connection across clients", backend.Name)
+		shared := make(map[string]BackendTransport, len(g.sharedClients)+1)
+		for name, sharedClient := range g.sharedClients {
+			shared[name] = sharedClient
+		}
+		shared[backend.Name] = startupClient
+		g.sharedClients = shared
+	}
+	g.toolsLock.Unlock()
+
+	g.mergeTools()
+	log.Printf("✅ %s connected, added its %d tools", backend.Name, len(listed.Tools))
+}
+
+// startupPending reports whether a backend hasn't connected since an eager startup, so
+// client sessions connect to it on first use rather than waiting for it
+func (g *Gateway) startupPending(backend string) bool {
+	if !g.config.Startup.eager() {
+		return false
+	}
+	g.toolsLock.RLock()
+	defer g.toolsLock.RUnlock()
+
+	_, pending := g.unavailableBackends[backend]
+	return pending
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newGatedBackend serves backend's MCP endpoint only once gate is closed
+func newGatedBackend(t *testing.T, backend *httptest.Server, gate <-chan struct{}) *httptest.Server {
+	target, _ := url.Parse(backend.URL)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	gated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-gate:
+			proxy.ServeHTTP(w, r)
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(gated.Close)
+	return gated
+}
+
+// listToolNamesRaw lists the gateway's tools over a raw session
+func listToolNamesRaw(t *testing.T, url, sessionID string) []string {
+	t.Helper()
+
+	resp := postMessage(t, url, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	var response struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("Failed to decode tools/list response %s: %v", body, err)
+	}
+	var names []string
+	for _, tool := range response.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// TestEagerStartup verifies clients initialize without waiting for a slow backend, and
+// are sent tools/list_changed once its tools are added
+func TestEagerStartup(t *testing.T) {
+	gate := make(chan struct{})
+	fast := newStubBackend(t, "Fast", textTool(mcp.NewTool("ping"), "pong"))
+	slow := newGatedBackend(t, newStubBackend(t, "Slow", textTool(mcp.NewTool("search"), "found")), gate)
+
+	config := DefaultConfig()
+	config.Startup.Mode = startupEager
+	started := time.Now()
+	_, gatewayURL := startTestGateway(t, config, fast, slow)
+	sessionID := initializeWithCapabilities(t, gatewayURL, `{}`)
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Expected initialize to return without waiting for the slow backend, took %v", elapsed)
+	}
+	if containsString(listToolNamesRaw(t, gatewayURL, sessionID), "server2-search") {
+		t.Fatal("Expected the slow backend's tools to be missing before it connects")
+	}
+
+	// Listen for notifications, then let the slow backend connect
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, gatewayURL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open the notification stream: %v", err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+	close(gate)
+
+	for {
+		event := readEvent(t, events)
+		if event.Method != mcp.MethodNotificationToolsListChanged {
+			continue
+		}
+		names := listToolNamesRaw(t, gatewayURL, sessionID)
+		if containsString(names, "server2-search") {
+			if !containsString(names, "server1-ping") {
+				t.Errorf("Expected the fast backend's tools to stay listed, got %v", names)
+			}
+			break
+		}
+	}
+
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server2-search", nil)); text != "found" {
+		t.Errorf("Expected the slow backend's tool to be callable, got %q", text)
+	}
+}
+
+// TestEagerStartupUnreachableBackend verifies a backend that can't connect doesn't fail
+// an eager startup
+func TestEagerStartupUnreachableBackend(t *testing.T) {
+	fast := newStubBackend(t, "Fast", textTool(mcp.NewTool("ping"), "pong"))
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	config := DefaultConfig()
+	config.Startup.Mode = startupEager
+	_, gatewayURL := startTestGateway(t, config, fast, down)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// The reachable backend's tools are added in the background
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if _, err := mcpClient.CallTool(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "server1-ping"}}); err == nil {
+			break
+		}
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-ping", nil)); text != "pong" {
+		t.Errorf("Expected the reachable backend's tool to be callable, got %q", text)
+	}
+}
+
+// TestStartupModeValidation verifies unknown startup modes are rejected
+func TestStartupModeValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.Startup.Mode = "lazy"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an unknown startup mode to be rejected")
+	}
+}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"log"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// sessionTracker is implemented by mcp-go transports that keep the backend's session ID
+type sessionTracker interface {
+	GetSessionId() string
+}
+
+// isStateless reports whether a connected backend didn't assign a session ID
+// (no Mcp-Session-Id in its initialize response). Transports that don't track
+// sessions are treated as stateful.
+func isStateless(backendClient BackendTransport) bool {
+	var tracker sessionTracker
+	ok := findTransport(backendClient, func(t transport.Interface) bool {
+		tracker, _ = t.(sessionTracker)
+		return tracker != nil
+	})
+	return ok && tracker.GetSessionId() == ""
+}
+
+// findTransport reports whether match holds for the mcp-go transport of a connected
+// backend, or for one of the gateway's transports wrapping it
+func findTransport(backendClient BackendTransport, match func(transport.Interface) bool) bool {
+	wrapper, ok := backendClient.(interface{ GetTransport() transport.Interface })
+	if !ok {
+		return false
+	}
+
+	t := wrapper.GetTransport()
+	for {
+		if match(t) {
+			return true
+		}
+		switch wrapped := t.(type) {
+		case *remappingTransport:
+			t = wrapped.Interface
+		case *replicaTransport:
+			t = wrapped.Interface
+		case *outputSchemaTransport:
+			t = wrapped.Interface
+		case *numberTransport:
+			t = wrapped.Interface
+		case *errorCodeTransport:
+			t = wrapped.Interface
+		case *batchingTransport:
+			t = wrapped.Interface
+		default:
+			return false
+		}
+	}
+}
+
+// detectStatelessBackends shares the startup connection of every backend that
+// didn't assign it a session: such a backend keeps no per-session state, so one
+// connection serves all client sessions
+func (g *Gateway) detectStatelessBackends() {
+	shared := make(map[string]BackendTransport)
+	for name, startupClient := range g.startupClients {
+		if isStateless(startupClient) {
+			log.Printf("🔗 %s is stateless (no session ID), sharing one connection across clients", name)
+			shared[name] = startupClient
+		}
+	}
+
+	g.toolsLock.Lock()
+	g.sharedClients = shared
+	g.toolsLock.Unlock()
+}
+
+// sharedClient returns the shared connection of a stateless backend
+func (g *Gateway) sharedClient(backend string) (BackendTransport, bool) {
+	g.toolsLock.RLock()
+	defer g.toolsLock.RUnlock()
+
+	backendClient, ok := g.sharedClients[backend]
+	return backendClient, ok
+}
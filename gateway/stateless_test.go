@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestStatelessBackend verifies a backend that sends no session ID is shared by all
+// clients while a stateful backend keeps a session per client
+func TestStatelessBackend(t *testing.T) {
+	stateful := newStubBackend(t, "Stateful", counterTool())
+
+	mcpServer := server.NewMCPServer("Stateless", "1.0.0", server.WithToolCapabilities(true))
+	ping := textTool(mcp.NewTool("ping"), "pong")
+	mcpServer.AddTool(ping.tool, ping.handler)
+	streamable := server.NewStreamableHTTPServer(mcpServer, server.WithStateLess(true))
+
+	var initializes atomic.Int64
+	stateless := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.Contains(body, []byte(`"method":"initialize"`)) {
+			initializes.Add(1)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(stateless.Close)
+
+	gateway, gatewayURL := startTestGateway(t, DefaultConfig(), stateful, stateless)
+	if _, ok := gateway.sharedClient("server1"); ok {
+		t.Errorf("Expected the stateful backend not to be shared")
+	}
+	if _, ok := gateway.sharedClient("server2"); !ok {
+		t.Fatalf("Expected the stateless backend to be shared")
+	}
+
+	for i := 0; i < 2; i++ {
+		mcpClient := newTestClient(t, gatewayURL)
+
+		// Each client still has its own session on the stateful backend
+		if text := extractTextFromResult(callTool(t, mcpClient, "server1-count", nil)); text != "1" {
+			t.Errorf("Client %d: expected its own stateful session (count 1), got %q", i, text)
+		}
+		if text := extractTextFromResult(callTool(t, mcpClient, "server2-ping", nil)); text != "pong" {
+			t.Errorf("Client %d: expected pong from the stateless backend, got %q", i, text)
+		}
+	}
+
+	if count := initializes.Load(); count != 1 {
+		t.Errorf("Expected the stateless backend to be initialized once, got %d", count)
+	}
+}
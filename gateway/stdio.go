@@ -0,0 +1,157 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// Name of the built-in transport that runs a backend as a subprocess
+const stdioTransport = "stdio"
+
+func init() {
+	RegisterTransport(stdioTransport, newStdioTransport)
+}
+
+// StdioConfig describes a backend run as a subprocess speaking MCP over stdin/stdout.
+// Every backend connection spawns its own process, so each client session gets an
+// isolated backend.
+type StdioConfig struct {
+	// Command is the executable to run
+	Command string `yaml:"command"`
+	// Args are passed to the command
+	Args []string `yaml:"args"`
+	// Env adds variables to the gateway's environment. Values are Go templates
+	// with .SessionID (the gateway client session, empty for connections the
+	// gateway makes itself) and .Backend.
+	Env map[string]string `yaml:"env"`
+}
+
+// stdioEnvData is the data available to stdio env templates
+type stdioEnvData struct {
+	SessionID string
+	Backend   string
+}
+
+// parseEnv parses the env templates
+func (c StdioConfig) parseEnv() (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(c.Env))
+	for name, value := range c.Env {
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("stdio.env.%s: %w", name, err)
+		}
+		templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// environment renders the env templates for a connection as NAME=value pairs
+func (c StdioConfig) environment(data stdioEnvData) ([]string, error) {
+	templates, err := c.parseEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		var value bytes.Buffer
+		if err := templates[name].Execute(&value, data); err != nil {
+			return nil, fmt.Errorf("stdio.env.%s: %w", name, err)
+		}
+		env = append(env, name+"="+value.String())
+	}
+	return env, nil
+}
+
+// newStdioTransport is the built-in stdio transport
+func newStdioTransport(ctx context.Context, backend BackendConfig, options TransportOptions) (BackendTransport, error) {
+	if backend.Stdio == nil {
+		return nil, fmt.Errorf("stdio transport requires stdio.command")
+	}
+	env, err := backend.Stdio.environment(stdioEnvData{SessionID: options.SessionID, Backend: backend.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	// The process is tied to the start context, so it must outlive the connect
+	// timeout; it exits when the connection is closed
+	process, err := startStdioProcess(context.WithoutCancel(ctx), backend, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", backend.Stdio.Command, err)
+	}
+
+	var backendTransport transport.Interface = process
+	if options.Wrap != nil {
+		backendTransport = options.Wrap(backendTransport)
+	}
+	backendClient := client.NewClient(backendTransport)
+
+	if err := backendClient.Start(ctx); err != nil {
+		backendClient.Close()
+		return nil, fmt.Errorf("failed to start %s: %w", backend.Stdio.Command, err)
+	}
+	return backendClient, nil
+}
+
+// stdioProcess is a stdio transport over a backend process the gateway started itself,
+// so its stdout can be read through the backend's message size limit
+type stdioProcess struct {
+	*transport.Stdio
+	cmd   *exec.Cmd
+	close sync.Once
+	err   error
+}
+
+// startStdioProcess runs the backend's command with env added to the gateway's environment
+func startStdioProcess(ctx context.Context, backend BackendConfig, env []string) (*stdioProcess, error) {
+	cmd := exec.CommandContext(ctx, backend.Stdio.Command, backend.Stdio.Args...)
+	cmd.Env = append(os.Environ(), env...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var messages io.Reader = stdout
+	if backend.MaxMessageBytes > 0 {
+		messages = newStdioLimitReader(stdout, backend.Name, backend.MaxMessageBytes)
+	}
+	return &stdioProcess{Stdio: transport.NewIO(messages, stdin, stderr), cmd: cmd}, nil
+}
+
+// Close closes the process's stdin and waits for it to exit
+func (p *stdioProcess) Close() error {
+	p.close.Do(func() {
+		p.err = p.Stdio.Close()
+		if err := p.cmd.Wait(); p.err == nil {
+			p.err = err
+		}
+	})
+	return p.err
+}
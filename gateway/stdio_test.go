@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Environment variable that makes the test binary run as a stub stdio backend
+const stdioHelperEnv = "GATEWAY_TEST_STDIO_BACKEND"
+
+// TestStdioHelperBackend is not a real test: re-executed with stdioHelperEnv set,
+// the test binary serves an MCP backend over stdio whose env tool echoes SESSION_TOKEN
+// and whose big tool returns 64 KiB of text
+func TestStdioHelperBackend(t *testing.T) {
+	if os.Getenv(stdioHelperEnv) == "" {
+		t.Skip("only runs as a stdio backend subprocess")
+	}
+
+	mcpServer := server.NewMCPServer("Stdio", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("env"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(os.Getenv("SESSION_TOKEN")), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("big"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("x", 64<<10)), nil
+	})
+	if err := server.ServeStdio(mcpServer); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// TestStdioSessionEnv verifies each client session gets its own stdio backend process
+// with session-derived environment variables
+func TestStdioSessionEnv(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:      "server1",
+		Transport: stdioTransport,
+		Stdio: &StdioConfig{
+			Command: os.Args[0],
+			Args:    []string{"-test.run=^TestStdioHelperBackend$"},
+			Env: map[string]string{
+				stdioHelperEnv:  "1",
+				"SESSION_TOKEN": "token-{{.SessionID}}",
+			},
+		},
+	}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid stdio config, got %v", err)
+	}
+	gateway, gatewayURL := startTestGateway(t, config)
+	t.Cleanup(func() { gateway.Shutdown(context.Background()) })
+
+	first := newTestClient(t, gatewayURL)
+	second := newTestClient(t, gatewayURL)
+
+	for _, mcpClient := range []interface {
+		CallTool(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		GetTransport() transport.Interface
+	}{first, second} {
+		sessionID := mcpClient.GetTransport().(*transport.StreamableHTTP).GetSessionId()
+
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "server1-env"
+		result, err := mcpClient.CallTool(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Failed to call server1-env: %v", err)
+		}
+		if text := extractTextFromResult(result); text != "token-"+sessionID {
+			t.Errorf("Expected session %s's backend to see token-%s, got %q", sessionID, sessionID, text)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// stdioSessionID is the session ID mcp-go gives the single stdio client
+const stdioSessionID = "stdio"
+
+// ServeStdio serves the gateway to a single MCP client over newline-delimited JSON-RPC
+// on stdin and stdout, for hosts that launch the gateway as a subprocess. Tools are
+// aggregated and routed exactly as over HTTP; the client's backend connections are
+// closed when stdin ends or ctx is cancelled. HTTP-only behaviour, such as response
+// headers and cancellation by notifications/cancelled, doesn't apply. Logs must not be written to stdout.
+func (g *Gateway) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	stdioServer := server.NewStdioServer(g.mcpServer)
+	stdioServer.SetErrorLogger(log.Default())
+
+	log.Println("🚀 Serving MCP over stdio")
+	err := stdioServer.Listen(ctx, stdin, stdout)
+	g.closeClientConnections(stdioSessionID)
+	log.Println("🔧 Stdio client disconnected")
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestServeStdio verifies a client on stdin/stdout lists and calls aggregated tools and
+// its backend connections are closed when stdin ends
+func TestServeStdio(t *testing.T) {
+	backend := newStubBackend(t, "Backend", textTool(mcp.NewTool("echo"), "hello"))
+	gateway, _ := startTestGateway(t, DefaultConfig(), backend)
+
+	stdinReader, stdin := io.Pipe()
+	stdoutReader, stdout := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- gateway.ServeStdio(context.Background(), stdinReader, stdout)
+		stdout.Close()
+	}()
+
+	responses := bufio.NewScanner(stdoutReader)
+	request := func(id int, method, params string) map[string]any {
+		t.Helper()
+		fmt.Fprintf(stdin, `{"jsonrpc":"2.0","id":%d,"method":"%s","params":%s}`+"\n", id, method, params)
+		if !responses.Scan() {
+			t.Fatalf("Expected a response to %s, got %v", method, responses.Err())
+		}
+		var response map[string]any
+		if err := json.Unmarshal(responses.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse %s response %s: %v", method, responses.Text(), err)
+		}
+		return response
+	}
+
+	initialized := request(1, "initialize", `{"protocolVersion":"`+mcp.LATEST_PROTOCOL_VERSION+`","capabilities":{},"clientInfo":{"name":"host","version":"1.0.0"}}`)
+	if _, ok := initialized["result"]; !ok {
+		t.Fatalf("Expected initialize to succeed, got %v", initialized)
+	}
+	fmt.Fprintln(stdin, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	listed, _ := json.Marshal(request(2, "tools/list", `{}`))
+	for _, name := range []string{"gateway_info", "server1-echo"} {
+		if !strings.Contains(string(listed), `"name":"`+name+`"`) {
+			t.Errorf("Expected tools/list to include %s, got %s", name, listed)
+		}
+	}
+
+	called, _ := json.Marshal(request(3, "tools/call", `{"name":"server1-echo"}`))
+	if !strings.Contains(string(called), `"text":"hello"`) {
+		t.Errorf("Expected the call to be routed to the backend, got %s", called)
+	}
+	gateway.connectionsLock.RLock()
+	_, connected := gateway.clientConnections[stdioSessionID]
+	gateway.connectionsLock.RUnlock()
+	if !connected {
+		t.Error("Expected backend connections for the stdio client")
+	}
+
+	stdin.Close()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected stdio serving to end cleanly, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected stdio serving to end with stdin")
+	}
+	gateway.connectionsLock.RLock()
+	_, connected = gateway.clientConnections[stdioSessionID]
+	gateway.connectionsLock.RUnlock()
+	if connected {
+		t.Error("Expected the stdio client's backend connections to be closed")
+	}
+}
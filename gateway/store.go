@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Name of the store used when none is configured
+const defaultStore = "memory"
+
+// KVStore holds gateway state that should survive retries, such as idempotency records.
+// Implementations must be safe for concurrent use; a store shared by several gateway
+// replicas lets them deduplicate each other's calls.
+type KVStore interface {
+	// Get returns the value stored under key, or false if it is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// SetIfAbsent stores value under key for ttl unless the key already holds an
+	// unexpired value, reporting whether it was stored
+	SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Set stores value under key for ttl, replacing any existing value
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key
+	Delete(ctx context.Context, key string) error
+}
+
+// StoreConfig selects the key-value store
+type StoreConfig struct {
+	// Type names a registered store (default memory)
+	Type string `yaml:"type"`
+	// Options are passed to the store factory (e.g. an address)
+	Options map[string]string `yaml:"options"`
+}
+
+// StoreFactory creates a key-value store from its options
+type StoreFactory func(options map[string]string) (KVStore, error)
+
+var (
+	storesLock sync.RWMutex
+	stores     = make(map[string]StoreFactory)
+)
+
+func init() {
+	RegisterStore(defaultStore, func(options map[string]string) (KVStore, error) {
+		return newMemoryStore(), nil
+	})
+}
+
+// RegisterStore makes a key-value store available by name to the store type setting.
+// It is meant to be called from init functions and panics if the name is empty or
+// already registered, or if factory is nil.
+func RegisterStore(name string, factory StoreFactory) {
+	storesLock.Lock()
+	defer storesLock.Unlock()
+
+	if name == "" || factory == nil {
+		panic("gateway: RegisterStore requires a name and a factory")
+	}
+	if _, exists := stores[name]; exists {
+		panic(fmt.Sprintf("gateway: store %s registered twice", name))
+	}
+	stores[name] = factory
+}
+
+// lookupStore returns the factory registered under name
+func lookupStore(name string) (StoreFactory, error) {
+	storesLock.RLock()
+	defer storesLock.RUnlock()
+
+	factory, ok := stores[name]
+	if !ok {
+		names := make([]string, 0, len(stores))
+		for registered := range stores {
+			names = append(names, registered)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown store %q (registered: %v)", name, names)
+	}
+	return factory, nil
+}
+
+// typeName returns the configured store, defaulting to memory
+func (c StoreConfig) typeName() string {
+	if c.Type != "" {
+		return c.Type
+	}
+	return defaultStore
+}
+
+// newStore creates the configured store
+func newStore(config StoreConfig) (KVStore, error) {
+	factory, err := lookupStore(config.typeName())
+	if err != nil {
+		return nil, err
+	}
+	return factory(config.Options)
+}
+
+// Number of writes between sweeps of expired memory store entries
+const memoryStoreSweepInterval = 256
+
+// memoryEntry is a value held by the memory store
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryStore is the built-in store, local to one gateway process
+type memoryStore struct {
+	entries map[string]memoryEntry
+	writes  int
+	lock    sync.Mutex
+}
+
+// newMemoryStore creates an empty memory store
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Get returns an unexpired value
+func (s *memoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entry, ok := s.lookup(key, time.Now())
+	return entry.value, ok, nil
+}
+
+// SetIfAbsent stores a value unless an unexpired one exists
+func (s *memoryStore) SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	if _, ok := s.lookup(key, now); ok {
+		return false, nil
+	}
+	s.write(key, value, now.Add(ttl))
+	return true, nil
+}
+
+// Set stores a value
+func (s *memoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.write(key, value, time.Now().Add(ttl))
+	return nil
+}
+
+// Delete removes a value
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// lookup returns an unexpired entry, removing it if expired; the lock must be held
+func (s *memoryStore) lookup(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := s.entries[key]
+	if ok && now.After(entry.expiresAt) {
+		delete(s.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, ok
+}
+
+// write stores an entry, periodically sweeping expired ones; the lock must be held
+func (s *memoryStore) write(key string, value []byte, expiresAt time.Time) {
+	s.entries[key] = memoryEntry{value: value, expiresAt: expiresAt}
+
+	s.writes++
+	if s.writes%memoryStoreSweepInterval != 0 {
+		return
+	}
+	now := time.Now()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
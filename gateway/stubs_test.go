@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// stubTool pairs a tool definition with its handler for a stub backend
+type stubTool struct {
+	tool    mcp.Tool
+	handler server.ToolHandlerFunc
+}
+
+// textTool returns a stub tool that always responds with the given text
+func textTool(tool mcp.Tool, text string) stubTool {
+	return stubTool{
+		tool: tool,
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(text), nil
+		},
+	}
+}
+
+// newStubBackend starts an in-process MCP backend exposing the given tools
+func newStubBackend(t testing.TB, name string, tools ...stubTool) *httptest.Server {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer(name, "1.0.0", server.WithToolCapabilities(true))
+	for _, st := range tools {
+		mcpServer.AddTool(st.tool, st.handler)
+	}
+
+	backend := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// newTestClient connects and initializes an MCP client against the given URL
+func newTestClient(t testing.TB, url string, options ...transport.StreamableHTTPCOption) *client.Client {
+	t.Helper()
+
+	httpTransport, err := transport.NewStreamableHTTP(url, options...)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+	mcpClient := client.NewClient(httpTransport)
+	t.Cleanup(func() { mcpClient.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    fmt.Sprintf("Test Client (%s)", t.Name()),
+		Version: "1.0.0",
+	}
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize client: %v", err)
+	}
+
+	return mcpClient
+}
+
+// callTool calls a tool with the given arguments and fails the test on transport errors
+func callTool(t testing.TB, mcpClient *client.Client, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+
+	result, err := mcpClient.CallTool(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to call %s: %v", name, err)
+	}
+	return result
+}
+
+// listToolNames returns the names of the tools visible to the client
+func listToolNames(t testing.TB, mcpClient *client.Client) []string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// extractTextFromResult extracts text content from a CallToolResult
+func extractTextFromResult(result *mcp.CallToolResult) string {
+	if len(result.Content) > 0 {
+		if textContent, ok := result.Content[0].(mcp.TextContent); ok {
+			return textContent.Text
+		}
+	}
+	return ""
+}
+
+// containsString reports whether a slice contains the given value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// startTestGateway starts a gateway in front of the given stub backends (named server1, server2, ...)
+// and returns its URL. Backends already present in config are used as-is.
+func startTestGateway(t testing.TB, config *Config, backends ...*httptest.Server) (*Gateway, string) {
+	t.Helper()
+
+	if len(backends) > 0 {
+		config.Backends = nil
+		for i, backend := range backends {
+			config.Backends = append(config.Backends, BackendConfig{
+				Name: fmt.Sprintf("server%d", i+1),
+				URL:  backend.URL,
+			})
+		}
+	}
+
+	gateway, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to initialize backends: %v", err)
+	}
+
+	gatewayServer := httptest.NewServer(gateway.Handler())
+	t.Cleanup(gatewayServer.Close)
+	return gateway, gatewayServer.URL
+}
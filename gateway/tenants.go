@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// TLSConfig holds the certificate used to serve the gateway over HTTPS
+type TLSConfig struct {
+	// CertFile is a PEM certificate covering every tenant hostname
+	CertFile string `yaml:"certFile"`
+	// KeyFile is the certificate's PEM private key
+	KeyFile string `yaml:"keyFile"`
+}
+
+// TenantConfig is a logical gateway served on the shared listener for one TLS server name
+type TenantConfig struct {
+	// Backends replace the top-level backends for connections to this hostname
+	Backends []BackendConfig `yaml:"backends"`
+}
+
+// tenantConfig derives a tenant's config from the top-level one, sharing every
+// setting except the backends
+func (c *Config) tenantConfig(tenant TenantConfig) *Config {
+	config := *c
+	config.Backends = tenant.Backends
+	config.Tenants = nil
+	return &config
+}
+
+// newTenants creates a gateway for each tenant hostname
+func newTenants(config *Config) (map[string]*Gateway, error) {
+	tenants := make(map[string]*Gateway, len(config.Tenants))
+	for host, tenant := range config.Tenants {
+		log.Printf("🔧 Initializing tenant %s", host)
+		tenantGateway, err := New(config.tenantConfig(tenant))
+		if err != nil {
+			for _, created := range tenants {
+				created.Shutdown(context.Background())
+			}
+			return nil, fmt.Errorf("tenant %s: %w", host, err)
+		}
+		tenants[strings.ToLower(host)] = tenantGateway
+	}
+	return tenants, nil
+}
+
+// tenantMiddleware routes each request to the tenant named by the connection's TLS
+// server name. Connections without a matching server name are served by next.
+func (g *Gateway) tenantMiddleware(next http.Handler) http.Handler {
+	handlers := make(map[string]http.Handler, len(g.tenants))
+	for host, tenant := range g.tenants {
+		handlers[host] = tenant.Handler()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			if handler, ok := handlers[strings.ToLower(r.TLS.ServerName)]; ok {
+				handler.ServeHTTP(w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
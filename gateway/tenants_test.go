@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newSNIClient connects an MCP client over TLS, presenting the given server name
+func newSNIClient(t *testing.T, url string, serverName string) *client.Client {
+	t.Helper()
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}}
+	httpTransport, err := transport.NewStreamableHTTP(url, transport.WithHTTPBasicClient(httpClient))
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+	mcpClient := client.NewClient(httpTransport)
+	t.Cleanup(func() { mcpClient.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "SNI Test Client", Version: "1.0.0"}
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize client for %s: %v", serverName, err)
+	}
+	return mcpClient
+}
+
+// TestTenantsBySNI verifies the TLS server name selects a tenant's disjoint backend set
+func TestTenantsBySNI(t *testing.T) {
+	defaultBackend := newStubBackend(t, "Default", textTool(mcp.NewTool("echo"), "default"))
+	alphaBackend := newStubBackend(t, "Alpha", textTool(mcp.NewTool("alpha_only"), "alpha"))
+	betaBackend := newStubBackend(t, "Beta", textTool(mcp.NewTool("beta_only"), "beta"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "default", URL: defaultBackend.URL}}
+	config.Tenants = map[string]TenantConfig{
+		"alpha.example.com": {Backends: []BackendConfig{{Name: "alpha", URL: alphaBackend.URL}}},
+		"Beta.example.com":  {Backends: []BackendConfig{{Name: "beta", URL: betaBackend.URL}}},
+	}
+
+	gateway, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+	gatewayServer := httptest.NewTLSServer(gateway.Handler())
+	t.Cleanup(gatewayServer.Close)
+
+	alpha := newSNIClient(t, gatewayServer.URL, "alpha.example.com")
+	alphaTools := listToolNames(t, alpha)
+	if !containsString(alphaTools, "alpha-alpha_only") || containsString(alphaTools, "beta-beta_only") || containsString(alphaTools, "default-echo") {
+		t.Errorf("Expected only alpha's tools for alpha.example.com, got %v", alphaTools)
+	}
+	if text := extractTextFromResult(callTool(t, alpha, "alpha-alpha_only", nil)); text != "alpha" {
+		t.Errorf("Expected alpha's backend to answer, got %q", text)
+	}
+
+	beta := newSNIClient(t, gatewayServer.URL, "beta.example.com")
+	betaTools := listToolNames(t, beta)
+	if !containsString(betaTools, "beta-beta_only") || containsString(betaTools, "alpha-alpha_only") {
+		t.Errorf("Expected only beta's tools for beta.example.com, got %v", betaTools)
+	}
+
+	// A call to another tenant's tool is unknown
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "alpha-alpha_only"
+	if result, err := beta.CallTool(context.Background(), req); err == nil && !result.IsError {
+		t.Error("Expected beta.example.com to reject alpha's tool")
+	}
+
+	// Unmatched server names get the top-level backends
+	other := newSNIClient(t, gatewayServer.URL, "other.example.com")
+	if tools := listToolNames(t, other); !containsString(tools, "default-echo") || containsString(tools, "alpha-alpha_only") {
+		t.Errorf("Expected the default backends for an unknown server name, got %v", tools)
+	}
+}
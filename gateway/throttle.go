@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Backoff bounds used when a 429 response carries no usable Retry-After
+const (
+	minThrottleBackoff = time.Second
+	maxThrottleBackoff = time.Minute
+)
+
+// BackendThrottledError reports that a backend rejected a request with 429 Too Many Requests
+type BackendThrottledError struct {
+	Backend    string
+	RetryAfter time.Duration
+}
+
+func (e *BackendThrottledError) Error() string {
+	return fmt.Sprintf("backend %s is rate limited, retry after %s", e.Backend, e.RetryAfter.Round(time.Second))
+}
+
+// backendThrottle pauses routing to a backend after it responds with 429. Without a
+// Retry-After the pause doubles on each consecutive 429, from 1s up to 1m.
+type backendThrottle struct {
+	backend string
+
+	lock    sync.Mutex
+	until   time.Time
+	backoff time.Duration
+}
+
+// throttled records a 429 response and returns how long routing is paused
+func (t *backendThrottle) throttled(retryAfter time.Duration) time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if retryAfter <= 0 {
+		t.backoff = min(max(2*t.backoff, minThrottleBackoff), maxThrottleBackoff)
+		retryAfter = t.backoff
+	}
+	if until := time.Now().Add(retryAfter); until.After(t.until) {
+		t.until = until
+	}
+	log.Printf("⚠️ %s returned 429, pausing calls for %s", t.backend, retryAfter)
+	return retryAfter
+}
+
+// recovered resets the adaptive backoff after a successful response
+func (t *backendThrottle) recovered() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.backoff = 0
+}
+
+// remaining returns how long calls to the backend remain paused
+func (t *backendThrottle) remaining() time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return time.Until(t.until)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date;
+// it returns 0 when the header is missing or invalid
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
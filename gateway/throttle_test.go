@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestBackendRateLimit verifies a 429 with Retry-After pauses calls to the backend
+// with a clear error instead of retrying
+func TestBackendRateLimit(t *testing.T) {
+	stub := newStubBackend(t, "Stub", textTool(mcp.NewTool("echo"), "ok"))
+
+	var limited atomic.Bool
+	var toolCalls atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limited.Load() && r.Method == http.MethodPost {
+			toolCalls.Add(1)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		stub.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	config := DefaultConfig()
+	config.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 5}
+	_, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	// Establish the backend session before the backend starts rate limiting
+	callTool(t, mcpClient, "server1-echo", nil)
+	limited.Store(true)
+
+	result := callTool(t, mcpClient, "server1-echo", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "rate limited, retry after 1s") {
+		t.Fatalf("Expected a rate limit error, got %+v", result)
+	}
+	if calls := toolCalls.Load(); calls != 1 {
+		t.Fatalf("Expected the gateway not to retry, backend saw %d calls", calls)
+	}
+
+	// While paused, calls are rejected without reaching the backend
+	result = callTool(t, mcpClient, "server1-echo", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "rate limited") {
+		t.Fatalf("Expected the gateway to back off, got %+v", result)
+	}
+	if calls := toolCalls.Load(); calls != 1 {
+		t.Errorf("Expected no backend call while throttled, backend saw %d calls", calls)
+	}
+
+	// After Retry-After, calls flow again
+	limited.Store(false)
+	time.Sleep(1100 * time.Millisecond)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); text != "ok" {
+		t.Errorf("Expected calls to resume after Retry-After, got %q", text)
+	}
+}
+
+// TestParseRetryAfter verifies both Retry-After formats
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("Expected 3s, got %s", got)
+	}
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got < 8*time.Second || got > 10*time.Second {
+		t.Errorf("Expected about 10s from an HTTP date, got %s", got)
+	}
+	if got := parseRetryAfter("soon"); got != 0 {
+		t.Errorf("Expected 0 for an invalid header, got %s", got)
+	}
+}
@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Default backend timeouts
+const (
+	defaultDialTimeout    = 10 * time.Second
+	defaultRequestTimeout = 30 * time.Second
+	defaultIdleTimeout    = 30 * time.Minute
+)
+
+// BackendTimeouts configures the timeouts for a backend. Zero values use the defaults.
+type BackendTimeouts struct {
+	// Dial limits establishing a TCP connection (default 10s)
+	Dial time.Duration `yaml:"dial"`
+	// Request limits a single tool call, from sending it to receiving the result (default 30s)
+	Request time.Duration `yaml:"request"`
+	// Idle closes a client's backend session after this long without calls; the next call
+	// starts a new backend session (default 30m)
+	Idle time.Duration `yaml:"idle"`
+}
+
+// dial returns the dial timeout
+func (t BackendTimeouts) dial() time.Duration {
+	if t.Dial > 0 {
+		return t.Dial
+	}
+	return defaultDialTimeout
+}
+
+// request returns the per-request timeout
+func (t BackendTimeouts) request() time.Duration {
+	if t.Request > 0 {
+		return t.Request
+	}
+	return defaultRequestTimeout
+}
+
+// idle returns the session idle timeout
+func (t BackendTimeouts) idle() time.Duration {
+	if t.Idle > 0 {
+		return t.Idle
+	}
+	return defaultIdleTimeout
+}
+
+// newBackendTransport creates the base HTTP transport for a backend with its dial timeout;
+// control, if set, runs on each socket before it connects
+func newBackendTransport(timeouts BackendTimeouts, control func(network, address string, c syscall.RawConn) error) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   timeouts.dial(),
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	baseTransport.DialContext = dialer.DialContext
+	return baseTransport
+}
+
+// backendClient returns the client's connection to a backend, replacing it with a new
+// backend session if it has been idle longer than the backend's idle timeout.
+// Stateless backends use their shared connection, re-picking its replica once it has
+// outlived the backend's affinity TTL.
+func (g *Gateway) backendClient(ctx context.Context, connections *ClientBackendConnections, backend BackendConfig) (BackendTransport, error) {
+	if shared, ok := g.sharedClient(backend.Name); ok {
+		if replica, expired := affinityReplica(backend, shared); expired {
+			return g.rebalanceSharedClient(ctx, backend, shared, replica), nil
+		}
+		return shared, nil
+	}
+
+	connections.lock.Lock()
+	defer connections.lock.Unlock()
+
+	backendClient, ok := connections.Clients[backend.Name]
+	lastUsed := connections.lastUsed[backend.Name]
+	if ok && time.Since(lastUsed) > backend.Timeouts.idle() {
+		log.Printf("⚠️ %s session for client %s idle since %s, reconnecting",
+			backend.Name, connections.ClientSessionID, lastUsed.Format(time.RFC3339))
+		backendClient.Close()
+		delete(connections.Clients, backend.Name)
+		ok = false
+	}
+
+	if !ok {
+		if err := g.createClientBackendConnection(ctx, backend, connections); err != nil {
+			return nil, err
+		}
+		backendClient = connections.Clients[backend.Name]
+	}
+
+	connections.lastUsed[backend.Name] = time.Now()
+	return backendClient, nil
+}
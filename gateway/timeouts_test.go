@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// slowTool returns a stub tool that responds after the given delay
+func slowTool(name string, delay time.Duration) stubTool {
+	return stubTool{
+		tool: mcp.NewTool(name),
+		handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+			}
+			return mcp.NewToolResultText("done"), nil
+		},
+	}
+}
+
+// TestDialTimeout verifies a slow connect fails after the dial timeout, independent of the request timeout
+func TestDialTimeout(t *testing.T) {
+	backend := newStubBackend(t, "Stub", textTool(mcp.NewTool("echo"), "ok"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:     "slow",
+		URL:      backend.URL,
+		Timeouts: BackendTimeouts{Dial: 50 * time.Millisecond, Request: time.Minute},
+	}}
+
+	// Simulate a backend that is slow to accept connections
+	config.dialControl = func(network, address string, c syscall.RawConn) error {
+		time.Sleep(300 * time.Millisecond)
+		return nil
+	}
+
+	_, err := New(config)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("Expected a dial timeout, got %v", err)
+	}
+
+	// A dial timeout longer than the connect delay succeeds
+	config.Backends[0].Timeouts.Dial = 5 * time.Second
+	if _, err := New(config); err != nil {
+		t.Fatalf("Expected connect within the dial timeout to succeed: %v", err)
+	}
+}
+
+// TestRequestTimeout verifies a long call fails after the request timeout without affecting other calls
+func TestRequestTimeout(t *testing.T) {
+	backend := newStubBackend(t, "Stub",
+		slowTool("slow", 500*time.Millisecond),
+		slowTool("fast", 0),
+	)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:     "server1",
+		URL:      backend.URL,
+		Timeouts: BackendTimeouts{Request: 100 * time.Millisecond},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	started := time.Now()
+	result := callTool(t, mcpClient, "server1-slow", nil)
+	if !result.IsError || !strings.Contains(extractTextFromResult(result), "deadline exceeded") {
+		t.Errorf("Expected the request timeout to fire, got %q", extractTextFromResult(result))
+	}
+	if elapsed := time.Since(started); elapsed > 400*time.Millisecond {
+		t.Errorf("Expected the call to be aborted after ~100ms, took %s", elapsed)
+	}
+
+	if result := callTool(t, mcpClient, "server1-fast", nil); result.IsError {
+		t.Errorf("Expected a fast call to succeed, got %q", extractTextFromResult(result))
+	}
+}
+
+// TestIdleTimeout verifies an idle backend session is replaced on the next call
+func TestIdleTimeout(t *testing.T) {
+	backend := newStubBackend(t, "Stateful", counterTool())
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name:     "server1",
+		URL:      backend.URL,
+		Timeouts: BackendTimeouts{Idle: 100 * time.Millisecond},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	callTool(t, mcpClient, "server1-count", nil)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-count", nil)); text != "2" {
+		t.Fatalf("Expected the backend session to be reused within the idle timeout, got %q", text)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-count", nil)); text != "1" {
+		t.Errorf("Expected a new backend session after the idle timeout, got %q", text)
+	}
+}
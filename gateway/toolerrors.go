@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// How failed calls of backend tools are returned to the client
+const (
+	// toolErrorsResult returns a CallToolResult with isError set (default)
+	toolErrorsResult = "result"
+	// toolErrorsProtocol returns a JSON-RPC error
+	toolErrorsProtocol = "protocol"
+)
+
+// toolErrorsMiddleware returns every failed call of a backend tool in the configured
+// form, whether the backend answered with an error result or a JSON-RPC error, or the
+// gateway rejected the call
+func (g *Gateway) toolErrorsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+
+		g.toolsLock.RLock()
+		_, routed := g.toolRoutes[req.Params.Name]
+		g.toolsLock.RUnlock()
+		if !routed {
+			return result, err
+		}
+
+		switch g.config.ToolErrors {
+		case toolErrorsProtocol:
+			if err == nil && result != nil && result.IsError {
+				message := resultText(result)
+				if message == "" {
+					message = "tool call failed"
+				}
+				log.Printf("❌ Returning error result of %s as a protocol error", req.Params.Name)
+				return nil, errors.New(message)
+			}
+		default:
+			if err != nil {
+				log.Printf("❌ Returning protocol error of %s as an error result", req.Params.Name)
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		return result, err
+	}
+}
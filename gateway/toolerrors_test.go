@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newFailingBackend returns a backend with a tool returning an error result and
+// a tool failing with a JSON-RPC error
+func newFailingBackend(t *testing.T) string {
+	return newStubBackend(t, "Failing",
+		stubTool{
+			tool: mcp.NewTool("quota"),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError("quota exceeded"), nil
+			},
+		},
+		stubTool{
+			tool: mcp.NewTool("crash"),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return nil, errors.New("database down")
+			},
+		},
+	).URL
+}
+
+// TestToolErrorsAsResults verifies backend errors of both kinds reach the client as error results by default
+func TestToolErrorsAsResults(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: newFailingBackend(t)}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	for tool, message := range map[string]string{"server1-quota": "quota exceeded", "server1-crash": "database down"} {
+		result := callTool(t, mcpClient, tool, nil)
+		if !result.IsError || !strings.Contains(extractTextFromResult(result), message) {
+			t.Errorf("Expected %s to return an error result containing %q, got %+v", tool, message, result)
+		}
+	}
+}
+
+// TestToolErrorsAsProtocolErrors verifies backend errors of both kinds reach the client as JSON-RPC errors
+func TestToolErrorsAsProtocolErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "server1", URL: newFailingBackend(t)}}
+	config.ToolErrors = toolErrorsProtocol
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	for tool, message := range map[string]string{"server1-quota": "quota exceeded", "server1-crash": "database down"} {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		result, err := mcpClient.CallTool(context.Background(), req)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected %s to fail with a protocol error containing %q, got result %+v, error %v", tool, message, result, err)
+		}
+	}
+
+	// The gateway's own tools are unaffected
+	if result := callTool(t, mcpClient, "gateway_info", nil); result.IsError {
+		t.Errorf("Expected gateway_info to succeed, got %+v", result)
+	}
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Separators between a backend's namespace and its tool names
+const (
+	toolNameSeparatorDash = "-"
+	toolNameSeparatorPath = "/"
+)
+
+// ToolNamesConfig controls how backend tools are named by the gateway
+type ToolNamesConfig struct {
+	// Separator joins a backend's namespace and tool name: "-" (default, e.g.
+	// server1-echo) or "/" for hierarchical names (e.g. team/server1/echo)
+	Separator string `yaml:"separator"`
+}
+
+// separator returns the configured separator, defaulting to a dash
+func (c ToolNamesConfig) separator() string {
+	if c.Separator == "" {
+		return toolNameSeparatorDash
+	}
+	return c.Separator
+}
+
+// namespace returns the prefix of the backend's gateway tool names, defaulting to its name
+func (b BackendConfig) namespace() string {
+	if b.Namespace == "" {
+		return b.Name
+	}
+	return b.Namespace
+}
+
+// validateToolNames checks the separator and that backend namespaces are well-formed and unique
+func validateToolNames(config ToolNamesConfig, backends []BackendConfig) error {
+	separator := config.separator()
+	if separator != toolNameSeparatorDash && separator != toolNameSeparatorPath {
+		return fmt.Errorf("toolNames.separator must be %q or %q", toolNameSeparatorDash, toolNameSeparatorPath)
+	}
+
+	seen := make(map[string]string)
+	for _, backend := range backends {
+		namespace := backend.namespace()
+		if strings.Contains(namespace, toolNameSeparatorPath) {
+			if separator != toolNameSeparatorPath {
+				return fmt.Errorf("backend %s: namespace %q may only contain / with toolNames.separator /", backend.Name, namespace)
+			}
+			for _, segment := range strings.Split(namespace, toolNameSeparatorPath) {
+				if segment == "" {
+					return fmt.Errorf("backend %s: namespace %q has an empty segment", backend.Name, namespace)
+				}
+			}
+		}
+		if other, ok := seen[namespace]; ok {
+			return fmt.Errorf("backend %s: namespace %q is already used by %s", backend.Name, namespace, other)
+		}
+		seen[namespace] = backend.Name
+	}
+	return nil
+}
+
+// gatewayToolName returns the name a backend tool is exposed under. Calls are routed back
+// through the tool registry rather than by splitting the name, so namespaces with several
+// segments and backend tool names containing the separator route unambiguously.
+func (g *Gateway) gatewayToolName(backend BackendConfig, tool string) string {
+	return backend.namespace() + g.config.ToolNames.separator() + tool
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestNestedToolNamespaces verifies slash-separated, multi-segment namespaces route calls
+// to the right backend, including backend tool names that contain a slash
+func TestNestedToolNamespaces(t *testing.T) {
+	team := newStubBackend(t, "Team",
+		textTool(mcp.NewTool("echo"), "team echo"),
+		textTool(mcp.NewTool("other/echo"), "team other echo"),
+	)
+	nested := newStubBackend(t, "Nested", textTool(mcp.NewTool("echo"), "nested echo"))
+	plain := newStubBackend(t, "Plain", textTool(mcp.NewTool("echo"), "plain echo"))
+
+	config := DefaultConfig()
+	config.ToolNames.Separator = toolNameSeparatorPath
+	config.Backends = []BackendConfig{
+		{Name: "team", URL: team.URL},
+		{Name: "nested", URL: nested.URL, Namespace: "team/server1"},
+		{Name: "server3", URL: plain.URL},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a valid config, got %v", err)
+	}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	for name, want := range map[string]string{
+		"team/echo":         "team echo",
+		"team/other/echo":   "team other echo",
+		"team/server1/echo": "nested echo",
+		"server3/echo":      "plain echo",
+	} {
+		if text := extractTextFromResult(callTool(t, mcpClient, name, nil)); text != want {
+			t.Errorf("Expected %s to return %q, got %q", name, want, text)
+		}
+	}
+}
+
+// TestToolNamespaceValidation verifies malformed and duplicate namespaces are rejected
+func TestToolNamespaceValidation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		separator  string
+		namespaces []string
+	}{
+		"slash with dash separator": {"", []string{"team/server1", ""}},
+		"empty segment":             {toolNameSeparatorPath, []string{"team//server1", ""}},
+		"trailing slash":            {toolNameSeparatorPath, []string{"team/", ""}},
+		"duplicate":                 {toolNameSeparatorPath, []string{"team", "team"}},
+		"unknown separator":         {".", []string{"", ""}},
+	} {
+		config := DefaultConfig()
+		config.ToolNames.Separator = tc.separator
+		config.Backends = []BackendConfig{
+			{Name: "server1", URL: "http://localhost:1", Namespace: tc.namespaces[0]},
+			{Name: "server2", URL: "http://localhost:2", Namespace: tc.namespaces[1]},
+		}
+		if err := config.Validate(); err == nil {
+			t.Errorf("%s: expected the config to be rejected", name)
+		}
+	}
+}
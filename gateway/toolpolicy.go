@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"fmt"
+	"path"
+)
+
+// Tool policy defaults
+const (
+	ToolPolicyAllow = "allow"
+	ToolPolicyDeny  = "deny"
+)
+
+// ToolPolicy decides which of a backend's tools are exposed through the gateway.
+//
+// Precedence: a tool matching any Except pattern gets the opposite of Default;
+// every other tool, including tools the backend adds later, gets Default.
+// Patterns match backend (unprefixed) tool names and support path.Match globs.
+type ToolPolicy struct {
+	// Default is "allow" (the default, for trusted backends) or "deny" (opt-in only)
+	Default string `yaml:"default"`
+	// Except lists tool name patterns that invert the default
+	Except []string `yaml:"except"`
+}
+
+// Allows reports whether the named backend tool should be exposed
+func (p ToolPolicy) Allows(toolName string) bool {
+	allowByDefault := p.Default != ToolPolicyDeny
+	if matchesAny(p.Except, toolName) {
+		return !allowByDefault
+	}
+	return allowByDefault
+}
+
+// Validate checks the policy's default and patterns
+func (p ToolPolicy) Validate() error {
+	switch p.Default {
+	case "", ToolPolicyAllow, ToolPolicyDeny:
+	default:
+		return fmt.Errorf("tools.default must be %q or %q, got %q", ToolPolicyAllow, ToolPolicyDeny, p.Default)
+	}
+	return validatePatterns(p.Except)
+}
+
+// matchesAny reports whether name matches any of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePatterns checks that every glob pattern is well-formed
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
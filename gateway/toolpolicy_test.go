@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestToolPolicyDefaults verifies allow/deny defaults with exceptions, including tools that appear later
+func TestToolPolicyDefaults(t *testing.T) {
+	trusted := server.NewMCPServer("Trusted", "1.0.0", server.WithToolCapabilities(true))
+	untrusted := server.NewMCPServer("Untrusted", "1.0.0", server.WithToolCapabilities(true))
+	for _, st := range []stubTool{
+		textTool(mcp.NewTool("read"), "read"),
+		textTool(mcp.NewTool("admin_reset"), "reset"),
+	} {
+		trusted.AddTool(st.tool, st.handler)
+		untrusted.AddTool(st.tool, st.handler)
+	}
+
+	trustedServer := server.NewTestStreamableHTTPServer(trusted)
+	t.Cleanup(trustedServer.Close)
+	untrustedServer := server.NewTestStreamableHTTPServer(untrusted)
+	t.Cleanup(untrustedServer.Close)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "trusted", URL: trustedServer.URL, Tools: ToolPolicy{Default: ToolPolicyAllow, Except: []string{"admin_*"}}},
+		{Name: "untrusted", URL: untrustedServer.URL, Tools: ToolPolicy{Default: ToolPolicyDeny, Except: []string{"read"}}},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected valid config: %v", err)
+	}
+
+	gateway, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	assertTools := func(expected map[string]bool) {
+		t.Helper()
+		for name, want := range expected {
+			if _, got := gateway.lookupTool(name); got != want {
+				t.Errorf("Tool %s exposed=%v, want %v", name, got, want)
+			}
+		}
+	}
+
+	assertTools(map[string]bool{
+		"trusted-read":          true,
+		"trusted-admin_reset":   false,
+		"untrusted-read":        true,
+		"untrusted-admin_reset": false,
+	})
+
+	// A tool added later follows each backend's default
+	newTool := textTool(mcp.NewTool("summarize"), "summary")
+	trusted.AddTool(newTool.tool, newTool.handler)
+	untrusted.AddTool(newTool.tool, newTool.handler)
+	if err := gateway.aggregateTools(); err != nil {
+		t.Fatalf("Failed to re-aggregate tools: %v", err)
+	}
+
+	assertTools(map[string]bool{
+		"trusted-summarize":   true,
+		"untrusted-summarize": false,
+	})
+}
+
+// TestToolPolicyValidation verifies invalid defaults and patterns are rejected
+func TestToolPolicyValidation(t *testing.T) {
+	if err := (ToolPolicy{Default: "maybe"}).Validate(); err == nil {
+		t.Error("Expected invalid default to be rejected")
+	}
+	if err := (ToolPolicy{Except: []string{"["}}).Validate(); err == nil {
+		t.Error("Expected malformed pattern to be rejected")
+	}
+}
@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"context"
+	"log"
+	"path"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Initialize _meta key a client uses to send its own tool priority
+const toolPriorityMetaKey = "toolPriority"
+
+// toolPriorityFromMeta reads a client's tool priority patterns from initialize _meta,
+// dropping malformed patterns
+func toolPriorityFromMeta(meta map[string]any) []string {
+	values, ok := meta[toolPriorityMetaKey].([]any)
+	if !ok {
+		return nil
+	}
+
+	var patterns []string
+	for _, value := range values {
+		pattern, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			log.Printf("⚠️ Ignoring invalid tool priority pattern %q: %v", pattern, err)
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// recordToolPriority stores the tool priority hint a client sent with initialize
+func (g *Gateway) recordToolPriority(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	patterns := toolPriorityFromMeta(initializeMetaFromContext(ctx))
+	session := server.ClientSessionFromContext(ctx)
+	if len(patterns) == 0 || session == nil {
+		return
+	}
+	g.sessions.setToolPriority(session.SessionID(), patterns)
+	log.Printf("🔧 Client %s prioritized tools %v", session.SessionID(), patterns)
+}
+
+// toolPriorityFilter floats tools matching the priority patterns to the top of tools/list,
+// in pattern order, keeping the existing order otherwise. A client's initialize hint
+// takes precedence over the configured priority.
+func (g *Gateway) toolPriorityFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	patterns := g.config.ToolPriority
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		if clientPatterns := g.sessions.toolPriority(session.SessionID()); len(clientPatterns) > 0 {
+			patterns = clientPatterns
+		}
+	}
+	if len(patterns) == 0 {
+		return tools
+	}
+
+	buckets := make([][]mcp.Tool, len(patterns)+1)
+	for _, tool := range tools {
+		bucket := len(patterns)
+		for i, pattern := range patterns {
+			if ok, _ := path.Match(pattern, tool.Name); ok {
+				bucket = i
+				break
+			}
+		}
+		buckets[bucket] = append(buckets[bucket], tool)
+	}
+
+	ordered := make([]mcp.Tool, 0, len(tools))
+	for _, bucket := range buckets {
+		ordered = append(ordered, bucket...)
+	}
+	return ordered
+}
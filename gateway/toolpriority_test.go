@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// priorityBackend starts a stub backend with tools alpha, beta, gamma and delta
+func priorityBackend(t *testing.T) *Config {
+	backend := newStubBackend(t, "Stub",
+		textTool(mcp.NewTool("alpha"), "a"),
+		textTool(mcp.NewTool("beta"), "b"),
+		textTool(mcp.NewTool("gamma"), "g"),
+		textTool(mcp.NewTool("delta"), "d"),
+	)
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "s", URL: backend.URL}}
+	return config
+}
+
+// TestToolPriorityConfig verifies configured priority patterns float matching tools to the top
+func TestToolPriorityConfig(t *testing.T) {
+	config := priorityBackend(t)
+	config.ToolPriority = []string{"s-gamma", "s-*a"}
+
+	_, gatewayURL := startTestGateway(t, config)
+	names := listToolNames(t, newTestClient(t, gatewayURL))
+
+	// s-gamma first, then other names ending in "a" in default order, then the rest unchanged
+	expected := []string{"s-gamma", "s-alpha", "s-beta", "s-delta", "gateway_info"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Got tool order %v, want %v", names, expected)
+	}
+}
+
+// TestToolPriorityInitializeHint verifies a client's initialize _meta hint overrides the configured priority
+func TestToolPriorityInitializeHint(t *testing.T) {
+	config := priorityBackend(t)
+	config.ToolPriority = []string{"s-gamma"}
+	_, gatewayURL := startTestGateway(t, config)
+
+	httpTransport, err := transport.NewStreamableHTTP(gatewayURL)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+	t.Cleanup(func() { httpTransport.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	send := func(id int64, method string, params any) json.RawMessage {
+		t.Helper()
+		response, err := httpTransport.SendRequest(ctx, transport.JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      mcp.NewRequestId(id),
+			Method:  method,
+			Params:  params,
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", method, err)
+		}
+		return response.Result
+	}
+
+	send(1, string(mcp.MethodInitialize), map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"clientInfo":      map[string]any{"name": "priority-client", "version": "1.0.0"},
+		"capabilities":    map[string]any{},
+		"_meta":           map[string]any{"toolPriority": []string{"s-delta", "s-beta"}},
+	})
+
+	var listed mcp.ListToolsResult
+	if err := json.Unmarshal(send(2, string(mcp.MethodToolsList), map[string]any{}), &listed); err != nil {
+		t.Fatalf("Failed to decode tools/list: %v", err)
+	}
+	var names []string
+	for _, tool := range listed.Tools {
+		names = append(names, tool.Name)
+	}
+
+	expected := []string{"s-delta", "s-beta", "gateway_info", "s-alpha", "s-gamma"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Got tool order %v, want %v", names, expected)
+	}
+}
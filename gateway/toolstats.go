@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Default number of tools gateway_tool_stats tracks
+const defaultToolStats = 1000
+
+// Number of a tool's most recent call latencies its percentiles are computed from, so
+// each tracked tool's memory stays bounded
+const toolStatsLatencySamples = 256
+
+// toolStats returns the number of tools whose stats are tracked, defaulting to 1000
+func (c AdminConfig) toolStats() int {
+	if c.ToolStats > 0 {
+		return c.ToolStats
+	}
+	return defaultToolStats
+}
+
+// toolStat is a tool's call statistics reported by gateway_tool_stats
+type toolStat struct {
+	Tool       string    `json:"tool"`
+	Backend    string    `json:"backend,omitempty"`
+	Calls      int64     `json:"calls"`
+	Errors     int64     `json:"errors"`
+	AverageMs  float64   `json:"averageMs"`
+	P50Ms      float64   `json:"p50Ms"`
+	P95Ms      float64   `json:"p95Ms"`
+	P99Ms      float64   `json:"p99Ms"`
+	LastCalled time.Time `json:"lastCalled"`
+}
+
+// toolCallStats accumulates one tool's calls
+type toolCallStats struct {
+	backend    string
+	calls      int64
+	errors     int64
+	total      time.Duration
+	lastCalled time.Time
+	// latencies is a ring of the most recent call durations
+	latencies []time.Duration
+	next      int
+}
+
+// toolStatsLog tracks call statistics for up to a fixed number of tools; calls to
+// tools beyond it are not tracked
+type toolStatsLog struct {
+	tools  map[string]*toolCallStats
+	limit  int
+	warned bool
+	lock   sync.Mutex
+}
+
+func newToolStatsLog(limit int) *toolStatsLog {
+	return &toolStatsLog{tools: make(map[string]*toolCallStats), limit: limit}
+}
+
+// add records a tool call
+func (l *toolStatsLog) add(toolName, backend string, duration time.Duration, failed bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	stats, ok := l.tools[toolName]
+	if !ok {
+		if len(l.tools) >= l.limit {
+			if !l.warned {
+				log.Printf("⚠️ Tool stats track %d tools, not tracking %s or other new tools", l.limit, toolName)
+				l.warned = true
+			}
+			return
+		}
+		stats = &toolCallStats{latencies: make([]time.Duration, 0, toolStatsLatencySamples)}
+		l.tools[toolName] = stats
+	}
+
+	stats.backend = backend
+	stats.calls++
+	if failed {
+		stats.errors++
+	}
+	stats.total += duration
+	stats.lastCalled = time.Now().UTC()
+	if len(stats.latencies) < toolStatsLatencySamples {
+		stats.latencies = append(stats.latencies, duration)
+	} else {
+		stats.latencies[stats.next] = duration
+		stats.next = (stats.next + 1) % toolStatsLatencySamples
+	}
+}
+
+// list returns the stats of the tracked tools, or only toolName's when set, from the
+// most called to the least
+func (l *toolStatsLog) list(toolName string) []toolStat {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	stats := make([]toolStat, 0, len(l.tools))
+	for name, tool := range l.tools {
+		if toolName != "" && name != toolName {
+			continue
+		}
+		latencies := append([]time.Duration(nil), tool.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		stats = append(stats, toolStat{
+			Tool:       name,
+			Backend:    tool.backend,
+			Calls:      tool.calls,
+			Errors:     tool.errors,
+			AverageMs:  milliseconds(tool.total / time.Duration(tool.calls)),
+			P50Ms:      milliseconds(percentile(latencies, 0.50)),
+			P95Ms:      milliseconds(percentile(latencies, 0.95)),
+			P99Ms:      milliseconds(percentile(latencies, 0.99)),
+			LastCalled: tool.lastCalled,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		return stats[i].Tool < stats[j].Tool
+	})
+	return stats
+}
+
+// percentile returns the value at quantile q of an ascending-sorted slice
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1) * q)
+	return sorted[index]
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// recordToolStats adds a tool call to gateway_tool_stats; it does nothing unless admin
+// tools are enabled
+func (g *Gateway) recordToolStats(toolName, backend string, duration time.Duration, failed bool) {
+	if g.toolStats == nil {
+		return
+	}
+	g.toolStats.add(toolName, backend, duration, failed)
+}
+
+// handleToolStats handles the gateway_tool_stats tool
+func (g *Gateway) handleToolStats(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(g.toolStats.list(req.GetString("tool", "")), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode tool stats: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestToolStats verifies gateway_tool_stats tracks each tool's calls and errors separately
+func TestToolStats(t *testing.T) {
+	config := DefaultConfig()
+	config.Admin.Enabled = true
+	config.Backends = []BackendConfig{{Name: "server1", URL: newFailingBackend(t)}}
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	started := time.Now().UTC()
+	for _, tool := range []string{"server1-quota", "server1-crash", "server1-quota", "server1-quota"} {
+		callTool(t, mcpClient, tool, nil)
+	}
+
+	toolStats := func(args map[string]interface{}) []toolStat {
+		result := callTool(t, mcpClient, "gateway_tool_stats", args)
+		if result.IsError {
+			t.Fatalf("gateway_tool_stats failed: %s", extractTextFromResult(result))
+		}
+		var stats []toolStat
+		if err := json.Unmarshal([]byte(extractTextFromResult(result)), &stats); err != nil {
+			t.Fatalf("Expected a JSON list of tool stats: %v", err)
+		}
+		return stats
+	}
+
+	stats := toolStats(nil)
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for the two called tools, got %+v", stats)
+	}
+	quota, crash := stats[0], stats[1]
+	if quota.Tool != "server1-quota" || quota.Calls != 3 || quota.Errors != 3 || quota.Backend != "server1" {
+		t.Errorf("Expected the most called tool first with 3 failed calls, got %+v", quota)
+	}
+	if crash.Tool != "server1-crash" || crash.Calls != 1 || crash.Errors != 1 {
+		t.Errorf("Expected the other tool's single failed call, got %+v", crash)
+	}
+	for _, stat := range stats {
+		if stat.LastCalled.Before(started) {
+			t.Errorf("Expected %s's last call time to be set, got %v", stat.Tool, stat.LastCalled)
+		}
+		if stat.AverageMs <= 0 || stat.P50Ms > stat.P95Ms || stat.P95Ms > stat.P99Ms {
+			t.Errorf("Expected %s's latencies to be ordered, got %+v", stat.Tool, stat)
+		}
+	}
+
+	if stats := toolStats(map[string]interface{}{"tool": "server1-crash"}); len(stats) != 1 || stats[0].Tool != "server1-crash" {
+		t.Errorf("Expected only server1-crash's stats, got %+v", stats)
+	}
+}
+
+// TestToolStatsLimit verifies calls to tools beyond the tracked number aren't tracked
+func TestToolStatsLimit(t *testing.T) {
+	stats := newToolStatsLog(1)
+	stats.add("server1-echo", "server1", time.Millisecond, false)
+	stats.add("server1-time", "server1", time.Millisecond, false)
+	stats.add("server1-echo", "server1", 3*time.Millisecond, true)
+
+	listed := stats.list("")
+	if len(listed) != 1 || listed[0].Tool != "server1-echo" || listed[0].Calls != 2 || listed[0].Errors != 1 {
+		t.Fatalf("Expected only the first tool to be tracked, got %+v", listed)
+	}
+	if listed[0].AverageMs != 2 || listed[0].P50Ms != 1 {
+		t.Errorf("Expected an average of 2ms and a median of 1ms, got %+v", listed[0])
+	}
+}
@@ -0,0 +1,131 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Name of the transport used by backends that don't set one
+const defaultTransport = "streamable-http"
+
+// BackendTransport is a connection from the gateway to one backend MCP server.
+// *client.Client from mcp-go satisfies it.
+//
+// The gateway calls Initialize exactly once, before any other method; a failed
+// Initialize is followed by Close. After that, ListTools and CallTool may be called
+// concurrently. Close releases the connection and may be called more than once.
+// Errors should wrap their cause so the gateway can recognise *BackendThrottledError,
+// *UnexpectedResponseError and ErrSessionTerminated failures.
+//
+// A transport may also implement ListResources and ListPrompts (used by
+// gateway_describe_backend) and OnNotification (used to forward backend notifications),
+// with the same signatures as *client.Client.
+type BackendTransport interface {
+	Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error)
+	ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error)
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	Close() error
+}
+
+// resourceLister is implemented by transports that can list resources and prompts
+type resourceLister interface {
+	ListResources(ctx context.Context, request mcp.ListResourcesRequest) (*mcp.ListResourcesResult, error)
+	ListPrompts(ctx context.Context, request mcp.ListPromptsRequest) (*mcp.ListPromptsResult, error)
+}
+
+// notificationSource is implemented by transports that deliver backend notifications
+type notificationSource interface {
+	OnNotification(handler func(notification mcp.JSONRPCNotification))
+}
+
+// TransportOptions carries gateway-provided settings to a transport factory
+type TransportOptions struct {
+	// HTTPClient applies the backend's signing, dial timeout and response checks;
+	// HTTP-based transports should send all requests through it
+	HTTPClient *http.Client
+	// Wrap, when set, must be applied to any mcp-go transport.Interface the
+	// connection is built on; it adds request ID remapping and replica tracking
+	Wrap func(transport.Interface) transport.Interface
+	// SessionID is the gateway client session the connection serves; it is empty
+	// for connections the gateway makes itself (startup discovery, admin tools)
+	SessionID string
+}
+
+// TransportFactory creates an uninitialized connection to a backend
+type TransportFactory func(ctx context.Context, backend BackendConfig, options TransportOptions) (BackendTransport, error)
+
+var (
+	transportsLock sync.RWMutex
+	transports     = make(map[string]TransportFactory)
+)
+
+func init() {
+	RegisterTransport(defaultTransport, newStreamableHTTPTransport)
+}
+
+// RegisterTransport makes a backend transport available by name to the backend
+// transport setting. It is meant to be called from init functions and panics if
+// the name is empty or already registered, or if factory is nil.
+func RegisterTransport(name string, factory TransportFactory) {
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+
+	if name == "" || factory == nil {
+		panic("gateway: RegisterTransport requires a name and a factory")
+	}
+	if _, exists := transports[name]; exists {
+		panic(fmt.Sprintf("gateway: transport %s registered twice", name))
+	}
+	transports[name] = factory
+}
+
+// lookupTransport returns the factory registered under name
+func lookupTransport(name string) (TransportFactory, error) {
+	transportsLock.RLock()
+	defer transportsLock.RUnlock()
+
+	factory, ok := transports[name]
+	if !ok {
+		names := make([]string, 0, len(transports))
+		for registered := range transports {
+			names = append(names, registered)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown transport %q (registered: %v)", name, names)
+	}
+	return factory, nil
+}
+
+// transportName returns the backend's transport, defaulting to streamable HTTP
+func (b BackendConfig) transportName() string {
+	if b.Transport != "" {
+		return b.Transport
+	}
+	return defaultTransport
+}
+
+// newStreamableHTTPTransport is the built-in streamable HTTP transport
+func newStreamableHTTPTransport(ctx context.Context, backend BackendConfig, options TransportOptions) (BackendTransport, error) {
+	httpTransport, err := transport.NewStreamableHTTP(backend.URL, transport.WithHTTPBasicClient(options.HTTPClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP transport: %w", err)
+	}
+
+	var backendTransport transport.Interface = httpTransport
+	if options.Wrap != nil {
+		backendTransport = options.Wrap(backendTransport)
+	}
+	backendClient := client.NewClient(backendTransport)
+	if err := backendClient.Start(ctx); err != nil {
+		backendClient.Close()
+		return nil, fmt.Errorf("failed to start client: %w", err)
+	}
+	return backendClient, nil
+}
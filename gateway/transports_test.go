@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// inMemoryTransport is an example custom transport serving tools from Go functions
+// in the same process, addressed as mem://<name>
+type inMemoryTransport struct {
+	tools       []mcp.Tool
+	handlers    map[string]func(args map[string]any) string
+	initialized atomic.Bool
+	closed      atomic.Bool
+}
+
+func (t *inMemoryTransport) Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error) {
+	t.initialized.Store(true)
+	return &mcp.InitializeResult{
+		ProtocolVersion: request.Params.ProtocolVersion,
+		ServerInfo:      mcp.Implementation{Name: "In-Memory", Version: "1.0.0"},
+	}, nil
+}
+
+func (t *inMemoryTransport) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	if !t.initialized.Load() {
+		return nil, fmt.Errorf("not initialized")
+	}
+	return &mcp.ListToolsResult{Tools: t.tools}, nil
+}
+
+func (t *inMemoryTransport) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	handler, ok := t.handlers[request.Params.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %s", request.Params.Name)
+	}
+	return mcp.NewToolResultText(handler(request.GetArguments())), nil
+}
+
+func (t *inMemoryTransport) Close() error {
+	t.closed.Store(true)
+	return nil
+}
+
+func init() {
+	RegisterTransport("in-memory", func(ctx context.Context, backend BackendConfig, options TransportOptions) (BackendTransport, error) {
+		if backend.URL != "mem://shouter" {
+			return nil, fmt.Errorf("no in-memory backend at %s", backend.URL)
+		}
+		return &inMemoryTransport{
+			tools: []mcp.Tool{mcp.NewTool("shout", mcp.WithString("message"))},
+			handlers: map[string]func(map[string]any) string{
+				"shout": func(args map[string]any) string { return strings.ToUpper(fmt.Sprint(args["message"])) },
+			},
+		}, nil
+	})
+}
+
+// TestCustomTransport verifies a registered transport is used for backends that name it
+// alongside the built-in streamable HTTP transport
+func TestCustomTransport(t *testing.T) {
+	httpBackend := newStubBackend(t, "Stub", textTool(mcp.NewTool("echo"), "ok"))
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{
+		{Name: "http", URL: httpBackend.URL},
+		{Name: "memory", URL: "mem://shouter", Transport: "in-memory"},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected a registered transport to validate: %v", err)
+	}
+
+	_, gatewayURL := startTestGateway(t, config)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	if text := extractTextFromResult(callTool(t, mcpClient, "memory-shout", map[string]any{"message": "hello"})); text != "HELLO" {
+		t.Errorf("Expected the in-memory transport to answer, got %q", text)
+	}
+	if text := extractTextFromResult(callTool(t, mcpClient, "http-echo", nil)); text != "ok" {
+		t.Errorf("Expected the built-in transport to answer, got %q", text)
+	}
+}
+
+// TestUnknownTransport verifies config validation rejects unregistered transports
+func TestUnknownTransport(t *testing.T) {
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{Name: "carrier-pigeon", URL: "coop://roof", Transport: "pigeon"}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `unknown transport "pigeon"`) {
+		t.Fatalf("Expected an unknown transport error, got %v", err)
+	}
+}
+
+// TestRegisterTransportTwice verifies duplicate registration panics
+func TestRegisterTransportTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering streamable-http again to panic")
+		}
+	}()
+	RegisterTransport(defaultTransport, newStreamableHTTPTransport)
+}
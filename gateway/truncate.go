@@ -0,0 +1,64 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resultMetaTruncated is the _meta key listing truncated content items
+const resultMetaTruncated = "truncated"
+
+// maxTextBytes returns the text content limit for a tool, or 0 if truncation is off
+func (g *Gateway) maxTextBytes(toolName string) int {
+	if limit, ok := g.config.Truncation.Tools[toolName]; ok {
+		return limit
+	}
+	return g.config.Truncation.MaxTextBytes
+}
+
+// truncateResult shortens oversized text content items in place
+func (g *Gateway) truncateResult(toolName string, result *mcp.CallToolResult) {
+	limit := g.maxTextBytes(toolName)
+	if result == nil || limit <= 0 {
+		return
+	}
+
+	var truncated []map[string]any
+	for i, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok || len(text.Text) <= limit {
+			continue
+		}
+
+		originalBytes := len(text.Text)
+		text.Text = truncateUTF8(text.Text, limit)
+		result.Content[i] = text
+		truncated = append(truncated, map[string]any{"index": i, "originalBytes": originalBytes})
+
+		log.Printf("✂️ Truncated %s content item %d from %d to %d bytes", toolName, i, originalBytes, limit)
+	}
+
+	if len(truncated) > 0 && g.config.Truncation.AddMeta {
+		if result.Meta == nil {
+			result.Meta = make(map[string]any)
+		}
+		result.Meta[resultMetaTruncated] = truncated
+	}
+}
+
+// truncateUTF8 keeps at most limit bytes of s without splitting a rune and appends a marker
+// giving the number of bytes removed
+func truncateUTF8(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + fmt.Sprintf("...[truncated %d bytes]", len(s)-cut)
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestTruncateUTF8MultibyteBoundary verifies truncation never splits a rune
+func TestTruncateUTF8MultibyteBoundary(t *testing.T) {
+	// "héllo" is h(1) é(2) l l o; a 2-byte limit falls in the middle of é
+	got := truncateUTF8("héllo", 2)
+	if got != "h...[truncated 5 bytes]" {
+		t.Fatalf("Unexpected truncation: %q", got)
+	}
+	if !utf8.ValidString(got) {
+		t.Fatalf("Truncated string is not valid UTF-8: %q", got)
+	}
+
+	// A limit on a rune boundary keeps the whole rune
+	if got := truncateUTF8("héllo", 3); got != "hé...[truncated 3 bytes]" {
+		t.Fatalf("Unexpected truncation: %q", got)
+	}
+
+	// Content within the limit is untouched
+	if got := truncateUTF8("héllo", 6); got != "héllo" {
+		t.Fatalf("Expected untouched string, got %q", got)
+	}
+}
+
+// TestTruncationThroughGateway verifies the marker and _meta note on oversized backend results
+func TestTruncationThroughGateway(t *testing.T) {
+	big := strings.Repeat("€", 100) // 300 bytes
+	backend1 := newStubBackend(t, "Stub Server 1", textTool(mcp.NewTool("big"), big))
+	backend2 := newStubBackend(t, "Stub Server 2", textTool(mcp.NewTool("big"), big))
+
+	config := DefaultConfig()
+	config.Truncation.MaxTextBytes = 10
+	config.Truncation.Tools = map[string]int{"server2-big": 0}
+	config.Truncation.AddMeta = true
+
+	_, gatewayURL := startTestGateway(t, config, backend1, backend2)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-big", nil)
+	text := extractTextFromResult(result)
+	if text != "€€€...[truncated 291 bytes]" {
+		t.Fatalf("Unexpected truncated text: %q", text)
+	}
+	if _, ok := result.Meta[resultMetaTruncated]; !ok {
+		t.Fatalf("Expected _meta.%s note, got %v", resultMetaTruncated, result.Meta)
+	}
+
+	// Per-tool override disables truncation
+	result = callTool(t, mcpClient, "server2-big", nil)
+	if extractTextFromResult(result) != big {
+		t.Fatalf("Expected server2-big to be untruncated")
+	}
+}
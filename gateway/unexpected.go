@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Maximum number of body bytes logged for an unexpected backend response
+const unexpectedBodySnippetBytes = 256
+
+// UnexpectedResponseError reports a backend HTTP response that is not an MCP message,
+// such as an HTML error page returned by a proxy in front of the backend
+type UnexpectedResponseError struct {
+	Backend     string
+	StatusCode  int
+	ContentType string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("backend %s returned an unexpected response (HTTP %d, content type %q)", e.Backend, e.StatusCode, e.ContentType)
+}
+
+// responseCheckRoundTripper rejects backend responses that cannot carry MCP messages
+// and feeds 429 responses to the backend's throttle
+type responseCheckRoundTripper struct {
+	next     http.RoundTripper
+	backend  string
+	throttle *backendThrottle
+}
+
+func (c *responseCheckRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.next.RoundTrip(req)
+	if err != nil || req.Method == http.MethodDelete {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if c.throttle != nil {
+			retryAfter = c.throttle.throttled(retryAfter)
+		}
+		return nil, &BackendThrottledError{Backend: c.backend, RetryAfter: retryAfter}
+	}
+	if c.throttle != nil && resp.StatusCode < http.StatusBadRequest {
+		c.throttle.recovered()
+	}
+
+	// A 404 on a session means the backend lost it
+	if resp.StatusCode == http.StatusNotFound && req.Header.Get("Mcp-Session-Id") != "" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("backend %s: %w", c.backend, ErrSessionTerminated)
+	}
+
+	if isMCPResponse(resp) {
+		return resp, nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, unexpectedBodySnippetBytes))
+	resp.Body.Close()
+
+	contentType := resp.Header.Get("Content-Type")
+	log.Printf("❌ Unexpected response from %s: HTTP %d (%s): %q",
+		c.backend, resp.StatusCode, contentType, strings.ToValidUTF8(string(snippet), ""))
+
+	return nil, &UnexpectedResponseError{
+		Backend:     c.backend,
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+	}
+}
+
+// isMCPResponse reports whether a response can be handled by the MCP client:
+// JSON or SSE bodies, accepted notifications, and 404s to requests without a session
+func isMCPResponse(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusNotFound {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return true
+	case "text/event-stream":
+		return resp.StatusCode == http.StatusOK
+	default:
+		return false
+	}
+}
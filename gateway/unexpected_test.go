@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestHTMLErrorPage verifies an HTML 502 from a proxy becomes a clean error and trips the circuit breaker
+func TestHTMLErrorPage(t *testing.T) {
+	mcpServer := server.NewMCPServer("Proxied", "1.0.0", server.WithToolCapabilities(true))
+	st := textTool(mcp.NewTool("echo"), "ok")
+	mcpServer.AddTool(st.tool, st.handler)
+	streamable := server.NewStreamableHTTPServer(mcpServer)
+
+	// The proxy lets the session through but fails tool calls with an HTML error page
+	var toolCalls atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if strings.Contains(string(body), `"tools/call"`) {
+			toolCalls.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html><body><h1>502 Bad Gateway</h1></body></html>"))
+			return
+		}
+		streamable.ServeHTTP(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	config := DefaultConfig()
+	config.CircuitBreaker = CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute}
+	gateway, gatewayURL := startTestGateway(t, config, backend)
+	mcpClient := newTestClient(t, gatewayURL)
+
+	result := callTool(t, mcpClient, "server1-echo", nil)
+	text := extractTextFromResult(result)
+	if !result.IsError || !strings.Contains(text, "unexpected response (HTTP 502") {
+		t.Fatalf("Expected a clean unexpected response error, got %q", text)
+	}
+	if strings.Contains(text, "<html>") {
+		t.Errorf("Expected the HTML body to be kept out of the error, got %q", text)
+	}
+	if failures := gateway.breakers["server1"].failureCount(); failures != 1 {
+		t.Errorf("Expected 1 circuit breaker failure, got %d", failures)
+	}
+
+	// The second failure opens the circuit, after which calls fail fast
+	callTool(t, mcpClient, "server1-echo", nil)
+	result = callTool(t, mcpClient, "server1-echo", nil)
+	if !strings.Contains(extractTextFromResult(result), "circuit open") {
+		t.Errorf("Expected the circuit to be open, got %q", extractTextFromResult(result))
+	}
+	if calls := toolCalls.Load(); calls != 2 {
+		t.Errorf("Expected 2 tool calls to reach the backend, got %d", calls)
+	}
+}
+
+// TestCircuitBreakerHalfOpen verifies a trial call after the open duration closes the circuit on success
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	breaker := newCircuitBreaker("test", CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: 20 * time.Millisecond})
+
+	breaker.recordFailure()
+	if breaker.allow() {
+		t.Fatal("Expected the circuit to be open")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !breaker.allow() {
+		t.Fatal("Expected a trial call once the open duration passed")
+	}
+	if breaker.allow() {
+		t.Fatal("Expected only one trial call while half-open")
+	}
+
+	breaker.recordSuccess()
+	if !breaker.allow() || breaker.failureCount() != 0 {
+		t.Error("Expected the circuit to close after a successful trial")
+	}
+}
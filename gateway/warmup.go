@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WarmupConfig lists requests sent right after connecting to a backend, so a
+// backend with cold-start latency is ready before the first client call
+type WarmupConfig struct {
+	// Ping sends an MCP ping as a generic no-op
+	Ping bool `yaml:"ping"`
+	// Calls are tool calls made in order, using the backend's own tool names
+	Calls []WarmupCall `yaml:"calls"`
+}
+
+// WarmupCall is a tool call made to warm up a backend
+type WarmupCall struct {
+	// Tool is the backend's tool name (without the gateway prefix)
+	Tool string `yaml:"tool"`
+	// Arguments are passed to the tool as-is
+	Arguments map[string]any `yaml:"arguments"`
+}
+
+// pinger is implemented by transports that support the MCP ping request
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// warmUp sends the backend's warmup requests over a new connection. Failures are
+// logged and never fail the connection.
+func (g *Gateway) warmUp(ctx context.Context, backend BackendConfig, backendClient BackendTransport) {
+	warmup := backend.Warmup
+	if !warmup.Ping && len(warmup.Calls) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backend.Timeouts.request())
+	defer cancel()
+
+	if warmup.Ping {
+		if p, ok := backendClient.(pinger); !ok {
+			log.Printf("⚠️ Warmup ping skipped for %s: transport does not support ping", backend.Name)
+		} else if err := p.Ping(ctx); err != nil {
+			log.Printf("⚠️ Warmup ping to %s failed: %v", backend.Name, err)
+		}
+	}
+
+	for _, call := range warmup.Calls {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = call.Tool
+		req.Params.Arguments = call.Arguments
+
+		result, err := backendClient.CallTool(ctx, req)
+		if err == nil && result.IsError {
+			err = fmt.Errorf("tool returned an error: %s", resultText(result))
+		}
+		if err != nil {
+			log.Printf("⚠️ Warmup call %s on %s failed: %v", call.Tool, backend.Name, err)
+			continue
+		}
+		log.Printf("🔧 Warmed up %s with %s", backend.Name, call.Tool)
+	}
+}
+
+// resultText joins the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text += textContent.Text
+		}
+	}
+	return text
+}
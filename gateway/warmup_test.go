@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestBackendWarmup verifies warmup calls are made on every new connection and
+// a failing warmup call doesn't fail the connection
+func TestBackendWarmup(t *testing.T) {
+	var warmups atomic.Int64
+	var warmupArgs atomic.Value
+	backend := newStubBackend(t, "Cold",
+		textTool(mcp.NewTool("echo"), "hello"),
+		stubTool{
+			tool: mcp.NewTool("warm", mcp.WithString("level")),
+			handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				warmups.Add(1)
+				warmupArgs.Store(req.GetString("level", ""))
+				return mcp.NewToolResultText("ready"), nil
+			},
+		},
+	)
+
+	config := DefaultConfig()
+	config.Backends = []BackendConfig{{
+		Name: "server1",
+		URL:  backend.URL,
+		Warmup: WarmupConfig{
+			Ping: true,
+			Calls: []WarmupCall{
+				{Tool: "missing"},
+				{Tool: "warm", Arguments: map[string]any{"level": "full"}},
+			},
+		},
+	}}
+	_, gatewayURL := startTestGateway(t, config)
+
+	// The startup connection is warmed up
+	if warmups.Load() != 1 {
+		t.Fatalf("Expected 1 warmup call after startup, got %d", warmups.Load())
+	}
+	if got := warmupArgs.Load(); got != "full" {
+		t.Errorf("Expected the configured warmup arguments, got %v", got)
+	}
+
+	// So is the client's dedicated connection, before its first call is forwarded
+	mcpClient := newTestClient(t, gatewayURL)
+	if text := extractTextFromResult(callTool(t, mcpClient, "server1-echo", nil)); text != "hello" {
+		t.Fatalf("Expected the call to succeed despite the failing warmup call, got %q", text)
+	}
+	if warmups.Load() != 2 {
+		t.Errorf("Expected 2 warmup calls after the client connected, got %d", warmups.Load())
+	}
+}
@@ -116,7 +116,7 @@ wait_for_server "$URL_GATEWAY" "gateway" || exit 1
 
 # Run tests
 echo "🧪 [test] Running E2E tests..."
-if go test -v ./e2e_test.go; then
+if E2E_EXTERNAL=1 go test -v -count=1 -run TestE2E .; then
     echo "✅ [test] E2E tests PASSED."
     exit 0
 else